@@ -34,6 +34,8 @@ func main() {
 	recommendService := recommend.NewRecommendService(
 		viewRecordRepo,
 		productRepo,
+		repository.NewBookRepository(db),
+		repository.NewCampusRepository(db),
 		db,
 		nil, // Redis可选
 	)
@@ -93,7 +95,7 @@ func main() {
 
 	// 测试4: 获取首页数据
 	fmt.Println("\n--- 测试4: 获取首页数据 ---")
-	homeData, err := recommendService.GetHomeData(ctx, &testUserID, 1, 10)
+	homeData, err := recommendService.GetHomeData(ctx, &testUserID, nil, 1, 10) // 不限校区
 	if err != nil {
 		log.Printf("✗ 获取首页数据失败: %v", err)
 	} else {
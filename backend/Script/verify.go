@@ -98,7 +98,7 @@ func VerifyService() []VerifyResult {
 	fmt.Println("验证服务层实现...")
 
 	// 检查UserService方法
-	userService := userservice.NewUserService(nil)
+	userService := userservice.NewUserService(nil, nil, nil, nil, nil, nil, nil, userservice.EmailVerificationConfig{}, "")
 	userServiceType := reflect.TypeOf(userService)
 	requiredUserServiceMethods := []string{
 		"Register",
@@ -127,7 +127,7 @@ func VerifyService() []VerifyResult {
 	}
 
 	// 检查ProductService方法
-	productService := productservice.NewProductService(nil, nil, nil, nil)
+	productService := productservice.NewProductService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	productServiceType := reflect.TypeOf(productService)
 	requiredProductServiceMethods := []string{
 		"CreateProduct",
@@ -254,7 +254,7 @@ func VerifyAdminModule() []VerifyResult {
 	fmt.Println("验证管理员模块实现...")
 
 	// 检查AdminService
-	adminService := adminservice.NewAdminService(nil)
+	adminService := adminservice.NewAdminService(nil, nil, nil, nil, nil, nil)
 	adminServiceType := reflect.TypeOf(adminService)
 	requiredAdminServiceMethods := []string{
 		"GetDashboardStats",
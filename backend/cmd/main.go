@@ -0,0 +1,164 @@
+// Package main 是应用程序的入口点
+// 负责初始化配置、数据库连接、内存缓存，并启动HTTP服务器
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cache"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/config"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/migrations"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/router"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/service/scheduler"
+)
+
+// main 函数是程序的启动入口
+// 执行流程：
+// 1. 加载配置（从.env文件或环境变量）
+// 2. 初始化数据库连接（PostgreSQL + GORM），执行 migrate 子命令或检查数据库结构版本
+// 3. 初始化内存缓存服务（用于推荐系统和状态撤销）
+// 4. 设置路由和中间件，并启动后台定时任务
+// 5. 启动HTTP服务器，收到 SIGINT/SIGTERM 后优雅关闭
+func main() {
+	// 步骤1: 加载应用配置
+	// LoadConfig 会尝试从以下来源读取配置（优先级从高到低）：
+	// - 环境变量
+	// - .env 文件（位于backend目录下）
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		// 如果配置加载失败，记录致命错误并退出程序
+		log.Fatalf("load config: %v", err)
+	}
+
+	// 打印已加载的配置信息（用于调试）
+	// 注意：生产环境应避免打印敏感信息（如密码）
+	log.Printf("Loaded config: DB_DSN=%s, HTTP_PORT=%d", cfg.DBDSN, cfg.HTTPPort)
+
+	// 步骤2: 初始化数据库连接
+	// 使用GORM（Go的ORM库）连接PostgreSQL数据库
+	// 如果DSN为空字符串，NewDB会返回nil（允许在没有数据库的情况下运行）
+	db, err := config.NewDB(cfg.DBDSN)
+	if err != nil {
+		log.Fatalf("failed to init DB, please check DB_DSN/network: %v", err)
+	}
+	if db == nil {
+		log.Fatalf("DB is nil, please set a valid DB_DSN (current: %s)", cfg.DBDSN)
+	}
+	log.Println("DB connection established successfully")
+
+	// 子命令 migrate：执行数据库迁移后退出，不启动HTTP服务器
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(db, os.Args[2:]))
+	}
+
+	// 数据库结构版本必须与程序一致，否则拒绝启动，避免在旧结构或更新版本的结构上运行
+	if err := migrations.New(db).Check(context.Background()); err != nil {
+		log.Fatalf("check database schema: %v (run `%s migrate status` for details)", err, os.Args[0])
+	}
+
+	// 步骤3: 初始化内存缓存服务
+	// 内存缓存用于：
+	// - 推荐系统的缓存
+	// - 商品状态变更的撤销记录（3秒窗口期）
+	memCache := cache.NewMemoryCache()
+	log.Println("Memory cache initialized successfully")
+
+	// 步骤4: 设置路由和中间件
+	// SetupRouter 会注册所有HTTP路由和中间件
+	// 包括：用户模块、商品模块、分类标签模块等
+	// 各模块的后台定时任务（如商品自动过期、过期数据清理）注册到调度器中，路由初始化完成后统一启动
+	// 任务状态保存在数据库中，多实例部署时同一任务只会在一个实例上执行
+	jobScheduler := scheduler.NewScheduler(repository.NewJobRepository(db), scheduler.Config{PollInterval: cfg.JobPollInterval})
+	r := router.SetupRouter(db, memCache, cfg, jobScheduler)
+	if cfg.JobSchedulerEnabled {
+		if err := jobScheduler.Start(context.Background()); err != nil {
+			log.Fatalf("start job scheduler: %v", err)
+		}
+	} else {
+		log.Println("job scheduler disabled on this instance (JOB_SCHEDULER_ENABLED=false)")
+	}
+
+	// 步骤5: 启动HTTP服务器
+	// 使用 http.Server 而不是 r.Run()，以便设置超时并在退出时优雅关闭
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.HTTPPort),
+		Handler:           r,
+		ReadHeaderTimeout: cfg.HTTPReadTimeout,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+
+	// 监听退出信号：Ctrl+C（SIGINT）与容器/进程管理器发出的 SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("starting server on %s", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	exitCode := 0
+	select {
+	case err := <-serverErr:
+		// 端口被占用等启动或运行时错误，仍需释放已创建的资源
+		log.Printf("server error: %v", err)
+		exitCode = 1
+	case <-ctx.Done():
+		// 先让就绪探针返回 503，等待负载均衡器摘除本实例后再停止接收新请求
+		router.MarkShuttingDown()
+		log.Printf("shutdown signal received, waiting %s for load balancers to stop routing...", cfg.ShutdownDrainDelay)
+	}
+	// 再次收到信号时直接按默认行为退出，不再等待
+	stop()
+	if exitCode == 0 {
+		time.Sleep(cfg.ShutdownDrainDelay)
+		log.Println("draining requests...")
+	}
+
+	shutdown(srv, jobScheduler, memCache, db, cfg.ShutdownTimeout)
+	os.Exit(exitCode)
+}
+
+// shutdown 按依赖顺序释放资源：
+//  1. 停止接收新请求（调用前就绪探针已返回 503 一段时间），等待进行中的请求完成（最多 timeout）
+//  2. 停止定时任务调度器，取消并等待正在执行的任务记录结果
+//  3. 停止内存缓存的后台清理
+//  4. 关闭数据库连接池（最后关闭，前面的步骤都可能访问数据库）
+func shutdown(srv *http.Server, jobScheduler *scheduler.Scheduler, memCache *cache.MemoryCache, db *gorm.DB, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		// 超过期限仍未完成的请求连接会被强制关闭
+		log.Printf("http server shutdown: %v", err)
+	}
+
+	jobScheduler.Stop()
+	log.Println("job scheduler stopped")
+
+	if err := memCache.Close(); err != nil {
+		log.Printf("close memory cache: %v", err)
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			log.Printf("close database: %v", err)
+		}
+	}
+	log.Println("server exited")
+}
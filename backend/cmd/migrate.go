@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/migrations"
+)
+
+const migrateUsage = `usage: %[1]s migrate <command>
+
+commands:
+  up               执行全部未执行的迁移
+  down [n]         回滚最近的 n 个迁移（默认 1）
+  status           查看各迁移的执行状态
+  force <version>  将数据库标记为指定版本而不执行脚本
+                   （通过旧 SQL 脚本建库的数据库先运行 force 1，再运行 up）
+`
+
+// runMigrate 执行 migrate 子命令，返回进程退出码
+func runMigrate(db *gorm.DB, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, migrateUsage, os.Args[0])
+		return 2
+	}
+
+	ctx := context.Background()
+	migrator := migrations.New(db)
+
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(ctx)
+		for _, mig := range done {
+			fmt.Printf("applied %d_%s\n", mig.Version, mig.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate up: %v\n", err)
+			return 1
+		}
+		if len(done) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		fmt.Printf("schema version: %d\n", migrations.Latest())
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				fmt.Fprintf(os.Stderr, "invalid step count %q\n", args[1])
+				return 2
+			}
+			steps = n
+		}
+		done, err := migrator.Down(ctx, steps)
+		for _, mig := range done {
+			fmt.Printf("reverted %d_%s\n", mig.Version, mig.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate down: %v\n", err)
+			return 1
+		}
+		if len(done) == 0 {
+			fmt.Println("no applied migrations")
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate status: %v\n", err)
+			return 1
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if !s.Known {
+				state += " (unknown to this binary)"
+			}
+			fmt.Printf("%4d  %-40s %s\n", s.Version, s.Name, state)
+		}
+
+	case "force":
+		if len(args) < 2 {
+			fmt.Fprintf(os.Stderr, migrateUsage, os.Args[0])
+			return 2
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			fmt.Fprintf(os.Stderr, "invalid version %q\n", args[1])
+			return 2
+		}
+		if err := migrator.Force(ctx, version); err != nil {
+			fmt.Fprintf(os.Stderr, "migrate force: %v\n", err)
+			return 1
+		}
+		fmt.Printf("schema version forced to %d\n", version)
+
+	default:
+		fmt.Fprintf(os.Stderr, migrateUsage, os.Args[0])
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/config"
+)
+
+// emailVerifyPurpose 邮箱验证令牌的用途标识
+const emailVerifyPurpose = "email_verify"
+
+// ErrInvalidEmailToken 邮箱验证令牌无效或已过期
+var ErrInvalidEmailToken = errors.New("invalid or expired email verification token")
+
+// GenerateEmailVerifyToken 生成邮箱验证令牌
+//
+// 令牌为带过期时间的 HS256 签名，绑定用户ID与邮箱地址：用户修改邮箱后旧令牌自动失效。
+// 签名密钥由 JWT_SECRET 派生但与登录令牌不同，验证令牌不能被当作登录令牌使用。
+func GenerateEmailVerifyToken(userID int64, email string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"purpose": emailVerifyPurpose,
+		"user_id": userID,
+		"email":   email,
+		"exp":     now.Add(ttl).Unix(),
+		"iat":     now.Unix(),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(emailVerifyKey())
+}
+
+// ParseEmailVerifyToken 校验邮箱验证令牌，返回其中的用户ID与邮箱地址
+func ParseEmailVerifyToken(token string) (int64, string, error) {
+	parsed, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return emailVerifyKey(), nil
+	}, jwt.WithExpirationRequired())
+	if err != nil || !parsed.Valid {
+		return 0, "", ErrInvalidEmailToken
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != emailVerifyPurpose {
+		return 0, "", ErrInvalidEmailToken
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, "", ErrInvalidEmailToken
+	}
+	email, ok := claims["email"].(string)
+	if !ok || email == "" {
+		return 0, "", ErrInvalidEmailToken
+	}
+	return int64(userID), email, nil
+}
+
+// emailVerifyKey 邮箱验证令牌的签名密钥
+func emailVerifyKey() []byte {
+	secret := "please-change-this"
+	if cfg, err := config.LoadConfig(); err == nil {
+		secret = cfg.JWTSecret
+	}
+	return []byte(secret + ":" + emailVerifyPurpose)
+}
@@ -131,6 +131,139 @@ func GenerateToken(userID int64) (string, error) {
 //  3. 验证token有效性：token.Valid
 //  4. 提取Claims并返回userID和isAdmin
 func ParseToken(token string) (int64, error) {
+	userID, _, err := ParseTokenWithIssuedAt(token)
+	return userID, err
+}
+
+// ParseTokenWithIssuedAt 解析并验证JWT token，同时返回签发时间
+// 用于判断令牌是否签发于用户的令牌作废时间（如重置密码）之前
+func ParseTokenWithIssuedAt(token string) (int64, time.Time, error) {
+	claims, err := ParseTokenClaims(token)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return claims.UserID, claims.IssuedAt, nil
+}
+
+// TokenClaims 登录令牌中的声明
+type TokenClaims struct {
+	UserID   int64
+	IssuedAt time.Time
+	// MFAAt 最近一次通过两步验证的时间，未经两步验证签发的令牌为零值
+	MFAAt time.Time
+	// SessionID 登录会话标识（sessions.token），会话被删除后令牌失效；旧版令牌为空
+	SessionID string
+}
+
+// TokenTTL 登录令牌有效期
+const TokenTTL = time.Hour
+
+// purposeTwoFactorChallenge 两步验证登录挑战令牌的用途标识
+// 带有 purpose 声明的令牌不能作为登录令牌使用
+const purposeTwoFactorChallenge = "2fa_challenge"
+
+// TwoFactorChallengeTTL 两步验证登录挑战令牌有效期
+const TwoFactorChallengeTTL = 5 * time.Minute
+
+// GenerateSessionToken 生成绑定登录会话的令牌
+// mfaAt 为最近一次通过两步验证的时间，未经两步验证时传零值
+func GenerateSessionToken(userID int64, sessionID string, mfaAt time.Time) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"exp":     now.Add(TokenTTL).Unix(),
+		"iat":     now.Unix(),
+		"sid":     sessionID,
+	}
+	if !mfaAt.IsZero() {
+		claims["mfa_at"] = mfaAt.Unix()
+	}
+	return signClaims(claims)
+}
+
+// GenerateTwoFactorChallenge 生成两步验证登录挑战令牌
+// 密码校验通过但账号已启用两步验证时签发，仅可用于提交动态验证码换取登录令牌
+func GenerateTwoFactorChallenge(userID int64) (string, error) {
+	now := time.Now()
+	return signClaims(jwt.MapClaims{
+		"user_id": userID,
+		"exp":     now.Add(TwoFactorChallengeTTL).Unix(),
+		"iat":     now.Unix(),
+		"purpose": purposeTwoFactorChallenge,
+	})
+}
+
+// ParseTwoFactorChallenge 解析两步验证登录挑战令牌，返回用户ID
+func ParseTwoFactorChallenge(token string) (int64, error) {
+	claims, err := parseSignedToken(token)
+	if err != nil {
+		return 0, err
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != purposeTwoFactorChallenge {
+		return 0, errors.New("not a two-factor challenge token")
+	}
+	return userIDFromClaims(claims)
+}
+
+// ParseTokenClaims 解析并验证登录令牌，返回全部声明
+// 带有 purpose 声明的专用令牌（如两步验证挑战令牌）会被拒绝
+func ParseTokenClaims(token string) (*TokenClaims, error) {
+	claims, err := parseSignedToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := claims["purpose"]; ok {
+		return nil, errors.New("token is not a login token")
+	}
+
+	userID, err := userIDFromClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TokenClaims{UserID: userID}
+	if iat, ok := claims["iat"].(float64); ok {
+		result.IssuedAt = time.Unix(int64(iat), 0)
+	}
+	if mfaAt, ok := claims["mfa_at"].(float64); ok && mfaAt > 0 {
+		result.MFAAt = time.Unix(int64(mfaAt), 0)
+	}
+	if sid, ok := claims["sid"].(string); ok {
+		result.SessionID = sid
+	}
+	return result, nil
+}
+
+// jwtSecret 读取签名密钥，未配置时使用默认值
+func jwtSecret() []byte {
+	secret := "please-change-this"
+	if cfg, err := config.LoadConfig(); err == nil {
+		secret = cfg.JWTSecret
+	}
+	return []byte(secret)
+}
+
+// signClaims 使用 HS256 签名声明
+func signClaims(claims jwt.MapClaims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret())
+}
+
+// userIDFromClaims 提取声明中的用户ID
+func userIDFromClaims(claims jwt.MapClaims) (int64, error) {
+	userIDValue, ok := claims["user_id"]
+	if !ok {
+		return 0, fmt.Errorf("user_id not found in token")
+	}
+
+	userIDFloat, ok := userIDValue.(float64)
+	if !ok {
+		return 0, fmt.Errorf("invalid user_id type")
+	}
+	return int64(userIDFloat), nil
+}
+
+// parseSignedToken 校验签名与过期时间并返回声明
+func parseSignedToken(token string) (jwt.MapClaims, error) {
 	// TODO: 实现JWT解析逻辑
 	// 伪代码示例：
 	//
@@ -168,20 +301,15 @@ func ParseToken(token string) (int64, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
-
-		jwtSecret := "please-change-this"
-		if cfg, err := config.LoadConfig(); err == nil {
-			jwtSecret = cfg.JWTSecret
-		}
-		return []byte(jwtSecret), nil
+		return jwtSecret(), nil
 	})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	claims, ok := parsedToken.Claims.(jwt.MapClaims)
 	if !ok || !parsedToken.Valid {
-		return 0, fmt.Errorf("invalid token")
+		return nil, fmt.Errorf("invalid token")
 	}
 
 	// 校验过期时间
@@ -189,24 +317,14 @@ func ParseToken(token string) (int64, error) {
 		switch exp := expValue.(type) {
 		case float64:
 			if time.Unix(int64(exp), 0).Before(time.Now()) {
-				return 0, errors.New("token expired")
+				return nil, errors.New("token expired")
 			}
 		case int64:
 			if time.Unix(exp, 0).Before(time.Now()) {
-				return 0, errors.New("token expired")
+				return nil, errors.New("token expired")
 			}
 		}
 	}
 
-	userIDValue, ok := claims["user_id"]
-	if !ok {
-		return 0, fmt.Errorf("user_id not found in token")
-	}
-
-	userIDFloat, ok := userIDValue.(float64)
-	if !ok {
-		return 0, fmt.Errorf("invalid user_id type")
-	}
-
-	return int64(userIDFloat), nil
+	return claims, nil
 }
@@ -18,9 +18,10 @@ type cacheItem struct {
 // MemoryCache 内存缓存服务
 // 线程安全的内存缓存实现，支持TTL（过期时间）
 type MemoryCache struct {
-	data   map[string]*cacheItem
-	mu     sync.RWMutex
-	stopCh chan struct{}
+	data      map[string]*cacheItem
+	mu        sync.RWMutex
+	stopCh    chan struct{}
+	closeOnce sync.Once
 }
 
 // NewMemoryCache 创建新的内存缓存实例
@@ -145,9 +146,9 @@ func (mc *MemoryCache) cleanupExpired() {
 }
 
 // Close 关闭缓存服务
-// 停止后台清理goroutine并清空所有数据
+// 停止后台清理goroutine并清空所有数据，可重复调用
 func (mc *MemoryCache) Close() error {
-	close(mc.stopCh)
+	mc.closeOnce.Do(func() { close(mc.stopCh) })
 
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
@@ -0,0 +1,291 @@
+// Package cron 解析 cron 表达式并计算下次执行时间
+//
+// 支持标准的 5 段表达式：分 时 日 月 周，例如 "30 3 * * *"（每天 3:30）、"*/15 * * * *"（每 15 分钟）。
+// 每段支持 *、数值、范围 a-b、列表 a,b 与步长 */n、a-b/n；月份与星期可使用英文缩写（JAN、MON）。
+// 周的取值为 0-7，0 与 7 都表示周日；日与周同时指定时满足其一即可（与 Vixie cron 一致）。
+// 另外支持 @yearly、@monthly、@weekly、@daily、@hourly 与 @every <时长>（如 @every 10m）。
+//
+// 夏令时切换的处理与 Vixie cron 一致：小时段为 * 的任务按实际经过的时间执行；
+// 指定了小时的任务，若执行时刻落在夏令时开始跳过的区间内则在跳变后立即执行，落在夏令时结束重复的区间内只执行一次。
+package cron
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 执行计划
+type Schedule interface {
+	// Next 返回严格晚于 t 的下一次执行时间，找不到时返回零值
+	Next(t time.Time) time.Time
+}
+
+// field 单段取值范围
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// descriptors 预定义表达式
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse 解析 cron 表达式
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || interval < time.Second {
+			return nil, fmt.Errorf("cron: invalid interval in %q", spec)
+		}
+		return everySchedule{interval: interval}, nil
+	}
+	if expanded, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("cron: expected 5 fields in %q, got %d", spec, len(parts))
+	}
+
+	var s specSchedule
+	var err error
+	if s.minute, err = parseField(parts[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(parts[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(parts[2], domField); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(parts[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(parts[4], dowField); err != nil {
+		return nil, err
+	}
+	// 7 与 0 都表示周日
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.hourAny = parts[1] == "*"
+	s.domAny = parts[2] == "*" || parts[2] == "?"
+	s.dowAny = parts[4] == "*" || parts[4] == "?"
+	return s, nil
+}
+
+// parseField 将单段表达式解析为位图
+func parseField(expr string, f field) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(expr, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("cron: invalid step %q in %s field", item, f.name)
+			}
+			step = n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			lo, hi = f.min, f.max
+			if f.max == 7 {
+				hi = 6 // 星期的 * 不重复包含 7
+			}
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = f.value(a); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(b); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("cron: invalid range %q in %s field", rangePart, f.name)
+			}
+		default:
+			v, err := f.value(rangePart)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+			if hasStep {
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// value 解析单个取值（数值或英文缩写）并检查范围
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("cron: invalid value %q in %s field (%d-%d)", s, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+// specSchedule 5 段表达式，各段以位图表示允许的取值
+type specSchedule struct {
+	minute, hour, dom, month, dow uint64
+	hourAny, domAny, dowAny       bool
+}
+
+// maxSearchYears 查找下次执行时间的年份上限（如 2 月 30 日永远不会匹配）
+const maxSearchYears = 5
+
+// Next 返回下次执行时间，使用 t 的时区
+// 小时与分钟按绝对时间前进，避免 time.Date 在夏令时跳过的区间内换算到更早的时间导致死循环
+func (s specSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+	loc := t.Location()
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = startOfDay(t.Year(), t.Month()+1, 1, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = startOfDay(t.Year(), t.Month(), t.Day()+1, loc)
+			continue
+		}
+		if s.missedAtDayStart(t) {
+			return t
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			next := nextHour(t)
+			if s.skippedHourMatches(t, next) {
+				return next
+			}
+			t = next
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			// 跳到本小时内下一个允许的分钟，没有则进入下一小时
+			rest := s.minute >> uint(t.Minute())
+			if rest == 0 {
+				next := nextHour(t)
+				if s.skippedHourMatches(t, next) {
+					return next
+				}
+				t = next
+			} else {
+				t = t.Add(time.Duration(bits.TrailingZeros64(rest)) * time.Minute)
+			}
+			continue
+		}
+		if !s.hourAny && isRepeatedHour(t) {
+			// 夏令时结束时重复的小时，该时刻已在第一次出现时执行过
+			t = nextHour(t)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// skippedHourMatches 判断从 prev 前进到 next 时，是否跳过了当天某个允许执行的小时（夏令时开始）
+// 小时段为 * 的任务不补执行
+func (s specSchedule) skippedHourMatches(prev, next time.Time) bool {
+	if s.hourAny || next.Day() != prev.Day() {
+		return false
+	}
+	for h := prev.Hour() + 1; h < next.Hour(); h++ {
+		if s.hour&(1<<uint(h)) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// missedAtDayStart 判断 t 是否为当天第一个时刻、且零点起被夏令时跳过的小时中有允许执行的（如零点切换夏令时的时区）
+func (s specSchedule) missedAtDayStart(t time.Time) bool {
+	if s.hourAny || t.Hour() == 0 || t.Minute() != 0 {
+		return false
+	}
+	if !t.Equal(startOfDay(t.Year(), t.Month(), t.Day(), t.Location())) {
+		return false
+	}
+	return s.hour&(1<<uint(t.Hour())-1) != 0
+}
+
+// nextHour 返回下一个整点（按绝对时间计算）
+func nextHour(t time.Time) time.Time {
+	return t.Add(time.Duration(60-t.Minute()) * time.Minute)
+}
+
+// isRepeatedHour 判断 t 是否处于夏令时结束后第二次出现的小时
+func isRepeatedHour(t time.Time) bool {
+	prev := t.Add(-time.Hour)
+	return prev.Hour() == t.Hour() && prev.Day() == t.Day()
+}
+
+// startOfDay 返回某天的第一个时刻（day 超出当月天数时顺延）
+// 零点因夏令时不存在时 time.Date 可能换算到前一天，此时按绝对时间前进到当天
+func startOfDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	y, m, d := time.Date(year, month, day, 12, 0, 0, 0, loc).Date()
+	t := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	for t.Day() != d {
+		t = t.Add(time.Hour)
+	}
+	return t
+}
+
+// dayMatches 判断日期是否匹配日与周两段
+func (s specSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// everySchedule 固定间隔
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Next 返回 t 之后按间隔对齐的下一次时间
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(time.Second).Add(s.interval)
+}
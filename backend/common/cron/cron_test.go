@@ -0,0 +1,381 @@
+package cron
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // 测试夏令时需要时区数据，不依赖运行环境是否安装
+)
+
+// mustLoad 加载时区
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load location %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"* * * FOO *",
+		"@every",
+		"@every 500ms",
+		"@every abc",
+		"@sometimes",
+	}
+	for _, spec := range tests {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	utc := time.UTC
+	// 2024-01-01 是周一
+	base := time.Date(2024, 1, 1, 10, 7, 30, 0, utc)
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want []time.Time // 依次调用 Next 的结果
+	}{
+		{
+			name: "每分钟，忽略秒",
+			spec: "* * * * *",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 1, 10, 8, 0, 0, utc),
+				time.Date(2024, 1, 1, 10, 9, 0, 0, utc),
+			},
+		},
+		{
+			name: "结果严格晚于起始时间",
+			spec: "8 10 * * *",
+			from: time.Date(2024, 1, 1, 10, 8, 0, 0, utc),
+			want: []time.Time{time.Date(2024, 1, 2, 10, 8, 0, 0, utc)},
+		},
+		{
+			name: "每天 3:30",
+			spec: "30 3 * * *",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 2, 3, 30, 0, 0, utc),
+				time.Date(2024, 1, 3, 3, 30, 0, 0, utc),
+			},
+		},
+		{
+			name: "*/15",
+			spec: "*/15 * * * *",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 1, 10, 15, 0, 0, utc),
+				time.Date(2024, 1, 1, 10, 30, 0, 0, utc),
+				time.Date(2024, 1, 1, 10, 45, 0, 0, utc),
+				time.Date(2024, 1, 1, 11, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "a/n 从 a 开始到段上限",
+			spec: "5/20 * * * *",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 1, 10, 25, 0, 0, utc),
+				time.Date(2024, 1, 1, 10, 45, 0, 0, utc),
+				time.Date(2024, 1, 1, 11, 5, 0, 0, utc),
+			},
+		},
+		{
+			name: "a-b/n",
+			spec: "0 9-17/4 * * *",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 1, 13, 0, 0, 0, utc),
+				time.Date(2024, 1, 1, 17, 0, 0, 0, utc),
+				time.Date(2024, 1, 2, 9, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "列表与范围",
+			spec: "0,30 8-9 * * *",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 2, 8, 0, 0, 0, utc),
+				time.Date(2024, 1, 2, 8, 30, 0, 0, utc),
+				time.Date(2024, 1, 2, 9, 0, 0, 0, utc),
+				time.Date(2024, 1, 2, 9, 30, 0, 0, utc),
+				time.Date(2024, 1, 3, 8, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "周 7 等同于周日",
+			spec: "0 0 * * 7",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 7, 0, 0, 0, 0, utc),
+				time.Date(2024, 1, 14, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "周 0 为周日",
+			spec: "0 0 * * 0",
+			from: base,
+			want: []time.Time{time.Date(2024, 1, 7, 0, 0, 0, 0, utc)},
+		},
+		{
+			name: "周范围包含 7",
+			spec: "0 0 * * 5-7",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 5, 0, 0, 0, 0, utc),
+				time.Date(2024, 1, 6, 0, 0, 0, 0, utc),
+				time.Date(2024, 1, 7, 0, 0, 0, 0, utc),
+				time.Date(2024, 1, 12, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "英文缩写",
+			spec: "0 12 * feb MON-wed",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 2, 5, 12, 0, 0, 0, utc),
+				time.Date(2024, 2, 6, 12, 0, 0, 0, utc),
+				time.Date(2024, 2, 7, 12, 0, 0, 0, utc),
+				time.Date(2024, 2, 12, 12, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "日与周同时指定时满足其一即可",
+			spec: "0 0 13 * 5",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 5, 0, 0, 0, 0, utc),  // 周五
+				time.Date(2024, 1, 12, 0, 0, 0, 0, utc), // 周五
+				time.Date(2024, 1, 13, 0, 0, 0, 0, utc), // 13 日（周六）
+				time.Date(2024, 1, 19, 0, 0, 0, 0, utc), // 周五
+			},
+		},
+		{
+			name: "日为 * 时只按周匹配",
+			spec: "0 0 * * 5",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 5, 0, 0, 0, 0, utc),
+				time.Date(2024, 1, 12, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "周为 * 时只按日匹配",
+			spec: "0 0 13 * *",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 13, 0, 0, 0, 0, utc),
+				time.Date(2024, 2, 13, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "31 日跳过小月",
+			spec: "0 0 31 * *",
+			from: base,
+			want: []time.Time{
+				time.Date(2024, 1, 31, 0, 0, 0, 0, utc),
+				time.Date(2024, 3, 31, 0, 0, 0, 0, utc),
+				time.Date(2024, 5, 31, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "2 月 29 日只在闰年",
+			spec: "0 0 29 2 *",
+			from: time.Date(2024, 3, 1, 0, 0, 0, 0, utc),
+			want: []time.Time{
+				time.Date(2028, 2, 29, 0, 0, 0, 0, utc),
+				time.Date(2032, 2, 29, 0, 0, 0, 0, utc),
+			},
+		},
+		{
+			name: "5 年内无匹配返回零值",
+			spec: "0 0 30 2 *",
+			from: base,
+			want: []time.Time{{}},
+		},
+		{
+			name: "@daily",
+			spec: "@daily",
+			from: base,
+			want: []time.Time{time.Date(2024, 1, 2, 0, 0, 0, 0, utc)},
+		},
+		{
+			name: "@weekly",
+			spec: "@weekly",
+			from: base,
+			want: []time.Time{time.Date(2024, 1, 7, 0, 0, 0, 0, utc)},
+		},
+		{
+			name: "@yearly",
+			spec: "@yearly",
+			from: base,
+			want: []time.Time{time.Date(2025, 1, 1, 0, 0, 0, 0, utc)},
+		},
+		{
+			name: "@every 按秒对齐",
+			spec: "@every 90s",
+			from: time.Date(2024, 1, 1, 10, 7, 30, 500, utc),
+			want: []time.Time{
+				time.Date(2024, 1, 1, 10, 9, 0, 0, utc),
+				time.Date(2024, 1, 1, 10, 10, 30, 0, utc),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+			from := tt.from
+			for i, want := range tt.want {
+				got := schedule.Next(from)
+				if !got.Equal(want) {
+					t.Fatalf("Next #%d after %v = %v, want %v", i+1, from, got, want)
+				}
+				from = got
+			}
+		})
+	}
+}
+
+func TestNextDST(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	// 智利在零点切换夏令时，当天零点不存在
+	santiago := mustLoad(t, "America/Santiago")
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want []time.Time
+	}{
+		{
+			name: "夏令时开始：跳过的时刻在跳变后补执行",
+			spec: "30 2 * * *",
+			from: time.Date(2024, 3, 10, 0, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2024, 3, 10, 3, 0, 0, 0, newYork),
+				time.Date(2024, 3, 11, 2, 30, 0, 0, newYork),
+			},
+		},
+		{
+			name: "夏令时开始：小时为 * 时不补执行",
+			spec: "0 * * * *",
+			from: time.Date(2024, 3, 10, 1, 30, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2024, 3, 10, 3, 0, 0, 0, newYork),
+				time.Date(2024, 3, 10, 4, 0, 0, 0, newYork),
+			},
+		},
+		{
+			name: "夏令时开始：跳过区间之外的任务不受影响",
+			spec: "0 4 * * *",
+			from: time.Date(2024, 3, 10, 0, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2024, 3, 10, 4, 0, 0, 0, newYork),
+				time.Date(2024, 3, 11, 4, 0, 0, 0, newYork),
+			},
+		},
+		{
+			name: "夏令时结束：重复的时刻只执行一次",
+			spec: "30 1 * * *",
+			from: time.Date(2024, 11, 3, 0, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2024, 11, 3, 1, 30, 0, 0, newYork), // EDT
+				time.Date(2024, 11, 4, 1, 30, 0, 0, newYork),
+			},
+		},
+		{
+			name: "夏令时结束：小时为 * 时按实际经过的时间执行",
+			spec: "*/30 * * * *",
+			from: time.Date(2024, 11, 3, 5, 45, 0, 0, time.UTC).In(newYork), // 01:45 EDT
+			want: []time.Time{
+				time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC),  // 01:00 EST
+				time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC), // 01:30 EST
+				time.Date(2024, 11, 3, 7, 0, 0, 0, time.UTC),  // 02:00 EST
+			},
+		},
+		{
+			name: "零点不存在：零点任务在跳变后执行",
+			spec: "0 0 * * *",
+			from: time.Date(2024, 9, 7, 12, 0, 0, 0, santiago),
+			want: []time.Time{
+				time.Date(2024, 9, 8, 1, 0, 0, 0, santiago),
+				time.Date(2024, 9, 9, 0, 0, 0, 0, santiago),
+			},
+		},
+		{
+			name: "零点不存在：跨日前进不会死循环",
+			spec: "0 12 * * *",
+			from: time.Date(2024, 9, 7, 12, 0, 0, 0, santiago),
+			want: []time.Time{
+				time.Date(2024, 9, 8, 12, 0, 0, 0, santiago),
+				time.Date(2024, 9, 9, 12, 0, 0, 0, santiago),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+			from := tt.from
+			for i, want := range tt.want {
+				got := schedule.Next(from)
+				if !got.Equal(want) {
+					t.Fatalf("Next #%d after %v = %v, want %v", i+1, from, got, want)
+				}
+				if !got.After(from) {
+					t.Fatalf("Next #%d = %v is not after %v", i+1, got, from)
+				}
+				from = got
+			}
+		})
+	}
+}
+
+// TestNextAlwaysAdvances 在夏令时切换前后逐分钟调用 Next，结果必须严格晚于输入
+func TestNextAlwaysAdvances(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	specs := []string{"* * * * *", "*/7 * * * *", "30 2 * * *", "0 1-3 * * *", "15 * * * 0"}
+	windows := []time.Time{
+		time.Date(2024, 3, 9, 23, 0, 0, 0, newYork),
+		time.Date(2024, 11, 2, 23, 0, 0, 0, newYork),
+	}
+	for _, spec := range specs {
+		schedule, err := Parse(spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", spec, err)
+		}
+		for _, start := range windows {
+			for from := start; from.Before(start.Add(6 * time.Hour)); from = from.Add(time.Minute) {
+				if got := schedule.Next(from); !got.After(from) {
+					t.Fatalf("%q: Next(%v) = %v, not after input", spec, from, got)
+				}
+			}
+		}
+	}
+}
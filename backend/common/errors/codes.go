@@ -40,9 +40,39 @@ const (
 	// - 用户尝试删除他人的评论
 	// 示例消息："无权限访问"、"只能编辑自己的商品"
 	CodeForbidden = 1003
+
+	// CodeTooManyRequests 表示请求频率超出限流配额（HTTP 状态码同时为 429）
+	// 使用场景：
+	// - 短时间内频繁登录、上传图片、发布商品
+	// 响应头 Retry-After 与 data.retryAfterSeconds 为需要等待的秒数
+	CodeTooManyRequests = 1004
 )
 
 // ============ 用户模块错误码（2xxx）============
+// 2001/2002 已由前端约定为账号已存在/登录失败，新增错误码从 2003 开始
+
+const (
+	// CodeEmailNotVerified 表示开启校园邮箱验证后，用户尚未完成邮箱验证
+	// 使用场景：未验证用户发布商品、查看卖家联系方式
+	CodeEmailNotVerified = 2003
+
+	// CodeLoginThrottled 表示连续登录失败后处于冷却期，需等待后再试
+	// 响应 data 中携带 retryAfterSeconds（剩余秒数）与 locked（false）
+	CodeLoginThrottled = 2004
+
+	// CodeAccountLocked 表示连续登录失败次数过多，账号或IP已被临时锁定
+	// 响应 data 中携带 retryAfterSeconds（剩余秒数）与 locked（true），管理员可提前解锁
+	CodeAccountLocked = 2005
+
+	// CodeTwoFactorRequired 表示需要完成两步验证
+	// 使用场景：已启用两步验证的账号登录（data 中携带 twoFactorToken），
+	// 管理员令牌未经两步验证或验证已超过有效期时访问管理后台（需调用二次验证接口换取新令牌）
+	CodeTwoFactorRequired = 2006
+
+	// CodeTwoFactorSetupRequired 表示管理员尚未启用两步验证，需先完成绑定才能访问管理后台
+	CodeTwoFactorSetupRequired = 2007
+)
+
 // TODO: 根据需求添加更多用户相关错误码
 // 示例：
 // const (
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// csvFlushEvery 每写入多少行刷新一次缓冲
+const csvFlushEvery = 100
+
+// utf8BOM Excel 打开 UTF-8 CSV 时依赖 BOM 识别编码，否则中文乱码
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// CSVWriter 流式CSV写入器
+type CSVWriter struct {
+	out     io.Writer
+	w       *csv.Writer
+	rows    int
+	started bool
+}
+
+// NewCSVWriter 创建CSV写入器
+// BOM 延迟到写入第一行时输出，便于调用方在出错时仍能返回普通错误响应
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{out: w, w: csv.NewWriter(w)}
+}
+
+// WriteRow 写入一行
+func (cw *CSVWriter) WriteRow(cells []string) error {
+	if !cw.started {
+		if _, err := cw.out.Write(utf8BOM); err != nil {
+			return err
+		}
+		cw.started = true
+	}
+
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = escapeFormula(cell)
+	}
+	if err := cw.w.Write(escaped); err != nil {
+		return err
+	}
+
+	cw.rows++
+	if cw.rows%csvFlushEvery == 0 {
+		cw.w.Flush()
+		return cw.w.Error()
+	}
+	return nil
+}
+
+// Close 刷新剩余缓冲
+func (cw *CSVWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// ContentType CSV 的 MIME 类型
+func (cw *CSVWriter) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+// escapeFormula 防止CSV公式注入：以 = + - @ 开头的单元格前加单引号
+func escapeFormula(cell string) string {
+	if cell == "" {
+		return cell
+	}
+	switch cell[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + cell
+	}
+	return cell
+}
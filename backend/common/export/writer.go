@@ -0,0 +1,46 @@
+// Package export 提供表格数据的流式导出（CSV / XLSX）
+// 导出按行写入底层 io.Writer，不在内存中缓存完整数据集
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 支持的导出格式
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// RowWriter 按行写出表格数据
+type RowWriter interface {
+	// WriteRow 写入一行（第一行通常为表头）
+	WriteRow(cells []string) error
+	// Close 写出剩余缓冲数据与文件尾；不会关闭底层 io.Writer
+	Close() error
+	// ContentType 导出文件的 MIME 类型
+	ContentType() string
+}
+
+// NewWriter 根据格式创建导出写入器
+func NewWriter(format string, w io.Writer) (RowWriter, error) {
+	switch strings.ToLower(format) {
+	case "", FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w), nil
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
+
+// NormalizeFormat 规范化导出格式，空值默认为CSV
+func NormalizeFormat(format string) string {
+	format = strings.ToLower(format)
+	if format == "" {
+		return FormatCSV
+	}
+	return format
+}
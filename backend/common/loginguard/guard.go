@@ -0,0 +1,254 @@
+// Package loginguard 提供登录防暴力破解：按账号与 IP 统计连续失败次数，
+// 超过阈值后逐步延长下一次允许尝试的时间，账号达到上限后临时锁定
+//
+// 每次尝试在校验密码之前就计入失败次数（检查与计数在存储中原子完成），登录成功后再撤销，
+// 因此并发请求无法在计数更新之前一起通过检查、绕过渐进延迟。
+// IP 取自 c.ClientIP()，只有配置为可信代理（TRUSTED_PROXIES）转发的请求才会读取 X-Forwarded-For。
+package loginguard
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// State 某个账号或 IP 的失败计数状态
+type State struct {
+	Failures      int       // 窗口内连续失败次数
+	NextAttemptAt time.Time // 渐进延迟：此时间之前的尝试直接拒绝
+	LockedUntil   time.Time // 锁定截止时间
+}
+
+// Store 失败计数存储，默认实现基于内存缓存，多实例部署时可替换为共享存储
+type Store interface {
+	// Load 读取状态，不存在时返回 false
+	Load(ctx context.Context, key string) (State, bool)
+	// Update 原子地读取并修改状态：fn 收到当前状态（不存在时为零值与 false），返回新状态与保留时间，
+	// 保留时间 <= 0 表示不修改。同一键的并发 Update 必须串行执行，共享存储需使用事务或脚本实现
+	Update(ctx context.Context, key string, fn func(state State, exists bool) (State, time.Duration)) error
+	// Delete 删除状态
+	Delete(ctx context.Context, key string) error
+}
+
+// Policy 限制策略
+type Policy struct {
+	DelayAfter   int           // 连续失败达到该次数后开始渐进延迟
+	BaseDelay    time.Duration // 首次延迟，之后每次失败翻倍
+	MaxDelay     time.Duration // 延迟上限
+	LockAfter    int           // 连续失败达到该次数后锁定，<= 0 表示只延迟不锁定
+	LockDuration time.Duration // 锁定时长
+	Window       time.Duration // 失败计数保留时间，超过后重新计数
+}
+
+// DefaultAccountPolicy 单账号策略：3 次失败后开始延迟，10 次失败锁定 15 分钟
+func DefaultAccountPolicy() Policy {
+	return Policy{
+		DelayAfter:   3,
+		BaseDelay:    time.Second,
+		MaxDelay:     30 * time.Second,
+		LockAfter:    10,
+		LockDuration: 15 * time.Minute,
+		Window:       30 * time.Minute,
+	}
+}
+
+// DefaultIPPolicy 单 IP 策略：阈值更宽松，用于限制同一来源对多个账号的撞库
+// 只延迟不锁定：校园网等 NAT 出口后有大量用户共用一个 IP，锁定会让所有人都无法登录
+func DefaultIPPolicy() Policy {
+	return Policy{
+		DelayAfter: 10,
+		BaseDelay:  time.Second,
+		MaxDelay:   time.Minute,
+		Window:     time.Hour,
+	}
+}
+
+// Throttle 拒绝登录尝试的原因
+type Throttle struct {
+	Locked     bool          // true 表示已锁定，false 表示处于渐进延迟中
+	RetryAfter time.Duration // 距离允许下一次尝试的剩余时间
+}
+
+// Guard 登录防暴力破解守卫
+type Guard struct {
+	store         Store
+	accountPolicy Policy
+	ipPolicy      Policy
+}
+
+// NewGuard 创建登录守卫
+func NewGuard(store Store, accountPolicy, ipPolicy Policy) *Guard {
+	return &Guard{
+		store:         store,
+		accountPolicy: accountPolicy,
+		ipPolicy:      ipPolicy,
+	}
+}
+
+// Attempt 登录尝试开始时调用：未受限时将本次尝试计入失败次数并返回 nil，受限时返回限制原因（不计数）
+// 账号与 IP 分别原子地检查并计数；IP 受限时撤销已计入账号的次数。登录成功后需调用 RecordSuccess
+func (g *Guard) Attempt(ctx context.Context, account, ip string) *Throttle {
+	if g == nil {
+		return nil
+	}
+	now := time.Now()
+	keys := g.keys(account, ip)
+	for i, key := range keys {
+		policy := g.policy(i)
+		var throttle *Throttle
+		// 存储故障时放行，避免防护组件导致无法登录
+		_ = g.store.Update(ctx, key, func(state State, _ bool) (State, time.Duration) {
+			if throttle = throttleOf(state, now); throttle != nil {
+				return state, 0
+			}
+			state = policy.apply(state, now)
+			return state, policy.ttl(state, now)
+		})
+		if throttle != nil {
+			for j := 0; j < i; j++ {
+				g.refund(ctx, keys[j], g.policy(j), now)
+			}
+			return throttle
+		}
+	}
+	return nil
+}
+
+// Status 查询当前的限制状态（不计数），用于在失败后判断本次尝试是否触发了锁定
+// 账号与 IP 均受限时返回剩余时间较长的一个
+func (g *Guard) Status(ctx context.Context, account, ip string) *Throttle {
+	if g == nil {
+		return nil
+	}
+	now := time.Now()
+	var result *Throttle
+	for _, key := range g.keys(account, ip) {
+		state, ok := g.store.Load(ctx, key)
+		if !ok {
+			continue
+		}
+		if t := throttleOf(state, now); t != nil && (result == nil || t.RetryAfter > result.RetryAfter) {
+			result = t
+		}
+	}
+	return result
+}
+
+// RecordSuccess 登录成功后清除账号的失败计数，并撤销 Attempt 为本次尝试计入的 IP 次数
+// （IP 之前的失败计数保留，避免撞库时用自有账号重置）
+func (g *Guard) RecordSuccess(ctx context.Context, account, ip string) {
+	if g == nil {
+		return
+	}
+	_ = g.store.Delete(ctx, accountKey(account))
+	if ip != "" {
+		g.refund(ctx, ipKey(ip), g.ipPolicy, time.Now())
+	}
+}
+
+// Unlock 解除账号锁定并清除失败计数，供管理员使用
+func (g *Guard) Unlock(ctx context.Context, account string) error {
+	if g == nil {
+		return nil
+	}
+	return g.store.Delete(ctx, accountKey(account))
+}
+
+// AccountState 查询账号当前的失败计数状态
+func (g *Guard) AccountState(ctx context.Context, account string) (State, bool) {
+	if g == nil {
+		return State{}, false
+	}
+	return g.store.Load(ctx, accountKey(account))
+}
+
+// keys 返回账号与 IP 对应的存储键，IP 为空时只返回账号键
+func (g *Guard) keys(account, ip string) []string {
+	keys := []string{accountKey(account)}
+	if ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+	return keys
+}
+
+// policy 返回 keys 中第 i 个键对应的策略
+func (g *Guard) policy(i int) Policy {
+	if i == 0 {
+		return g.accountPolicy
+	}
+	return g.ipPolicy
+}
+
+// refund 撤销一次预先计入的失败
+func (g *Guard) refund(ctx context.Context, key string, policy Policy, now time.Time) {
+	_ = g.store.Update(ctx, key, func(state State, exists bool) (State, time.Duration) {
+		if !exists || state.Failures == 0 {
+			return state, 0
+		}
+		state = policy.revert(state)
+		return state, policy.ttl(state, now)
+	})
+}
+
+// ipKey IP 计数的存储键
+func ipKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+// accountKey 账号不区分大小写，不存在的账号同样计数，避免通过响应差异探测账号是否存在
+func accountKey(account string) string {
+	return "login:account:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+// apply 在状态上记录一次失败
+func (p Policy) apply(state State, now time.Time) State {
+	if !state.LockedUntil.IsZero() && now.After(state.LockedUntil) {
+		// 锁定已过期，重新计数
+		state = State{}
+	}
+	state.Failures++
+
+	switch {
+	case p.LockAfter > 0 && state.Failures >= p.LockAfter:
+		state.LockedUntil = now.Add(p.LockDuration)
+	case p.DelayAfter > 0 && state.Failures >= p.DelayAfter:
+		delay := p.BaseDelay << uint(state.Failures-p.DelayAfter)
+		if delay <= 0 || delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+		state.NextAttemptAt = now.Add(delay)
+	}
+	return state
+}
+
+// revert 撤销一次失败，清除因该次失败产生的延迟或锁定
+func (p Policy) revert(state State) State {
+	state.Failures--
+	if p.LockAfter <= 0 || state.Failures < p.LockAfter {
+		state.LockedUntil = time.Time{}
+	}
+	if p.DelayAfter <= 0 || state.Failures < p.DelayAfter {
+		state.NextAttemptAt = time.Time{}
+	}
+	return state
+}
+
+// ttl 状态保留时间：锁定期间至少保留到解锁
+func (p Policy) ttl(state State, now time.Time) time.Duration {
+	ttl := p.Window
+	if remaining := state.LockedUntil.Sub(now); remaining > ttl {
+		ttl = remaining
+	}
+	return ttl
+}
+
+// throttleOf 根据状态计算当前是否受限
+func throttleOf(state State, now time.Time) *Throttle {
+	if now.Before(state.LockedUntil) {
+		return &Throttle{Locked: true, RetryAfter: state.LockedUntil.Sub(now)}
+	}
+	if now.Before(state.NextAttemptAt) {
+		return &Throttle{RetryAfter: state.NextAttemptAt.Sub(now)}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package loginguard
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cache"
+)
+
+// memoryStore 基于 cache.MemoryCache 的失败计数存储（单实例部署）
+// 互斥锁保证同一进程内 Update 的读改写不会交错
+type memoryStore struct {
+	cache *cache.MemoryCache
+	mu    sync.Mutex
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore(memCache *cache.MemoryCache) Store {
+	return &memoryStore{cache: memCache}
+}
+
+// Load 实现 Store
+func (s *memoryStore) Load(ctx context.Context, key string) (State, bool) {
+	value, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return State{}, false
+	}
+	state, ok := value.(State)
+	return state, ok
+}
+
+// Update 实现 Store
+func (s *memoryStore) Update(ctx context.Context, key string, fn func(state State, exists bool) (State, time.Duration)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.Load(ctx, key)
+	state, ttl := fn(state, ok)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.cache.Set(ctx, key, state, ttl)
+}
+
+// Delete 实现 Store
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache.Delete(ctx, key)
+}
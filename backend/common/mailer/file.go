@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// unsafeFileChars 收件人地址中不适合出现在文件名里的字符
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9@._-]`)
+
+// fileMailer 将每封邮件保存为目录下的 .eml 文件，仅用于开发环境
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer 创建文件邮件发送实例，目录不存在时自动创建
+func NewFileMailer(dir, from string) (Mailer, error) {
+	if dir == "" {
+		dir = "./mail"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create mail dir failed: %w", err)
+	}
+	return &fileMailer{dir: dir, from: from}, nil
+}
+
+// Send 将邮件写入文件，文件名包含时间戳与收件人
+func (m *fileMailer) Send(_ context.Context, msg Message) error {
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	return os.WriteFile(filepath.Join(m.dir, name), buildMIME(m.from, msg), 0o600)
+}
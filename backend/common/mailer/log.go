@@ -0,0 +1,20 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// logMailer 将邮件内容输出到日志，仅用于开发环境
+type logMailer struct{}
+
+// NewLogMailer 创建日志邮件发送实例
+func NewLogMailer() Mailer {
+	return logMailer{}
+}
+
+// Send 将邮件写入日志
+func (logMailer) Send(_ context.Context, msg Message) error {
+	log.Printf("mail to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,54 @@
+// Package mailer 提供可替换的邮件发送实现
+// 生产环境使用 SMTP，开发环境可将邮件输出到日志或写入文件，便于在不配置邮件服务器的情况下调试
+package mailer
+
+import (
+	"context"
+	"fmt"
+)
+
+// 邮件发送方式
+const (
+	DriverLog  = "log"
+	DriverFile = "file"
+	DriverSMTP = "smtp"
+)
+
+// Message 待发送的纯文本邮件
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config 邮件发送配置
+type Config struct {
+	Driver string // log/file/smtp，为空时使用 log
+	From   string // 发件人地址
+
+	FileDir string // file 方式下邮件文件的保存目录
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// New 根据配置创建邮件发送实例
+func New(cfg Config) (Mailer, error) {
+	switch cfg.Driver {
+	case "", DriverLog:
+		return NewLogMailer(), nil
+	case DriverFile:
+		return NewFileMailer(cfg.FileDir, cfg.From)
+	case DriverSMTP:
+		return NewSMTPMailer(cfg)
+	default:
+		return nil, fmt.Errorf("unknown mail driver: %s", cfg.Driver)
+	}
+}
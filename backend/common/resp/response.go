@@ -81,3 +81,35 @@ func Error(c *gin.Context, code int, message string) {
 		"data":    nil,     // 错误时data字段固定为null
 	})
 }
+
+// ErrorWithData 返回携带附加信息的错误响应
+//
+// 用于前端需要根据错误详情展示提示的场景，如登录冷却剩余时间：
+//   {
+//     "code": 2005,
+//     "message": "登录失败次数过多，账号已临时锁定，请 15 分钟后再试",
+//     "data": {"retryAfterSeconds": 900, "locked": true}
+//   }
+func ErrorWithData(c *gin.Context, code int, message string, data interface{}) {
+	c.JSON(200, gin.H{
+		"code":    code,
+		"message": message,
+		"data":    data,
+	})
+}
+
+// ErrorWithStatus 以指定的 HTTP 状态码返回错误响应，响应体格式与 Error 相同
+//
+// 仅用于需要被网关、客户端按 HTTP 语义识别的场景，如限流返回 429 并配合 Retry-After 响应头：
+//   {
+//     "code": 1004,
+//     "message": "请求过于频繁，请 30 秒后再试",
+//     "data": {"retryAfterSeconds": 30}
+//   }
+func ErrorWithStatus(c *gin.Context, status int, code int, message string, data interface{}) {
+	c.JSON(status, gin.H{
+		"code":    code,
+		"message": message,
+		"data":    data,
+	})
+}
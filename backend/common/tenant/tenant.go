@@ -0,0 +1,28 @@
+// Package tenant 提供多学校租户的上下文传递
+// 租户（学校）由中间件在请求入口解析后写入 context，仓库层据此过滤数据
+package tenant
+
+import "context"
+
+// DefaultSchoolID 默认学校ID，未能解析到租户时（如后台脚本）使用
+const DefaultSchoolID int64 = 1
+
+// HeaderSchoolCode 指定学校编码的请求头，超级管理员可借此切换管理的学校
+const HeaderSchoolCode = "X-School-Code"
+
+type contextKey struct{}
+
+// WithSchoolID 返回携带学校ID的 context
+func WithSchoolID(ctx context.Context, schoolID int64) context.Context {
+	return context.WithValue(ctx, contextKey{}, schoolID)
+}
+
+// SchoolID 获取 context 中的学校ID，未设置时返回 DefaultSchoolID
+func SchoolID(ctx context.Context) int64 {
+	if ctx != nil {
+		if id, ok := ctx.Value(contextKey{}).(int64); ok && id > 0 {
+			return id
+		}
+	}
+	return DefaultSchoolID
+}
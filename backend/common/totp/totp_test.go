@@ -0,0 +1,156 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret RFC 6238 附录 B 中 SHA-1 测试使用的密钥 "12345678901234567890" 的 Base32 编码
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// rfcVectors RFC 6238 附录 B 的 SHA-1 测试向量，8 位验证码取后 6 位即为本包的 6 位验证码
+var rfcVectors = []struct {
+	unix int64
+	code string // 8 位
+}{
+	{59, "94287082"},
+	{1111111109, "07081804"},
+	{1111111111, "14050471"},
+	{1234567890, "89005924"},
+	{2000000000, "69279037"},
+	{20000000000, "65353130"},
+}
+
+func TestCodeRFC6238(t *testing.T) {
+	for _, tt := range rfcVectors {
+		want := tt.code[len(tt.code)-Digits:]
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d): %v", tt.unix, err)
+		}
+		if got != want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, want)
+		}
+	}
+}
+
+func TestValidateRFC6238(t *testing.T) {
+	for _, tt := range rfcVectors {
+		now := time.Unix(tt.unix, 0)
+		step, ok := Validate(rfcSecret, tt.code[len(tt.code)-Digits:], now, 0)
+		if !ok || step != Step(now) {
+			t.Errorf("Validate at %d = (%d, %v), want (%d, true)", tt.unix, step, ok, Step(now))
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+
+	tests := []struct {
+		name     string
+		offset   int64 // 验证码所在时间步相对当前时间步的偏移
+		skew     int
+		wantOK   bool
+		wantStep int64
+	}{
+		{"当前时间步", 0, 1, true, current},
+		{"慢一个时间步", -1, 1, true, current - 1},
+		{"快一个时间步", 1, 1, true, current + 1},
+		{"超出偏差（过去）", -2, 1, false, 0},
+		{"超出偏差（未来）", 2, 1, false, 0},
+		{"不允许偏差", -1, 0, false, 0},
+		{"更大的偏差", -2, 2, true, current - 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, current+tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			step, ok := Validate(rfcSecret, code, now, tt.skew)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestValidateReplay 验证码在允许偏差内的任意时刻重复提交都返回同一个时间步，
+// 调用方只接受大于已记录时间步的结果（last_used_step < step），因此同一验证码不能使用两次
+func TestValidateReplay(t *testing.T) {
+	start := time.Unix(1111111109, 0)
+	code, err := Code(rfcSecret, Step(start))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastUsed := int64(0)
+	accept := func(now time.Time, input string) bool {
+		step, ok := Validate(rfcSecret, input, now, 1)
+		if !ok || step <= lastUsed {
+			return false
+		}
+		lastUsed = step
+		return true
+	}
+
+	if !accept(start, code) {
+		t.Fatal("first use of a valid code was rejected")
+	}
+	for _, delay := range []time.Duration{0, time.Second, Period * time.Second} {
+		if accept(start.Add(delay), code) {
+			t.Errorf("replayed code accepted after %v", delay)
+		}
+	}
+
+	// 下一个时间步的新验证码仍可使用
+	next, err := Code(rfcSecret, Step(start)+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !accept(start.Add(Period*time.Second), next) {
+		t.Error("code of the next step was rejected")
+	}
+}
+
+func TestValidateInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	tests := []struct {
+		name   string
+		secret string
+		input  string
+		wantOK bool
+	}{
+		{"空格与小写密钥", strings.ToLower(rfcSecret[:8]) + " " + rfcSecret[8:], "287082", true},
+		{"验证码含空格", rfcSecret, " 287 082 ", true},
+		{"错误验证码", rfcSecret, "287083", false},
+		{"位数不足", rfcSecret, "28708", false},
+		{"8 位验证码", rfcSecret, "94287082", false},
+		{"非法密钥", "not-base32!", "287082", false},
+		{"空密钥", "", "287082", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Validate(tt.secret, tt.input, now, 1); ok != tt.wantOK {
+				t.Errorf("Validate(%q, %q) ok = %v, want %v", tt.secret, tt.input, ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		t.Fatalf("generated secret %q does not decode: %v", secret, err)
+	}
+	if len(key) != secretSize {
+		t.Errorf("secret size = %d, want %d", len(key), secretSize)
+	}
+}
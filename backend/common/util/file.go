@@ -113,6 +113,27 @@ func SaveImage(file multipart.File, header *multipart.FileHeader) (string, error
 	return url, nil
 }
 
+// LocalUploadPath 将 SaveImage 返回的URL还原为本地文件路径
+// 参数：
+// - url: 图片URL（BaseURL/uploads/... 或 /uploads/...）
+// 返回值：
+// - path: 本地文件路径
+// - ok: URL 不指向本地上传目录（如外部链接或包含 ..）时为 false
+func LocalUploadPath(url string) (string, bool) {
+	relativePath, ok := strings.CutPrefix(url, BaseURL+"/uploads/")
+	if !ok {
+		relativePath, ok = strings.CutPrefix(url, "/uploads/")
+	}
+	if !ok || relativePath == "" {
+		return "", false
+	}
+	relativePath = filepath.Clean(filepath.FromSlash(relativePath))
+	if filepath.IsAbs(relativePath) || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.Join(FileStorageDir, relativePath), true
+}
+
 // DeleteFile 删除指定路径的文件
 // 参数：
 // - filePath: 文件路径
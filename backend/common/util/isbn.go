@@ -0,0 +1,87 @@
+package util
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidISBN ISBN格式或校验位错误
+var ErrInvalidISBN = errors.New("无效的ISBN")
+
+// courseCodePattern 规范化后的课程代码格式，如 CS101、MATH2010
+var courseCodePattern = regexp.MustCompile(`^[A-Z]{1,8}[0-9]{2,6}[A-Z]?$`)
+
+// NormalizeISBN 将ISBN规范化为不含分隔符的13位形式
+// 支持带连字符或空格的ISBN-10与ISBN-13，ISBN-10会转换为978前缀的ISBN-13，两种形式均校验校验位
+func NormalizeISBN(raw string) (string, error) {
+	var b strings.Builder
+	for _, r := range raw {
+		switch {
+		case r == '-' || r == ' ':
+			continue
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == 'x' || r == 'X':
+			b.WriteRune('X')
+		default:
+			return "", ErrInvalidISBN
+		}
+	}
+	digits := b.String()
+
+	switch len(digits) {
+	case 10:
+		sum := 0
+		for i := 0; i < 10; i++ {
+			var v int
+			if digits[i] == 'X' {
+				if i != 9 {
+					return "", ErrInvalidISBN
+				}
+				v = 10
+			} else {
+				v = int(digits[i] - '0')
+			}
+			sum += v * (10 - i)
+		}
+		if sum%11 != 0 {
+			return "", ErrInvalidISBN
+		}
+		isbn13 := "978" + digits[:9]
+		return isbn13 + string(isbn13CheckDigit(isbn13)), nil
+	case 13:
+		if strings.Contains(digits, "X") {
+			return "", ErrInvalidISBN
+		}
+		if isbn13CheckDigit(digits[:12]) != digits[12] {
+			return "", ErrInvalidISBN
+		}
+		return digits, nil
+	default:
+		return "", ErrInvalidISBN
+	}
+}
+
+// isbn13CheckDigit 计算ISBN-13前12位对应的校验位
+func isbn13CheckDigit(first12 string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		v := int(first12[i] - '0')
+		if i%2 == 1 {
+			v *= 3
+		}
+		sum += v
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// NormalizeCourseCode 规范化课程代码：去除空格与连字符并转为大写
+// 不符合课程代码格式时返回空字符串，如 "cs 101" 返回 "CS101"
+func NormalizeCourseCode(raw string) string {
+	code := strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.TrimSpace(raw)))
+	if !courseCodePattern.MatchString(code) {
+		return ""
+	}
+	return code
+}
@@ -4,6 +4,8 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -16,6 +18,67 @@ type Config struct {
 	DBDSN          string // 数据库连接字符串（PostgreSQL）
 	JWTSecret      string // JWT签名密钥，用于token的生成和验证
 	FileStorageDir string // 文件上传存储目录，用于保存商品图片等
+	// TenantBaseDomain 多学校部署的基础域名，如 market.example.com，
+	// 请求 <学校编码>.market.example.com 时按子域名解析学校；为空时仅通过 X-School-Code 请求头解析
+	TenantBaseDomain string
+	// TrustedProxies 可信反向代理的 IP 或 CIDR，仅来自这些地址的请求才读取 X-Forwarded-For / X-Real-IP 作为客户端 IP；
+	// 为空时不信任任何代理，客户端 IP 取 TCP 连接的对端地址，防止伪造请求头绕过按 IP 的限流与登录保护
+	TrustedProxies []string
+
+	// 校园邮箱验证
+	EmailVerifyEnabled  bool          // 是否要求验证校园邮箱，开启后未验证用户不能发布商品或查看联系方式
+	EmailAllowedDomains []string      // 允许的邮箱域名（含其子域名），为空时不限制
+	EmailVerifyTTL      time.Duration // 验证链接有效期
+	AppBaseURL          string        // 前端访问地址，用于拼接邮件中的验证链接
+	PasswordResetTTL    time.Duration // 找回密码链接有效期
+
+	// 邮件发送
+	MailDriver   string // log/file/smtp
+	MailFrom     string // 发件人地址
+	MailFileDir  string // file 方式下邮件的保存目录
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	// 限流配额，格式为 "次数/周期[,burst=N]"，如 300/m、20/h；为空或 0 表示该分组不限流
+	RateLimitEnabled       bool
+	RateLimitGlobal        string // 全部请求（按IP）
+	RateLimitAuth          string // 登录、注册、找回密码
+	RateLimitUpload        string // 图片上传
+	RateLimitProductCreate string // 发布商品
+	RateLimitDataExport    string // 导出个人数据
+
+	// 发布配额，取值 <= 0 表示不限制；管理员可为个别用户设置覆盖值
+	QuotaMaxForSale           int // 同时在售商品数上限
+	QuotaMaxDaily             int // 24 小时内发布商品数上限
+	QuotaNewAccountDays       int // 注册不足该天数的账号使用新账号配额
+	QuotaNewAccountMaxForSale int
+	QuotaNewAccountMaxDaily   int
+
+	// 两步验证（TOTP）
+	TwoFactorIssuer        string        // 验证器应用中显示的发行方名称
+	TwoFactorAdminRequired bool          // 管理员访问管理后台是否必须通过两步验证
+	TwoFactorStepUpTTL     time.Duration // 两步验证有效期，超过后访问管理后台需重新验证
+
+	// 商品自动过期：卖家长期无活动的在售商品自动下架，下架前发送提醒
+	ListingExpireDays       int    // 无活动多少天后自动下架，<= 0 表示不启用
+	ListingExpireRemindDays int    // 提前多少天提醒，<= 0 表示不提醒
+	ListingExpirySchedule   string // 检查计划（cron 表达式）
+
+	// 定时任务调度器
+	JobSchedulerEnabled bool          // 本实例是否执行定时任务（多实例部署时可只在部分实例开启）
+	JobPollInterval     time.Duration // 轮询到期任务的间隔
+	CleanupSchedule     string        // 过期数据清理任务的计划（cron 表达式）
+	JobRunRetentionDays int           // 任务执行记录保留天数
+
+	// HTTP 服务器超时与优雅关闭
+	HTTPReadTimeout        time.Duration // 读取完整请求（含请求体）的超时
+	HTTPWriteTimeout       time.Duration // 写出响应的超时，需覆盖图片上传
+	HTTPExportWriteTimeout time.Duration // 流式导出接口（CSV/XLSX、个人数据 ZIP）写出响应的超时，覆盖 HTTPWriteTimeout
+	HTTPIdleTimeout        time.Duration // keep-alive 连接空闲超时
+	ShutdownDrainDelay     time.Duration // 收到退出信号后就绪探针先返回 503，等待负载均衡器摘除流量的时间
+	ShutdownTimeout        time.Duration // 停止接收新请求后等待进行中请求完成的最长时间
 }
 
 // LoadConfig 从配置源加载应用配置
@@ -59,6 +122,44 @@ func LoadConfig() (*Config, error) {
 	v.SetDefault("DB_DSN", "")                       // 默认无数据库连接
 	v.SetDefault("JWT_SECRET", "please-change-this") // 默认JWT密钥（生产环境必须修改）
 	v.SetDefault("FILE_STORAGE_DIR", "./uploads")    // 默认文件存储目录
+	v.SetDefault("TENANT_BASE_DOMAIN", "")           // 默认不按子域名解析学校
+	v.SetDefault("TRUSTED_PROXIES", "")              // 逗号分隔，如 127.0.0.1,10.0.0.0/8；默认不信任任何代理
+	v.SetDefault("EMAIL_VERIFY_ENABLED", false)      // 默认不要求邮箱验证
+	v.SetDefault("EMAIL_ALLOWED_DOMAINS", "")        // 逗号分隔，如 stu.example.edu.cn,example.edu.cn
+	v.SetDefault("EMAIL_VERIFY_TTL_MINUTES", 30)     // 验证链接30分钟内有效
+	v.SetDefault("APP_BASE_URL", "http://localhost:5173")
+	v.SetDefault("PASSWORD_RESET_TTL_MINUTES", 30) // 找回密码链接30分钟内有效
+	v.SetDefault("MAIL_DRIVER", "log")             // 开发环境默认输出到日志
+	v.SetDefault("MAIL_FROM", "no-reply@localhost")
+	v.SetDefault("MAIL_FILE_DIR", "./mail")
+	v.SetDefault("SMTP_PORT", 587)
+	v.SetDefault("RATE_LIMIT_ENABLED", true)
+	v.SetDefault("RATE_LIMIT_GLOBAL", "300/m")
+	v.SetDefault("RATE_LIMIT_AUTH", "10/m")
+	v.SetDefault("RATE_LIMIT_UPLOAD", "30/m")
+	v.SetDefault("RATE_LIMIT_PRODUCT_CREATE", "20/h,burst=5")
+	v.SetDefault("RATE_LIMIT_DATA_EXPORT", "3/h")
+	v.SetDefault("QUOTA_MAX_FOR_SALE", 50)
+	v.SetDefault("QUOTA_MAX_DAILY", 10)
+	v.SetDefault("QUOTA_NEW_ACCOUNT_DAYS", 7)
+	v.SetDefault("QUOTA_NEW_ACCOUNT_MAX_FOR_SALE", 5)
+	v.SetDefault("QUOTA_NEW_ACCOUNT_MAX_DAILY", 2)
+	v.SetDefault("TWO_FACTOR_ISSUER", "SchoolSecondhand")
+	v.SetDefault("TWO_FACTOR_ADMIN_REQUIRED", true)
+	v.SetDefault("TWO_FACTOR_STEP_UP_MINUTES", 60) // 与登录令牌有效期一致
+	v.SetDefault("LISTING_EXPIRE_DAYS", 90)
+	v.SetDefault("LISTING_EXPIRE_REMIND_DAYS", 7)
+	v.SetDefault("LISTING_EXPIRY_SCHEDULE", "0 * * * *") // 每小时整点
+	v.SetDefault("JOB_SCHEDULER_ENABLED", true)
+	v.SetDefault("JOB_POLL_SECONDS", 15)
+	v.SetDefault("CLEANUP_SCHEDULE", "30 3 * * *") // 每天 3:30
+	v.SetDefault("JOB_RUN_RETENTION_DAYS", 30)
+	v.SetDefault("HTTP_READ_TIMEOUT_SECONDS", 30)
+	v.SetDefault("HTTP_WRITE_TIMEOUT_SECONDS", 120)
+	v.SetDefault("HTTP_EXPORT_WRITE_TIMEOUT_SECONDS", 1800)
+	v.SetDefault("HTTP_IDLE_TIMEOUT_SECONDS", 120)
+	v.SetDefault("SHUTDOWN_DRAIN_SECONDS", 5)
+	v.SetDefault("SHUTDOWN_TIMEOUT_SECONDS", 30)
 
 	// 从Viper中读取配置值并构建Config对象
 	cfg := &Config{
@@ -67,6 +168,56 @@ func LoadConfig() (*Config, error) {
 		DBDSN:          v.GetString("DB_DSN"),
 		JWTSecret:      v.GetString("JWT_SECRET"),
 		FileStorageDir: v.GetString("FILE_STORAGE_DIR"),
+
+		TenantBaseDomain: v.GetString("TENANT_BASE_DOMAIN"),
+		TrustedProxies:   splitList(v.GetString("TRUSTED_PROXIES")),
+
+		EmailVerifyEnabled:  v.GetBool("EMAIL_VERIFY_ENABLED"),
+		EmailAllowedDomains: splitList(v.GetString("EMAIL_ALLOWED_DOMAINS")),
+		EmailVerifyTTL:      time.Duration(v.GetInt("EMAIL_VERIFY_TTL_MINUTES")) * time.Minute,
+		AppBaseURL:          strings.TrimRight(v.GetString("APP_BASE_URL"), "/"),
+		PasswordResetTTL:    time.Duration(v.GetInt("PASSWORD_RESET_TTL_MINUTES")) * time.Minute,
+
+		MailDriver:   v.GetString("MAIL_DRIVER"),
+		MailFrom:     v.GetString("MAIL_FROM"),
+		MailFileDir:  v.GetString("MAIL_FILE_DIR"),
+		SMTPHost:     v.GetString("SMTP_HOST"),
+		SMTPPort:     v.GetInt("SMTP_PORT"),
+		SMTPUsername: v.GetString("SMTP_USERNAME"),
+		SMTPPassword: v.GetString("SMTP_PASSWORD"),
+
+		RateLimitEnabled:       v.GetBool("RATE_LIMIT_ENABLED"),
+		RateLimitGlobal:        v.GetString("RATE_LIMIT_GLOBAL"),
+		RateLimitAuth:          v.GetString("RATE_LIMIT_AUTH"),
+		RateLimitUpload:        v.GetString("RATE_LIMIT_UPLOAD"),
+		RateLimitProductCreate: v.GetString("RATE_LIMIT_PRODUCT_CREATE"),
+		RateLimitDataExport:    v.GetString("RATE_LIMIT_DATA_EXPORT"),
+
+		QuotaMaxForSale:           v.GetInt("QUOTA_MAX_FOR_SALE"),
+		QuotaMaxDaily:             v.GetInt("QUOTA_MAX_DAILY"),
+		QuotaNewAccountDays:       v.GetInt("QUOTA_NEW_ACCOUNT_DAYS"),
+		QuotaNewAccountMaxForSale: v.GetInt("QUOTA_NEW_ACCOUNT_MAX_FOR_SALE"),
+		QuotaNewAccountMaxDaily:   v.GetInt("QUOTA_NEW_ACCOUNT_MAX_DAILY"),
+
+		TwoFactorIssuer:        v.GetString("TWO_FACTOR_ISSUER"),
+		TwoFactorAdminRequired: v.GetBool("TWO_FACTOR_ADMIN_REQUIRED"),
+		TwoFactorStepUpTTL:     time.Duration(v.GetInt("TWO_FACTOR_STEP_UP_MINUTES")) * time.Minute,
+
+		ListingExpireDays:       v.GetInt("LISTING_EXPIRE_DAYS"),
+		ListingExpireRemindDays: v.GetInt("LISTING_EXPIRE_REMIND_DAYS"),
+		ListingExpirySchedule:   v.GetString("LISTING_EXPIRY_SCHEDULE"),
+
+		JobSchedulerEnabled: v.GetBool("JOB_SCHEDULER_ENABLED"),
+		JobPollInterval:     time.Duration(v.GetInt("JOB_POLL_SECONDS")) * time.Second,
+		CleanupSchedule:     v.GetString("CLEANUP_SCHEDULE"),
+		JobRunRetentionDays: v.GetInt("JOB_RUN_RETENTION_DAYS"),
+
+		HTTPReadTimeout:        time.Duration(v.GetInt("HTTP_READ_TIMEOUT_SECONDS")) * time.Second,
+		HTTPWriteTimeout:       time.Duration(v.GetInt("HTTP_WRITE_TIMEOUT_SECONDS")) * time.Second,
+		HTTPExportWriteTimeout: time.Duration(v.GetInt("HTTP_EXPORT_WRITE_TIMEOUT_SECONDS")) * time.Second,
+		HTTPIdleTimeout:        time.Duration(v.GetInt("HTTP_IDLE_TIMEOUT_SECONDS")) * time.Second,
+		ShutdownDrainDelay:     time.Duration(v.GetInt("SHUTDOWN_DRAIN_SECONDS")) * time.Second,
+		ShutdownTimeout:        time.Duration(v.GetInt("SHUTDOWN_TIMEOUT_SECONDS")) * time.Second,
 	}
 
 	// 配置验证：HTTP端口不能为0
@@ -74,5 +225,42 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("invalid HTTP_PORT: 0")
 	}
 
+	if cfg.EmailVerifyTTL <= 0 {
+		return nil, fmt.Errorf("invalid EMAIL_VERIFY_TTL_MINUTES: must be positive")
+	}
+	if cfg.PasswordResetTTL <= 0 {
+		return nil, fmt.Errorf("invalid PASSWORD_RESET_TTL_MINUTES: must be positive")
+	}
+	if cfg.JobPollInterval <= 0 {
+		return nil, fmt.Errorf("invalid JOB_POLL_SECONDS: must be positive")
+	}
+	if cfg.JobRunRetentionDays <= 0 {
+		return nil, fmt.Errorf("invalid JOB_RUN_RETENTION_DAYS: must be positive")
+	}
+	if cfg.HTTPReadTimeout <= 0 || cfg.HTTPWriteTimeout <= 0 || cfg.HTTPExportWriteTimeout <= 0 || cfg.HTTPIdleTimeout <= 0 {
+		return nil, fmt.Errorf("invalid HTTP_*_TIMEOUT_SECONDS: must be positive")
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		return nil, fmt.Errorf("invalid SHUTDOWN_TIMEOUT_SECONDS: must be positive")
+	}
+	if cfg.ShutdownDrainDelay < 0 {
+		return nil, fmt.Errorf("invalid SHUTDOWN_DRAIN_SECONDS: must not be negative")
+	}
+	if cfg.ListingExpireDays > 0 && cfg.ListingExpireRemindDays >= cfg.ListingExpireDays {
+		return nil, fmt.Errorf("invalid LISTING_EXPIRE_REMIND_DAYS: must be less than LISTING_EXPIRE_DAYS")
+	}
+
 	return cfg, nil
 }
+
+// splitList 解析逗号分隔的配置项，去除空白与空项并转为小写
+func splitList(raw string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,101 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/yycy134679/school-secondhand-trading-system/backend/common/errors"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/service/account"
+)
+
+// Controller 账号数据导出与注销控制器
+type Controller struct {
+	service account.Service
+}
+
+// NewController 创建控制器实例
+func NewController(service account.Service) *Controller {
+	return &Controller{service: service}
+}
+
+// Export 下载我的数据（ZIP）
+// GET /api/v1/users/me/export
+func (ac *Controller) Export(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	filename := fmt.Sprintf("my-data-%s.zip", time.Now().Format("20060102150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+
+	if err := ac.service.Export(c.Request.Context(), userID, c.Writer); err != nil {
+		// 尚未写出任何数据时返回普通错误响应；已开始传输则只能中断并记录日志
+		if c.Writer.Written() {
+			log.Printf("导出个人数据中断 user=%d: %v", userID, err)
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Content-Type")
+		handleAccountError(c, "导出个人数据失败", err)
+	}
+}
+
+// deleteRequest 注销账号请求
+type deleteRequest struct {
+	Password string `json:"password"`
+}
+
+// Delete 注销账号
+// POST /api/v1/users/me/delete {"password": "..."}
+func (ac *Controller) Delete(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var req deleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, apperrors.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := ac.service.Delete(c.Request.Context(), userID, req.Password)
+	if err != nil {
+		handleAccountError(c, "注销账号失败", err)
+		return
+	}
+	resp.Success(c, result)
+}
+
+// handleAccountError 将服务层错误转换为响应
+func handleAccountError(c *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, account.ErrPasswordRequired), errors.Is(err, account.ErrIncorrectPassword):
+		resp.Error(c, apperrors.CodeInvalidParams, err.Error())
+	case errors.Is(err, account.ErrAdminAccount):
+		resp.Error(c, apperrors.CodeForbidden, err.Error())
+	case errors.Is(err, account.ErrUserNotFound), errors.Is(err, account.ErrAccountDeleted):
+		resp.Error(c, apperrors.CodeUnauthenticated, err.Error())
+	default:
+		resp.Error(c, 500, msg+": "+err.Error())
+	}
+}
+
+// currentUserID 获取当前登录用户ID，失败时已写入错误响应
+func currentUserID(c *gin.Context) (int64, bool) {
+	userID, err := strconv.ParseInt(c.GetString("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		resp.Error(c, apperrors.CodeUnauthenticated, "用户未登录")
+		return 0, false
+	}
+	return userID, true
+}
@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/service/admin"
+)
+
+// AuditController 审计日志控制器
+type AuditController struct {
+	adminService *admin.AdminService
+}
+
+// NewAuditController 创建审计日志控制器
+func NewAuditController(adminService *admin.AdminService) *AuditController {
+	return &AuditController{
+		adminService: adminService,
+	}
+}
+
+// ListAuditLogs 审计日志列表接口
+// GET /api/v1/admin/audit-logs?adminId=&action=&targetType=&targetId=&page=&pageSize=
+func (ac *AuditController) ListAuditLogs(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	query := admin.AuditLogQuery{
+		Action:     c.Query("action"),
+		TargetType: c.Query("targetType"),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	if id, err := strconv.ParseInt(c.Query("adminId"), 10, 64); err == nil && id > 0 {
+		query.AdminID = id
+	}
+	if id, err := strconv.ParseInt(c.Query("targetId"), 10, 64); err == nil && id > 0 {
+		query.TargetID = id
+	}
+
+	result, err := ac.adminService.ListAuditLogs(c.Request.Context(), query)
+	if err != nil {
+		resp.Error(c, 500, "获取审计日志失败: "+err.Error())
+		return
+	}
+
+	resp.Success(c, result)
+}
@@ -1,6 +1,9 @@
 package admin
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/service/admin"
@@ -51,3 +54,54 @@ func (ctrl *DashboardController) GetDashboard(c *gin.Context) {
 	// 返回成功响应
 	resp.Success(c, stats)
 }
+
+// GetDashboardMetrics 获取仪表盘时间序列数据
+//
+// 功能说明：
+//   - 按天返回新增用户、新发布商品、成交商品数、成交额（GMV）
+//   - 返回成交中位时长与热门分类
+//   - labels 与各 series.data 一一对应，可直接用于折线图/柱状图
+//
+// API路径：GET /api/v1/admin/dashboard/metrics?from=2025-01-01&to=2025-01-31
+//
+// 查询参数：
+//   - from: 开始日期（YYYY-MM-DD），默认结束日期前29天
+//   - to: 结束日期（YYYY-MM-DD），默认今天
+func (ctrl *DashboardController) GetDashboardMetrics(c *gin.Context) {
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", toStr, time.Local)
+		if err != nil {
+			resp.Error(c, 1001, "无效的结束日期，格式应为YYYY-MM-DD")
+			return
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -29)
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", fromStr, time.Local)
+		if err != nil {
+			resp.Error(c, 1001, "无效的开始日期，格式应为YYYY-MM-DD")
+			return
+		}
+		from = parsed
+	}
+
+	if to.Before(from) {
+		resp.Error(c, 1001, "结束日期不能早于开始日期")
+		return
+	}
+	if to.Sub(from) >= admin.DashboardMaxRangeDays*24*time.Hour {
+		resp.Error(c, 1001, fmt.Sprintf("日期区间不能超过%d天", admin.DashboardMaxRangeDays))
+		return
+	}
+
+	metrics, err := ctrl.adminService.GetDashboardMetrics(c.Request.Context(), from, to)
+	if err != nil {
+		resp.Error(c, 500, "获取统计数据失败: "+err.Error())
+		return
+	}
+
+	resp.Success(c, metrics)
+}
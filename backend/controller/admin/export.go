@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/export"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+)
+
+// ExportProducts 导出商品列表
+// GET /api/v1/admin/products/export?format=csv|xlsx&status=&sellerId=&keyword=
+func (pc *ProductController) ExportProducts(c *gin.Context) {
+	status := c.Query("status")
+	keyword := c.Query("keyword")
+
+	// 卖家ID转换（与列表接口保持一致，非法值视为不过滤）
+	var sellerID int64 = 0
+	if sellerIDStr := c.Query("sellerId"); sellerIDStr != "" {
+		id, err := strconv.ParseInt(sellerIDStr, 10, 64)
+		if err == nil && id > 0 {
+			sellerID = id
+		}
+	}
+
+	writer, format, ok := newExportWriter(c)
+	if !ok {
+		return
+	}
+	setExportHeaders(c, writer, "products", format)
+
+	if err := pc.adminService.ExportProducts(c.Request.Context(), status, sellerID, keyword, writer); err != nil {
+		handleExportError(c, "导出商品失败", err)
+	}
+}
+
+// ExportUsers 导出用户列表
+// GET /api/v1/admin/users/export?format=csv|xlsx&keyword=
+func (uc *UserController) ExportUsers(c *gin.Context) {
+	keyword := c.Query("keyword")
+
+	writer, format, ok := newExportWriter(c)
+	if !ok {
+		return
+	}
+	setExportHeaders(c, writer, "users", format)
+
+	if err := uc.adminService.ExportUsers(c.Request.Context(), keyword, writer); err != nil {
+		handleExportError(c, "导出用户失败", err)
+	}
+}
+
+// newExportWriter 根据 format 参数创建导出写入器，参数非法时直接返回错误响应
+func newExportWriter(c *gin.Context) (export.RowWriter, string, bool) {
+	format := export.NormalizeFormat(c.Query("format"))
+	writer, err := export.NewWriter(format, c.Writer)
+	if err != nil {
+		resp.Error(c, 1001, err.Error())
+		return nil, "", false
+	}
+	return writer, format, true
+}
+
+// setExportHeaders 设置下载相关响应头
+func setExportHeaders(c *gin.Context, writer export.RowWriter, name, format string) {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102150405"), format)
+	c.Header("Content-Type", writer.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+}
+
+// handleExportError 处理导出错误
+// 尚未写出任何数据时返回普通错误响应；已开始传输则只能中断并记录日志
+func handleExportError(c *gin.Context, msg string, err error) {
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Content-Type")
+		resp.Error(c, 500, msg+": "+err.Error())
+		return
+	}
+	log.Printf("warn: %s after streaming started: %v", msg, err)
+}
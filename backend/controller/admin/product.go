@@ -73,6 +73,12 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 		return
 	}
 
+	// 获取当前管理员ID
+	var adminID int64
+	if userIDStr, exists := c.Get("user_id"); exists {
+		adminID, _ = strconv.ParseInt(userIDStr.(string), 10, 64)
+	}
+
 	// 绑定请求体
 	var req admin.UpdateProductRequest
 	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
@@ -81,7 +87,7 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 	}
 
 	// 调用服务层方法
-	updateErr := pc.adminService.UpdateProductAsAdmin(c.Request.Context(), productID, req)
+	updateErr := pc.adminService.UpdateProductAsAdmin(c.Request.Context(), adminID, productID, req)
 	if updateErr != nil {
 		// 检查是否是禁止修改状态的错误
 		errMsg := updateErr.Error()
@@ -96,3 +102,33 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 	// 返回成功响应
 	resp.Success(c, gin.H{"message": "商品更新成功"})
 }
+
+// BulkAction 批量商品操作接口
+// POST /api/v1/admin/products/bulk
+// 支持 delist/recategorize/add_tags/remove_tags/delete，返回逐项处理结果
+func (pc *ProductController) BulkAction(c *gin.Context) {
+	// 获取当前管理员ID
+	var adminID int64
+	if userIDStr, exists := c.Get("user_id"); exists {
+		adminID, _ = strconv.ParseInt(userIDStr.(string), 10, 64)
+	}
+
+	// 绑定请求体
+	var req admin.BulkProductActionRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		resp.Error(c, 1001, "请求参数无效: "+bindErr.Error())
+		return
+	}
+
+	result, err := pc.adminService.BulkProductAction(c.Request.Context(), adminID, req)
+	if err != nil {
+		if admin.IsParamError(err) {
+			resp.Error(c, 1001, err.Error())
+			return
+		}
+		resp.Error(c, 500, "批量操作失败: "+err.Error())
+		return
+	}
+
+	resp.Success(c, result)
+}
@@ -26,7 +26,7 @@ func NewTagController(tagService tag.TagService) *TagController {
 // GET /api/v1/admin/tags
 func (tc *TagController) ListTags(c *gin.Context) {
 	// 调用服务层获取标签列表
-	tags, err := tc.tagService.ListTags(c.Request.Context())
+	tags, err := tc.tagService.ListTags(c.Request.Context(), 0)
 	if err != nil {
 		resp.Error(c, 500, "获取标签列表失败: "+err.Error())
 		return
@@ -1,7 +1,9 @@
 package admin
 
 import (
+	"errors"
 	"strconv"
+
 	"github.com/gin-gonic/gin"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
 	adminservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/admin"
@@ -60,3 +62,128 @@ func (uc *UserController) ListUsers(ctx *gin.Context) {
 	// 返回成功响应
 	resp.Success(ctx, result)
 }
+
+// SetAdminRequest 授予/撤销管理员请求
+type SetAdminRequest struct {
+	IsAdmin *bool `json:"isAdmin"`
+}
+
+// GetUserDetail 获取用户详情
+// GET /api/v1/admin/users/:id
+func (uc *UserController) GetUserDetail(ctx *gin.Context) {
+	userID, ok := parseUserIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	detail, err := uc.adminService.GetUserDetail(ctx.Request.Context(), userID)
+	if err != nil {
+		handleUserManageError(ctx, "获取用户详情失败", err)
+		return
+	}
+
+	resp.Success(ctx, detail)
+}
+
+// SetAdmin 授予或撤销管理员权限
+// PUT /api/v1/admin/users/:id/admin
+func (uc *UserController) SetAdmin(ctx *gin.Context) {
+	userID, ok := parseUserIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var req SetAdminRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || req.IsAdmin == nil {
+		resp.Error(ctx, 1001, "请求参数无效，需提供isAdmin")
+		return
+	}
+
+	user, err := uc.adminService.SetUserAdmin(ctx.Request.Context(), currentAdminID(ctx), userID, *req.IsAdmin)
+	if err != nil {
+		handleUserManageError(ctx, "修改管理员权限失败", err)
+		return
+	}
+
+	resp.Success(ctx, user)
+}
+
+// ResetPassword 强制重置用户密码
+// POST /api/v1/admin/users/:id/reset-password
+func (uc *UserController) ResetPassword(ctx *gin.Context) {
+	userID, ok := parseUserIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := uc.adminService.ForcePasswordReset(ctx.Request.Context(), currentAdminID(ctx), userID)
+	if err != nil {
+		handleUserManageError(ctx, "重置密码失败", err)
+		return
+	}
+
+	resp.Success(ctx, result)
+}
+
+// UnlockLogin 解除用户因连续登录失败导致的锁定
+// POST /api/v1/admin/users/:id/unlock
+func (uc *UserController) UnlockLogin(ctx *gin.Context) {
+	userID, ok := parseUserIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := uc.adminService.UnlockUserLogin(ctx.Request.Context(), currentAdminID(ctx), userID); err != nil {
+		handleUserManageError(ctx, "解除锁定失败", err)
+		return
+	}
+
+	resp.Success(ctx, gin.H{"message": "已解除登录锁定"})
+}
+
+// ClearWechat 清空用户微信号
+// DELETE /api/v1/admin/users/:id/wechat
+func (uc *UserController) ClearWechat(ctx *gin.Context) {
+	userID, ok := parseUserIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := uc.adminService.ClearUserWechat(ctx.Request.Context(), currentAdminID(ctx), userID); err != nil {
+		handleUserManageError(ctx, "清空微信号失败", err)
+		return
+	}
+
+	resp.Success(ctx, gin.H{"message": "微信号已清空"})
+}
+
+// parseUserIDParam 解析路径中的用户ID
+func parseUserIDParam(ctx *gin.Context) (int64, bool) {
+	userID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		resp.Error(ctx, 1001, "无效的用户ID")
+		return 0, false
+	}
+	return userID, true
+}
+
+// currentAdminID 获取当前操作的管理员ID
+func currentAdminID(ctx *gin.Context) int64 {
+	var adminID int64
+	if userIDStr, exists := ctx.Get("user_id"); exists {
+		adminID, _ = strconv.ParseInt(userIDStr.(string), 10, 64)
+	}
+	return adminID
+}
+
+// handleUserManageError 将用户管理错误映射为响应
+func handleUserManageError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, adminservice.ErrUserNotFound):
+		resp.Error(ctx, 404, err.Error())
+	case errors.Is(err, adminservice.ErrLastAdmin):
+		resp.Error(ctx, 1003, err.Error())
+	default:
+		resp.Error(ctx, 500, msg+": "+err.Error())
+	}
+}
@@ -0,0 +1,162 @@
+package book
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/util"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/service/book"
+)
+
+// maxImportFileSize CSV导入文件大小上限：5MB
+const maxImportFileSize = 5 * 1024 * 1024
+
+// BookController 图书目录控制器
+type BookController struct {
+	bookService *book.BookService
+}
+
+// NewBookController 创建图书目录控制器实例
+func NewBookController(bookService *book.BookService) *BookController {
+	return &BookController{
+		bookService: bookService,
+	}
+}
+
+// LookupISBN 根据ISBN查询图书（发布教材时自动填充书名、作者与版次）
+// GET /api/v1/books/isbn/:isbn
+func (bc *BookController) LookupISBN(c *gin.Context) {
+	dto, err := bc.bookService.LookupByISBN(c.Request.Context(), c.Param("isbn"))
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidISBN):
+			resp.Error(c, 1001, err.Error())
+		case errors.Is(err, book.ErrBookNotFound):
+			resp.Error(c, 404, err.Error())
+		default:
+			resp.Error(c, 500, "查询图书失败")
+		}
+		return
+	}
+
+	resp.Success(c, dto)
+}
+
+// ListBooks 分页查询图书目录（管理端接口）
+// GET /api/v1/admin/books
+func (bc *BookController) ListBooks(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	result, err := bc.bookService.ListBooks(c.Request.Context(), c.Query("keyword"), page, pageSize)
+	if err != nil {
+		resp.Error(c, 500, "获取图书列表失败")
+		return
+	}
+
+	resp.Success(c, gin.H{
+		"items":    result.Items,
+		"total":    result.Total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// ImportBooks 通过CSV导入图书目录（管理端接口）
+// POST /api/v1/super/books/import
+// 表单字段 file：CSV文件，表头包含 isbn,title,author,edition,publisher,courses
+func (bc *BookController) ImportBooks(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		resp.Error(c, 1001, "请上传CSV文件")
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxImportFileSize {
+		resp.Error(c, 1001, "文件大小不能超过5MB")
+		return
+	}
+
+	result, err := bc.bookService.ImportCSV(c.Request.Context(), file)
+	if err != nil {
+		if errors.Is(err, book.ErrInvalidCSV) {
+			resp.Error(c, 1001, err.Error())
+			return
+		}
+		resp.Error(c, 500, "导入图书失败: "+err.Error())
+		return
+	}
+
+	resp.Success(c, result)
+}
+
+// GetMyCourses 获取当前用户选修的课程
+// GET /api/v1/users/courses
+func (bc *BookController) GetMyCourses(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	courses, err := bc.bookService.GetUserCourses(c.Request.Context(), userID)
+	if err != nil {
+		resp.Error(c, 500, "获取选修课程失败")
+		return
+	}
+
+	resp.Success(c, gin.H{"courses": courses})
+}
+
+// UpdateMyCourses 设置当前用户选修的课程（整体替换）
+// PUT /api/v1/users/courses
+func (bc *BookController) UpdateMyCourses(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Courses []string `json:"courses"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误")
+		return
+	}
+
+	courses, err := bc.bookService.SetUserCourses(c.Request.Context(), userID, req.Courses)
+	if err != nil {
+		if errors.Is(err, book.ErrInvalidCourseCode) || errors.Is(err, book.ErrTooManyCourses) {
+			resp.Error(c, 1001, err.Error())
+			return
+		}
+		resp.Error(c, 500, "保存选修课程失败")
+		return
+	}
+
+	resp.Success(c, gin.H{"courses": courses})
+}
+
+// currentUserID 获取当前登录用户ID，失败时已写入错误响应
+func currentUserID(c *gin.Context) (int64, bool) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		resp.Error(c, 401, "用户未登录")
+		return 0, false
+	}
+	userID, err := strconv.ParseInt(userIDStr.(string), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的用户ID")
+		return 0, false
+	}
+	return userID, true
+}
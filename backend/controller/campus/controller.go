@@ -0,0 +1,241 @@
+package campus
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/service/campus"
+)
+
+// Controller 校园片区与面交地点控制器
+type Controller struct {
+	service campus.Service
+}
+
+// NewController 创建控制器实例
+func NewController(service campus.Service) *Controller {
+	return &Controller{service: service}
+}
+
+// ZoneRequest 创建或修改片区的请求体
+type ZoneRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	SortOrder   int32  `json:"sortOrder"`
+}
+
+// PointRequest 创建或修改面交地点的请求体
+type PointRequest struct {
+	ZoneID      int64  `json:"zoneId"` // 仅修改时有效，用于将地点移动到其他片区
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	SortOrder   int32  `json:"sortOrder"`
+}
+
+// ListZones 获取全部片区及其面交地点
+// GET /api/v1/campus/zones
+func (cc *Controller) ListZones(c *gin.Context) {
+	zones, err := cc.service.ListZones(c.Request.Context())
+	if err != nil {
+		resp.Error(c, 500, "获取校园片区失败: "+err.Error())
+		return
+	}
+	resp.Success(c, zones)
+}
+
+// UpdateDefaultZone 设置当前用户的默认片区
+// PUT /api/v1/users/default-zone
+// 请求体 zoneId 为空或0时清除默认片区
+func (cc *Controller) UpdateDefaultZone(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ZoneID *int64 `json:"zoneId"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	if err := cc.service.SetDefaultZone(c.Request.Context(), userID, req.ZoneID); err != nil {
+		handleCampusError(c, "设置默认片区失败", err)
+		return
+	}
+	resp.Success(c, gin.H{"defaultZoneId": req.ZoneID})
+}
+
+// CreateZone 创建片区（管理端接口）
+// POST /api/v1/admin/campus/zones
+func (cc *Controller) CreateZone(c *gin.Context) {
+	var req ZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	zone, err := cc.service.CreateZone(c.Request.Context(), campus.ZoneInput{
+		Name:        req.Name,
+		Description: req.Description,
+		SortOrder:   req.SortOrder,
+	})
+	if err != nil {
+		handleCampusError(c, "创建片区失败", err)
+		return
+	}
+	resp.Success(c, zone)
+}
+
+// UpdateZone 修改片区（管理端接口）
+// PUT /api/v1/admin/campus/zones/:id
+func (cc *Controller) UpdateZone(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的片区ID")
+		return
+	}
+
+	var req ZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	zone, err := cc.service.UpdateZone(c.Request.Context(), id, campus.ZoneInput{
+		Name:        req.Name,
+		Description: req.Description,
+		SortOrder:   req.SortOrder,
+	})
+	if err != nil {
+		handleCampusError(c, "更新片区失败", err)
+		return
+	}
+	resp.Success(c, zone)
+}
+
+// DeleteZone 删除片区（管理端接口）
+// DELETE /api/v1/admin/campus/zones/:id
+func (cc *Controller) DeleteZone(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的片区ID")
+		return
+	}
+
+	if err := cc.service.DeleteZone(c.Request.Context(), id); err != nil {
+		handleCampusError(c, "删除片区失败", err)
+		return
+	}
+	resp.Success(c, nil)
+}
+
+// CreatePoint 在片区下创建面交地点（管理端接口）
+// POST /api/v1/admin/campus/zones/:id/points
+func (cc *Controller) CreatePoint(c *gin.Context) {
+	zoneID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的片区ID")
+		return
+	}
+
+	var req PointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	point, err := cc.service.CreatePoint(c.Request.Context(), zoneID, campus.PointInput{
+		Name:        req.Name,
+		Description: req.Description,
+		SortOrder:   req.SortOrder,
+	})
+	if err != nil {
+		handleCampusError(c, "创建面交地点失败", err)
+		return
+	}
+	resp.Success(c, point)
+}
+
+// UpdatePoint 修改面交地点（管理端接口）
+// PUT /api/v1/admin/campus/points/:id
+func (cc *Controller) UpdatePoint(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的面交地点ID")
+		return
+	}
+
+	var req PointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	point, err := cc.service.UpdatePoint(c.Request.Context(), id, campus.PointInput{
+		ZoneID:      req.ZoneID,
+		Name:        req.Name,
+		Description: req.Description,
+		SortOrder:   req.SortOrder,
+	})
+	if err != nil {
+		handleCampusError(c, "更新面交地点失败", err)
+		return
+	}
+	resp.Success(c, point)
+}
+
+// DeletePoint 删除面交地点（管理端接口）
+// DELETE /api/v1/admin/campus/points/:id
+func (cc *Controller) DeletePoint(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的面交地点ID")
+		return
+	}
+
+	if err := cc.service.DeletePoint(c.Request.Context(), id); err != nil {
+		handleCampusError(c, "删除面交地点失败", err)
+		return
+	}
+	resp.Success(c, nil)
+}
+
+// handleCampusError 将校园片区服务错误映射为响应错误码
+func handleCampusError(c *gin.Context, prefix string, err error) {
+	switch {
+	case errors.Is(err, campus.ErrZoneNotFound),
+		errors.Is(err, campus.ErrPointNotFound),
+		errors.Is(err, campus.ErrUserNotFound):
+		resp.Error(c, 404, err.Error())
+	case errors.Is(err, campus.ErrZoneHasPoints):
+		resp.Error(c, campus.ErrCodeZoneHasPoints, err.Error())
+	case errors.Is(err, campus.ErrPointInUse):
+		resp.Error(c, campus.ErrCodePointInUse, err.Error())
+	case errors.Is(err, campus.ErrInvalidCampus),
+		errors.Is(err, campus.ErrZoneNameExists),
+		errors.Is(err, campus.ErrPointNameExists):
+		resp.Error(c, 1001, err.Error())
+	default:
+		resp.Error(c, 500, prefix+": "+err.Error())
+	}
+}
+
+// currentUserID 获取当前登录用户ID，失败时已写入错误响应
+func currentUserID(c *gin.Context) (int64, bool) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		resp.Error(c, 401, "用户未登录")
+		return 0, false
+	}
+	userID, err := strconv.ParseInt(userIDStr.(string), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的用户ID")
+		return 0, false
+	}
+	return userID, true
+}
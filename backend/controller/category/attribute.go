@@ -0,0 +1,133 @@
+package category
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/service/category"
+)
+
+// AttributeController 分类属性控制器
+type AttributeController struct {
+	attributeService category.AttributeService
+}
+
+// NewAttributeController 创建分类属性控制器实例
+func NewAttributeController(attributeService category.AttributeService) *AttributeController {
+	return &AttributeController{
+		attributeService: attributeService,
+	}
+}
+
+// ListAttributes 获取分类生效的属性定义（前台公开接口，用于渲染发布表单与筛选项）
+// GET /api/v1/categories/:id/attributes
+// 默认包含从祖先分类继承的属性，own=true 时只返回分类自身定义的属性
+func (ac *AttributeController) ListAttributes(c *gin.Context) {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的分类ID")
+		return
+	}
+	ownOnly, _ := strconv.ParseBool(c.Query("own"))
+
+	attrs, err := ac.attributeService.ListAttributes(c.Request.Context(), categoryID, !ownOnly)
+	if err != nil {
+		handleAttributeError(c, "获取分类属性失败", err)
+		return
+	}
+
+	resp.Success(c, attrs)
+}
+
+// CreateAttribute 为分类新增属性定义（管理端接口）
+// POST /api/v1/admin/categories/:id/attributes
+func (ac *AttributeController) CreateAttribute(c *gin.Context) {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的分类ID")
+		return
+	}
+
+	var req category.AttributeInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	attr, err := ac.attributeService.CreateAttribute(c.Request.Context(), categoryID, req)
+	if err != nil {
+		handleAttributeError(c, "创建分类属性失败", err)
+		return
+	}
+
+	resp.Success(c, attr)
+}
+
+// UpdateAttribute 更新属性定义（管理端接口）
+// PUT /api/v1/admin/categories/:id/attributes/:attrId
+func (ac *AttributeController) UpdateAttribute(c *gin.Context) {
+	categoryID, attributeID, ok := parseAttributeParams(c)
+	if !ok {
+		return
+	}
+
+	var req category.AttributeInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	attr, err := ac.attributeService.UpdateAttribute(c.Request.Context(), categoryID, attributeID, req)
+	if err != nil {
+		handleAttributeError(c, "更新分类属性失败", err)
+		return
+	}
+
+	resp.Success(c, attr)
+}
+
+// DeleteAttribute 删除属性定义（管理端接口）
+// DELETE /api/v1/admin/categories/:id/attributes/:attrId
+func (ac *AttributeController) DeleteAttribute(c *gin.Context) {
+	categoryID, attributeID, ok := parseAttributeParams(c)
+	if !ok {
+		return
+	}
+
+	if err := ac.attributeService.DeleteAttribute(c.Request.Context(), categoryID, attributeID); err != nil {
+		handleAttributeError(c, "删除分类属性失败", err)
+		return
+	}
+
+	resp.Success(c, gin.H{"message": "分类属性删除成功"})
+}
+
+// parseAttributeParams 解析路径中的分类ID与属性ID，失败时已写入错误响应
+func parseAttributeParams(c *gin.Context) (int64, int64, bool) {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的分类ID")
+		return 0, 0, false
+	}
+	attributeID, err := strconv.ParseInt(c.Param("attrId"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的属性ID")
+		return 0, 0, false
+	}
+	return categoryID, attributeID, true
+}
+
+// handleAttributeError 将分类属性服务错误映射为响应
+func handleAttributeError(c *gin.Context, prefix string, err error) {
+	switch {
+	case errors.Is(err, category.ErrCategoryNotFound), errors.Is(err, category.ErrAttributeNotFound):
+		resp.Error(c, 404, err.Error())
+	case errors.Is(err, category.ErrInvalidAttribute), errors.Is(err, category.ErrAttributeKeyExists):
+		resp.Error(c, 1001, err.Error())
+	default:
+		resp.Error(c, 500, prefix+": "+err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package category
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 
@@ -25,7 +26,18 @@ func NewCategoryController(categoryService category.CategoryService) *CategoryCo
 
 // ListCategories 获取所有分类（前台公开接口）
 // GET /api/v1/categories
+// 默认返回扁平列表（含 parentId/sortOrder），tree=true 时返回嵌套树
 func (cc *CategoryController) ListCategories(c *gin.Context) {
+	if tree, _ := strconv.ParseBool(c.Query("tree")); tree {
+		nodes, err := cc.categoryService.ListCategoryTree(c.Request.Context())
+		if err != nil {
+			resp.Error(c, 500, "获取分类树失败: "+err.Error())
+			return
+		}
+		resp.Success(c, nodes)
+		return
+	}
+
 	categories, err := cc.categoryService.ListCategories(c.Request.Context())
 	if err != nil {
 		resp.Error(c, 500, "获取分类列表失败: "+err.Error())
@@ -42,6 +54,7 @@ func (cc *CategoryController) CreateCategory(c *gin.Context) {
 	type CreateRequest struct {
 		Name        string `json:"name" binding:"required"`
 		Description string `json:"description"`
+		ParentID    *int64 `json:"parentId"`
 	}
 
 	var req CreateRequest
@@ -53,6 +66,7 @@ func (cc *CategoryController) CreateCategory(c *gin.Context) {
 
 	// 创建分类模型
 	category := &model.Category{
+		ParentID:    req.ParentID,
 		Name:        req.Name,
 		Description: req.Description,
 	}
@@ -60,7 +74,7 @@ func (cc *CategoryController) CreateCategory(c *gin.Context) {
 	// 调用服务层创建分类
 	err = cc.categoryService.CreateCategory(c.Request.Context(), category)
 	if err != nil {
-		resp.Error(c, 500, "创建分类失败: "+err.Error())
+		handleCategoryError(c, "创建分类失败", err)
 		return
 	}
 
@@ -100,7 +114,7 @@ func (cc *CategoryController) UpdateCategory(c *gin.Context) {
 	// 调用服务层更新分类
 	err = cc.categoryService.UpdateCategory(c.Request.Context(), category)
 	if err != nil {
-		resp.Error(c, 500, "更新分类失败: "+err.Error())
+		handleCategoryError(c, "更新分类失败", err)
 		return
 	}
 
@@ -125,10 +139,78 @@ func (cc *CategoryController) DeleteCategory(c *gin.Context) {
 		if strings.Contains(err.Error(), "category has products") {
 			resp.Error(c, category.ErrCodeCategoryHasProducts, err.Error())
 		} else {
-			resp.Error(c, 500, "删除分类失败: "+err.Error())
+			handleCategoryError(c, "删除分类失败", err)
 		}
 		return
 	}
 
 	resp.Success(c, gin.H{"message": "分类删除成功"})
 }
+
+// MoveCategory 移动分类到新的父分类下（管理端接口）
+// PUT /api/v1/admin/categories/:id/move
+// parentId 为空表示移到顶级；sortOrder 为空时保持原位（同级）或追加到末尾
+func (cc *CategoryController) MoveCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的分类ID")
+		return
+	}
+
+	type MoveRequest struct {
+		ParentID  *int64 `json:"parentId"`
+		SortOrder *int   `json:"sortOrder"`
+	}
+
+	var req MoveRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	moved, err := cc.categoryService.MoveCategory(c.Request.Context(), categoryID, req.ParentID, req.SortOrder)
+	if err != nil {
+		handleCategoryError(c, "移动分类失败", err)
+		return
+	}
+
+	resp.Success(c, moved)
+}
+
+// ReorderCategories 重排同一父分类下的子分类（管理端接口）
+// PUT /api/v1/admin/categories/reorder
+func (cc *CategoryController) ReorderCategories(c *gin.Context) {
+	type ReorderRequest struct {
+		ParentID   *int64  `json:"parentId"`
+		OrderedIDs []int64 `json:"orderedIds" binding:"required"`
+	}
+
+	var req ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	if err := cc.categoryService.ReorderCategories(c.Request.Context(), req.ParentID, req.OrderedIDs); err != nil {
+		handleCategoryError(c, "分类排序失败", err)
+		return
+	}
+
+	resp.Success(c, gin.H{"message": "分类排序成功"})
+}
+
+// handleCategoryError 将分类服务错误映射为响应
+func handleCategoryError(c *gin.Context, prefix string, err error) {
+	switch {
+	case errors.Is(err, category.ErrCategoryNotFound):
+		resp.Error(c, 404, err.Error())
+	case errors.Is(err, category.ErrParentNotFound), errors.Is(err, category.ErrInvalidReorder):
+		resp.Error(c, 1001, err.Error())
+	case errors.Is(err, category.ErrCategoryHasChildren):
+		resp.Error(c, category.ErrCodeCategoryHasChildren, err.Error())
+	case errors.Is(err, category.ErrCategoryCycle):
+		resp.Error(c, category.ErrCodeCategoryCycle, err.Error())
+	default:
+		resp.Error(c, 500, prefix+": "+err.Error())
+	}
+}
@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/yycy134679/school-secondhand-trading-system/backend/common/errors"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/service/notification"
+)
+
+// Controller 站内通知控制器
+type Controller struct {
+	service notification.Service
+}
+
+// NewController 创建控制器实例
+func NewController(service notification.Service) *Controller {
+	return &Controller{service: service}
+}
+
+// List 获取当前用户的通知列表
+// GET /api/v1/notifications?page=1&pageSize=20&unread=true
+func (nc *Controller) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		resp.Error(c, apperrors.CodeInvalidParams, "无效的页码参数")
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		resp.Error(c, apperrors.CodeInvalidParams, "无效的每页数量参数，范围1-100")
+		return
+	}
+	unreadOnly := c.Query("unread") == "true"
+
+	result, err := nc.service.List(c.Request.Context(), userID, unreadOnly, page, pageSize)
+	if err != nil {
+		resp.Error(c, 500, "获取通知失败: "+err.Error())
+		return
+	}
+	resp.Success(c, result)
+}
+
+// UnreadCount 获取未读通知数
+// GET /api/v1/notifications/unread-count
+func (nc *Controller) UnreadCount(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	count, err := nc.service.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		resp.Error(c, 500, "获取未读通知数失败: "+err.Error())
+		return
+	}
+	resp.Success(c, gin.H{"count": count})
+}
+
+// MarkRead 将指定通知标记为已读
+// POST /api/v1/notifications/:id/read
+func (nc *Controller) MarkRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		resp.Error(c, apperrors.CodeInvalidParams, "无效的通知ID")
+		return
+	}
+
+	if err := nc.service.MarkRead(c.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, notification.ErrNotificationNotFound) {
+			resp.Error(c, 404, err.Error())
+			return
+		}
+		resp.Error(c, 500, "标记已读失败: "+err.Error())
+		return
+	}
+	resp.Success(c, nil)
+}
+
+// MarkAllRead 将全部通知标记为已读
+// POST /api/v1/notifications/read-all
+func (nc *Controller) MarkAllRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	updated, err := nc.service.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		resp.Error(c, 500, "标记已读失败: "+err.Error())
+		return
+	}
+	resp.Success(c, gin.H{"updated": updated})
+}
+
+// currentUserID 获取当前登录用户ID，失败时已写入错误响应
+func currentUserID(c *gin.Context) (int64, bool) {
+	userID, err := strconv.ParseInt(c.GetString("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		resp.Error(c, apperrors.CodeUnauthenticated, "用户未登录")
+		return 0, false
+	}
+	return userID, true
+}
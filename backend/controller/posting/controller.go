@@ -0,0 +1,157 @@
+package posting
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/service/posting"
+)
+
+// Controller 发布限制管理控制器（屏蔽词与用户配额）
+type Controller struct {
+	service posting.Service
+}
+
+// NewController 创建控制器实例
+func NewController(service posting.Service) *Controller {
+	return &Controller{service: service}
+}
+
+// ListKeywords 获取屏蔽词列表
+// GET /api/v1/admin/posting/keywords
+func (pc *Controller) ListKeywords(c *gin.Context) {
+	keywords, err := pc.service.ListKeywords(c.Request.Context())
+	if err != nil {
+		resp.Error(c, 500, "获取屏蔽词失败: "+err.Error())
+		return
+	}
+	resp.Success(c, keywords)
+}
+
+// AddKeyword 添加屏蔽词
+// POST /api/v1/admin/posting/keywords
+func (pc *Controller) AddKeyword(c *gin.Context) {
+	var req struct {
+		Keyword string `json:"keyword" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	keyword, err := pc.service.AddKeyword(c.Request.Context(), currentUserID(c), req.Keyword)
+	if err != nil {
+		handlePostingError(c, "添加屏蔽词失败", err)
+		return
+	}
+	resp.Success(c, keyword)
+}
+
+// DeleteKeyword 删除屏蔽词
+// DELETE /api/v1/admin/posting/keywords/:id
+func (pc *Controller) DeleteKeyword(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的屏蔽词ID")
+		return
+	}
+
+	if err := pc.service.DeleteKeyword(c.Request.Context(), id); err != nil {
+		handlePostingError(c, "删除屏蔽词失败", err)
+		return
+	}
+	resp.Success(c, nil)
+}
+
+// GetUserQuota 获取用户生效的发布配额与当前用量
+// GET /api/v1/admin/users/:id/posting-quota
+func (pc *Controller) GetUserQuota(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	quota, err := pc.service.GetUserQuota(c.Request.Context(), userID)
+	if err != nil {
+		handlePostingError(c, "获取发布配额失败", err)
+		return
+	}
+	resp.Success(c, quota)
+}
+
+// SetUserQuota 设置用户的发布配额覆盖
+// PUT /api/v1/admin/users/:id/posting-quota
+// 字段为 null 时沿用默认值，0 表示禁止该用户发布或上架商品
+func (pc *Controller) SetUserQuota(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		MaxForSale *int   `json:"maxForSale"`
+		MaxDaily   *int   `json:"maxDaily"`
+		Note       string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	quota, err := pc.service.SetUserQuota(c.Request.Context(), currentUserID(c), userID, req.MaxForSale, req.MaxDaily, req.Note)
+	if err != nil {
+		handlePostingError(c, "设置发布配额失败", err)
+		return
+	}
+	resp.Success(c, quota)
+}
+
+// ClearUserQuota 删除用户的发布配额覆盖，恢复默认值
+// DELETE /api/v1/admin/users/:id/posting-quota
+func (pc *Controller) ClearUserQuota(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	quota, err := pc.service.ClearUserQuota(c.Request.Context(), userID)
+	if err != nil {
+		handlePostingError(c, "恢复默认配额失败", err)
+		return
+	}
+	resp.Success(c, quota)
+}
+
+// parseUserID 解析路径中的用户ID
+func parseUserID(c *gin.Context) (int64, bool) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		resp.Error(c, 1001, "无效的用户ID")
+		return 0, false
+	}
+	return userID, true
+}
+
+// currentUserID 获取当前操作的管理员ID
+func currentUserID(c *gin.Context) int64 {
+	userID, _ := strconv.ParseInt(c.GetString("user_id"), 10, 64)
+	return userID
+}
+
+// handlePostingError 将发布限制服务错误映射为响应错误码
+func handlePostingError(c *gin.Context, prefix string, err error) {
+	switch {
+	case errors.Is(err, posting.ErrKeywordNotFound), errors.Is(err, posting.ErrUserNotFound):
+		resp.Error(c, 404, err.Error())
+	case errors.Is(err, posting.ErrInvalidKeyword),
+		errors.Is(err, posting.ErrKeywordExists),
+		errors.Is(err, posting.ErrInvalidQuota),
+		errors.Is(err, posting.ErrQuotaNoteTooLong):
+		resp.Error(c, 1001, err.Error())
+	default:
+		resp.Error(c, 500, prefix+": "+err.Error())
+	}
+}
@@ -1,12 +1,16 @@
 package product
 
 import (
+	"encoding/json"
+	"errors"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/service/posting"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/service/product"
 )
 
@@ -45,9 +49,10 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 	categoryIDStr := c.PostForm("categoryId")
 	conditionIDStr := c.PostForm("conditionId")
 	tagIDsStr := c.PostForm("tagIds")
+	isbn := c.PostForm("isbn")
 
-	// 基本参数校验
-	if title == "" || priceStr == "" || categoryIDStr == "" || conditionIDStr == "" {
+	// 基本参数校验（填写ISBN时标题可由图书目录补全）
+	if (title == "" && isbn == "") || priceStr == "" || categoryIDStr == "" || conditionIDStr == "" {
 		resp.Error(c, 400, "标题、价格、分类ID和商品状态为必填项")
 		return
 	}
@@ -84,6 +89,33 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 		}
 	}
 
+	// 面交地点（可选）
+	var meetupPointID int64
+	if pointIDStr := c.PostForm("meetupPointId"); pointIDStr != "" {
+		meetupPointID, err = strconv.ParseInt(pointIDStr, 10, 64)
+		if err != nil {
+			resp.Error(c, 400, "无效的面交地点ID")
+			return
+		}
+	}
+
+	// 解析卖家提议的新标签（可重复字段或逗号分隔）
+	var proposedTags []string
+	for _, value := range c.PostFormArray("proposedTags") {
+		for _, name := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '，' }) {
+			proposedTags = append(proposedTags, name)
+		}
+	}
+
+	// 解析分类属性（JSON对象字符串，可选）
+	var attributes map[string]interface{}
+	if attributesStr := c.PostForm("attributes"); attributesStr != "" {
+		if err := json.Unmarshal([]byte(attributesStr), &attributes); err != nil {
+			resp.Error(c, 400, "无效的商品属性格式")
+			return
+		}
+	}
+
 	// 获取上传的文件
 	form, err := c.MultipartForm()
 	if err != nil {
@@ -115,6 +147,10 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 		CategoryID:        categoryID,
 		ConditionID:       conditionID,
 		TagIDs:            tagIDs,
+		ProposedTags:      proposedTags,
+		Attributes:        attributes,
+		ISBN:              isbn,
+		MeetupPointID:     meetupPointID,
 		Images:            files,
 		PrimaryImageIndex: primaryImageIndex,
 	}
@@ -123,7 +159,9 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 	productDTO, err := pc.productService.CreateProduct(c.Request.Context(), userID, req)
 	if err != nil {
 		// 根据错误类型返回不同的错误码
-		if strings.Contains(err.Error(), "请先完善微信号") {
+		if code, ok := postingErrorCode(err); ok {
+			resp.Error(c, code, err.Error())
+		} else if strings.Contains(err.Error(), "请先完善微信号") {
 			resp.Error(c, 1001, err.Error())
 		} else {
 			resp.Error(c, 400, err.Error())
@@ -153,7 +191,7 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 	// 判断是否为管理员
 	isAdmin := false
 	role, exists := c.Get("role")
-	if exists && role == "admin" {
+	if exists && middleware.IsAdminRole(role) {
 		isAdmin = true
 	}
 
@@ -175,6 +213,10 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 	// 调用服务层方法
 	productDTO, err := pc.productService.UpdateProduct(c.Request.Context(), userID, productID, &req, isAdmin)
 	if err != nil {
+		if code, ok := postingErrorCode(err); ok {
+			resp.Error(c, code, err.Error())
+			return
+		}
 		resp.Error(c, 400, err.Error())
 		return
 	}
@@ -220,7 +262,9 @@ func (pc *ProductController) ChangeProductStatus(c *gin.Context) {
 	err = pc.productService.ChangeStatus(c.Request.Context(), userID, productID, req.Action)
 	if err != nil {
 		// 根据错误类型返回不同的错误码
-		if strings.Contains(err.Error(), "终态") {
+		if code, ok := postingErrorCode(err); ok {
+			resp.Error(c, code, err.Error())
+		} else if strings.Contains(err.Error(), "终态") {
 			resp.Error(c, 3004, err.Error())
 		} else {
 			resp.Error(c, 400, err.Error())
@@ -272,6 +316,43 @@ func (pc *ProductController) UndoLastStatusChange(c *gin.Context) {
 	resp.Success(c, gin.H{"message": "状态撤销成功"})
 }
 
+// BumpProduct 擦亮在售商品，续期避免被自动下架
+// POST /api/v1/products/:id/bump
+func (pc *ProductController) BumpProduct(c *gin.Context) {
+	// 从上下文中获取用户ID
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		resp.Error(c, 401, "用户未登录")
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr.(string), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的用户ID")
+		return
+	}
+
+	// 获取商品ID
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的商品ID")
+		return
+	}
+
+	if err := pc.productService.BumpProduct(c.Request.Context(), userID, productID); err != nil {
+		if strings.Contains(err.Error(), "不存在") {
+			resp.Error(c, 404, err.Error())
+		} else if strings.Contains(err.Error(), "无权限") {
+			resp.Error(c, 403, err.Error())
+		} else {
+			resp.Error(c, 400, err.Error())
+		}
+		return
+	}
+
+	resp.Success(c, gin.H{"message": "擦亮成功"})
+}
+
 // GetProductDetail 获取商品详情
 // GET /api/v1/products/:id
 func (pc *ProductController) GetProductDetail(c *gin.Context) {
@@ -380,6 +461,8 @@ func (pc *ProductController) ListMyProducts(c *gin.Context) {
 
 // SearchProducts 搜索商品
 // GET /api/v1/products/search
+// 属性筛选通过可重复的 attr 参数传递，如 attr=storage>=128&attr=brand=Apple
+// zoneId 按面交地点所在的校园片区筛选
 func (pc *ProductController) SearchProducts(c *gin.Context) {
 	// 解析查询参数
 	keyword := c.Query("keyword")
@@ -388,6 +471,7 @@ func (pc *ProductController) SearchProducts(c *gin.Context) {
 	maxPriceStr := c.Query("maxPrice")
 	conditionIDStr := c.Query("conditionId")
 	tagIDStr := c.Query("tagId")
+	zoneIDStr := c.Query("zoneId")
 	pageStr := c.DefaultQuery("page", "1")
 	pageSizeStr := c.DefaultQuery("pageSize", "10")
 
@@ -403,9 +487,10 @@ func (pc *ProductController) SearchProducts(c *gin.Context) {
 
 	// 构建搜索参数
 	params := &product.SearchParams{
-		Keyword:  keyword,
-		Page:     page,
-		PageSize: pageSize,
+		Keyword:          keyword,
+		AttributeFilters: c.QueryArray("attr"),
+		Page:             page,
+		PageSize:         pageSize,
 	}
 
 	// 解析可选参数
@@ -439,9 +524,19 @@ func (pc *ProductController) SearchProducts(c *gin.Context) {
 		}
 	}
 
+	if zoneIDStr != "" {
+		if zoneID, err := strconv.ParseInt(zoneIDStr, 10, 64); err == nil {
+			params.ZoneID = &zoneID
+		}
+	}
+
 	// 调用服务层方法
 	products, total, err := pc.productService.Search(c.Request.Context(), params)
 	if err != nil {
+		if errors.Is(err, product.ErrInvalidAttributeFilter) {
+			resp.Error(c, 1001, err.Error())
+			return
+		}
 		resp.Error(c, 500, "搜索商品失败")
 		return
 	}
@@ -472,6 +567,7 @@ func (pc *ProductController) GetProductsByCategory(c *gin.Context) {
 	if conditionIDsStr == "" {
 		conditionIDsStr = c.Query("conditionId")
 	}
+	zoneIDStr := c.Query("zoneId")
 	sort := c.DefaultQuery("sort", "latest")
 	pageStr := c.DefaultQuery("page", "1")
 	pageSizeStr := c.DefaultQuery("pageSize", "10")
@@ -488,10 +584,11 @@ func (pc *ProductController) GetProductsByCategory(c *gin.Context) {
 
 	// 构建查询参数
 	params := &product.SearchParams{
-		CategoryID: &categoryID,
-		Page:       page,
-		PageSize:   pageSize,
-		Sort:       sort,
+		CategoryID:       &categoryID,
+		AttributeFilters: c.QueryArray("attr"),
+		Page:             page,
+		PageSize:         pageSize,
+		Sort:             sort,
 	}
 
 	// 解析可选参数
@@ -522,9 +619,19 @@ func (pc *ProductController) GetProductsByCategory(c *gin.Context) {
 		params.ConditionIDs = ids
 	}
 
+	if zoneIDStr != "" {
+		if zoneID, err := strconv.ParseInt(zoneIDStr, 10, 64); err == nil {
+			params.ZoneID = &zoneID
+		}
+	}
+
 	// 调用服务层方法
 	products, total, err := pc.productService.ListByCategory(c.Request.Context(), categoryID, params)
 	if err != nil {
+		if errors.Is(err, product.ErrInvalidAttributeFilter) {
+			resp.Error(c, 1001, err.Error())
+			return
+		}
 		resp.Error(c, 500, "获取分类商品失败")
 		return
 	}
@@ -536,3 +643,15 @@ func (pc *ProductController) GetProductsByCategory(c *gin.Context) {
 		"pageSize": pageSize,
 	})
 }
+
+// postingErrorCode 将发布限制错误映射为业务错误码
+func postingErrorCode(err error) (int, bool) {
+	switch {
+	case errors.Is(err, posting.ErrForSaleLimit), errors.Is(err, posting.ErrDailyLimit):
+		return posting.ErrCodeQuotaExceeded, true
+	case errors.Is(err, posting.ErrBlockedKeyword):
+		return posting.ErrCodeBlockedKeyword, true
+	default:
+		return 0, false
+	}
+}
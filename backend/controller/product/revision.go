@@ -0,0 +1,131 @@
+package product
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
+)
+
+// ListRevisions 获取商品修订历史（卖家本人或管理员）
+// GET /api/v1/products/:id/revisions
+func (pc *ProductController) ListRevisions(c *gin.Context) {
+	// 从上下文中获取用户ID和角色
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		resp.Error(c, 401, "用户未登录")
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr.(string), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的用户ID")
+		return
+	}
+
+	isAdmin := false
+	if role, exists := c.Get("role"); exists && middleware.IsAdminRole(role) {
+		isAdmin = true
+	}
+
+	// 获取商品ID
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的商品ID")
+		return
+	}
+
+	revisions, err := pc.productService.ListRevisions(c.Request.Context(), userID, productID, isAdmin)
+	if err != nil {
+		switch err.Error() {
+		case "商品不存在":
+			resp.Error(c, 3001, err.Error())
+		case "无权限操作该商品":
+			resp.Error(c, 1003, err.Error())
+		default:
+			resp.Error(c, 500, "获取修订历史失败: "+err.Error())
+		}
+		return
+	}
+
+	resp.Success(c, revisions)
+}
+
+// GetPriceHistory 获取商品价格历史（公开接口）
+// GET /api/v1/products/:id/price-history
+func (pc *ProductController) GetPriceHistory(c *gin.Context) {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的商品ID")
+		return
+	}
+
+	// 可选登录：卖家本人与管理员可查看已下架商品的价格历史
+	var viewerID *int64
+	if userIDStr, exists := c.Get("user_id"); exists {
+		if id, err := strconv.ParseInt(userIDStr.(string), 10, 64); err == nil {
+			viewerID = &id
+		}
+	}
+	isAdmin := false
+	if role, exists := c.Get("role"); exists && middleware.IsAdminRole(role) {
+		isAdmin = true
+	}
+
+	points, err := pc.productService.GetPriceHistory(c.Request.Context(), productID, viewerID, isAdmin)
+	if err != nil {
+		if err.Error() == "商品不存在" {
+			resp.Error(c, 3001, err.Error())
+			return
+		}
+		log.Printf("product: get price history of product %d failed: %v", productID, err)
+		resp.Error(c, 500, "获取价格历史失败")
+		return
+	}
+
+	resp.Success(c, points)
+}
+
+// RevertRevision 管理员将商品回滚到指定版本
+// POST /api/v1/admin/products/:id/revisions/:version/revert
+func (pc *ProductController) RevertRevision(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		resp.Error(c, 401, "用户未登录")
+		return
+	}
+
+	adminID, err := strconv.ParseInt(userIDStr.(string), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的用户ID")
+		return
+	}
+
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的商品ID")
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		resp.Error(c, 400, "无效的版本号")
+		return
+	}
+
+	product, err := pc.productService.RevertToRevision(c.Request.Context(), adminID, productID, version)
+	if err != nil {
+		switch err.Error() {
+		case "商品不存在", "版本不存在":
+			resp.Error(c, 3001, err.Error())
+		default:
+			resp.Error(c, 500, "回滚商品失败: "+err.Error())
+		}
+		return
+	}
+
+	resp.Success(c, product)
+}
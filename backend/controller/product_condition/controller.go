@@ -1,6 +1,9 @@
 package productcondition
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
@@ -27,3 +30,106 @@ func (pc *Controller) ListProductConditions(c *gin.Context) {
 	}
 	resp.Success(c, conditions)
 }
+
+// CreateProductCondition 创建新旧程度（管理端接口）
+// POST /api/v1/super/product-conditions
+func (pc *Controller) CreateProductCondition(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	condition, err := pc.service.CreateProductCondition(c.Request.Context(), req.Code, req.Name)
+	if err != nil {
+		handleConditionError(c, "创建新旧程度失败", err)
+		return
+	}
+	resp.Success(c, condition)
+}
+
+// UpdateProductCondition 修改新旧程度名称（管理端接口）
+// PUT /api/v1/super/product-conditions/:id
+func (pc *Controller) UpdateProductCondition(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的新旧程度ID")
+		return
+	}
+
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	condition, err := pc.service.UpdateProductCondition(c.Request.Context(), id, req.Name)
+	if err != nil {
+		handleConditionError(c, "更新新旧程度失败", err)
+		return
+	}
+	resp.Success(c, condition)
+}
+
+// DeleteProductCondition 删除新旧程度（管理端接口）
+// DELETE /api/v1/super/product-conditions/:id
+func (pc *Controller) DeleteProductCondition(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的新旧程度ID")
+		return
+	}
+
+	if err := pc.service.DeleteProductCondition(c.Request.Context(), id); err != nil {
+		handleConditionError(c, "删除新旧程度失败", err)
+		return
+	}
+	resp.Success(c, nil)
+}
+
+// ReorderProductConditions 调整新旧程度顺序（管理端接口）
+// PUT /api/v1/super/product-conditions/reorder
+// 请求体 orderedIds 需包含全部新旧程度ID
+func (pc *Controller) ReorderProductConditions(c *gin.Context) {
+	var req struct {
+		OrderedIDs []int64 `json:"orderedIds" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	if err := pc.service.ReorderProductConditions(c.Request.Context(), req.OrderedIDs); err != nil {
+		handleConditionError(c, "调整新旧程度顺序失败", err)
+		return
+	}
+
+	conditions, err := pc.service.ListProductConditions(c.Request.Context())
+	if err != nil {
+		resp.Error(c, 500, "获取新旧程度失败: "+err.Error())
+		return
+	}
+	resp.Success(c, conditions)
+}
+
+// handleConditionError 将新旧程度服务错误映射为响应错误码
+func handleConditionError(c *gin.Context, prefix string, err error) {
+	switch {
+	case errors.Is(err, productcondition.ErrConditionNotFound):
+		resp.Error(c, 404, err.Error())
+	case errors.Is(err, productcondition.ErrConditionInUse):
+		resp.Error(c, productcondition.ErrCodeConditionInUse, err.Error())
+	case errors.Is(err, productcondition.ErrInvalidCondition),
+		errors.Is(err, productcondition.ErrConditionCodeExists),
+		errors.Is(err, productcondition.ErrConditionNameExists),
+		errors.Is(err, productcondition.ErrInvalidReorder):
+		resp.Error(c, 1001, err.Error())
+	default:
+		resp.Error(c, 500, prefix+": "+err.Error())
+	}
+}
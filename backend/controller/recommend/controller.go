@@ -48,8 +48,16 @@ func (rc *RecommendController) GetHomeData(c *gin.Context) {
 		}
 	}
 
+	// 指定校园片区时附近商品优先（可选，默认使用用户的默认片区）
+	var zoneID *int64
+	if zoneIDStr := c.Query("zoneId"); zoneIDStr != "" {
+		if zid, err := strconv.ParseInt(zoneIDStr, 10, 64); err == nil && zid > 0 {
+			zoneID = &zid
+		}
+	}
+
 	// 获取首页数据
-	homeData, err := rc.recommendService.GetHomeData(c.Request.Context(), userID, page, pageSize)
+	homeData, err := rc.recommendService.GetHomeData(c.Request.Context(), userID, zoneID, page, pageSize)
 	if err != nil {
 		resp.Error(c, 500, "获取首页数据失败")
 		return
@@ -130,3 +138,27 @@ func (rc *RecommendController) RecordProductView(c *gin.Context) {
 
 	resp.Success(c, gin.H{"recorded": true})
 }
+
+// GetCourseBooks 获取用户选修课程的教材推荐
+// GET /api/v1/users/courses/books
+func (rc *RecommendController) GetCourseBooks(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		resp.Error(c, 401, "用户未登录")
+		return
+	}
+
+	userID, err := strconv.ParseInt(userIDStr.(string), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的用户ID")
+		return
+	}
+
+	items, err := rc.recommendService.GetCourseBookRecommendations(c.Request.Context(), userID)
+	if err != nil {
+		resp.Error(c, 500, "获取课程教材推荐失败")
+		return
+	}
+
+	resp.Success(c, gin.H{"items": items})
+}
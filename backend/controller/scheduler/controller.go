@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/service/scheduler"
+)
+
+const (
+	defaultRunsLimit = 20
+	maxRunsLimit     = 100
+)
+
+// Controller 定时任务管理控制器
+type Controller struct {
+	scheduler *scheduler.Scheduler
+}
+
+// NewController 创建控制器实例
+func NewController(scheduler *scheduler.Scheduler) *Controller {
+	return &Controller{scheduler: scheduler}
+}
+
+// ListJobs 获取全部定时任务及最近一次执行状态
+// GET /api/v1/super/jobs
+func (jc *Controller) ListJobs(c *gin.Context) {
+	jobs, err := jc.scheduler.ListJobs(c.Request.Context())
+	if err != nil {
+		resp.Error(c, 500, "获取定时任务失败: "+err.Error())
+		return
+	}
+	resp.Success(c, jobs)
+}
+
+// ListRuns 获取定时任务最近的执行记录
+// GET /api/v1/super/jobs/:name/runs?limit=20
+func (jc *Controller) ListRuns(c *gin.Context) {
+	limit := defaultRunsLimit
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			resp.Error(c, 1001, "无效的 limit 参数")
+			return
+		}
+		limit = min(n, maxRunsLimit)
+	}
+
+	runs, err := jc.scheduler.ListRuns(c.Request.Context(), c.Param("name"), limit)
+	if err != nil {
+		handleSchedulerError(c, "获取执行记录失败", err)
+		return
+	}
+	resp.Success(c, runs)
+}
+
+// Trigger 手动触发定时任务，任务在下一次轮询时执行（通常几秒内）
+// POST /api/v1/super/jobs/:name/run
+func (jc *Controller) Trigger(c *gin.Context) {
+	if err := jc.scheduler.Trigger(c.Request.Context(), c.Param("name"), currentUserID(c)); err != nil {
+		handleSchedulerError(c, "触发任务失败", err)
+		return
+	}
+	resp.Success(c, nil)
+}
+
+// currentUserID 获取当前操作的管理员ID
+func currentUserID(c *gin.Context) int64 {
+	userID, _ := strconv.ParseInt(c.GetString("user_id"), 10, 64)
+	return userID
+}
+
+// handleSchedulerError 将调度器错误映射为响应错误码
+func handleSchedulerError(c *gin.Context, prefix string, err error) {
+	if errors.Is(err, scheduler.ErrJobNotFound) {
+		resp.Error(c, 404, err.Error())
+		return
+	}
+	resp.Error(c, 500, prefix+": "+err.Error())
+}
@@ -0,0 +1,111 @@
+package school
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/service/school"
+)
+
+// Controller 学校（租户）控制器
+type Controller struct {
+	service school.Service
+}
+
+// NewController 创建控制器实例
+func NewController(service school.Service) *Controller {
+	return &Controller{service: service}
+}
+
+// SchoolRequest 创建或修改学校的请求体
+type SchoolRequest struct {
+	Code     string `json:"code"` // 仅创建时有效
+	Name     string `json:"name" binding:"required"`
+	IsActive *bool  `json:"isActive"`
+}
+
+// ListActive 获取启用的学校
+// GET /api/v1/schools
+func (sc *Controller) ListActive(c *gin.Context) {
+	schools, err := sc.service.ListActive(c.Request.Context())
+	if err != nil {
+		resp.Error(c, 500, "获取学校列表失败: "+err.Error())
+		return
+	}
+	resp.Success(c, schools)
+}
+
+// ListAll 获取全部学校（超级管理员接口）
+// GET /api/v1/super/schools
+func (sc *Controller) ListAll(c *gin.Context) {
+	schools, err := sc.service.ListAll(c.Request.Context())
+	if err != nil {
+		resp.Error(c, 500, "获取学校列表失败: "+err.Error())
+		return
+	}
+	resp.Success(c, schools)
+}
+
+// Create 创建学校（超级管理员接口）
+// POST /api/v1/super/schools
+// isActive 省略时默认启用
+func (sc *Controller) Create(c *gin.Context) {
+	var req SchoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数格式错误: "+err.Error())
+		return
+	}
+
+	created, err := sc.service.Create(c.Request.Context(), school.SchoolInput{
+		Code:     req.Code,
+		Name:     req.Name,
+		IsActive: req.IsActive == nil || *req.IsActive,
+	})
+	if err != nil {
+		handleSchoolError(c, "创建学校失败", err)
+		return
+	}
+	resp.Success(c, created)
+}
+
+// Update 修改学校名称与启用状态（超级管理员接口）
+// PUT /api/v1/super/schools/:id
+func (sc *Controller) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的学校ID")
+		return
+	}
+
+	var req SchoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.IsActive == nil {
+		resp.Error(c, 400, "请求参数格式错误，需提供 name 与 isActive")
+		return
+	}
+
+	updated, err := sc.service.Update(c.Request.Context(), id, school.SchoolInput{
+		Name:     req.Name,
+		IsActive: *req.IsActive,
+	})
+	if err != nil {
+		handleSchoolError(c, "更新学校失败", err)
+		return
+	}
+	resp.Success(c, updated)
+}
+
+// handleSchoolError 将学校服务错误映射为响应错误码
+func handleSchoolError(c *gin.Context, prefix string, err error) {
+	switch {
+	case errors.Is(err, school.ErrSchoolNotFound):
+		resp.Error(c, 404, err.Error())
+	case errors.Is(err, school.ErrInvalidSchool),
+		errors.Is(err, school.ErrSchoolCodeExists):
+		resp.Error(c, 1001, err.Error())
+	default:
+		resp.Error(c, 500, prefix+": "+err.Error())
+	}
+}
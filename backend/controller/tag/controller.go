@@ -1,6 +1,7 @@
 package tag
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -22,17 +23,28 @@ func NewTagController(tagService tag.TagService) *TagController {
 	}
 }
 
-// ListTags 获取所有标签列表（前台公开接口）
-// @Summary 获取所有标签
-// @Description 获取系统中所有可用的标签列表，无需登录
+// ListTags 获取标签列表（前台公开接口）
+// @Summary 获取标签
+// @Description 获取系统中可用的标签列表，无需登录；指定 categoryId 时仅返回适用于该分类（含其祖先分类）的标签
 // @Tags 前台-标签
 // @Accept json
 // @Produce json
+// @Param categoryId query int false "分类ID"
 // @Success 200 {object} response.Response{data=[]model.Tag}
 // @Router /api/v1/tags [get]
 func (tc *TagController) ListTags(c *gin.Context) {
+	var categoryID int64
+	if raw := c.Query("categoryId"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			resp.Error(c, 1001, "无效的分类ID")
+			return
+		}
+		categoryID = id
+	}
+
 	// 调用服务层获取标签列表
-	tags, err := tc.tagService.ListTags(c)
+	tags, err := tc.tagService.ListTags(c.Request.Context(), categoryID)
 	if err != nil {
 		resp.Error(c, 500, "获取标签列表失败: "+err.Error())
 		return
@@ -59,14 +71,15 @@ func (tc *TagController) CreateTag(c *gin.Context) {
 
 	// 创建标签模型
 	tag := &model.Tag{
-		Name:       req.Name,
-		CategoryID: req.CategoryID,
+		Name:        req.Name,
+		CategoryID:  req.CategoryID,
+		CategoryIDs: req.CategoryIDs,
 	}
 
 	// 调用服务层创建标签
 	err := tc.tagService.CreateTag(c.Request.Context(), tag)
 	if err != nil {
-		resp.Error(c, 500, "创建标签失败: "+err.Error())
+		handleTagError(c, "创建标签失败", err)
 		return
 	}
 
@@ -101,15 +114,16 @@ func (tc *TagController) UpdateTag(c *gin.Context) {
 
 	// 创建标签模型
 	tag := &model.Tag{
-		ID:         id,
-		Name:       req.Name,
-		CategoryID: req.CategoryID,
+		ID:          id,
+		Name:        req.Name,
+		CategoryID:  req.CategoryID,
+		CategoryIDs: req.CategoryIDs,
 	}
 
 	// 调用服务层更新标签
 	err = tc.tagService.UpdateTag(c.Request.Context(), tag)
 	if err != nil {
-		resp.Error(c, 500, "更新标签失败: "+err.Error())
+		handleTagError(c, "更新标签失败", err)
 		return
 	}
 
@@ -135,28 +149,231 @@ func (tc *TagController) DeleteTag(c *gin.Context) {
 	}
 
 	// 调用服务层删除标签
-	err = tc.tagService.DeleteTag(c, id)
+	err = tc.tagService.DeleteTag(c.Request.Context(), id)
 	if err != nil {
-		// 判断是否为标签下有商品的错误（错误码4002）
-		if err == tag.ErrTagHasProducts {
-			resp.Error(c, tag.ErrCodeTagHasProducts, err.Error())
-			return
-		}
-		resp.Error(c, 500, "删除标签失败: "+err.Error())
+		// 标签下有商品时返回错误码4002
+		handleTagError(c, "删除标签失败", err)
+		return
+	}
+
+	resp.Success(c, nil)
+}
+
+// SetTagAliases 设置标签同义词（管理端接口）
+// @Summary 设置标签同义词
+// @Description 整体替换标签的别名列表，搜索关键词命中别名等同于命中该标签
+// @Tags 管理端-标签
+// @Accept json
+// @Produce json
+// @Param id path int true "标签ID"
+// @Param body body TagAliasesRequest true "别名列表"
+// @Success 200 {object} response.Response{data=model.Tag}
+// @Router /api/v1/admin/tags/{id}/aliases [put]
+func (tc *TagController) SetTagAliases(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的标签ID")
+		return
+	}
+
+	var req TagAliasesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数无效: "+err.Error())
+		return
+	}
+
+	result, err := tc.tagService.SetAliases(c.Request.Context(), id, req.Aliases)
+	if err != nil {
+		handleTagError(c, "设置标签别名失败", err)
+		return
+	}
+
+	resp.Success(c, result)
+}
+
+// MergeTag 合并标签（管理端接口）
+// @Summary 合并标签
+// @Description 将路径中的标签合并到目标标签：商品标签关联改挂到目标标签，原标签名称成为目标标签的别名，随后删除原标签
+// @Tags 管理端-标签
+// @Accept json
+// @Produce json
+// @Param id path int true "被合并的标签ID"
+// @Param body body TagMergeRequest true "目标标签"
+// @Success 200 {object} response.Response{data=model.Tag}
+// @Router /api/v1/admin/tags/{id}/merge [post]
+func (tc *TagController) MergeTag(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的标签ID")
+		return
+	}
+
+	var req TagMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数无效: "+err.Error())
+		return
+	}
+
+	result, err := tc.tagService.MergeTags(c.Request.Context(), id, req.TargetID)
+	if err != nil {
+		handleTagError(c, "合并标签失败", err)
+		return
+	}
+
+	resp.Success(c, result)
+}
+
+// ListReviewTags 获取待审核等指定状态的标签及使用次数（管理端接口）
+// @Summary 标签审核列表
+// @Description 按状态分页查询标签，默认查询待审核标签，按使用该标签的商品数降序排列
+// @Tags 管理端-标签
+// @Produce json
+// @Param status query string false "Pending / Approved / Rejected，默认 Pending"
+// @Param page query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/tags [get]
+func (tc *TagController) ListReviewTags(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	tags, total, err := tc.tagService.ListReviewTags(c.Request.Context(), c.Query("status"), page, pageSize)
+	if err != nil {
+		handleTagError(c, "获取标签列表失败", err)
+		return
+	}
+
+	resp.Success(c, gin.H{
+		"items":    tags,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// ApproveTag 通过卖家提议的标签（管理端接口）
+// @Summary 通过标签
+// @Tags 管理端-标签
+// @Produce json
+// @Param id path int true "标签ID"
+// @Success 200 {object} response.Response{data=model.Tag}
+// @Router /api/v1/admin/tags/{id}/approve [post]
+func (tc *TagController) ApproveTag(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的标签ID")
+		return
+	}
+
+	result, err := tc.tagService.ApproveTag(c.Request.Context(), id)
+	if err != nil {
+		handleTagError(c, "审核标签失败", err)
+		return
+	}
+
+	resp.Success(c, result)
+}
+
+// RejectTag 驳回卖家提议的标签（管理端接口）
+// @Summary 驳回标签
+// @Description 驳回后移除商品上的该标签，之后同名提议将被忽略
+// @Tags 管理端-标签
+// @Produce json
+// @Param id path int true "标签ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/admin/tags/{id}/reject [post]
+func (tc *TagController) RejectTag(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的标签ID")
+		return
+	}
+
+	if err := tc.tagService.RejectTag(c.Request.Context(), id); err != nil {
+		handleTagError(c, "驳回标签失败", err)
 		return
 	}
 
 	resp.Success(c, nil)
 }
 
+// MapTag 将卖家提议的标签映射到已有标签（管理端接口）
+// @Summary 映射标签
+// @Description 商品改挂到目标标签，提议的名称成为目标标签的别名
+// @Tags 管理端-标签
+// @Accept json
+// @Produce json
+// @Param id path int true "待审核标签ID"
+// @Param body body TagMergeRequest true "目标标签"
+// @Success 200 {object} response.Response{data=model.Tag}
+// @Router /api/v1/admin/tags/{id}/map [post]
+func (tc *TagController) MapTag(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Error(c, 400, "无效的标签ID")
+		return
+	}
+
+	var req TagMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, 400, "请求参数无效: "+err.Error())
+		return
+	}
+
+	result, err := tc.tagService.MapTag(c.Request.Context(), id, req.TargetID)
+	if err != nil {
+		handleTagError(c, "映射标签失败", err)
+		return
+	}
+
+	resp.Success(c, result)
+}
+
+// handleTagError 将标签服务错误映射为响应错误码
+func handleTagError(c *gin.Context, prefix string, err error) {
+	switch {
+	case errors.Is(err, tag.ErrTagNotFound):
+		resp.Error(c, 404, err.Error())
+	case errors.Is(err, tag.ErrTagHasProducts):
+		resp.Error(c, tag.ErrCodeTagHasProducts, err.Error())
+	case errors.Is(err, tag.ErrTagNameConflict):
+		resp.Error(c, tag.ErrCodeTagNameConflict, err.Error())
+	case errors.Is(err, tag.ErrTagStatus):
+		resp.Error(c, tag.ErrCodeTagStatus, err.Error())
+	case errors.Is(err, tag.ErrInvalidTag), errors.Is(err, tag.ErrCategoryNotFound), errors.Is(err, tag.ErrInvalidMergeTarget):
+		resp.Error(c, 1001, err.Error())
+	default:
+		resp.Error(c, 500, prefix+": "+err.Error())
+	}
+}
+
 // TagCreateRequest 标签创建请求
+// CategoryID 为主分类，CategoryIDs 为标签适用的其他分类；主分类为空时取 CategoryIDs 的第一个
 type TagCreateRequest struct {
-	Name       string `json:"name" binding:"required,min=1,max=50"`
-	CategoryID int64  `json:"categoryId" binding:"required,gt=0"`
+	Name        string  `json:"name" binding:"required,min=1,max=50"`
+	CategoryID  int64   `json:"categoryId" binding:"omitempty,gt=0"`
+	CategoryIDs []int64 `json:"categoryIds"`
 }
 
-// TagUpdateRequest 标签更新请求
+// TagUpdateRequest 标签更新请求，CategoryIDs 整体替换标签适用的分类
 type TagUpdateRequest struct {
-	Name       string `json:"name" binding:"required,min=1,max=50"`
-	CategoryID int64  `json:"categoryId" binding:"required,gt=0"`
+	Name        string  `json:"name" binding:"required,min=1,max=50"`
+	CategoryID  int64   `json:"categoryId" binding:"omitempty,gt=0"`
+	CategoryIDs []int64 `json:"categoryIds"`
+}
+
+// TagAliasesRequest 标签别名设置请求
+type TagAliasesRequest struct {
+	Aliases []string `json:"aliases"`
+}
+
+// TagMergeRequest 标签合并请求
+type TagMergeRequest struct {
+	TargetID int64 `json:"targetId" binding:"required,gt=0"`
 }
@@ -3,6 +3,7 @@
 package user
 
 import (
+	stderrors "errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -28,6 +29,22 @@ import (
 //	GET    /users/profile          - 获取个人信息（需要登录）
 //	PUT    /users/profile          - 更新个人信息（需要登录）
 //	PUT    /users/password         - 修改密码（需要登录）
+//	POST   /users/password/forgot  - 找回密码，向已验证邮箱发送重置链接
+//	POST   /users/password/reset   - 使用重置令牌设置新密码
+//	POST   /users/email/verify     - 校验邮箱验证令牌
+//	PUT    /users/email            - 设置/修改邮箱并发送验证邮件（需要登录）
+//	POST   /users/email/resend     - 重新发送验证邮件（需要登录）
+//	POST   /users/login/2fa        - 提交两步验证码完成登录
+//	GET    /users/2fa              - 获取两步验证状态（需要登录）
+//	POST   /users/2fa/setup        - 生成 TOTP 密钥与绑定二维码链接（需要登录）
+//	POST   /users/2fa/enable       - 校验验证码并启用两步验证，返回备用码（需要登录）
+//	POST   /users/2fa/disable      - 关闭两步验证（需要登录）
+//	POST   /users/2fa/backup-codes - 重新生成备用码（需要登录）
+//	POST   /users/2fa/verify       - 二次验证，换取带两步验证时间的新令牌（需要登录）
+//	GET    /users/sessions         - 已登录设备列表（需要登录）
+//	DELETE /users/sessions/:id     - 移除指定设备（需要登录）
+//	DELETE /users/sessions         - 移除当前设备以外的全部设备（需要登录）
+//	GET    /users/login-history    - 最近登录记录（需要登录）
 //
 // 参数：
 //   - rg: 父路由组，通常是 /api/v1
@@ -40,12 +57,13 @@ func RegisterRoutes(rg *gin.RouterGroup, userService *user.UserService) {
 		// ============ 公开接口（无需登录）============
 
 		// POST /api/v1/users/register - 用户注册
-		usr.POST("/register", func(c *gin.Context) {
+		usr.POST("/register", middleware.RateLimitMiddleware(middleware.RateLimitAuth), func(c *gin.Context) {
 			var req struct {
 				Account         string  `json:"account" binding:"required"`
 				Nickname        string  `json:"nickname" binding:"required"`
 				Password        string  `json:"password" binding:"required"`
 				ConfirmPassword string  `json:"confirmPassword" binding:"required"`
+				Email           string  `json:"email"`
 				WechatID        *string `json:"wechatId,omitempty"`
 			}
 
@@ -62,7 +80,7 @@ func RegisterRoutes(rg *gin.RouterGroup, userService *user.UserService) {
 			}
 
 			// 调用服务层注册用户
-			authResp, err := userService.Register(c.Request.Context(), req.Account, req.Nickname, req.Password, req.WechatID)
+			authResp, err := userService.Register(c.Request.Context(), req.Account, req.Nickname, req.Password, req.Email, req.WechatID, clientInfo(c))
 			if err != nil {
 				// 根据错误类型返回对应的错误信息
 				resp.Error(c, errors.CodeInvalidParams, err.Error())
@@ -74,7 +92,7 @@ func RegisterRoutes(rg *gin.RouterGroup, userService *user.UserService) {
 		})
 
 		// POST /api/v1/users/login - 用户登录
-		usr.POST("/login", func(c *gin.Context) {
+		usr.POST("/login", middleware.RateLimitMiddleware(middleware.RateLimitAuth), func(c *gin.Context) {
 			var req struct {
 				Account    string `json:"account" binding:"required"`
 				Password   string `json:"password" binding:"required"`
@@ -88,8 +106,31 @@ func RegisterRoutes(rg *gin.RouterGroup, userService *user.UserService) {
 			}
 
 			// 调用服务层登录
-			authResp, err := userService.Login(c.Request.Context(), req.Account, req.Password, req.RememberMe)
+			authResp, err := userService.Login(c.Request.Context(), req.Account, req.Password, req.RememberMe, clientInfo(c))
 			if err != nil {
+				// 连续失败被限制时返回剩余冷却时间
+				var throttled *user.LoginThrottledError
+				if stderrors.As(err, &throttled) {
+					code := errors.CodeLoginThrottled
+					if throttled.Locked {
+						code = errors.CodeAccountLocked
+					}
+					c.Header("Retry-After", strconv.Itoa(throttled.RetrySeconds()))
+					resp.ErrorWithData(c, code, throttled.Error(), gin.H{
+						"retryAfterSeconds": throttled.RetrySeconds(),
+						"locked":            throttled.Locked,
+					})
+					return
+				}
+				// 已启用两步验证：返回挑战令牌，由前端提交验证码完成登录
+				var twoFactor *user.TwoFactorRequiredError
+				if stderrors.As(err, &twoFactor) {
+					resp.ErrorWithData(c, errors.CodeTwoFactorRequired, twoFactor.Error(), gin.H{
+						"twoFactorToken":   twoFactor.ChallengeToken,
+						"expiresInSeconds": int(twoFactor.ExpiresIn.Seconds()),
+					})
+					return
+				}
 				// 根据错误类型返回对应的错误信息
 				resp.Error(c, errors.CodeInvalidParams, err.Error())
 				return
@@ -99,6 +140,89 @@ func RegisterRoutes(rg *gin.RouterGroup, userService *user.UserService) {
 			resp.Success(c, authResp)
 		})
 
+		// POST /api/v1/users/login/2fa - 提交两步验证码（或备用码）完成登录
+		usr.POST("/login/2fa", middleware.RateLimitMiddleware(middleware.RateLimitAuth), func(c *gin.Context) {
+			var req struct {
+				TwoFactorToken string `json:"twoFactorToken" binding:"required"`
+				Code           string `json:"code" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				resp.Error(c, errors.CodeInvalidParams, "请求参数错误: "+err.Error())
+				return
+			}
+
+			authResp, err := userService.CompleteTwoFactorLogin(c.Request.Context(), req.TwoFactorToken, req.Code, clientInfo(c))
+			if err != nil {
+				resp.Error(c, twoFactorErrorCode(err), err.Error())
+				return
+			}
+
+			resp.Success(c, authResp)
+		})
+
+		// POST /api/v1/users/password/forgot - 找回密码
+		// 无论账号是否存在都返回成功，避免泄露账号信息
+		usr.POST("/password/forgot", middleware.RateLimitMiddleware(middleware.RateLimitAuth), func(c *gin.Context) {
+			var req struct {
+				Account string `json:"account" binding:"required"` // 登录账号或邮箱
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				resp.Error(c, errors.CodeInvalidParams, "请求参数错误: "+err.Error())
+				return
+			}
+
+			if err := userService.ForgotPassword(c.Request.Context(), req.Account, c.ClientIP()); err != nil {
+				resp.Error(c, errors.CodeInvalidParams, err.Error())
+				return
+			}
+
+			resp.Success(c, nil)
+		})
+
+		// POST /api/v1/users/password/reset - 使用重置令牌设置新密码，成功后需重新登录
+		usr.POST("/password/reset", middleware.RateLimitMiddleware(middleware.RateLimitAuth), func(c *gin.Context) {
+			var req struct {
+				Token           string `json:"token" binding:"required"`
+				NewPassword     string `json:"newPassword" binding:"required"`
+				ConfirmPassword string `json:"confirmPassword" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				resp.Error(c, errors.CodeInvalidParams, "请求参数错误: "+err.Error())
+				return
+			}
+
+			if req.NewPassword != req.ConfirmPassword {
+				resp.Error(c, errors.CodeInvalidParams, "两次输入的新密码不一致")
+				return
+			}
+
+			if err := userService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword, c.ClientIP()); err != nil {
+				resp.Error(c, errors.CodeInvalidParams, err.Error())
+				return
+			}
+
+			resp.Success(c, nil)
+		})
+
+		// POST /api/v1/users/email/verify - 校验邮箱验证令牌（来自验证邮件中的链接）
+		usr.POST("/email/verify", func(c *gin.Context) {
+			var req struct {
+				Token string `json:"token" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				resp.Error(c, errors.CodeInvalidParams, "请求参数错误: "+err.Error())
+				return
+			}
+
+			userResp, err := userService.VerifyEmail(c.Request.Context(), req.Token)
+			if err != nil {
+				resp.Error(c, errors.CodeInvalidParams, err.Error())
+				return
+			}
+
+			resp.Success(c, userResp)
+		})
+
 		// ============ 需要登录的接口 ============
 		// 创建需要认证的路由组
 		authorized := usr.Group("")
@@ -227,6 +351,283 @@ func RegisterRoutes(rg *gin.RouterGroup, userService *user.UserService) {
 				// 返回成功响应
 				resp.Success(c, userResp)
 			})
+
+			// PUT /api/v1/users/email - 设置或修改邮箱，修改后需重新验证
+			authorized.PUT("/email", func(c *gin.Context) {
+				var req struct {
+					Email string `json:"email" binding:"required"`
+				}
+				if err := c.ShouldBindJSON(&req); err != nil {
+					resp.Error(c, errors.CodeInvalidParams, "请求参数错误: "+err.Error())
+					return
+				}
+
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+
+				userResp, err := userService.SetEmail(c.Request.Context(), userID, req.Email)
+				if err != nil {
+					resp.Error(c, errors.CodeInvalidParams, err.Error())
+					return
+				}
+
+				resp.Success(c, userResp)
+			})
+
+			// POST /api/v1/users/email/resend - 重新发送验证邮件
+			authorized.POST("/email/resend", func(c *gin.Context) {
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+
+				if err := userService.ResendVerificationEmail(c.Request.Context(), userID); err != nil {
+					resp.Error(c, errors.CodeInvalidParams, err.Error())
+					return
+				}
+
+				resp.Success(c, nil)
+			})
+
+			// GET /api/v1/users/2fa - 获取两步验证状态
+			authorized.GET("/2fa", func(c *gin.Context) {
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+
+				status, err := userService.GetTwoFactorStatus(c.Request.Context(), userID)
+				if err != nil {
+					resp.Error(c, twoFactorErrorCode(err), err.Error())
+					return
+				}
+
+				resp.Success(c, status)
+			})
+
+			// POST /api/v1/users/2fa/setup - 生成 TOTP 密钥，需调用 /2fa/enable 确认后才生效
+			authorized.POST("/2fa/setup", func(c *gin.Context) {
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+
+				setup, err := userService.SetupTwoFactor(c.Request.Context(), userID)
+				if err != nil {
+					resp.Error(c, twoFactorErrorCode(err), err.Error())
+					return
+				}
+
+				resp.Success(c, setup)
+			})
+
+			// POST /api/v1/users/2fa/enable - 校验验证器应用中的验证码并启用两步验证
+			authorized.POST("/2fa/enable", func(c *gin.Context) {
+				code, ok := bindTwoFactorCode(c)
+				if !ok {
+					return
+				}
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+
+				result, err := userService.EnableTwoFactor(c.Request.Context(), userID, c.GetString(middleware.ContextKeySessionID), code)
+				if err != nil {
+					resp.Error(c, twoFactorErrorCode(err), err.Error())
+					return
+				}
+
+				resp.Success(c, result)
+			})
+
+			// POST /api/v1/users/2fa/disable - 关闭两步验证
+			authorized.POST("/2fa/disable", func(c *gin.Context) {
+				code, ok := bindTwoFactorCode(c)
+				if !ok {
+					return
+				}
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+
+				if err := userService.DisableTwoFactor(c.Request.Context(), userID, code); err != nil {
+					resp.Error(c, twoFactorErrorCode(err), err.Error())
+					return
+				}
+
+				resp.Success(c, nil)
+			})
+
+			// POST /api/v1/users/2fa/backup-codes - 重新生成备用码，旧备用码全部作废
+			authorized.POST("/2fa/backup-codes", func(c *gin.Context) {
+				code, ok := bindTwoFactorCode(c)
+				if !ok {
+					return
+				}
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+
+				codes, err := userService.RegenerateBackupCodes(c.Request.Context(), userID, code)
+				if err != nil {
+					resp.Error(c, twoFactorErrorCode(err), err.Error())
+					return
+				}
+
+				resp.Success(c, gin.H{"backupCodes": codes})
+			})
+
+			// POST /api/v1/users/2fa/verify - 二次验证（step-up），返回新的登录令牌
+			authorized.POST("/2fa/verify", func(c *gin.Context) {
+				code, ok := bindTwoFactorCode(c)
+				if !ok {
+					return
+				}
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+
+				token, err := userService.StepUpTwoFactor(c.Request.Context(), userID, c.GetString(middleware.ContextKeySessionID), code)
+				if err != nil {
+					resp.Error(c, twoFactorErrorCode(err), err.Error())
+					return
+				}
+
+				resp.Success(c, gin.H{"token": token})
+			})
+
+			// GET /api/v1/users/sessions - 已登录设备列表，当前设备标记 current
+			authorized.GET("/sessions", func(c *gin.Context) {
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+
+				sessions, err := userService.ListSessions(c.Request.Context(), userID, c.GetString(middleware.ContextKeySessionID))
+				if err != nil {
+					resp.Error(c, 500, "获取登录设备失败: "+err.Error())
+					return
+				}
+
+				resp.Success(c, sessions)
+			})
+
+			// DELETE /api/v1/users/sessions/:id - 移除指定设备，该设备需重新登录
+			authorized.DELETE("/sessions/:id", func(c *gin.Context) {
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+				sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+				if err != nil || sessionID <= 0 {
+					resp.Error(c, errors.CodeInvalidParams, "无效的设备ID")
+					return
+				}
+
+				if err := userService.RevokeSession(c.Request.Context(), userID, sessionID); err != nil {
+					if stderrors.Is(err, user.ErrSessionNotFound) {
+						resp.Error(c, 404, err.Error())
+						return
+					}
+					resp.Error(c, 500, "移除登录设备失败: "+err.Error())
+					return
+				}
+
+				resp.Success(c, nil)
+			})
+
+			// DELETE /api/v1/users/sessions - 移除当前设备以外的全部设备
+			authorized.DELETE("/sessions", func(c *gin.Context) {
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+
+				revoked, err := userService.RevokeOtherSessions(c.Request.Context(), userID, c.GetString(middleware.ContextKeySessionID))
+				if err != nil {
+					resp.Error(c, 500, "移除登录设备失败: "+err.Error())
+					return
+				}
+
+				resp.Success(c, gin.H{"revoked": revoked})
+			})
+
+			// GET /api/v1/users/login-history?limit=20 - 最近登录记录（含失败记录）
+			authorized.GET("/login-history", func(c *gin.Context) {
+				userID, ok := currentUserID(c)
+				if !ok {
+					return
+				}
+				limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+				if err != nil || limit < 1 {
+					resp.Error(c, errors.CodeInvalidParams, "无效的数量参数")
+					return
+				}
+
+				records, err := userService.ListLoginHistory(c.Request.Context(), userID, limit)
+				if err != nil {
+					resp.Error(c, 500, "获取登录记录失败: "+err.Error())
+					return
+				}
+
+				resp.Success(c, records)
+			})
 		}
 	}
 }
+
+// clientInfo 获取请求的客户端 IP 与 User-Agent，用于登录记录、登录防暴力破解与会话管理
+// ClientIP 只采信可信代理（TRUSTED_PROXIES，见 router.SetupRouter）转发的 X-Forwarded-For，客户端无法伪造
+func clientInfo(c *gin.Context) user.ClientInfo {
+	return user.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// bindTwoFactorCode 绑定请求体中的验证码（6 位动态验证码或备用码），失败时已写入错误响应
+func bindTwoFactorCode(c *gin.Context) (string, bool) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Error(c, errors.CodeInvalidParams, "请求参数错误: "+err.Error())
+		return "", false
+	}
+	return req.Code, true
+}
+
+// twoFactorErrorCode 将两步验证相关错误映射为错误码
+func twoFactorErrorCode(err error) int {
+	switch {
+	case stderrors.Is(err, user.ErrTwoFactorTooManyAttempts):
+		return errors.CodeTooManyRequests
+	case stderrors.Is(err, user.ErrInvalidTwoFactorChallenge):
+		return errors.CodeUnauthenticated
+	default:
+		return errors.CodeInvalidParams
+	}
+}
+
+// currentUserID 从上下文获取当前登录用户ID（由AuthMiddleware注入），失败时已写入错误响应
+func currentUserID(c *gin.Context) (uint, bool) {
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		resp.Error(c, errors.CodeUnauthenticated, "用户未登录")
+		return 0, false
+	}
+	userIDStr, ok := userIDInterface.(string)
+	if !ok {
+		resp.Error(c, errors.CodeInvalidParams, "用户ID格式错误")
+		return 0, false
+	}
+	userIDParsed, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		resp.Error(c, errors.CodeInvalidParams, "用户ID格式错误")
+		return 0, false
+	}
+	return uint(userIDParsed), true
+}
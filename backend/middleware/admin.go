@@ -7,7 +7,7 @@ import (
 )
 
 // AdminMiddleware 管理员权限中间件
-// 检查用户是否为管理员角色，否则拒绝访问
+// 检查用户是否为管理员角色，否则拒绝访问；启用两步验证检查时还要求令牌已通过两步验证
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 从上下文获取用户角色
@@ -18,14 +18,46 @@ func AdminMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 检查角色是否为管理员
-		if role != "admin" {
+		// 检查角色是否为管理员（超级管理员同样具备管理员权限）
+		if !IsAdminRole(role) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "权限不足，需要管理员权限"})
 			c.Abort()
 			return
 		}
 
+		// 管理员需通过两步验证
+		if !requireTwoFactor(c) {
+			c.Abort()
+			return
+		}
+
 		// 权限通过，继续处理请求
 		c.Next()
 	}
 }
+
+// SuperAdminMiddleware 超级管理员权限中间件
+// 用于学校管理等跨租户接口
+func SuperAdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "未登录或登录已过期"})
+			c.Abort()
+			return
+		}
+
+		if role != RoleSuperAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "权限不足，需要超级管理员权限"})
+			c.Abort()
+			return
+		}
+
+		if !requireTwoFactor(c) {
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"log"
 	"strconv"
 	"strings"
 
@@ -31,15 +32,43 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		userID, err := auth.ParseToken(token)
+		claims, err := auth.ParseTokenClaims(token)
 		if err != nil {
 			resp.Error(c, errors.CodeUnauthenticated, "登录已过期，请重新登录")
 			c.Abort()
 			return
 		}
+		revoked, err := checkTokenRevoked(c.Request.Context(), claims)
+		if err != nil {
+			// 无法确认令牌状态时拒绝请求（fail closed）
+			log.Printf("auth: verify token of user %d failed: %v", claims.UserID, err)
+			resp.Error(c, errors.CodeUnauthenticated, "登录状态校验失败，请稍后重试")
+			c.Abort()
+			return
+		}
+		if revoked {
+			resp.Error(c, errors.CodeUnauthenticated, "登录已过期，请重新登录")
+			c.Abort()
+			return
+		}
+		userID := claims.UserID
+
+		role, schoolID, err := resolveRole(c.Request.Context(), userID)
+		if err != nil {
+			resp.Error(c, errors.CodeUnauthenticated, "账号不存在，请重新登录")
+			c.Abort()
+			return
+		}
 
 		c.Set("user_id", strconv.FormatInt(userID, 10))
-		c.Set("role", "user")
+		c.Set("role", role)
+		if !claims.MFAAt.IsZero() {
+			c.Set(ContextKeyMFAAt, claims.MFAAt)
+		}
+		if claims.SessionID != "" {
+			c.Set(ContextKeySessionID, claims.SessionID)
+		}
+		bindUserTenant(c, role, schoolID)
 		c.Next()
 	}
 }
@@ -62,9 +91,19 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if userID, err := auth.ParseToken(token); err == nil {
-			c.Set("user_id", strconv.FormatInt(userID, 10))
-			c.Set("role", "user")
+		// 令牌无效、已作废或无法确认状态时按未登录处理
+		if claims, err := auth.ParseTokenClaims(token); err == nil {
+			revoked, err := checkTokenRevoked(c.Request.Context(), claims)
+			if err != nil {
+				log.Printf("auth: verify token of user %d failed: %v", claims.UserID, err)
+			}
+			if err == nil && !revoked {
+				if role, schoolID, err := resolveRole(c.Request.Context(), claims.UserID); err == nil {
+					c.Set("user_id", strconv.FormatInt(claims.UserID, 10))
+					c.Set("role", role)
+					bindUserTenant(c, role, schoolID)
+				}
+			}
 		}
 
 		c.Next()
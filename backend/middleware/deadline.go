@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportWriteTimeout 流式导出接口写出响应的超时，由 SetExportWriteTimeout 注入；为 0 时沿用服务器的 WriteTimeout
+var exportWriteTimeout time.Duration
+
+// SetExportWriteTimeout 设置流式导出接口写出响应的超时
+func SetExportWriteTimeout(timeout time.Duration) {
+	exportWriteTimeout = timeout
+}
+
+// ExportWriteDeadline 延长流式导出接口的写出期限
+//
+// 服务器级 WriteTimeout 从读完请求头开始计时，大批量 CSV/XLSX 或 ZIP 导出可能超过该时间，
+// 到期后连接被直接关闭，客户端只会得到一个截断的文件而没有任何错误提示。
+// 该中间件在处理前将本次请求的写出期限重置为 exportWriteTimeout 之后。
+func ExportWriteDeadline() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if exportWriteTimeout > 0 {
+			deadline := time.Now().Add(exportWriteTimeout)
+			if err := http.NewResponseController(c.Writer).SetWriteDeadline(deadline); err != nil {
+				log.Printf("export: extend write deadline failed: %v", err)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/errors"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+)
+
+// 限流分组，每个分组使用独立的配额与计数
+const (
+	RateLimitGlobal        = "global"         // 全部请求（按IP）
+	RateLimitAuth          = "auth"           // 登录、注册、找回密码
+	RateLimitUpload        = "upload"         // 图片上传
+	RateLimitProductCreate = "product_create" // 发布商品
+	RateLimitDataExport    = "data_export"    // 导出个人数据
+)
+
+// RateLimit 令牌桶配额：每 Period 补充 Limit 个令牌，桶容量为 Burst（为0时等于 Limit）
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+
+// capacity 桶容量
+func (l RateLimit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Limit)
+}
+
+// ratePerSecond 每秒补充的令牌数
+func (l RateLimit) ratePerSecond() float64 {
+	return float64(l.Limit) / l.Period.Seconds()
+}
+
+// RateLimitResult 一次取令牌的结果
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int           // 桶容量
+	Remaining  int           // 剩余令牌数
+	RetryAfter time.Duration // 被拒绝时距离下一个令牌的时间
+	ResetAfter time.Duration // 令牌补满所需时间
+}
+
+// RateLimitStore 令牌桶存储，Take 需保证同一 key 的并发调用原子执行
+// 默认实现保存在进程内存中，多实例部署时可替换为共享存储
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
+
+// rateLimitStore 与 rateLimits 由路由初始化时注入；未注入或分组未配置时不限流
+var (
+	rateLimitStore RateLimitStore
+	rateLimits     map[string]RateLimit
+)
+
+// SetRateLimiter 设置限流存储与各分组配额
+func SetRateLimiter(store RateLimitStore, limits map[string]RateLimit) {
+	rateLimitStore = store
+	rateLimits = limits
+}
+
+// RateLimitMiddleware 按分组限流
+//
+// 已登录请求（需放在 AuthMiddleware 之后）按用户ID计数，其余按客户端IP计数（仅信任 TRUSTED_PROXIES 转发的请求头）。
+// 响应头携带 X-RateLimit-Limit / X-RateLimit-Remaining / X-RateLimit-Reset（秒），
+// 超限时返回 HTTP 429 与 Retry-After（秒）。
+func RateLimitMiddleware(group string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, ok := rateLimits[group]
+		if rateLimitStore == nil || !ok || limit.Limit <= 0 || limit.Period <= 0 {
+			c.Next()
+			return
+		}
+
+		key := "ratelimit:" + group + ":ip:" + c.ClientIP()
+		if userID := c.GetString("user_id"); userID != "" {
+			key = "ratelimit:" + group + ":user:" + userID
+		}
+
+		result, err := rateLimitStore.Take(c.Request.Context(), key, limit)
+		if err != nil {
+			// 存储故障时放行，避免限流组件导致服务不可用
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			resp.ErrorWithStatus(c, http.StatusTooManyRequests, errors.CodeTooManyRequests,
+				fmt.Sprintf("请求过于频繁，请 %d 秒后再试", retryAfter),
+				gin.H{"retryAfterSeconds": retryAfter})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ParseRateLimit 解析配额配置，格式为 "次数/周期"，周期可为 s/m/h 或 Go 时长（如 10/30s），
+// 可选 ",burst=N" 指定桶容量，如 "300/m"、"20/h,burst=5"；空字符串或 "0" 表示不限流
+func ParseRateLimit(raw string) (RateLimit, bool, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "0" {
+		return RateLimit{}, false, nil
+	}
+
+	spec, burstPart, hasBurst := strings.Cut(raw, ",")
+	countPart, periodPart, ok := strings.Cut(spec, "/")
+	if !ok {
+		return RateLimit{}, false, fmt.Errorf("invalid rate limit %q: expected count/period", raw)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countPart))
+	if err != nil || count <= 0 {
+		return RateLimit{}, false, fmt.Errorf("invalid rate limit %q: count must be a positive integer", raw)
+	}
+
+	periodPart = strings.TrimSpace(periodPart)
+	switch periodPart {
+	case "s", "m", "h":
+		periodPart = "1" + periodPart
+	}
+	period, err := time.ParseDuration(periodPart)
+	if err != nil || period <= 0 {
+		return RateLimit{}, false, fmt.Errorf("invalid rate limit %q: bad period", raw)
+	}
+
+	limit := RateLimit{Limit: count, Period: period}
+	if hasBurst {
+		value, found := strings.CutPrefix(strings.TrimSpace(burstPart), "burst=")
+		burst, err := strconv.Atoi(value)
+		if !found || err != nil || burst <= 0 {
+			return RateLimit{}, false, fmt.Errorf("invalid rate limit %q: bad burst", raw)
+		}
+		limit.Burst = burst
+	}
+	return limit, true, nil
+}
+
+// ceilSeconds 向上取整到秒
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
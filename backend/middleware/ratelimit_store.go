@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cache"
+)
+
+// tokenBucket 令牌桶状态
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// memoryRateLimitStore 基于 cache.MemoryCache 的令牌桶存储
+// 桶在补满后自动过期，由缓存的后台清理回收
+type memoryRateLimitStore struct {
+	cache *cache.MemoryCache
+	mu    sync.Mutex
+}
+
+// NewMemoryRateLimitStore 创建内存令牌桶存储
+func NewMemoryRateLimitStore(memCache *cache.MemoryCache) RateLimitStore {
+	return &memoryRateLimitStore{cache: memCache}
+}
+
+// Take 实现 RateLimitStore
+func (s *memoryRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	capacity := limit.capacity()
+	rate := limit.ratePerSecond()
+
+	bucket := tokenBucket{tokens: capacity, updated: now}
+	if value, err := s.cache.Get(ctx, key); err == nil {
+		if existing, ok := value.(tokenBucket); ok {
+			bucket = existing
+			bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.updated).Seconds()*rate)
+			bucket.updated = now
+		}
+	}
+
+	result := RateLimitResult{Limit: int(capacity)}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - bucket.tokens) / rate)
+	}
+	result.Remaining = int(bucket.tokens)
+	result.ResetAfter = secondsToDuration((capacity - bucket.tokens) / rate)
+
+	if err := s.cache.Set(ctx, key, bucket, result.ResetAfter+time.Second); err != nil {
+		return RateLimitResult{}, err
+	}
+	return result, nil
+}
+
+// secondsToDuration 将秒数转换为时长
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+)
+
+// 用户角色
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+	// RoleSuperAdmin 超级管理员，可管理全部学校
+	RoleSuperAdmin = "super_admin"
+)
+
+// ErrUserNotFound 角色解析时用户不存在（账号已被删除）
+var ErrUserNotFound = errors.New("user not found")
+
+// RoleResolver 根据用户ID解析当前角色及所属学校
+// 每次请求实时解析，撤销管理员权限后无需等待token过期即可生效
+type RoleResolver interface {
+	ResolveRole(ctx context.Context, userID int64) (role string, schoolID int64, err error)
+}
+
+// roleResolver 全局角色解析器，由路由初始化时注入；未注入时所有用户视为普通用户
+var roleResolver RoleResolver
+
+// SetRoleResolver 设置角色解析器
+func SetRoleResolver(resolver RoleResolver) {
+	roleResolver = resolver
+}
+
+// IsAdminRole 判断角色是否具备管理员权限（含超级管理员）
+func IsAdminRole(role interface{}) bool {
+	return role == RoleAdmin || role == RoleSuperAdmin
+}
+
+// resolveRole 解析用户角色与所属学校，解析失败时降级为普通用户（学校ID为0表示未知）
+func resolveRole(ctx context.Context, userID int64) (string, int64, error) {
+	if roleResolver == nil {
+		return RoleUser, 0, nil
+	}
+	role, schoolID, err := roleResolver.ResolveRole(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return "", 0, err
+		}
+		return RoleUser, 0, nil
+	}
+	return role, schoolID, nil
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/auth"
+)
+
+// TokenRevocationChecker 判断登录令牌是否已被作废（如重置密码后作废此前签发的全部令牌）
+type TokenRevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, userID int64, issuedAt time.Time) (bool, error)
+}
+
+// tokenRevocationChecker 全局令牌作废检查器，由路由初始化时注入；未注入时不做检查
+var tokenRevocationChecker TokenRevocationChecker
+
+// SetTokenRevocationChecker 设置令牌作废检查器
+func SetTokenRevocationChecker(checker TokenRevocationChecker) {
+	tokenRevocationChecker = checker
+}
+
+// ContextKeySessionID gin 上下文中保存当前登录会话标识的键
+const ContextKeySessionID = "session_id"
+
+// SessionValidator 判断登录会话是否仍然有效（用户移除设备或重置密码后会话被删除）
+type SessionValidator interface {
+	IsSessionActive(ctx context.Context, userID int64, sessionID string) (bool, error)
+}
+
+// sessionValidator 全局会话校验器，由路由初始化时注入；未注入时不校验会话
+var sessionValidator SessionValidator
+
+// SetSessionValidator 设置会话校验器
+func SetSessionValidator(validator SessionValidator) {
+	sessionValidator = validator
+}
+
+// isSessionRevoked 判断令牌绑定的会话是否已被移除，未绑定会话的令牌（旧版令牌）不拦截
+func isSessionRevoked(ctx context.Context, userID int64, sessionID string) (bool, error) {
+	if sessionValidator == nil || sessionID == "" {
+		return false, nil
+	}
+	active, err := sessionValidator.IsSessionActive(ctx, userID, sessionID)
+	if err != nil {
+		return false, err
+	}
+	return !active, nil
+}
+
+// isTokenRevoked 判断令牌是否已作废
+func isTokenRevoked(ctx context.Context, userID int64, issuedAt time.Time) (bool, error) {
+	if tokenRevocationChecker == nil {
+		return false, nil
+	}
+	return tokenRevocationChecker.IsTokenRevoked(ctx, userID, issuedAt)
+}
+
+// checkTokenRevoked 判断令牌是否已作废或其会话已被移除
+// 检查失败（如数据库不可用）时返回错误，调用方必须拒绝该令牌：无法确认时放行会让已作废的令牌在故障期间重新生效
+func checkTokenRevoked(ctx context.Context, claims *auth.TokenClaims) (bool, error) {
+	revoked, err := isTokenRevoked(ctx, claims.UserID, claims.IssuedAt)
+	if err != nil {
+		return false, fmt.Errorf("check token revocation: %w", err)
+	}
+	if revoked {
+		return true, nil
+	}
+	revoked, err = isSessionRevoked(ctx, claims.UserID, claims.SessionID)
+	if err != nil {
+		return false, fmt.Errorf("check session: %w", err)
+	}
+	return revoked, nil
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/yycy134679/school-secondhand-trading-system/backend/common/errors"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
+)
+
+// ErrSchoolNotFound 学校编码不存在
+var ErrSchoolNotFound = errors.New("school not found")
+
+// SchoolResolver 根据学校编码解析学校ID与启用状态
+type SchoolResolver interface {
+	ResolveSchool(ctx context.Context, code string) (schoolID int64, active bool, err error)
+}
+
+// schoolResolver 全局学校解析器，由路由初始化时注入；未注入时所有请求使用默认学校
+var schoolResolver SchoolResolver
+
+// SetSchoolResolver 设置学校解析器
+func SetSchoolResolver(resolver SchoolResolver) {
+	schoolResolver = resolver
+}
+
+// TenantMiddleware 解析请求所属的学校并写入 context
+//
+// 解析顺序：
+//   - X-School-Code 请求头
+//   - 请求域名为 baseDomain 的子域名时，取子域名作为学校编码（www 除外）
+//   - 以上均未指定时使用默认学校
+//
+// 登录用户的学校最终以账号所属学校为准（超级管理员除外），见 AuthMiddleware
+func TenantMiddleware(baseDomain string) gin.HandlerFunc {
+	baseDomain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(baseDomain), "."))
+	return func(c *gin.Context) {
+		code := strings.ToLower(strings.TrimSpace(c.GetHeader(tenant.HeaderSchoolCode)))
+		if code == "" {
+			code = subdomainOf(c.Request.Host, baseDomain)
+		}
+
+		schoolID := tenant.DefaultSchoolID
+		if code != "" && schoolResolver != nil {
+			id, active, err := schoolResolver.ResolveSchool(c.Request.Context(), code)
+			if err != nil {
+				if errors.Is(err, ErrSchoolNotFound) {
+					resp.Error(c, apperrors.CodeInvalidParams, "学校不存在")
+				} else {
+					resp.Error(c, 500, "解析学校失败: "+err.Error())
+				}
+				c.Abort()
+				return
+			}
+			if !active {
+				resp.Error(c, apperrors.CodeForbidden, "该学校已停用")
+				c.Abort()
+				return
+			}
+			schoolID = id
+		}
+
+		setTenant(c, schoolID)
+		c.Next()
+	}
+}
+
+// bindUserTenant 将请求的学校切换为登录用户所属学校
+// 超级管理员可管理全部学校，保留请求头或子域名指定的学校
+func bindUserTenant(c *gin.Context, role string, schoolID int64) {
+	if role == RoleSuperAdmin || schoolID <= 0 {
+		return
+	}
+	setTenant(c, schoolID)
+}
+
+// setTenant 将学校ID写入请求 context 与 gin 上下文
+func setTenant(c *gin.Context, schoolID int64) {
+	c.Request = c.Request.WithContext(tenant.WithSchoolID(c.Request.Context(), schoolID))
+	c.Set("school_id", schoolID)
+}
+
+// subdomainOf 返回 host 在 baseDomain 下的一级子域名，不匹配时返回空字符串
+func subdomainOf(host, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	suffix := "." + baseDomain
+	if !strings.HasSuffix(host, suffix) {
+		return ""
+	}
+	sub := strings.TrimSuffix(host, suffix)
+	if sub == "" || sub == "www" || strings.Contains(sub, ".") {
+		return ""
+	}
+	return sub
+}
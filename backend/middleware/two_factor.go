@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/errors"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/resp"
+)
+
+// ContextKeyMFAAt gin 上下文中保存令牌两步验证时间（time.Time）的键
+const ContextKeyMFAAt = "mfa_at"
+
+// TwoFactorChecker 判断用户是否已启用两步验证
+type TwoFactorChecker interface {
+	IsTwoFactorEnabled(ctx context.Context, userID int64) (bool, error)
+}
+
+var (
+	// twoFactorChecker 全局两步验证检查器，由路由初始化时注入；未注入时管理后台不要求两步验证
+	twoFactorChecker TwoFactorChecker
+	// twoFactorStepUpTTL 两步验证的有效期，超过后访问管理后台需重新验证
+	twoFactorStepUpTTL time.Duration
+)
+
+// SetTwoFactorChecker 设置两步验证检查器与二次验证有效期（<= 0 表示在令牌有效期内一直有效）
+func SetTwoFactorChecker(checker TwoFactorChecker, stepUpTTL time.Duration) {
+	twoFactorChecker = checker
+	twoFactorStepUpTTL = stepUpTTL
+}
+
+// requireTwoFactor 要求管理员已启用两步验证且当前令牌在有效期内通过了两步验证
+// 校验失败时写入错误响应并返回 false
+func requireTwoFactor(c *gin.Context) bool {
+	if twoFactorChecker == nil {
+		return true
+	}
+
+	userID, err := strconv.ParseInt(c.GetString("user_id"), 10, 64)
+	if err != nil {
+		resp.Error(c, errors.CodeUnauthenticated, "请先登录")
+		return false
+	}
+
+	enabled, err := twoFactorChecker.IsTwoFactorEnabled(c.Request.Context(), userID)
+	if err != nil {
+		resp.Error(c, 500, "校验两步验证状态失败: "+err.Error())
+		return false
+	}
+	if !enabled {
+		resp.Error(c, errors.CodeTwoFactorSetupRequired, "管理员账号需先启用两步验证")
+		return false
+	}
+
+	mfaAt, ok := c.Get(ContextKeyMFAAt)
+	verifiedAt, _ := mfaAt.(time.Time)
+	if !ok || verifiedAt.IsZero() || (twoFactorStepUpTTL > 0 && time.Since(verifiedAt) > twoFactorStepUpTTL) {
+		resp.Error(c, errors.CodeTwoFactorRequired, "请完成两步验证后再访问管理后台")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,21 @@
+-- 回滚初始数据库结构：删除全部表、触发器函数与枚举类型（数据将丢失）
+-- pg_trgm 扩展可能被其他数据库对象使用，不在此删除
+
+DROP TABLE IF EXISTS "public"."users" CASCADE;
+DROP TABLE IF EXISTS "public"."user_recent_views" CASCADE;
+DROP TABLE IF EXISTS "public"."test_users" CASCADE;
+DROP TABLE IF EXISTS "public"."test_products" CASCADE;
+DROP TABLE IF EXISTS "public"."tags" CASCADE;
+DROP TABLE IF EXISTS "public"."simple_users" CASCADE;
+DROP TABLE IF EXISTS "public"."sessions" CASCADE;
+DROP TABLE IF EXISTS "public"."products" CASCADE;
+DROP TABLE IF EXISTS "public"."product_tags" CASCADE;
+DROP TABLE IF EXISTS "public"."product_images" CASCADE;
+DROP TABLE IF EXISTS "public"."product_conditions" CASCADE;
+DROP TABLE IF EXISTS "public"."categories" CASCADE;
+
+DROP FUNCTION IF EXISTS "public"."trg_set_updated_at"();
+DROP FUNCTION IF EXISTS "public"."trg_prune_user_recent_views"();
+DROP FUNCTION IF EXISTS "public"."trg_products_status_guard"();
+
+DROP TYPE IF EXISTS "public"."product_status";
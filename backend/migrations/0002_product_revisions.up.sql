@@ -0,0 +1,23 @@
+-- 新增商品修订历史表 product_revisions
+
+CREATE TABLE "public"."product_revisions" (
+  "id" bigserial NOT NULL,
+  "product_id" int8 NOT NULL,
+  "version" int4 NOT NULL,
+  "editor_id" int8 NOT NULL,
+  "reason" varchar(16) COLLATE "pg_catalog"."default" NOT NULL,
+  "title" varchar(100) COLLATE "pg_catalog"."default" NOT NULL,
+  "description" text COLLATE "pg_catalog"."default" NOT NULL,
+  "price" numeric(10,2) NOT NULL,
+  "category_id" int8 NOT NULL,
+  "condition_id" int2 NOT NULL,
+  "image_urls" jsonb NOT NULL DEFAULT '[]'::jsonb,
+  "tag_ids" jsonb NOT NULL DEFAULT '[]'::jsonb,
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "product_revisions_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "product_revisions_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "public"."products" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."product_revisions"."reason" IS '修订原因：create/initial/edit/admin_edit/revert';
+COMMENT ON TABLE "public"."product_revisions" IS '商品修订历史：每次编辑保存一份完整快照（版本号按商品递增），用于差异对比、管理员回滚与价格历史。';
+
+CREATE UNIQUE INDEX "uq_product_revisions_version" ON "public"."product_revisions" USING btree ("product_id", "version");
@@ -0,0 +1 @@
+ALTER TABLE "public"."products" DROP COLUMN IF EXISTS "sold_at";
@@ -0,0 +1,4 @@
+-- 商品表新增成交时间 sold_at
+
+ALTER TABLE "public"."products" ADD COLUMN "sold_at" timestamptz(6);
+COMMENT ON COLUMN "public"."products"."sold_at" IS '成交时间：状态变为 Sold 时写入，用于统计成交周期；历史数据为空时以 updated_at 近似。';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "public"."admin_audit_logs";
@@ -0,0 +1,19 @@
+-- 新增管理员操作审计日志表 admin_audit_logs
+
+CREATE TABLE "public"."admin_audit_logs" (
+  "id" bigserial NOT NULL,
+  "admin_id" int8 NOT NULL,
+  "action" varchar(32) COLLATE "pg_catalog"."default" NOT NULL,
+  "target_type" varchar(32) COLLATE "pg_catalog"."default" NOT NULL,
+  "target_id" int8 NOT NULL,
+  "detail" jsonb NOT NULL DEFAULT '{}'::jsonb,
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "admin_audit_logs_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "admin_audit_logs_admin_id_fkey" FOREIGN KEY ("admin_id") REFERENCES "public"."users" ("id") ON DELETE NO ACTION ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."admin_audit_logs"."action" IS '操作类型，如 product.delist / product.delete / user.grant_admin。';
+COMMENT ON COLUMN "public"."admin_audit_logs"."target_id" IS '被操作对象 ID；对象被删除后日志仍保留，因此不设外键。';
+COMMENT ON TABLE "public"."admin_audit_logs" IS '管理员操作审计日志：每个受影响对象一条记录，与变更在同一事务内写入。';
+
+CREATE INDEX "idx_admin_audit_logs_admin" ON "public"."admin_audit_logs" USING btree ("admin_id", "created_at" DESC);
+CREATE INDEX "idx_admin_audit_logs_target" ON "public"."admin_audit_logs" USING btree ("target_type", "target_id");
@@ -0,0 +1,3 @@
+ALTER TABLE "public"."users"
+  DROP COLUMN IF EXISTS "last_login_at",
+  DROP COLUMN IF EXISTS "must_reset_password";
@@ -0,0 +1,7 @@
+-- 用户表新增强制重置密码标记与最近登录时间
+
+ALTER TABLE "public"."users"
+  ADD COLUMN "must_reset_password" bool NOT NULL DEFAULT false,
+  ADD COLUMN "last_login_at" timestamptz(6);
+COMMENT ON COLUMN "public"."users"."must_reset_password" IS '管理员强制重置密码后置为 true，用户修改密码后清除。';
+COMMENT ON COLUMN "public"."users"."last_login_at" IS '最近一次成功登录时间。';
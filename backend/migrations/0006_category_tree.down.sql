@@ -0,0 +1,10 @@
+-- 回滚为单级分类：子分类会变成同名的顶级分类，存在重名时恢复全局唯一约束将失败
+
+DROP INDEX IF EXISTS "public"."uq_categories_parent_name";
+DROP INDEX IF EXISTS "public"."idx_categories_parent";
+ALTER TABLE "public"."categories" DROP CONSTRAINT IF EXISTS "categories_parent_id_fkey";
+ALTER TABLE "public"."categories"
+  DROP COLUMN IF EXISTS "sort_order",
+  DROP COLUMN IF EXISTS "parent_id";
+ALTER TABLE "public"."categories" ADD CONSTRAINT "categories_name_key" UNIQUE ("name");
+COMMENT ON TABLE "public"."categories" IS '商品分类（由管理员维护）。删除被引用的分类将因外键而失败。';
@@ -0,0 +1,14 @@
+-- 分类支持多级嵌套：新增 parent_id、sort_order，分类名称改为同级唯一
+
+ALTER TABLE "public"."categories"
+  ADD COLUMN "parent_id" int8,
+  ADD COLUMN "sort_order" int4 NOT NULL DEFAULT 0;
+COMMENT ON COLUMN "public"."categories"."parent_id" IS '父分类ID，为空表示顶级分类';
+COMMENT ON COLUMN "public"."categories"."sort_order" IS '同级分类排序值，升序排列';
+COMMENT ON TABLE "public"."categories" IS '商品分类（由管理员维护，支持多级嵌套）。删除被引用或仍有子分类的分类将因外键而失败。';
+
+ALTER TABLE "public"."categories" DROP CONSTRAINT IF EXISTS "categories_name_key";
+ALTER TABLE "public"."categories" ADD CONSTRAINT "categories_parent_id_fkey" FOREIGN KEY ("parent_id") REFERENCES "public"."categories" ("id") ON DELETE RESTRICT ON UPDATE NO ACTION;
+
+CREATE INDEX "idx_categories_parent" ON "public"."categories" USING btree ("parent_id", "sort_order");
+CREATE UNIQUE INDEX "uq_categories_parent_name" ON "public"."categories" USING btree (COALESCE(parent_id, 0::bigint), "name");
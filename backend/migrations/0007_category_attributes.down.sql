@@ -0,0 +1,3 @@
+ALTER TABLE "public"."product_revisions" DROP COLUMN IF EXISTS "attributes";
+ALTER TABLE "public"."products" DROP COLUMN IF EXISTS "attributes";
+DROP TABLE IF EXISTS "public"."category_attributes";
@@ -0,0 +1,33 @@
+-- 新增分类属性定义表 category_attributes，商品与修订记录新增 attributes 属性值
+
+CREATE TABLE "public"."category_attributes" (
+  "id" bigserial NOT NULL,
+  "category_id" int8 NOT NULL,
+  "attr_key" varchar(32) COLLATE "pg_catalog"."default" NOT NULL,
+  "label" varchar(50) COLLATE "pg_catalog"."default" NOT NULL,
+  "value_type" varchar(16) COLLATE "pg_catalog"."default" NOT NULL,
+  "required" bool NOT NULL DEFAULT false,
+  "options" jsonb NOT NULL DEFAULT '[]'::jsonb,
+  "unit" varchar(16) COLLATE "pg_catalog"."default",
+  "sort_order" int4 NOT NULL DEFAULT 0,
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  "updated_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "category_attributes_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "category_attributes_category_id_fkey" FOREIGN KEY ("category_id") REFERENCES "public"."categories" ("id") ON DELETE CASCADE ON UPDATE NO ACTION,
+  CONSTRAINT "category_attributes_value_type_check" CHECK (value_type::text = ANY (ARRAY['string'::character varying, 'number'::character varying, 'enum'::character varying, 'boolean'::character varying]::text[]))
+);
+COMMENT ON COLUMN "public"."category_attributes"."attr_key" IS '属性键，商品 attributes 中的键名，创建后不可修改。';
+COMMENT ON COLUMN "public"."category_attributes"."value_type" IS '取值类型：string / number / enum / boolean，创建后不可修改。';
+COMMENT ON COLUMN "public"."category_attributes"."options" IS '枚举可选值（JSON 数组），仅 enum 类型使用。';
+COMMENT ON TABLE "public"."category_attributes" IS '分类属性定义：子分类继承祖先分类的属性，同名属性以最近的分类为准。';
+
+CREATE UNIQUE INDEX "uq_category_attributes_key" ON "public"."category_attributes" USING btree ("category_id", "attr_key");
+
+CREATE TRIGGER "category_attributes_set_updated_at" BEFORE UPDATE ON "public"."category_attributes"
+FOR EACH ROW
+EXECUTE PROCEDURE "public"."trg_set_updated_at"();
+
+ALTER TABLE "public"."products" ADD COLUMN "attributes" jsonb NOT NULL DEFAULT '{}'::jsonb;
+COMMENT ON COLUMN "public"."products"."attributes" IS '分类属性值（JSON 对象，键为 category_attributes.attr_key），发布/编辑时按分类属性定义校验。';
+
+ALTER TABLE "public"."product_revisions" ADD COLUMN "attributes" jsonb NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "public"."idx_products_isbn";
+ALTER TABLE "public"."products" DROP COLUMN IF EXISTS "isbn";
+DROP TABLE IF EXISTS "public"."user_courses";
+DROP TABLE IF EXISTS "public"."book_courses";
+DROP TABLE IF EXISTS "public"."books";
@@ -0,0 +1,44 @@
+-- 新增图书目录 books、图书课程关联 book_courses、用户课程 user_courses，商品表新增 isbn
+
+CREATE TABLE "public"."books" (
+  "id" bigserial NOT NULL,
+  "isbn" varchar(13) COLLATE "pg_catalog"."default" NOT NULL,
+  "title" varchar(200) COLLATE "pg_catalog"."default" NOT NULL,
+  "author" varchar(200) COLLATE "pg_catalog"."default",
+  "edition" varchar(50) COLLATE "pg_catalog"."default",
+  "publisher" varchar(100) COLLATE "pg_catalog"."default",
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  "updated_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "books_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "books_isbn_key" UNIQUE ("isbn")
+);
+COMMENT ON COLUMN "public"."books"."isbn" IS '规范化的 ISBN-13（不含分隔符，ISBN-10 导入时转换为 978 前缀）。';
+COMMENT ON TABLE "public"."books" IS '本地图书目录：由管理员通过 CSV 导入，发布教材时按 ISBN 自动填充书名、作者与版次。';
+
+CREATE TRIGGER "books_set_updated_at" BEFORE UPDATE ON "public"."books"
+FOR EACH ROW
+EXECUTE PROCEDURE "public"."trg_set_updated_at"();
+
+CREATE TABLE "public"."book_courses" (
+  "book_id" int8 NOT NULL,
+  "course_code" varchar(32) COLLATE "pg_catalog"."default" NOT NULL,
+  CONSTRAINT "book_courses_pkey" PRIMARY KEY ("book_id", "course_code"),
+  CONSTRAINT "book_courses_book_id_fkey" FOREIGN KEY ("book_id") REFERENCES "public"."books" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."book_courses"."course_code" IS '规范化的课程代码（大写、无空格），如 CS101。';
+COMMENT ON TABLE "public"."book_courses" IS '图书与课程的多对多关联：搜索课程代码可找到对应教材。';
+
+CREATE INDEX "idx_book_courses_course" ON "public"."book_courses" USING btree ("course_code");
+
+CREATE TABLE "public"."user_courses" (
+  "user_id" int8 NOT NULL,
+  "course_code" varchar(32) COLLATE "pg_catalog"."default" NOT NULL,
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "user_courses_pkey" PRIMARY KEY ("user_id", "course_code"),
+  CONSTRAINT "user_courses_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+COMMENT ON TABLE "public"."user_courses" IS '用户选修的课程，用于教材推荐。';
+
+ALTER TABLE "public"."products" ADD COLUMN "isbn" varchar(13) COLLATE "pg_catalog"."default";
+COMMENT ON COLUMN "public"."products"."isbn" IS '教材 ISBN-13（可选），与 books.isbn 对应；不设外键，目录中没有的书也可发布。';
+CREATE INDEX "idx_products_isbn" ON "public"."products" USING btree ("isbn") WHERE isbn IS NOT NULL;
@@ -0,0 +1,3 @@
+COMMENT ON COLUMN "public"."tags"."category_id" IS NULL;
+DROP TABLE IF EXISTS "public"."tag_categories";
+DROP TABLE IF EXISTS "public"."tag_aliases";
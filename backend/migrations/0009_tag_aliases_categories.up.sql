@@ -0,0 +1,32 @@
+-- 新增标签同义词表 tag_aliases 与标签多分类关联表 tag_categories
+
+CREATE TABLE "public"."tag_aliases" (
+  "id" bigserial NOT NULL,
+  "tag_id" int8 NOT NULL,
+  "alias" varchar(50) COLLATE "pg_catalog"."default" NOT NULL,
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "tag_aliases_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "tag_aliases_tag_id_fkey" FOREIGN KEY ("tag_id") REFERENCES "public"."tags" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."tag_aliases"."alias" IS '同义词/别名，不区分大小写全局唯一，且不得与任何标签名称相同（由应用层校验）。';
+COMMENT ON TABLE "public"."tag_aliases" IS '标签同义词：搜索时关键词命中别名等同于命中标签；合并标签时被合并标签的名称会成为目标标签的别名。';
+
+CREATE INDEX "idx_tag_aliases_tag" ON "public"."tag_aliases" USING btree ("tag_id");
+CREATE UNIQUE INDEX "uq_tag_aliases_alias" ON "public"."tag_aliases" USING btree (lower(alias::text));
+
+CREATE TABLE "public"."tag_categories" (
+  "tag_id" int8 NOT NULL,
+  "category_id" int8 NOT NULL,
+  CONSTRAINT "tag_categories_pkey" PRIMARY KEY ("tag_id", "category_id"),
+  CONSTRAINT "tag_categories_category_id_fkey" FOREIGN KEY ("category_id") REFERENCES "public"."categories" ("id") ON DELETE CASCADE ON UPDATE NO ACTION,
+  CONSTRAINT "tag_categories_tag_id_fkey" FOREIGN KEY ("tag_id") REFERENCES "public"."tags" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+COMMENT ON TABLE "public"."tag_categories" IS '标签与分类的多对多关联表，标签可用于多个分类（含 tags.category_id 主分类）。';
+
+CREATE INDEX "idx_tag_categories_category" ON "public"."tag_categories" USING btree ("category_id");
+
+-- 现有标签的主分类写入关联表
+INSERT INTO "public"."tag_categories" ("tag_id", "category_id")
+SELECT "id", "category_id" FROM "public"."tags";
+
+COMMENT ON COLUMN "public"."tags"."category_id" IS '主分类；标签适用的全部分类见 tag_categories（包含主分类）。';
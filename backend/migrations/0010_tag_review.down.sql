@@ -0,0 +1,9 @@
+-- 回滚后待审核与已驳回的标签会变为普通标签，如需保留请先清理
+
+DROP INDEX IF EXISTS "public"."idx_tags_status";
+ALTER TABLE "public"."tags"
+  DROP CONSTRAINT IF EXISTS "tags_created_by_fkey",
+  DROP CONSTRAINT IF EXISTS "tags_status_check",
+  DROP COLUMN IF EXISTS "created_by",
+  DROP COLUMN IF EXISTS "status";
+COMMENT ON TABLE "public"."tags" IS '商品标签库（由管理员维护，商品可多选标签）。';
@@ -0,0 +1,13 @@
+-- 标签表新增审核状态 status 与提议人 created_by：已有标签均视为已通过
+
+ALTER TABLE "public"."tags"
+  ADD COLUMN "status" varchar(16) COLLATE "pg_catalog"."default" NOT NULL DEFAULT 'Approved'::character varying,
+  ADD COLUMN "created_by" int8;
+COMMENT ON COLUMN "public"."tags"."status" IS '审核状态：Approved(已通过) / Pending(卖家提议待审核) / Rejected(已驳回，同名提议将被忽略)。仅 Approved 标签对外展示并参与搜索。';
+COMMENT ON COLUMN "public"."tags"."created_by" IS '提议该标签的卖家 ID，管理员创建的标签为空。';
+COMMENT ON TABLE "public"."tags" IS '商品标签库（由管理员维护，卖家可在发布商品时提议新标签，经审核后生效；商品可多选标签）。';
+
+ALTER TABLE "public"."tags" ADD CONSTRAINT "tags_status_check" CHECK (status::text = ANY (ARRAY['Approved'::character varying, 'Pending'::character varying, 'Rejected'::character varying]::text[]));
+ALTER TABLE "public"."tags" ADD CONSTRAINT "tags_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "public"."users" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
+
+CREATE INDEX "idx_tags_status" ON "public"."tags" USING btree ("status");
@@ -0,0 +1,5 @@
+ALTER TABLE "public"."users" DROP COLUMN IF EXISTS "default_zone_id";
+DROP INDEX IF EXISTS "public"."idx_products_meetup_point";
+ALTER TABLE "public"."products" DROP COLUMN IF EXISTS "meetup_point_id";
+DROP TABLE IF EXISTS "public"."meetup_points";
+DROP TABLE IF EXISTS "public"."campus_zones";
@@ -0,0 +1,47 @@
+-- 新增校园片区 campus_zones 与面交地点 meetup_points，商品新增面交地点，用户新增默认片区
+
+CREATE TABLE "public"."campus_zones" (
+  "id" bigserial NOT NULL,
+  "name" varchar(50) COLLATE "pg_catalog"."default" NOT NULL,
+  "description" varchar(255) COLLATE "pg_catalog"."default",
+  "sort_order" int4 NOT NULL DEFAULT 0,
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  "updated_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "campus_zones_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "campus_zones_name_key" UNIQUE ("name")
+);
+COMMENT ON COLUMN "public"."campus_zones"."name" IS '校区片区名称（如 北区宿舍、南区宿舍、图书馆）。';
+COMMENT ON TABLE "public"."campus_zones" IS '校园片区（管理员维护），用于按片区筛选商品与首页就近推荐。';
+
+CREATE TRIGGER "campus_zones_set_updated_at" BEFORE UPDATE ON "public"."campus_zones"
+FOR EACH ROW
+EXECUTE PROCEDURE "public"."trg_set_updated_at"();
+
+CREATE TABLE "public"."meetup_points" (
+  "id" bigserial NOT NULL,
+  "zone_id" int8 NOT NULL,
+  "name" varchar(50) COLLATE "pg_catalog"."default" NOT NULL,
+  "description" varchar(255) COLLATE "pg_catalog"."default",
+  "sort_order" int4 NOT NULL DEFAULT 0,
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  "updated_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "meetup_points_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "meetup_points_zone_id_fkey" FOREIGN KEY ("zone_id") REFERENCES "public"."campus_zones" ("id") ON DELETE RESTRICT ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."meetup_points"."zone_id" IS '所属校园片区；片区下存在交易地点时不可删除片区。';
+COMMENT ON TABLE "public"."meetup_points" IS '面交地点（管理员维护），商品可指定一个面交地点作为取货位置。';
+
+CREATE UNIQUE INDEX "uq_meetup_points_zone_name" ON "public"."meetup_points" USING btree ("zone_id", "name");
+
+CREATE TRIGGER "meetup_points_set_updated_at" BEFORE UPDATE ON "public"."meetup_points"
+FOR EACH ROW
+EXECUTE PROCEDURE "public"."trg_set_updated_at"();
+
+ALTER TABLE "public"."products" ADD COLUMN "meetup_point_id" int8;
+COMMENT ON COLUMN "public"."products"."meetup_point_id" IS '面交地点（可选），所属片区用于按片区筛选与首页就近推荐；地点删除时置空。';
+ALTER TABLE "public"."products" ADD CONSTRAINT "products_meetup_point_id_fkey" FOREIGN KEY ("meetup_point_id") REFERENCES "public"."meetup_points" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
+CREATE INDEX "idx_products_meetup_point" ON "public"."products" USING btree ("meetup_point_id") WHERE meetup_point_id IS NOT NULL;
+
+ALTER TABLE "public"."users" ADD COLUMN "default_zone_id" int8;
+COMMENT ON COLUMN "public"."users"."default_zone_id" IS '默认校园片区，首页优先展示该片区的商品；片区删除时置空。';
+ALTER TABLE "public"."users" ADD CONSTRAINT "users_default_zone_id_fkey" FOREIGN KEY ("default_zone_id") REFERENCES "public"."campus_zones" ("id") ON DELETE SET NULL ON UPDATE NO ACTION;
@@ -0,0 +1,25 @@
+-- 回滚为单学校：仅当全部数据属于同一学校且名称、别名不冲突时才能成功
+
+DROP INDEX IF EXISTS "public"."idx_users_school";
+ALTER TABLE "public"."users"
+  DROP COLUMN IF EXISTS "is_super_admin",
+  DROP COLUMN IF EXISTS "school_id";
+COMMENT ON COLUMN "public"."users"."is_admin" IS '是否管理员。';
+
+DROP INDEX IF EXISTS "public"."uq_tag_aliases_tag_alias";
+DROP INDEX IF EXISTS "public"."idx_tag_aliases_alias";
+CREATE UNIQUE INDEX "uq_tag_aliases_alias" ON "public"."tag_aliases" USING btree (lower(alias::text));
+
+DROP INDEX IF EXISTS "public"."idx_tags_school_status";
+ALTER TABLE "public"."tags" DROP CONSTRAINT IF EXISTS "tags_school_name_key";
+ALTER TABLE "public"."tags" DROP COLUMN IF EXISTS "school_id";
+ALTER TABLE "public"."tags" ADD CONSTRAINT "tags_name_key" UNIQUE ("name");
+
+DROP INDEX IF EXISTS "public"."idx_products_school_status_created";
+ALTER TABLE "public"."products" DROP COLUMN IF EXISTS "school_id";
+
+DROP INDEX IF EXISTS "public"."uq_categories_parent_name";
+ALTER TABLE "public"."categories" DROP COLUMN IF EXISTS "school_id";
+CREATE UNIQUE INDEX "uq_categories_parent_name" ON "public"."categories" USING btree (COALESCE(parent_id, 0::bigint), "name");
+
+DROP TABLE IF EXISTS "public"."schools";
@@ -0,0 +1,58 @@
+-- 新增学校表 schools，用户、商品、分类与标签增加所属学校：现有数据归入默认学校（id = 1）
+
+CREATE TABLE "public"."schools" (
+  "id" bigserial NOT NULL,
+  "code" varchar(32) COLLATE "pg_catalog"."default" NOT NULL,
+  "name" varchar(100) COLLATE "pg_catalog"."default" NOT NULL,
+  "is_active" bool NOT NULL DEFAULT true,
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  "updated_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "schools_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "schools_code_key" UNIQUE ("code"),
+  CONSTRAINT "schools_code_check" CHECK (code::text ~ '^[a-z0-9][a-z0-9-]{0,31}$'::text)
+);
+COMMENT ON COLUMN "public"."schools"."code" IS '学校编码（小写字母、数字和连字符），同时作为子域名与 X-School-Code 请求头的取值。';
+COMMENT ON COLUMN "public"."schools"."is_active" IS '是否启用；停用后该学校的请求将被拒绝。';
+COMMENT ON TABLE "public"."schools" IS '学校/校区租户：用户、商品、分类与标签均归属于某个学校，由超级管理员维护。';
+
+CREATE TRIGGER "schools_set_updated_at" BEFORE UPDATE ON "public"."schools"
+FOR EACH ROW
+EXECUTE PROCEDURE "public"."trg_set_updated_at"();
+
+INSERT INTO "public"."schools" ("id", "code", "name") VALUES (1, 'default', '默认学校');
+SELECT setval('"public"."schools_id_seq"', 1, true);
+
+-- 分类：名称改为学校内同级唯一
+ALTER TABLE "public"."categories" ADD COLUMN "school_id" int8 NOT NULL DEFAULT 1;
+COMMENT ON COLUMN "public"."categories"."school_id" IS '所属学校，父子分类属于同一学校';
+ALTER TABLE "public"."categories" ADD CONSTRAINT "categories_school_id_fkey" FOREIGN KEY ("school_id") REFERENCES "public"."schools" ("id") ON DELETE RESTRICT ON UPDATE NO ACTION;
+DROP INDEX IF EXISTS "public"."uq_categories_parent_name";
+CREATE UNIQUE INDEX "uq_categories_parent_name" ON "public"."categories" USING btree ("school_id", COALESCE(parent_id, 0::bigint), "name");
+
+-- 商品
+ALTER TABLE "public"."products" ADD COLUMN "school_id" int8 NOT NULL DEFAULT 1;
+COMMENT ON COLUMN "public"."products"."school_id" IS '所属学校，与卖家及分类所属学校一致。';
+ALTER TABLE "public"."products" ADD CONSTRAINT "products_school_id_fkey" FOREIGN KEY ("school_id") REFERENCES "public"."schools" ("id") ON DELETE RESTRICT ON UPDATE NO ACTION;
+CREATE INDEX "idx_products_school_status_created" ON "public"."products" USING btree ("school_id", "status", "created_at" DESC);
+
+-- 标签：名称改为学校内唯一；不同学校的标签可以有相同别名
+ALTER TABLE "public"."tags" ADD COLUMN "school_id" int8 NOT NULL DEFAULT 1;
+COMMENT ON COLUMN "public"."tags"."school_id" IS '所属学校，标签名称在学校内唯一。';
+ALTER TABLE "public"."tags" ADD CONSTRAINT "tags_school_id_fkey" FOREIGN KEY ("school_id") REFERENCES "public"."schools" ("id") ON DELETE RESTRICT ON UPDATE NO ACTION;
+ALTER TABLE "public"."tags" DROP CONSTRAINT IF EXISTS "tags_name_key";
+ALTER TABLE "public"."tags" ADD CONSTRAINT "tags_school_name_key" UNIQUE ("school_id", "name");
+CREATE INDEX "idx_tags_school_status" ON "public"."tags" USING btree ("school_id", "status");
+
+DROP INDEX IF EXISTS "public"."uq_tag_aliases_alias";
+CREATE INDEX "idx_tag_aliases_alias" ON "public"."tag_aliases" USING btree (lower(alias::text));
+CREATE UNIQUE INDEX "uq_tag_aliases_tag_alias" ON "public"."tag_aliases" USING btree ("tag_id", lower(alias::text));
+
+-- 用户
+ALTER TABLE "public"."users"
+  ADD COLUMN "school_id" int8 NOT NULL DEFAULT 1,
+  ADD COLUMN "is_super_admin" bool NOT NULL DEFAULT false;
+COMMENT ON COLUMN "public"."users"."is_admin" IS '是否管理员（仅管理所属学校）。';
+COMMENT ON COLUMN "public"."users"."school_id" IS '所属学校；登录后的请求固定使用该学校，账号在全部学校中唯一。';
+COMMENT ON COLUMN "public"."users"."is_super_admin" IS '是否超级管理员：可通过 X-School-Code 请求头或子域名切换并管理任意学校。';
+ALTER TABLE "public"."users" ADD CONSTRAINT "users_school_id_fkey" FOREIGN KEY ("school_id") REFERENCES "public"."schools" ("id") ON DELETE RESTRICT ON UPDATE NO ACTION;
+CREATE INDEX "idx_users_school" ON "public"."users" USING btree ("school_id");
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "public"."uq_users_email";
+ALTER TABLE "public"."users"
+  DROP COLUMN IF EXISTS "email_sent_at",
+  DROP COLUMN IF EXISTS "email_verified_at",
+  DROP COLUMN IF EXISTS "email";
@@ -0,0 +1,11 @@
+-- 用户表新增校园邮箱与邮箱验证字段
+
+ALTER TABLE "public"."users"
+  ADD COLUMN "email" varchar(255) COLLATE "pg_catalog"."default",
+  ADD COLUMN "email_verified_at" timestamptz(6),
+  ADD COLUMN "email_sent_at" timestamptz(6);
+COMMENT ON COLUMN "public"."users"."email" IS '校园邮箱（不区分大小写全局唯一）；启用邮箱验证时域名需在允许列表内。';
+COMMENT ON COLUMN "public"."users"."email_verified_at" IS '邮箱验证通过时间；为空表示未验证，修改邮箱后清空。';
+COMMENT ON COLUMN "public"."users"."email_sent_at" IS '最近一次发送验证邮件的时间，用于限制重发频率。';
+
+CREATE UNIQUE INDEX "uq_users_email" ON "public"."users" USING btree (lower(email::text)) WHERE email IS NOT NULL;
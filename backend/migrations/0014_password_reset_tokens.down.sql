@@ -0,0 +1,2 @@
+ALTER TABLE "public"."users" DROP COLUMN IF EXISTS "tokens_revoked_at";
+DROP TABLE IF EXISTS "public"."password_reset_tokens";
@@ -0,0 +1,24 @@
+-- 新增找回密码令牌表 password_reset_tokens，用户表新增登录令牌作废时间
+
+CREATE TABLE "public"."password_reset_tokens" (
+  "id" bigserial NOT NULL,
+  "user_id" int8 NOT NULL,
+  "token_hash" char(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "expires_at" timestamptz(6) NOT NULL,
+  "used_at" timestamptz(6),
+  "request_ip" varchar(64) COLLATE "pg_catalog"."default",
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "password_reset_tokens_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "password_reset_tokens_token_hash_key" UNIQUE ("token_hash"),
+  CONSTRAINT "password_reset_tokens_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."password_reset_tokens"."token_hash" IS '重置令牌的 SHA-256 哈希（十六进制）；令牌明文只出现在邮件中，不落库。';
+COMMENT ON COLUMN "public"."password_reset_tokens"."used_at" IS '使用时间；非空表示已使用，令牌只能使用一次。';
+COMMENT ON COLUMN "public"."password_reset_tokens"."request_ip" IS '发起找回密码请求的客户端 IP，便于审计。';
+COMMENT ON TABLE "public"."password_reset_tokens" IS '找回密码令牌：一次性、限时；重置成功后作废该用户的全部令牌与已登录会话。';
+
+CREATE INDEX "idx_password_reset_tokens_user" ON "public"."password_reset_tokens" USING btree ("user_id");
+CREATE INDEX "idx_password_reset_tokens_expires_at" ON "public"."password_reset_tokens" USING btree ("expires_at");
+
+ALTER TABLE "public"."users" ADD COLUMN "tokens_revoked_at" timestamptz(6);
+COMMENT ON COLUMN "public"."users"."tokens_revoked_at" IS '登录令牌作废时间：签发时间早于该时间的 JWT 一律视为失效（重置密码后写入）。';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "public"."user_posting_quotas";
+DROP TABLE IF EXISTS "public"."blocked_keywords";
@@ -0,0 +1,35 @@
+-- 新增发布屏蔽词表 blocked_keywords 与用户发布配额表 user_posting_quotas
+
+CREATE TABLE "public"."blocked_keywords" (
+  "id" bigserial NOT NULL,
+  "school_id" int8 NOT NULL DEFAULT 1,
+  "keyword" varchar(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "created_by" int8,
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "blocked_keywords_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "blocked_keywords_school_id_fkey" FOREIGN KEY ("school_id") REFERENCES "public"."schools" ("id") ON DELETE RESTRICT ON UPDATE NO ACTION,
+  CONSTRAINT "blocked_keywords_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "public"."users" ("id") ON DELETE SET NULL ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."blocked_keywords"."keyword" IS '屏蔽词，不区分大小写，匹配时忽略空白字符。';
+COMMENT ON COLUMN "public"."blocked_keywords"."created_by" IS '添加该屏蔽词的管理员；管理员账号删除时置空。';
+COMMENT ON TABLE "public"."blocked_keywords" IS '商品发布屏蔽词：标题或描述包含屏蔽词的商品不允许发布或修改，按学校维护。';
+
+CREATE UNIQUE INDEX "uq_blocked_keywords_school_keyword" ON "public"."blocked_keywords" USING btree ("school_id", lower(keyword::text));
+
+CREATE TABLE "public"."user_posting_quotas" (
+  "user_id" int8 NOT NULL,
+  "max_for_sale" int4,
+  "max_daily" int4,
+  "note" varchar(255) COLLATE "pg_catalog"."default",
+  "updated_by" int8,
+  "updated_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "user_posting_quotas_pkey" PRIMARY KEY ("user_id"),
+  CONSTRAINT "user_posting_quotas_max_for_sale_check" CHECK (max_for_sale IS NULL OR max_for_sale >= 0),
+  CONSTRAINT "user_posting_quotas_max_daily_check" CHECK (max_daily IS NULL OR max_daily >= 0),
+  CONSTRAINT "user_posting_quotas_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION,
+  CONSTRAINT "user_posting_quotas_updated_by_fkey" FOREIGN KEY ("updated_by") REFERENCES "public"."users" ("id") ON DELETE SET NULL ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."user_posting_quotas"."max_for_sale" IS '同时在售商品数上限；为空时使用全局默认值（新账号使用更严格的默认值）。';
+COMMENT ON COLUMN "public"."user_posting_quotas"."max_daily" IS '每日发布商品数上限；为空时使用全局默认值。';
+COMMENT ON COLUMN "public"."user_posting_quotas"."note" IS '管理员备注，如调整原因。';
+COMMENT ON TABLE "public"."user_posting_quotas" IS '用户发布配额覆盖：由管理员为个别用户放宽或收紧发布限制。';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "public"."two_factor_backup_codes";
+DROP TABLE IF EXISTS "public"."user_two_factor";
@@ -0,0 +1,31 @@
+-- 新增两步验证设置 user_two_factor 与备用码表 two_factor_backup_codes
+
+CREATE TABLE "public"."user_two_factor" (
+  "user_id" int8 NOT NULL,
+  "secret" varchar(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "enabled_at" timestamptz(6),
+  "last_used_step" int8 NOT NULL DEFAULT 0,
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  "updated_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "user_two_factor_pkey" PRIMARY KEY ("user_id"),
+  CONSTRAINT "user_two_factor_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."user_two_factor"."secret" IS 'TOTP 共享密钥（Base32），由服务端本地生成，用于计算 6 位动态验证码。';
+COMMENT ON COLUMN "public"."user_two_factor"."enabled_at" IS '启用时间；为空表示已生成密钥但尚未用验证码确认绑定。';
+COMMENT ON COLUMN "public"."user_two_factor"."last_used_step" IS '最近一次验证通过的时间步（Unix 秒 / 30），同一验证码不能重复使用。';
+COMMENT ON TABLE "public"."user_two_factor" IS '用户两步验证（TOTP）设置；管理员必须启用后才能访问管理后台接口。';
+
+CREATE TABLE "public"."two_factor_backup_codes" (
+  "id" bigserial NOT NULL,
+  "user_id" int8 NOT NULL,
+  "code_hash" char(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "used_at" timestamptz(6),
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "two_factor_backup_codes_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "two_factor_backup_codes_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."two_factor_backup_codes"."code_hash" IS '备用码的 SHA-256 哈希（十六进制）；明文只在生成时展示一次。';
+COMMENT ON COLUMN "public"."two_factor_backup_codes"."used_at" IS '使用时间；非空表示已使用，每个备用码只能使用一次。';
+COMMENT ON TABLE "public"."two_factor_backup_codes" IS '两步验证备用码：无法使用验证器时代替动态验证码，重新生成时整体替换。';
+
+CREATE INDEX "idx_two_factor_backup_codes_user" ON "public"."two_factor_backup_codes" USING btree ("user_id");
@@ -0,0 +1,10 @@
+ALTER TABLE "public"."sessions"
+  DROP COLUMN IF EXISTS "last_seen_at",
+  DROP COLUMN IF EXISTS "user_agent",
+  DROP COLUMN IF EXISTS "ip";
+COMMENT ON COLUMN "public"."sessions"."token" IS NULL;
+COMMENT ON COLUMN "public"."sessions"."expired_at" IS NULL;
+COMMENT ON TABLE "public"."sessions" IS '可选：服务端会话/令牌黑名单存储；若使用纯 JWT + Redis，可不创建本表。';
+
+DROP TABLE IF EXISTS "public"."notifications";
+DROP TABLE IF EXISTS "public"."login_history";
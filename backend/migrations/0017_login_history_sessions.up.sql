@@ -0,0 +1,50 @@
+-- 新增登录记录 login_history 与站内通知 notifications，会话表新增设备信息与最近活跃时间
+
+CREATE TABLE "public"."login_history" (
+  "id" bigserial NOT NULL,
+  "user_id" int8,
+  "account" varchar(50) COLLATE "pg_catalog"."default" NOT NULL,
+  "success" bool NOT NULL,
+  "failure_reason" varchar(32) COLLATE "pg_catalog"."default",
+  "ip" varchar(64) COLLATE "pg_catalog"."default",
+  "user_agent" varchar(512) COLLATE "pg_catalog"."default",
+  "device_hash" char(64) COLLATE "pg_catalog"."default",
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "login_history_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "login_history_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."login_history"."user_id" IS '登录的用户；账号不存在时为空（仍记录尝试的账号名）。';
+COMMENT ON COLUMN "public"."login_history"."failure_reason" IS '失败原因：invalid_credentials / throttled / two_factor；成功时为空。';
+COMMENT ON COLUMN "public"."login_history"."device_hash" IS '设备标识：User-Agent 的 SHA-256 哈希，用于识别新设备登录。';
+COMMENT ON TABLE "public"."login_history" IS '登录记录：每次登录尝试（成功或失败）的 IP、User-Agent 与时间，用户可在安全中心查看。';
+
+CREATE INDEX "idx_login_history_user_time" ON "public"."login_history" USING btree ("user_id", "created_at" DESC);
+
+CREATE TABLE "public"."notifications" (
+  "id" bigserial NOT NULL,
+  "user_id" int8 NOT NULL,
+  "type" varchar(32) COLLATE "pg_catalog"."default" NOT NULL,
+  "title" varchar(100) COLLATE "pg_catalog"."default" NOT NULL,
+  "content" text COLLATE "pg_catalog"."default" NOT NULL,
+  "link" varchar(255) COLLATE "pg_catalog"."default",
+  "read_at" timestamptz(6),
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "notifications_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "notifications_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."notifications"."type" IS '通知类型，如 security.new_device（新设备登录）。';
+COMMENT ON COLUMN "public"."notifications"."link" IS '前端跳转路径（可选）。';
+COMMENT ON COLUMN "public"."notifications"."read_at" IS '已读时间；为空表示未读。';
+COMMENT ON TABLE "public"."notifications" IS '站内通知：系统发给用户的提醒消息。';
+
+CREATE INDEX "idx_notifications_user_time" ON "public"."notifications" USING btree ("user_id", "created_at" DESC);
+CREATE INDEX "idx_notifications_user_unread" ON "public"."notifications" USING btree ("user_id") WHERE read_at IS NULL;
+
+ALTER TABLE "public"."sessions"
+  ADD COLUMN "ip" varchar(64) COLLATE "pg_catalog"."default",
+  ADD COLUMN "user_agent" varchar(512) COLLATE "pg_catalog"."default",
+  ADD COLUMN "last_seen_at" timestamptz(6) NOT NULL DEFAULT now();
+COMMENT ON COLUMN "public"."sessions"."token" IS '会话标识，写入登录令牌（JWT 的 sid 声明）；会话删除后对应令牌立即失效。';
+COMMENT ON COLUMN "public"."sessions"."expired_at" IS '会话过期时间，与最近签发的登录令牌过期时间一致。';
+COMMENT ON COLUMN "public"."sessions"."last_seen_at" IS '最近活跃时间（约每 5 分钟更新一次）。';
+COMMENT ON TABLE "public"."sessions" IS '登录会话：每次登录创建一条，用户可查看已登录设备并移除；重置密码时清空。';
@@ -0,0 +1,4 @@
+-- 用户表新增账号注销时间 deleted_at
+
+ALTER TABLE "public"."users" ADD COLUMN "deleted_at" timestamptz(6);
+COMMENT ON COLUMN "public"."users"."deleted_at" IS '账号注销时间；注销后个人资料被匿名化，已售记录保留且卖家显示为已注销用户。';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "public"."idx_products_for_sale_renewed";
+ALTER TABLE "public"."products"
+  DROP COLUMN IF EXISTS "expired_at",
+  DROP COLUMN IF EXISTS "expiry_reminded_at",
+  DROP COLUMN IF EXISTS "renewed_at";
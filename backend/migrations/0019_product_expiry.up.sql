@@ -0,0 +1,12 @@
+-- 商品表新增续期、过期提醒与自动下架时间
+-- 现有商品的续期时间取迁移执行时间，避免上线后一次性下架大量历史商品
+
+ALTER TABLE "public"."products"
+  ADD COLUMN "renewed_at" timestamptz(6) NOT NULL DEFAULT now(),
+  ADD COLUMN "expiry_reminded_at" timestamptz(6),
+  ADD COLUMN "expired_at" timestamptz(6);
+COMMENT ON COLUMN "public"."products"."renewed_at" IS '最近续期时间：发布、卖家编辑、重新上架或擦亮时写入；与卖家最近登录时间中较晚者作为过期计算起点。';
+COMMENT ON COLUMN "public"."products"."expiry_reminded_at" IS '最近一次发送即将过期提醒的时间；续期后清空。';
+COMMENT ON COLUMN "public"."products"."expired_at" IS '因卖家长期无活动被自动下架的时间；重新上架后清空。';
+
+CREATE INDEX "idx_products_for_sale_renewed" ON "public"."products" USING btree ("renewed_at") WHERE status = 'ForSale'::product_status;
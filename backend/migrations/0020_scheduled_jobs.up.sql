@@ -0,0 +1,67 @@
+-- 新增定时任务表 scheduled_jobs 与执行记录表 job_runs
+-- 任务定义由服务启动时按代码同步，这里不写入初始数据
+
+CREATE TABLE "public"."scheduled_jobs" (
+  "name" varchar(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "description" varchar(255) COLLATE "pg_catalog"."default" NOT NULL DEFAULT ''::character varying,
+  "schedule" varchar(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "max_retries" int4 NOT NULL DEFAULT 0,
+  "next_run_at" timestamptz(6) NOT NULL,
+  "attempt" int4 NOT NULL DEFAULT 0,
+  "run_requested_at" timestamptz(6),
+  "run_requested_by" int8,
+  "locked_by" varchar(128) COLLATE "pg_catalog"."default",
+  "locked_until" timestamptz(6),
+  "last_status" varchar(16) COLLATE "pg_catalog"."default",
+  "last_started_at" timestamptz(6),
+  "last_finished_at" timestamptz(6),
+  "last_duration_ms" int8,
+  "last_error" text COLLATE "pg_catalog"."default",
+  "created_at" timestamptz(6) NOT NULL DEFAULT now(),
+  "updated_at" timestamptz(6) NOT NULL DEFAULT now(),
+  CONSTRAINT "scheduled_jobs_pkey" PRIMARY KEY ("name"),
+  CONSTRAINT "scheduled_jobs_max_retries_check" CHECK (max_retries >= 0),
+  CONSTRAINT "scheduled_jobs_attempt_check" CHECK (attempt >= 0),
+  CONSTRAINT "scheduled_jobs_last_status_check" CHECK (last_status IS NULL OR last_status::text = ANY (ARRAY['running'::character varying, 'succeeded'::character varying, 'failed'::character varying]::text[])),
+  CONSTRAINT "scheduled_jobs_run_requested_by_fkey" FOREIGN KEY ("run_requested_by") REFERENCES "public"."users" ("id") ON DELETE SET NULL ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."scheduled_jobs"."name" IS '任务名称，与代码中注册的任务对应。';
+COMMENT ON COLUMN "public"."scheduled_jobs"."schedule" IS 'cron 表达式（分 时 日 月 周），或 @daily、@every 10m 等；服务启动时按代码中的定义同步。';
+COMMENT ON COLUMN "public"."scheduled_jobs"."next_run_at" IS '下次执行时间；失败重试时为退避后的重试时间。';
+COMMENT ON COLUMN "public"."scheduled_jobs"."attempt" IS '当前连续失败的重试次数，成功或重试用尽后归零。';
+COMMENT ON COLUMN "public"."scheduled_jobs"."run_requested_at" IS '管理员请求立即执行的时间，任务开始执行时清空。';
+COMMENT ON COLUMN "public"."scheduled_jobs"."locked_by" IS '持有执行锁的服务实例，同一时间只有一个实例执行该任务。';
+COMMENT ON COLUMN "public"."scheduled_jobs"."locked_until" IS '执行锁到期时间；实例异常退出时锁到期后可被其他实例接管。';
+COMMENT ON COLUMN "public"."scheduled_jobs"."last_status" IS '最近一次执行状态：running / succeeded / failed。';
+COMMENT ON TABLE "public"."scheduled_jobs" IS '定时任务：进程内调度器按 cron 计划执行，多实例部署时通过执行锁保证同一任务只在一个实例上运行。';
+
+CREATE TRIGGER "scheduled_jobs_set_updated_at" BEFORE UPDATE ON "public"."scheduled_jobs"
+FOR EACH ROW
+EXECUTE PROCEDURE "public"."trg_set_updated_at"();
+
+CREATE TABLE "public"."job_runs" (
+  "id" bigserial NOT NULL,
+  "job_name" varchar(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "trigger" varchar(16) COLLATE "pg_catalog"."default" NOT NULL,
+  "attempt" int4 NOT NULL DEFAULT 0,
+  "status" varchar(16) COLLATE "pg_catalog"."default" NOT NULL DEFAULT 'running'::character varying,
+  "error" text COLLATE "pg_catalog"."default",
+  "instance" varchar(128) COLLATE "pg_catalog"."default" NOT NULL,
+  "triggered_by" int8,
+  "started_at" timestamptz(6) NOT NULL DEFAULT now(),
+  "finished_at" timestamptz(6),
+  "duration_ms" int8,
+  CONSTRAINT "job_runs_pkey" PRIMARY KEY ("id"),
+  CONSTRAINT "job_runs_status_check" CHECK (status::text = ANY (ARRAY['running'::character varying, 'succeeded'::character varying, 'failed'::character varying]::text[])),
+  CONSTRAINT "job_runs_trigger_check" CHECK (trigger::text = ANY (ARRAY['schedule'::character varying, 'retry'::character varying, 'manual'::character varying]::text[])),
+  CONSTRAINT "job_runs_job_name_fkey" FOREIGN KEY ("job_name") REFERENCES "public"."scheduled_jobs" ("name") ON DELETE CASCADE ON UPDATE NO ACTION,
+  CONSTRAINT "job_runs_triggered_by_fkey" FOREIGN KEY ("triggered_by") REFERENCES "public"."users" ("id") ON DELETE SET NULL ON UPDATE NO ACTION
+);
+COMMENT ON COLUMN "public"."job_runs"."trigger" IS '触发方式：schedule（按计划）/ retry（失败重试）/ manual（管理员手动触发）。';
+COMMENT ON COLUMN "public"."job_runs"."attempt" IS '重试序号：0 表示首次执行，n 表示第 n 次重试。';
+COMMENT ON COLUMN "public"."job_runs"."status" IS '执行状态：running / succeeded / failed；实例退出导致锁过期的记录在下次执行时标记为 failed。';
+COMMENT ON COLUMN "public"."job_runs"."instance" IS '执行该任务的服务实例标识（主机名与进程号）。';
+COMMENT ON COLUMN "public"."job_runs"."triggered_by" IS '手动触发的管理员，按计划执行时为空。';
+COMMENT ON TABLE "public"."job_runs" IS '定时任务执行记录：每次执行一条，由清理任务定期删除过期记录。';
+
+CREATE INDEX "idx_job_runs_job_started" ON "public"."job_runs" USING btree ("job_name", "started_at" DESC);
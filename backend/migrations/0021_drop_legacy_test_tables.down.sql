@@ -0,0 +1,20 @@
+-- 恢复遗留测试表的结构（不恢复数据）
+
+CREATE TABLE "public"."simple_users" (
+  "id" bigserial PRIMARY KEY,
+  "account" text COLLATE "pg_catalog"."default",
+  "created_at" timestamptz(6)
+);
+
+CREATE TABLE "public"."test_products" (
+  "id" bigserial PRIMARY KEY,
+  "seller_id" int8 NOT NULL,
+  "title" varchar(100) COLLATE "pg_catalog"."default" NOT NULL,
+  "created_at" timestamp(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "public"."test_users" (
+  "id" bigserial PRIMARY KEY,
+  "account" varchar(50) COLLATE "pg_catalog"."default" NOT NULL,
+  "wechat_id" varchar(64) COLLATE "pg_catalog"."default"
+);
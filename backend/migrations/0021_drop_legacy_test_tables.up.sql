@@ -0,0 +1,6 @@
+-- 删除早期联调遗留的测试表（应用代码未使用）
+-- 序列由表的 id 列拥有（OWNED BY），随表一并删除
+
+DROP TABLE IF EXISTS "public"."test_products";
+DROP TABLE IF EXISTS "public"."test_users";
+DROP TABLE IF EXISTS "public"."simple_users";
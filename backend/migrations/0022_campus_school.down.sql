@@ -0,0 +1,8 @@
+-- 回滚为全局片区：仅当不同学校之间没有同名片区时才能成功
+
+DROP INDEX IF EXISTS "public"."idx_meetup_points_school";
+ALTER TABLE "public"."meetup_points" DROP COLUMN IF EXISTS "school_id";
+
+ALTER TABLE "public"."campus_zones" DROP CONSTRAINT IF EXISTS "campus_zones_school_name_key";
+ALTER TABLE "public"."campus_zones" DROP COLUMN IF EXISTS "school_id";
+ALTER TABLE "public"."campus_zones" ADD CONSTRAINT "campus_zones_name_key" UNIQUE ("name");
@@ -0,0 +1,12 @@
+-- 校园片区与面交地点增加所属学校：现有数据归入默认学校（id = 1），片区名称改为学校内唯一
+
+ALTER TABLE "public"."campus_zones" ADD COLUMN "school_id" int8 NOT NULL DEFAULT 1;
+COMMENT ON COLUMN "public"."campus_zones"."school_id" IS '所属学校，片区名称在学校内唯一。';
+ALTER TABLE "public"."campus_zones" ADD CONSTRAINT "campus_zones_school_id_fkey" FOREIGN KEY ("school_id") REFERENCES "public"."schools" ("id") ON DELETE RESTRICT ON UPDATE NO ACTION;
+ALTER TABLE "public"."campus_zones" DROP CONSTRAINT IF EXISTS "campus_zones_name_key";
+ALTER TABLE "public"."campus_zones" ADD CONSTRAINT "campus_zones_school_name_key" UNIQUE ("school_id", "name");
+
+ALTER TABLE "public"."meetup_points" ADD COLUMN "school_id" int8 NOT NULL DEFAULT 1;
+COMMENT ON COLUMN "public"."meetup_points"."school_id" IS '所属学校，与所属片区的学校一致。';
+ALTER TABLE "public"."meetup_points" ADD CONSTRAINT "meetup_points_school_id_fkey" FOREIGN KEY ("school_id") REFERENCES "public"."schools" ("id") ON DELETE RESTRICT ON UPDATE NO ACTION;
+CREATE INDEX "idx_meetup_points_school" ON "public"."meetup_points" USING btree ("school_id");
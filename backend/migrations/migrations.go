@@ -0,0 +1,365 @@
+// Package migrations 管理数据库结构版本
+//
+// 迁移脚本以 <版本号>_<名称>.up.sql / <版本号>_<名称>.down.sql 命名，嵌入到程序中并按版本号顺序执行。
+// 已执行的版本记录在 schema_migrations 表中；每个迁移与其版本记录在同一事务中提交，
+// 执行前获取 advisory lock，多个实例同时执行迁移时会依次等待，不会重复执行。
+//
+// 新增迁移时在本目录添加下一个版本号的 up/down 脚本即可，已发布的迁移脚本不要再修改。
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// lockKey 迁移使用的 advisory lock 键（任意固定值，仅需在本库内唯一）
+const lockKey = 7305202501
+
+// fileNamePattern 迁移脚本文件名格式
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// 错误定义
+var (
+	ErrNotInitialized = errors.New("数据库尚未记录结构版本")
+	ErrUnknownVersion = errors.New("数据库结构版本高于当前程序")
+	ErrPending        = errors.New("数据库结构版本低于当前程序")
+)
+
+// Migration 一个迁移版本
+type Migration struct {
+	Version int64
+	Name    string
+	up      string
+	down    string
+}
+
+// Status 迁移执行状态
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time // 未执行时为空
+	Known     bool       // 当前程序是否包含该版本（false 表示由更新版本的程序执行）
+}
+
+// schemaMigration schema_migrations 表记录
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// all 嵌入的全部迁移，按版本号升序
+var all = mustLoad()
+
+// mustLoad 解析嵌入的迁移脚本，脚本缺失或命名错误属于编码问题，直接 panic
+func mustLoad() []Migration {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		panic(err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		m := fileNamePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			panic(fmt.Sprintf("migrations: invalid file name %q", entry.Name()))
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		content, err := files.ReadFile(entry.Name())
+		if err != nil {
+			panic(err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != m[2] {
+			panic(fmt.Sprintf("migrations: version %d has conflicting names %q and %q", version, mig.Name, m[2]))
+		}
+		if m[3] == "up" {
+			mig.up = string(content)
+		} else {
+			mig.down = string(content)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Version <= 0 || mig.up == "" || mig.down == "" {
+			panic(fmt.Sprintf("migrations: version %d must be positive and have both up and down scripts", mig.Version))
+		}
+		result = append(result, *mig)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result
+}
+
+// Latest 当前程序所需的数据库结构版本
+func Latest() int64 {
+	if len(all) == 0 {
+		return 0
+	}
+	return all[len(all)-1].Version
+}
+
+// find 按版本号查找迁移
+func find(version int64) (Migration, bool) {
+	for _, mig := range all {
+		if mig.Version == version {
+			return mig, true
+		}
+	}
+	return Migration{}, false
+}
+
+// Migrator 执行迁移
+type Migrator struct {
+	db *gorm.DB
+}
+
+// New 创建迁移执行器
+func New(db *gorm.DB) *Migrator {
+	return &Migrator{db: db}
+}
+
+// Check 检查数据库结构版本与当前程序一致，服务启动前调用
+// 未记录版本、存在未知版本或有未执行的迁移时返回错误
+func (m *Migrator) Check(ctx context.Context) error {
+	exists, err := m.tableExists(ctx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotInitialized
+	}
+
+	applied, err := m.applied(m.db.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	for _, row := range applied {
+		if _, ok := find(row.Version); !ok {
+			return fmt.Errorf("%w：数据库版本 %d，程序最高支持 %d", ErrUnknownVersion, row.Version, Latest())
+		}
+	}
+	if pending := m.pending(applied); len(pending) > 0 {
+		return fmt.Errorf("%w：有 %d 个迁移未执行，需要版本 %d", ErrPending, len(pending), Latest())
+	}
+	return nil
+}
+
+// Up 依次执行全部未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for {
+		var executed *Migration
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := lock(tx); err != nil {
+				return err
+			}
+			applied, err := m.applied(tx)
+			if err != nil {
+				return err
+			}
+			pending := m.pending(applied)
+			if len(pending) == 0 {
+				return nil
+			}
+
+			mig := pending[0]
+			if err := tx.Exec(mig.up).Error; err != nil {
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			if err := tx.Create(&schemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now()}).Error; err != nil {
+				return err
+			}
+			executed = &mig
+			return nil
+		})
+		if err != nil {
+			return done, err
+		}
+		if executed == nil {
+			return done, nil
+		}
+		done = append(done, *executed)
+	}
+}
+
+// Down 回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := 0; i < steps; i++ {
+		var reverted *Migration
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := lock(tx); err != nil {
+				return err
+			}
+			applied, err := m.applied(tx)
+			if err != nil {
+				return err
+			}
+			if len(applied) == 0 {
+				return nil
+			}
+
+			last := applied[len(applied)-1]
+			mig, ok := find(last.Version)
+			if !ok {
+				return fmt.Errorf("%w：无法回滚版本 %d", ErrUnknownVersion, last.Version)
+			}
+			if err := tx.Exec(mig.down).Error; err != nil {
+				return fmt.Errorf("revert migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			if err := tx.Delete(&schemaMigration{}, "version = ?", mig.Version).Error; err != nil {
+				return err
+			}
+			reverted = &mig
+			return nil
+		})
+		if err != nil {
+			return done, err
+		}
+		if reverted == nil {
+			break
+		}
+		done = append(done, *reverted)
+	}
+	return done, nil
+}
+
+// Force 将数据库标记为指定版本而不执行脚本：不高于该版本的迁移记为已执行，更高的记录被删除
+// 用于接管通过旧 SQL 脚本创建的数据库，或在手工修复失败的迁移后校正版本记录
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if _, ok := find(version); !ok && version != 0 {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+	if err := m.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lock(tx); err != nil {
+			return err
+		}
+		if err := tx.Where("version > ?", version).Delete(&schemaMigration{}).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, mig := range all {
+			if mig.Version > version {
+				break
+			}
+			row := schemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: now}
+			if err := tx.Where("version = ?", mig.Version).FirstOrCreate(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Status 获取全部迁移（包括数据库中记录但当前程序未包含的版本）的执行状态
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	exists, err := m.tableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var applied []schemaMigration
+	if exists {
+		if applied, err = m.applied(m.db.WithContext(ctx)); err != nil {
+			return nil, err
+		}
+	}
+
+	byVersion := make(map[int64]*Status)
+	for _, mig := range all {
+		byVersion[mig.Version] = &Status{Version: mig.Version, Name: mig.Name, Known: true}
+	}
+	for _, row := range applied {
+		appliedAt := row.AppliedAt
+		if s, ok := byVersion[row.Version]; ok {
+			s.AppliedAt = &appliedAt
+		} else {
+			byVersion[row.Version] = &Status{Version: row.Version, Name: row.Name, AppliedAt: &appliedAt}
+		}
+	}
+
+	result := make([]Status, 0, len(byVersion))
+	for _, s := range byVersion {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// pending 未执行的迁移
+func (m *Migrator) pending(applied []schemaMigration) []Migration {
+	done := make(map[int64]bool, len(applied))
+	for _, row := range applied {
+		done[row.Version] = true
+	}
+	var result []Migration
+	for _, mig := range all {
+		if !done[mig.Version] {
+			result = append(result, mig)
+		}
+	}
+	return result
+}
+
+// applied 已执行的迁移记录，按版本号升序
+func (m *Migrator) applied(db *gorm.DB) ([]schemaMigration, error) {
+	var rows []schemaMigration
+	err := db.Order("version").Find(&rows).Error
+	return rows, err
+}
+
+// tableExists 判断版本表是否存在
+func (m *Migrator) tableExists(ctx context.Context) (bool, error) {
+	var exists bool
+	err := m.db.WithContext(ctx).Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists).Error
+	return exists, err
+}
+
+// ensureTable 创建版本表
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	return m.db.WithContext(ctx).Exec(`
+CREATE TABLE IF NOT EXISTS "public"."schema_migrations" (
+  "version" int8 NOT NULL PRIMARY KEY,
+  "name" varchar(255) NOT NULL,
+  "applied_at" timestamptz(6) NOT NULL DEFAULT now()
+)`).Error
+}
+
+// lock 获取事务级 advisory lock，事务结束时自动释放
+func lock(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", lockKey).Error
+}
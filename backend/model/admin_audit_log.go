@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 审计对象类型
+const (
+	AuditTargetProduct = "product"
+	AuditTargetUser    = "user"
+)
+
+// AdminAuditLog 管理员操作审计日志模型
+// 每条记录对应一次管理员操作对单个对象产生的影响
+type AdminAuditLog struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	AdminID    int64     `json:"adminId" gorm:"column:admin_id;not null;index"`
+	Action     string    `json:"action" gorm:"type:varchar(32);not null"`
+	TargetType string    `json:"targetType" gorm:"column:target_type;type:varchar(32);not null"`
+	TargetID   int64     `json:"targetId" gorm:"column:target_id;not null"`
+	Detail     string    `json:"-" gorm:"type:jsonb"` // JSON对象，记录变更前后的关键字段
+	CreatedAt  time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (AdminAuditLog) TableName() string {
+	return "admin_audit_logs"
+}
+
+// NewAdminAuditLog 构建审计日志，detail 会被序列化为JSON
+func NewAdminAuditLog(adminID int64, action, targetType string, targetID int64, detail interface{}) *AdminAuditLog {
+	raw := "{}"
+	if detail != nil {
+		if b, err := json.Marshal(detail); err == nil {
+			raw = string(b)
+		}
+	}
+	return &AdminAuditLog{
+		AdminID:    adminID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Detail:     raw,
+	}
+}
+
+// AdminAuditLogDTO 审计日志DTO
+type AdminAuditLogDTO struct {
+	ID         int64           `json:"id"`
+	AdminID    int64           `json:"adminId"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType"`
+	TargetID   int64           `json:"targetId"`
+	Detail     json.RawMessage `json:"detail"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// ToDTO 转换为DTO
+func (l *AdminAuditLog) ToDTO() AdminAuditLogDTO {
+	detail := json.RawMessage(l.Detail)
+	if len(detail) == 0 || !json.Valid(detail) {
+		detail = json.RawMessage("{}")
+	}
+	return AdminAuditLogDTO{
+		ID:         l.ID,
+		AdminID:    l.AdminID,
+		Action:     l.Action,
+		TargetType: l.TargetType,
+		TargetID:   l.TargetID,
+		Detail:     detail,
+		CreatedAt:  l.CreatedAt,
+	}
+}
@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// Book 图书目录模型（本地教材目录，由管理员通过CSV导入）
+type Book struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ISBN      string    `json:"isbn" gorm:"column:isbn;type:varchar(13);not null;uniqueIndex"` // 规范化的ISBN-13
+	Title     string    `json:"title" gorm:"type:varchar(200);not null"`
+	Author    string    `json:"author" gorm:"type:varchar(200)"`
+	Edition   string    `json:"edition" gorm:"type:varchar(50)"`
+	Publisher string    `json:"publisher" gorm:"type:varchar(100)"`
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (Book) TableName() string {
+	return "books"
+}
+
+// BookCourse 图书与课程的关联（多对多）
+type BookCourse struct {
+	BookID     int64  `json:"bookId" gorm:"column:book_id;primaryKey"`
+	CourseCode string `json:"courseCode" gorm:"column:course_code;type:varchar(32);primaryKey"`
+}
+
+// TableName 指定表名
+func (BookCourse) TableName() string {
+	return "book_courses"
+}
+
+// UserCourse 用户选修的课程
+type UserCourse struct {
+	UserID     int64     `json:"userId" gorm:"column:user_id;primaryKey"`
+	CourseCode string    `json:"courseCode" gorm:"column:course_code;type:varchar(32);primaryKey"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (UserCourse) TableName() string {
+	return "user_courses"
+}
+
+// BookDTO 图书信息DTO，附带关联的课程代码
+type BookDTO struct {
+	ID          int64    `json:"id"`
+	ISBN        string   `json:"isbn"`
+	Title       string   `json:"title"`
+	Author      string   `json:"author"`
+	Edition     string   `json:"edition"`
+	Publisher   string   `json:"publisher"`
+	CourseCodes []string `json:"courseCodes"`
+}
+
+// ToDTO 转换为DTO
+func (b *Book) ToDTO(courseCodes []string) BookDTO {
+	if courseCodes == nil {
+		courseCodes = []string{}
+	}
+	return BookDTO{
+		ID:          b.ID,
+		ISBN:        b.ISBN,
+		Title:       b.Title,
+		Author:      b.Author,
+		Edition:     b.Edition,
+		Publisher:   b.Publisher,
+		CourseCodes: courseCodes,
+	}
+}
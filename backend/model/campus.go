@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// CampusZone 校园片区模型（如 北区宿舍、南区宿舍、图书馆）
+type CampusZone struct {
+	ID          int64         `json:"id" gorm:"primaryKey;autoIncrement"`
+	SchoolID    int64         `json:"schoolId" gorm:"column:school_id;not null"`
+	Name        string        `json:"name" gorm:"type:varchar(50);not null"`
+	Description string        `json:"description" gorm:"type:varchar(255)"`
+	SortOrder   int32         `json:"sortOrder" gorm:"not null;default:0"`
+	Points      []MeetupPoint `json:"points" gorm:"-"`
+	CreatedAt   time.Time     `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time     `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (CampusZone) TableName() string {
+	return "campus_zones"
+}
+
+// MeetupPoint 面交地点模型，隶属于某个校园片区
+type MeetupPoint struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	SchoolID    int64     `json:"schoolId" gorm:"column:school_id;not null"`
+	ZoneID      int64     `json:"zoneId" gorm:"column:zone_id;not null"`
+	Name        string    `json:"name" gorm:"type:varchar(50);not null"`
+	Description string    `json:"description" gorm:"type:varchar(255)"`
+	SortOrder   int32     `json:"sortOrder" gorm:"not null;default:0"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (MeetupPoint) TableName() string {
+	return "meetup_points"
+}
+
+// MeetupPointDTO 商品详情中的面交地点信息
+type MeetupPointDTO struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	ZoneID   int64  `json:"zoneId"`
+	ZoneName string `json:"zoneName"`
+}
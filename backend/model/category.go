@@ -5,10 +5,14 @@ import (
 )
 
 // Category 商品分类模型
+// 分类支持多级嵌套：ParentID 为空表示顶级分类，同级分类按 SortOrder 升序排列
 type Category struct {
 	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name        string    `json:"name" gorm:"type:varchar(50);not null;uniqueIndex"`
+	ParentID    *int64    `json:"parentId" gorm:"column:parent_id;index"`
+	Name        string    `json:"name" gorm:"type:varchar(50);not null"`
 	Description string    `json:"description" gorm:"type:varchar(255)"`
+	SortOrder   int       `json:"sortOrder" gorm:"column:sort_order;not null;default:0"`
+	SchoolID    int64     `json:"schoolId" gorm:"column:school_id;not null"`
 	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
@@ -17,3 +21,31 @@ type Category struct {
 func (Category) TableName() string {
 	return "categories"
 }
+
+// CategoryNode 分类树节点
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// BuildCategoryTree 将按排序好的扁平分类列表组装为树，返回顶级节点
+// 父分类缺失的节点（数据异常）作为顶级节点返回，避免被静默丢弃
+func BuildCategoryTree(categories []Category) []*CategoryNode {
+	nodes := make(map[int64]*CategoryNode, len(categories))
+	for i := range categories {
+		nodes[categories[i].ID] = &CategoryNode{Category: categories[i], Children: []*CategoryNode{}}
+	}
+
+	roots := make([]*CategoryNode, 0)
+	for i := range categories {
+		node := nodes[categories[i].ID]
+		if categories[i].ParentID != nil {
+			if parent, ok := nodes[*categories[i].ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
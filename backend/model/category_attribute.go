@@ -0,0 +1,89 @@
+package model
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+)
+
+// 分类属性值类型
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeEnum    = "enum"
+	AttributeTypeBoolean = "boolean"
+)
+
+// AttributeKeyPattern 属性键格式：小写字母开头，仅含小写字母、数字和下划线
+var AttributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// CategoryAttribute 分类属性定义模型
+// 子分类继承祖先分类定义的属性，同名属性以距离最近的分类为准
+type CategoryAttribute struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	CategoryID int64     `json:"categoryId" gorm:"column:category_id;not null;uniqueIndex:uq_category_attributes_key"`
+	Key        string    `json:"key" gorm:"column:attr_key;type:varchar(32);not null;uniqueIndex:uq_category_attributes_key"`
+	Label      string    `json:"label" gorm:"type:varchar(50);not null"`
+	Type       string    `json:"type" gorm:"column:value_type;type:varchar(16);not null"`
+	Required   bool      `json:"required" gorm:"not null;default:false"`
+	Options    string    `json:"-" gorm:"type:jsonb"` // JSON数组，仅 enum 类型使用
+	Unit       string    `json:"unit" gorm:"type:varchar(16)"`
+	SortOrder  int       `json:"sortOrder" gorm:"column:sort_order;not null;default:0"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (CategoryAttribute) TableName() string {
+	return "category_attributes"
+}
+
+// OptionList 解析枚举可选值
+func (a *CategoryAttribute) OptionList() []string {
+	options := []string{}
+	if a.Options != "" {
+		_ = json.Unmarshal([]byte(a.Options), &options)
+	}
+	return options
+}
+
+// SetOptions 设置枚举可选值
+func (a *CategoryAttribute) SetOptions(options []string) {
+	if options == nil {
+		options = []string{}
+	}
+	raw, _ := json.Marshal(options)
+	a.Options = string(raw)
+}
+
+// CategoryAttributeDTO 分类属性定义DTO
+type CategoryAttributeDTO struct {
+	ID         int64     `json:"id"`
+	CategoryID int64     `json:"categoryId"`
+	Key        string    `json:"key"`
+	Label      string    `json:"label"`
+	Type       string    `json:"type"`
+	Required   bool      `json:"required"`
+	Options    []string  `json:"options"`
+	Unit       string    `json:"unit"`
+	SortOrder  int       `json:"sortOrder"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// ToDTO 转换为DTO
+func (a *CategoryAttribute) ToDTO() CategoryAttributeDTO {
+	return CategoryAttributeDTO{
+		ID:         a.ID,
+		CategoryID: a.CategoryID,
+		Key:        a.Key,
+		Label:      a.Label,
+		Type:       a.Type,
+		Required:   a.Required,
+		Options:    a.OptionList(),
+		Unit:       a.Unit,
+		SortOrder:  a.SortOrder,
+		CreatedAt:  a.CreatedAt,
+		UpdatedAt:  a.UpdatedAt,
+	}
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// 通知类型
+const (
+	// NotificationNewDeviceLogin 新设备登录提醒
+	NotificationNewDeviceLogin = "security.new_device"
+	// NotificationListingExpiring 商品即将因长期无活动自动下架
+	NotificationListingExpiring = "listing.expiring"
+	// NotificationListingExpired 商品已因长期无活动自动下架
+	NotificationListingExpired = "listing.expired"
+)
+
+// Notification 站内通知
+type Notification struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `json:"userId" gorm:"not null;index"`
+	Type      string     `json:"type" gorm:"type:varchar(32);not null"`
+	Title     string     `json:"title" gorm:"type:varchar(100);not null"`
+	Content   string     `json:"content" gorm:"type:text;not null"`
+	Link      *string    `json:"link" gorm:"type:varchar(255)"`
+	ReadAt    *time.Time `json:"readAt"`
+	CreatedAt time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// PasswordResetToken 找回密码令牌
+// 只保存令牌的 SHA-256 哈希，明文仅出现在发给用户的邮件中；令牌一次性使用且有过期时间
+type PasswordResetToken struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `json:"user_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	RequestIP string     `json:"request_ip" gorm:"type:varchar(64)"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// BlockedKeyword 商品发布屏蔽词（按学校维护）
+type BlockedKeyword struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	SchoolID  int64     `json:"schoolId" gorm:"not null"`
+	Keyword   string    `json:"keyword" gorm:"type:varchar(64);not null"`
+	CreatedBy *int64    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (BlockedKeyword) TableName() string {
+	return "blocked_keywords"
+}
+
+// UserPostingQuota 管理员为个别用户设置的发布配额，字段为空时使用全局默认值
+type UserPostingQuota struct {
+	UserID     int64     `json:"userId" gorm:"primaryKey"`
+	MaxForSale *int      `json:"maxForSale"`
+	MaxDaily   *int      `json:"maxDaily"`
+	Note       string    `json:"note" gorm:"type:varchar(255)"`
+	UpdatedBy  *int64    `json:"updatedBy"`
+	UpdatedAt  time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (UserPostingQuota) TableName() string {
+	return "user_posting_quotas"
+}
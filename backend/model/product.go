@@ -1,20 +1,57 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Product 商品模型
 type Product struct {
-	ID           int64     `json:"id" gorm:"primaryKey"`
-	Title        string    `json:"title"`
-	Description  string    `json:"description"`
-	Price        float64   `json:"price"`
-	CategoryID   int64     `json:"categoryId"`
-	ConditionID  int64     `json:"conditionId"`
-	SellerID     int64     `json:"sellerId"`
-	Status       string    `json:"status"`
-	MainImageURL string    `json:"mainImageUrl" gorm:"column:main_image_url"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
+	ID            int64      `json:"id" gorm:"primaryKey"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	Price         float64    `json:"price"`
+	CategoryID    int64      `json:"categoryId"`
+	ConditionID   int64      `json:"conditionId"`
+	SellerID      int64      `json:"sellerId"`
+	SchoolID      int64      `json:"schoolId" gorm:"column:school_id"` // 所属学校
+	Status        string     `json:"status"`
+	MainImageURL  string     `json:"mainImageUrl" gorm:"column:main_image_url"`
+	Attributes    string     `json:"-" gorm:"type:jsonb;default:'{}'"`                      // JSON对象，分类属性值，键为属性键
+	ISBN          *string    `json:"isbn,omitempty" gorm:"column:isbn"`                     // 教材ISBN-13，关联本地图书目录
+	MeetupPointID *int64     `json:"meetupPointId,omitempty" gorm:"column:meetup_point_id"` // 面交地点
+	SoldAt        *time.Time `json:"soldAt,omitempty"`                                      // 成交时间，仅 Sold 状态有值
+	RenewedAt     time.Time  `json:"renewedAt" gorm:"default:now()"`                        // 最近续期时间（发布、编辑、重新上架或擦亮）
+	RemindedAt    *time.Time `json:"-" gorm:"column:expiry_reminded_at"`                    // 最近发送即将过期提醒的时间
+	ExpiredAt     *time.Time `json:"expiredAt,omitempty"`                                   // 因长期无活动被自动下架的时间
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// AttributeMap 解析商品属性值
+func (p *Product) AttributeMap() map[string]interface{} {
+	return ParseAttributeValues(p.Attributes)
+}
+
+// ParseAttributeValues 解析JSON格式的属性值，非法内容按空对象处理
+func ParseAttributeValues(raw string) map[string]interface{} {
+	values := map[string]interface{}{}
+	if raw != "" {
+		_ = json.Unmarshal([]byte(raw), &values)
+	}
+	return values
+}
+
+// EncodeAttributeValues 将属性值序列化为JSON（键有序，便于比较）
+func EncodeAttributeValues(values map[string]interface{}) string {
+	if len(values) == 0 {
+		return "{}"
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return "{}"
+	}
+	return string(raw)
 }
 
 // ProductImage 商品图片模型
@@ -28,37 +65,46 @@ type ProductImage struct {
 
 // ProductDetailDTO 商品详情DTO
 type ProductDetailDTO struct {
-	ID             int64          `json:"id"`
-	Title          string         `json:"title"`
-	Description    string         `json:"description"`
-	Price          float64        `json:"price"`
-	CategoryID     int64          `json:"categoryId"`
-	ConditionID    int64          `json:"conditionId"`
-	ConditionName  string         `json:"conditionName"`
-	MainImageURL   string         `json:"mainImageUrl"`
-	Images         []ProductImage `json:"images"`
-	TagIDs         []int64        `json:"tagIds"`
-	Seller         SellerInfo     `json:"seller"`
-	ViewerIsSeller bool           `json:"viewerIsSeller"`
-	Status         string         `json:"status"`
-	CreatedAt      time.Time      `json:"createdAt"`
-	UpdatedAt      time.Time      `json:"updatedAt"`
-	SellerWechat   *string        `json:"sellerWechat,omitempty"`
+	ID             int64                  `json:"id"`
+	Title          string                 `json:"title"`
+	Description    string                 `json:"description"`
+	Price          float64                `json:"price"`
+	CategoryID     int64                  `json:"categoryId"`
+	ConditionID    int64                  `json:"conditionId"`
+	ConditionName  string                 `json:"conditionName"`
+	MainImageURL   string                 `json:"mainImageUrl"`
+	Images         []ProductImage         `json:"images"`
+	TagIDs         []int64                `json:"tagIds"`
+	Attributes     map[string]interface{} `json:"attributes"`
+	ISBN           *string                `json:"isbn,omitempty"`
+	Book           *BookDTO               `json:"book,omitempty"`        // ISBN在图书目录中存在时返回
+	MeetupPoint    *MeetupPointDTO        `json:"meetupPoint,omitempty"` // 卖家指定的面交地点
+	Seller         SellerInfo             `json:"seller"`
+	ViewerIsSeller bool                   `json:"viewerIsSeller"`
+	Status         string                 `json:"status"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+	SellerWechat   *string                `json:"sellerWechat,omitempty"`
+	// 价格历史（按时间升序），仅在商品发生过调价时返回
+	PriceHistory []PricePoint `json:"priceHistory,omitempty"`
+	// PriceDrop 相对上一次价格的降价金额，未降价时为0
+	PriceDrop float64 `json:"priceDrop"`
 }
 
 // ProductCardDTO 商品卡片DTO
 type ProductCardDTO struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Price       float64   `json:"price"`
-	MainImage   string    `json:"mainImageUrl"`
-	Status      string    `json:"status"`
-	SellerID    int64     `json:"sellerId"`
-	CategoryID  int64     `json:"categoryId"`
-	ConditionID int64     `json:"conditionId"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID            int64     `json:"id"`
+	Title         string    `json:"title"`
+	Price         float64   `json:"price"`
+	MainImage     string    `json:"mainImageUrl"`
+	Status        string    `json:"status"`
+	SellerID      int64     `json:"sellerId"`
+	CategoryID    int64     `json:"categoryId"`
+	ConditionID   int64     `json:"conditionId"`
+	Description   string    `json:"description"`
+	MeetupPointID *int64    `json:"meetupPointId,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
 }
 
 // SellerInfo 卖家简要信息
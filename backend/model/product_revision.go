@@ -0,0 +1,158 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 商品修订来源
+const (
+	RevisionReasonCreate    = "create"     // 发布时的初始版本
+	RevisionReasonInitial   = "initial"    // 历史商品首次编辑前补录的基线版本
+	RevisionReasonEdit      = "edit"       // 卖家编辑
+	RevisionReasonAdminEdit = "admin_edit" // 管理员编辑
+	RevisionReasonRevert    = "revert"     // 管理员回滚到历史版本
+)
+
+// ProductRevision 商品修订记录模型
+// 每条记录保存商品在某一版本下的完整快照，版本号按商品独立递增
+type ProductRevision struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProductID   int64     `json:"productId" gorm:"column:product_id;not null;uniqueIndex:uq_product_revisions_version"`
+	Version     int       `json:"version" gorm:"not null;uniqueIndex:uq_product_revisions_version"`
+	EditorID    int64     `json:"editorId" gorm:"column:editor_id;not null"`
+	Reason      string    `json:"reason" gorm:"type:varchar(16);not null"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Price       float64   `json:"price"`
+	CategoryID  int64     `json:"categoryId"`
+	ConditionID int64     `json:"conditionId"`
+	ImageURLs   string    `json:"-" gorm:"column:image_urls;type:jsonb"` // JSON数组，按排序保存图片URL
+	TagIDs      string    `json:"-" gorm:"column:tag_ids;type:jsonb"`    // JSON数组，保存标签ID
+	Attributes  string    `json:"-" gorm:"type:jsonb"`                   // JSON对象，保存分类属性值
+	CreatedAt   time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (ProductRevision) TableName() string {
+	return "product_revisions"
+}
+
+// NewProductRevision 根据商品当前状态构建修订快照（版本号由仓库层分配）
+func NewProductRevision(product *Product, images []ProductImage, tagIDs []int64, editorID int64, reason string) *ProductRevision {
+	urls := make([]string, 0, len(images))
+	for _, img := range images {
+		urls = append(urls, img.URL)
+	}
+	if tagIDs == nil {
+		tagIDs = []int64{}
+	}
+	urlsJSON, _ := json.Marshal(urls)
+	tagsJSON, _ := json.Marshal(tagIDs)
+
+	return &ProductRevision{
+		ProductID:   product.ID,
+		EditorID:    editorID,
+		Reason:      reason,
+		Title:       product.Title,
+		Description: product.Description,
+		Price:       product.Price,
+		CategoryID:  product.CategoryID,
+		ConditionID: product.ConditionID,
+		ImageURLs:   string(urlsJSON),
+		TagIDs:      string(tagsJSON),
+		Attributes:  EncodeAttributeValues(product.AttributeMap()),
+	}
+}
+
+// ImageURLList 解析快照中的图片URL列表
+func (r *ProductRevision) ImageURLList() []string {
+	urls := []string{}
+	if r.ImageURLs != "" {
+		_ = json.Unmarshal([]byte(r.ImageURLs), &urls)
+	}
+	return urls
+}
+
+// TagIDList 解析快照中的标签ID列表
+func (r *ProductRevision) TagIDList() []int64 {
+	ids := []int64{}
+	if r.TagIDs != "" {
+		_ = json.Unmarshal([]byte(r.TagIDs), &ids)
+	}
+	return ids
+}
+
+// AttributeMap 解析快照中的属性值
+func (r *ProductRevision) AttributeMap() map[string]interface{} {
+	return ParseAttributeValues(r.Attributes)
+}
+
+// SameContent 判断两个快照的商品内容是否一致（忽略版本、编辑人等元信息）
+func (r *ProductRevision) SameContent(other *ProductRevision) bool {
+	if other == nil {
+		return false
+	}
+	return r.Title == other.Title &&
+		r.Description == other.Description &&
+		r.Price == other.Price &&
+		r.CategoryID == other.CategoryID &&
+		r.ConditionID == other.ConditionID &&
+		equalStrings(r.ImageURLList(), other.ImageURLList()) &&
+		equalInt64s(r.TagIDList(), other.TagIDList()) &&
+		EncodeAttributeValues(r.AttributeMap()) == EncodeAttributeValues(other.AttributeMap())
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalInt64s(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// RevisionFieldChange 两个相邻版本之间单个字段的变化
+type RevisionFieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// ProductRevisionDTO 商品修订记录DTO，附带与上一版本的差异
+type ProductRevisionDTO struct {
+	Version     int                    `json:"version"`
+	EditorID    int64                  `json:"editorId"`
+	Reason      string                 `json:"reason"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Price       float64                `json:"price"`
+	CategoryID  int64                  `json:"categoryId"`
+	ConditionID int64                  `json:"conditionId"`
+	ImageURLs   []string               `json:"imageUrls"`
+	TagIDs      []int64                `json:"tagIds"`
+	Attributes  map[string]interface{} `json:"attributes"`
+	Changes     []RevisionFieldChange  `json:"changes"`
+	CreatedAt   time.Time              `json:"createdAt"`
+}
+
+// PricePoint 价格历史中的一个点
+type PricePoint struct {
+	Price     float64   `json:"price"`
+	ChangedAt time.Time `json:"changedAt"`
+}
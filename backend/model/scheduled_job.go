@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// 定时任务执行状态
+const (
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// 定时任务触发方式
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerRetry    = "retry"
+	JobTriggerManual   = "manual"
+)
+
+// ScheduledJob 定时任务，服务启动时按代码中注册的任务同步名称、计划与重试次数
+// LockedBy/LockedUntil 为执行锁，同一时间只有一个实例执行该任务
+type ScheduledJob struct {
+	Name           string     `json:"name" gorm:"primaryKey;type:varchar(64)"`
+	Description    string     `json:"description" gorm:"type:varchar(255);not null"`
+	Schedule       string     `json:"schedule" gorm:"type:varchar(64);not null"`
+	MaxRetries     int        `json:"maxRetries" gorm:"not null"`
+	NextRunAt      time.Time  `json:"nextRunAt" gorm:"not null"`
+	Attempt        int        `json:"attempt" gorm:"not null"`
+	RunRequestedAt *time.Time `json:"runRequestedAt"`
+	RunRequestedBy *int64     `json:"runRequestedBy"`
+	LockedBy       *string    `json:"lockedBy" gorm:"type:varchar(128)"`
+	LockedUntil    *time.Time `json:"lockedUntil"`
+	LastStatus     *string    `json:"lastStatus" gorm:"type:varchar(16)"`
+	LastStartedAt  *time.Time `json:"lastStartedAt"`
+	LastFinishedAt *time.Time `json:"lastFinishedAt"`
+	LastDurationMs *int64     `json:"lastDurationMs"`
+	LastError      *string    `json:"lastError"`
+	CreatedAt      time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (ScheduledJob) TableName() string {
+	return "scheduled_jobs"
+}
+
+// JobRun 定时任务执行记录
+type JobRun struct {
+	ID          int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	JobName     string     `json:"jobName" gorm:"type:varchar(64);not null;index"`
+	Trigger     string     `json:"trigger" gorm:"type:varchar(16);not null"`
+	Attempt     int        `json:"attempt" gorm:"not null"`
+	Status      string     `json:"status" gorm:"type:varchar(16);not null"`
+	Error       *string    `json:"error"`
+	Instance    string     `json:"instance" gorm:"type:varchar(128);not null"`
+	TriggeredBy *int64     `json:"triggeredBy"`
+	StartedAt   time.Time  `json:"startedAt" gorm:"not null"`
+	FinishedAt  *time.Time `json:"finishedAt"`
+	DurationMs  *int64     `json:"durationMs"`
+}
+
+// TableName 指定表名
+func (JobRun) TableName() string {
+	return "job_runs"
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// School 学校（租户）模型
+// 用户、商品、分类与标签均归属于某个学校，Code 同时用作子域名与 X-School-Code 请求头取值
+type School struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Code      string    `json:"code" gorm:"type:varchar(32);not null;uniqueIndex"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null"`
+	IsActive  bool      `json:"isActive" gorm:"not null;default:true"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (School) TableName() string {
+	return "schools"
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// Session 登录会话，每次登录创建一条
+// Token 为会话标识，写入登录令牌的 sid 声明；会话被删除后对应的登录令牌立即失效
+type Session struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64     `json:"user_id" gorm:"not null;index"`
+	Token      string    `json:"-" gorm:"type:varchar(128);not null;uniqueIndex"`
+	IP         string    `json:"ip" gorm:"type:varchar(64)"`
+	UserAgent  string    `json:"user_agent" gorm:"type:varchar(512)"`
+	ExpiredAt  time.Time `json:"expired_at" gorm:"not null"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// 登录失败原因
+const (
+	LoginFailureInvalidCredentials = "invalid_credentials"
+	LoginFailureThrottled          = "throttled"
+	LoginFailureTwoFactor          = "two_factor"
+)
+
+// LoginRecord 登录记录，成功与失败的登录尝试都会记录
+// 账号不存在时 UserID 为空；DeviceHash 为 User-Agent 的哈希，用于识别新设备
+type LoginRecord struct {
+	ID            int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID        *int64    `json:"user_id" gorm:"index"`
+	Account       string    `json:"account" gorm:"type:varchar(50);not null"`
+	Success       bool      `json:"success"`
+	FailureReason string    `json:"failure_reason" gorm:"type:varchar(32)"`
+	IP            string    `json:"ip" gorm:"type:varchar(64)"`
+	UserAgent     string    `json:"user_agent" gorm:"type:varchar(512)"`
+	DeviceHash    string    `json:"-" gorm:"type:char(64)"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (LoginRecord) TableName() string {
+	return "login_history"
+}
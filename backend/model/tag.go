@@ -4,11 +4,26 @@ import (
 	"time"
 )
 
+// 标签审核状态
+const (
+	TagStatusApproved = "Approved" // 已通过，对外展示并参与搜索
+	TagStatusPending  = "Pending"  // 卖家提议，待管理员审核
+	TagStatusRejected = "Rejected" // 已驳回，同名提议将被忽略
+)
+
 // Tag 商品标签模型
+// CategoryID 为主分类，标签适用的全部分类保存在 tag_categories 关联表中（包含主分类）
 type Tag struct {
-	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name       string    `json:"name" gorm:"type:varchar(50);not null;uniqueIndex"`
-	CategoryID int64     `json:"categoryId" gorm:"column:category_id;not null"`
+	ID          int64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string   `json:"name" gorm:"type:varchar(50);not null;uniqueIndex"`
+	CategoryID  int64    `json:"categoryId" gorm:"column:category_id;not null"`
+	CategoryIDs []int64  `json:"categoryIds" gorm:"-"`
+	Aliases     []string `json:"aliases" gorm:"-"`
+	Status      string   `json:"status" gorm:"type:varchar(16);not null;default:Approved"`
+	CreatedBy   *int64   `json:"createdBy,omitempty" gorm:"column:created_by"`
+	SchoolID    int64    `json:"schoolId" gorm:"column:school_id;not null"`
+	// UsageCount 使用该标签的商品数，仅在管理端列表查询时填充
+	UsageCount int64     `json:"usageCount" gorm:"column:usage_count;->;-:migration"`
 	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
@@ -17,3 +32,27 @@ type Tag struct {
 func (Tag) TableName() string {
 	return "tags"
 }
+
+// TagCategory 标签与分类的关联
+type TagCategory struct {
+	TagID      int64 `gorm:"column:tag_id;primaryKey"`
+	CategoryID int64 `gorm:"column:category_id;primaryKey"`
+}
+
+// TableName 指定表名
+func (TagCategory) TableName() string {
+	return "tag_categories"
+}
+
+// TagAlias 标签同义词，搜索时命中别名等同于命中标签
+type TagAlias struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	TagID     int64     `gorm:"column:tag_id;not null;index"`
+	Alias     string    `gorm:"type:varchar(50);not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (TagAlias) TableName() string {
+	return "tag_aliases"
+}
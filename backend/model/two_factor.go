@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// UserTwoFactor 用户两步验证（TOTP）设置
+// EnabledAt 为空表示已生成密钥但尚未确认绑定；LastUsedStep 记录最近验证通过的时间步，防止验证码重放
+type UserTwoFactor struct {
+	UserID       int64      `json:"user_id" gorm:"primaryKey"`
+	Secret       string     `json:"-" gorm:"type:varchar(64);not null"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep int64      `json:"-" gorm:"not null;default:0"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (UserTwoFactor) TableName() string {
+	return "user_two_factor"
+}
+
+// TwoFactorBackupCode 两步验证备用码，只保存 SHA-256 哈希，每个备用码只能使用一次
+type TwoFactorBackupCode struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `json:"user_id" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"type:char(64);not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (TwoFactorBackupCode) TableName() string {
+	return "two_factor_backup_codes"
+}
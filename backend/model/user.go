@@ -13,6 +13,16 @@ import "time"
 //   - Password: 密码哈希值（bcrypt加密后的字符串，不会返回给前端）
 //   - AvatarURL: 头像图片URL地址
 //   - IsAdmin: 是否为管理员（true=管理员，false=普通用户）
+//   - MustResetPassword: 是否需要在下次登录后修改密码（管理员强制重置时置为true）
+//   - LastLoginAt: 最近一次成功登录的时间
+//   - DefaultZoneID: 默认校园片区，首页优先推荐该片区的商品
+//   - SchoolID: 所属学校（租户），登录后的请求固定在该学校内
+//   - IsSuperAdmin: 是否超级管理员，可切换并管理任意学校
+//   - Email: 校园邮箱（可为空，不区分大小写全局唯一）
+//   - EmailVerifiedAt: 邮箱验证通过时间，为空表示未验证
+//   - EmailSentAt: 最近一次发送验证邮件的时间，用于限制重发频率
+//   - TokensRevokedAt: 登录令牌作废时间，签发早于该时间的令牌失效（重置密码后写入）
+//   - DeletedAt: 账号注销时间，注销后资料被匿名化（不使用GORM软删除，注销用户仍可被关联查询）
 //   - CreatedAt: 账号创建时间
 //   - UpdatedAt: 最后更新时间（GORM自动维护）
 //
@@ -26,6 +36,8 @@ import "time"
 //     wechat_id VARCHAR(64),
 //     is_admin BOOLEAN DEFAULT FALSE,
 //     last_nickname_changed_at TIMESTAMP,
+//     must_reset_password BOOLEAN DEFAULT FALSE,
+//     last_login_at TIMESTAMP,
 //     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 //     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 //   );
@@ -44,6 +56,16 @@ type User struct {
 	IsAdmin               bool       `json:"is_admin" gorm:"default:false"`                // 是否管理员
 	WechatID              string     `json:"wechat_id" gorm:"size:64"`                     // 微信号
 	LastNicknameChangedAt *time.Time `json:"last_nickname_changed_at" gorm:"index"`        // 最后昵称修改时间
+	MustResetPassword     bool       `json:"must_reset_password" gorm:"default:false"`     // 管理员强制重置密码后，登录需先修改密码
+	LastLoginAt           *time.Time `json:"last_login_at"`                                // 最近登录时间
+	DefaultZoneID         *int64     `json:"default_zone_id"`                              // 默认校园片区
+	SchoolID              int64      `json:"school_id"`                                    // 所属学校
+	IsSuperAdmin          bool       `json:"is_super_admin" gorm:"default:false"`          // 是否超级管理员（可管理全部学校）
+	Email                 *string    `json:"email" gorm:"size:255"`                        // 校园邮箱
+	EmailVerifiedAt       *time.Time `json:"email_verified_at"`                            // 邮箱验证时间
+	EmailSentAt           *time.Time `json:"-"`                                            // 最近发送验证邮件时间
+	TokensRevokedAt       *time.Time `json:"-"`                                            // 登录令牌作废时间
+	DeletedAt             *time.Time `json:"deleted_at"`                                   // 账号注销时间
 	CreatedAt             time.Time  `json:"created_at" gorm:"autoCreateTime"`             // 创建时间
 	UpdatedAt             time.Time  `json:"updated_at" gorm:"autoUpdateTime"`             // 更新时间
 }
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// DeletedUserNickname 注销用户的展示昵称
+const DeletedUserNickname = "已注销用户"
+
+// ViewHistoryRecord 浏览记录导出行，商品已被删除时标题为空
+type ViewHistoryRecord struct {
+	ProductID int64     `json:"productId"`
+	Title     string    `json:"title"`
+	ViewedAt  time.Time `json:"viewedAt"`
+}
+
+// AccountRepository 账号数据导出与注销仓库接口
+type AccountRepository interface {
+	// ListProducts 获取用户发布的全部商品（不限状态，按发布时间倒序）
+	ListProducts(ctx context.Context, sellerID int64) ([]model.Product, error)
+	// ListProductImages 获取指定商品的全部图片
+	ListProductImages(ctx context.Context, productIDs []int64) ([]model.ProductImage, error)
+	// ListViewHistory 获取用户的浏览记录（按浏览时间倒序）
+	ListViewHistory(ctx context.Context, userID int64) ([]ViewHistoryRecord, error)
+	// Anonymize 在一个事务中注销账号，返回被下架的商品数，账号不存在或已注销时返回 gorm.ErrRecordNotFound
+	Anonymize(ctx context.Context, userID int64, passwordHash string) (int64, error)
+}
+
+// accountRepo 仓库实现
+type accountRepo struct {
+	db *gorm.DB
+}
+
+// NewAccountRepository 创建账号仓库
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return &accountRepo{db: db}
+}
+
+// ListProducts 获取用户发布的全部商品
+func (r *accountRepo) ListProducts(ctx context.Context, sellerID int64) ([]model.Product, error) {
+	var products []model.Product
+	err := r.db.WithContext(ctx).
+		Where("seller_id = ?", sellerID).
+		Order("created_at DESC, id DESC").
+		Find(&products).Error
+	return products, err
+}
+
+// ListProductImages 获取指定商品的全部图片
+func (r *accountRepo) ListProductImages(ctx context.Context, productIDs []int64) ([]model.ProductImage, error) {
+	var images []model.ProductImage
+	if len(productIDs) == 0 {
+		return images, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("product_id IN ?", productIDs).
+		Order("product_id, sort_order, id").
+		Find(&images).Error
+	return images, err
+}
+
+// ListViewHistory 获取用户的浏览记录
+func (r *accountRepo) ListViewHistory(ctx context.Context, userID int64) ([]ViewHistoryRecord, error) {
+	var records []ViewHistoryRecord
+	err := r.db.WithContext(ctx).
+		Table("user_recent_views AS v").
+		Select("v.product_id, COALESCE(p.title, '') AS title, v.viewed_at").
+		Joins("LEFT JOIN products p ON p.id = v.product_id").
+		Where("v.user_id = ?", userID).
+		Order("v.viewed_at DESC, v.id DESC").
+		Scan(&records).Error
+	return records, err
+}
+
+// Anonymize 注销账号
+//
+// 处理内容：
+//   - 在售商品全部下架；已售商品保留，卖家显示为已注销用户
+//   - 删除浏览记录、会话、登录记录、通知、两步验证、选修课程等个人数据
+//   - 账号改为 deleted<ID>，清空头像、微信号、邮箱等资料，写入不可登录的密码哈希并作废已签发的令牌
+func (r *accountRepo) Anonymize(ctx context.Context, userID int64, passwordHash string) (int64, error) {
+	var delisted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NULL", userID).
+			First(&user).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.Product{}).
+			Where("seller_id = ? AND status = ?", userID, "ForSale").
+			Update("status", "Delisted")
+		if result.Error != nil {
+			return result.Error
+		}
+		delisted = result.RowsAffected
+
+		for _, table := range []string{
+			"user_recent_views",
+			"sessions",
+			"password_reset_tokens",
+			"login_history",
+			"notifications",
+			"two_factor_backup_codes",
+			"user_two_factor",
+			"user_courses",
+			"user_posting_quotas",
+		} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE user_id = ?", userID).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		return tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"account":                  fmt.Sprintf("deleted%d", userID),
+			"nickname":                 DeletedUserNickname,
+			"password_hash":            passwordHash,
+			"avatar_url":               nil,
+			"wechat_id":                nil,
+			"email":                    nil,
+			"email_verified_at":        nil,
+			"email_sent_at":            nil,
+			"default_zone_id":          nil,
+			"last_login_at":            nil,
+			"last_nickname_changed_at": nil,
+			"must_reset_password":      false,
+			"tokens_revoked_at":        now,
+			"deleted_at":               now,
+		}).Error
+	})
+	return delisted, err
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// BookImport 单条图书导入数据
+type BookImport struct {
+	Book        model.Book
+	CourseCodes []string
+	// ReplaceCourses 为true时用 CourseCodes 替换已有的课程关联（CSV包含课程列时）
+	ReplaceCourses bool
+}
+
+// BookRepository 图书目录仓库接口
+type BookRepository interface {
+	GetByISBN(ctx context.Context, isbn string) (*model.Book, error)
+	ListByIDs(ctx context.Context, ids []int64) ([]model.Book, error)
+	// List 分页查询图书，关键词匹配ISBN、书名、作者或课程代码
+	List(ctx context.Context, keyword, courseCode string, page, pageSize int) ([]model.Book, int64, error)
+	// ListCourseCodes 批量获取图书关联的课程代码
+	ListCourseCodes(ctx context.Context, bookIDs []int64) (map[int64][]string, error)
+	// ListCoursesByCodes 获取与指定课程关联的图书关系
+	ListCoursesByCodes(ctx context.Context, courseCodes []string) ([]model.BookCourse, error)
+	// Import 在一个事务中按ISBN新增或更新图书，返回新增与更新的数量
+	Import(ctx context.Context, items []BookImport) (created, updated int, err error)
+	// ListUserCourses 获取用户选修的课程代码
+	ListUserCourses(ctx context.Context, userID int64) ([]string, error)
+	// ReplaceUserCourses 替换用户选修的课程
+	ReplaceUserCourses(ctx context.Context, userID int64, courseCodes []string) error
+}
+
+// bookRepo 图书目录仓库实现
+type bookRepo struct {
+	db *gorm.DB
+}
+
+// NewBookRepository 创建图书目录仓库实例
+func NewBookRepository(db *gorm.DB) BookRepository {
+	return &bookRepo{db: db}
+}
+
+// GetByISBN 根据规范化的ISBN获取图书
+func (r *bookRepo) GetByISBN(ctx context.Context, isbn string) (*model.Book, error) {
+	var book model.Book
+	if err := r.db.WithContext(ctx).Where("isbn = ?", isbn).First(&book).Error; err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// ListByIDs 根据ID列表获取图书
+func (r *bookRepo) ListByIDs(ctx context.Context, ids []int64) ([]model.Book, error) {
+	books := make([]model.Book, 0)
+	if len(ids) == 0 {
+		return books, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Order("title ASC, id ASC").Find(&books).Error
+	return books, err
+}
+
+// List 分页查询图书
+func (r *bookRepo) List(ctx context.Context, keyword, courseCode string, page, pageSize int) ([]model.Book, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Book{})
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		if courseCode != "" {
+			query = query.Where("isbn LIKE ? OR title ILIKE ? OR author ILIKE ? OR id IN (SELECT book_id FROM book_courses WHERE course_code = ?)",
+				like, like, like, courseCode)
+		} else {
+			query = query.Where("isbn LIKE ? OR title ILIKE ? OR author ILIKE ?", like, like, like)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count books failed: %w", err)
+	}
+
+	var books []model.Book
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&books).Error; err != nil {
+		return nil, 0, fmt.Errorf("list books failed: %w", err)
+	}
+	return books, total, nil
+}
+
+// ListCourseCodes 批量获取图书关联的课程代码
+func (r *bookRepo) ListCourseCodes(ctx context.Context, bookIDs []int64) (map[int64][]string, error) {
+	result := make(map[int64][]string, len(bookIDs))
+	if len(bookIDs) == 0 {
+		return result, nil
+	}
+	var relations []model.BookCourse
+	if err := r.db.WithContext(ctx).
+		Where("book_id IN ?", bookIDs).
+		Order("course_code ASC").
+		Find(&relations).Error; err != nil {
+		return nil, err
+	}
+	for _, rel := range relations {
+		result[rel.BookID] = append(result[rel.BookID], rel.CourseCode)
+	}
+	return result, nil
+}
+
+// ListCoursesByCodes 获取与指定课程关联的图书关系
+func (r *bookRepo) ListCoursesByCodes(ctx context.Context, courseCodes []string) ([]model.BookCourse, error) {
+	relations := make([]model.BookCourse, 0)
+	if len(courseCodes) == 0 {
+		return relations, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("course_code IN ?", courseCodes).
+		Order("course_code ASC, book_id ASC").
+		Find(&relations).Error
+	return relations, err
+}
+
+// Import 在一个事务中按ISBN新增或更新图书
+// 利用 xmax = 0 区分本次是插入还是冲突后更新
+func (r *bookRepo) Import(ctx context.Context, items []BookImport) (int, int, error) {
+	created, updated := 0, 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			var row struct {
+				ID       int64
+				Inserted bool
+			}
+			b := item.Book
+			if err := tx.Raw(`INSERT INTO books (isbn, title, author, edition, publisher)
+				VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (isbn) DO UPDATE SET
+					title = EXCLUDED.title,
+					author = EXCLUDED.author,
+					edition = EXCLUDED.edition,
+					publisher = EXCLUDED.publisher
+				RETURNING id, (xmax = 0) AS inserted`,
+				b.ISBN, b.Title, b.Author, b.Edition, b.Publisher).Scan(&row).Error; err != nil {
+				return fmt.Errorf("import book %s failed: %w", b.ISBN, err)
+			}
+			if row.Inserted {
+				created++
+			} else {
+				updated++
+			}
+
+			if !item.ReplaceCourses {
+				continue
+			}
+			if err := tx.Where("book_id = ?", row.ID).Delete(&model.BookCourse{}).Error; err != nil {
+				return fmt.Errorf("clear book courses failed: %w", err)
+			}
+			if len(item.CourseCodes) == 0 {
+				continue
+			}
+			relations := make([]model.BookCourse, 0, len(item.CourseCodes))
+			for _, code := range item.CourseCodes {
+				relations = append(relations, model.BookCourse{BookID: row.ID, CourseCode: code})
+			}
+			if err := tx.Create(&relations).Error; err != nil {
+				return fmt.Errorf("create book courses failed: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return created, updated, nil
+}
+
+// ListUserCourses 获取用户选修的课程代码
+func (r *bookRepo) ListUserCourses(ctx context.Context, userID int64) ([]string, error) {
+	codes := make([]string, 0)
+	err := r.db.WithContext(ctx).Model(&model.UserCourse{}).
+		Where("user_id = ?", userID).
+		Order("course_code ASC").
+		Pluck("course_code", &codes).Error
+	return codes, err
+}
+
+// ReplaceUserCourses 替换用户选修的课程
+func (r *bookRepo) ReplaceUserCourses(ctx context.Context, userID int64, courseCodes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserCourse{}).Error; err != nil {
+			return err
+		}
+		if len(courseCodes) == 0 {
+			return nil
+		}
+		courses := make([]model.UserCourse, 0, len(courseCodes))
+		for _, code := range courseCodes {
+			courses = append(courses, model.UserCourse{UserID: userID, CourseCode: code})
+		}
+		return tx.Create(&courses).Error
+	})
+}
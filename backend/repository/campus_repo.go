@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// CampusRepository 校园片区与面交地点仓库接口
+// 片区与面交地点查询限定在 context 中的当前学校内
+type CampusRepository interface {
+	// ListZones 获取全部片区及其面交地点，按排序值与ID排序
+	ListZones(ctx context.Context) ([]model.CampusZone, error)
+	GetZone(ctx context.Context, id int64) (*model.CampusZone, error)
+	CreateZone(ctx context.Context, zone *model.CampusZone) error
+	UpdateZone(ctx context.Context, zone *model.CampusZone) error
+	DeleteZone(ctx context.Context, id int64) error
+	// ExistsZoneName 判断片区名称是否已被其他片区使用
+	ExistsZoneName(ctx context.Context, name string, excludeID int64) (bool, error)
+	// CountPoints 统计片区下的面交地点数量
+	CountPoints(ctx context.Context, zoneID int64) (int64, error)
+
+	GetPoint(ctx context.Context, id int64) (*model.MeetupPoint, error)
+	// GetPointDetail 获取面交地点及所属片区名称
+	GetPointDetail(ctx context.Context, id int64) (*model.MeetupPointDTO, error)
+	CreatePoint(ctx context.Context, point *model.MeetupPoint) error
+	UpdatePoint(ctx context.Context, point *model.MeetupPoint) error
+	DeletePoint(ctx context.Context, id int64) error
+	// ExistsPointName 判断片区内是否已有同名面交地点
+	ExistsPointName(ctx context.Context, zoneID int64, name string, excludeID int64) (bool, error)
+	// CountForSaleProductsByPoint 统计使用该面交地点的在售商品数量
+	CountForSaleProductsByPoint(ctx context.Context, pointID int64) (int64, error)
+
+	// GetUserDefaultZone 获取用户的默认片区，未设置时返回 nil
+	GetUserDefaultZone(ctx context.Context, userID int64) (*int64, error)
+	// SetUserDefaultZone 设置用户的默认片区，zoneID 为 nil 时清除
+	SetUserDefaultZone(ctx context.Context, userID int64, zoneID *int64) error
+}
+
+// campusRepo 校园片区仓库实现
+type campusRepo struct {
+	db *gorm.DB
+}
+
+// NewCampusRepository 创建校园片区仓库实例
+func NewCampusRepository(db *gorm.DB) CampusRepository {
+	return &campusRepo{db: db}
+}
+
+// ListZones 获取全部片区及其面交地点
+func (r *campusRepo) ListZones(ctx context.Context) ([]model.CampusZone, error) {
+	var zones []model.CampusZone
+	if err := scopeTenant(ctx, r.db.WithContext(ctx), "campus_zones").Order("sort_order ASC, id ASC").Find(&zones).Error; err != nil {
+		return nil, err
+	}
+	var points []model.MeetupPoint
+	if err := scopeTenant(ctx, r.db.WithContext(ctx), "meetup_points").Order("sort_order ASC, id ASC").Find(&points).Error; err != nil {
+		return nil, err
+	}
+
+	byZone := make(map[int64][]model.MeetupPoint, len(zones))
+	for _, point := range points {
+		byZone[point.ZoneID] = append(byZone[point.ZoneID], point)
+	}
+	for i := range zones {
+		zones[i].Points = byZone[zones[i].ID]
+		if zones[i].Points == nil {
+			zones[i].Points = []model.MeetupPoint{}
+		}
+	}
+	return zones, nil
+}
+
+// GetZone 根据ID获取片区
+func (r *campusRepo) GetZone(ctx context.Context, id int64) (*model.CampusZone, error) {
+	var zone model.CampusZone
+	if err := scopeTenant(ctx, r.db.WithContext(ctx), "campus_zones").First(&zone, id).Error; err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+// CreateZone 在当前学校下创建片区
+func (r *campusRepo) CreateZone(ctx context.Context, zone *model.CampusZone) error {
+	zone.SchoolID = tenant.SchoolID(ctx)
+	return r.db.WithContext(ctx).Create(zone).Error
+}
+
+// UpdateZone 更新片区名称、描述与排序值
+func (r *campusRepo) UpdateZone(ctx context.Context, zone *model.CampusZone) error {
+	result := scopeTenant(ctx, r.db.WithContext(ctx), "campus_zones").Model(&model.CampusZone{}).Where("id = ?", zone.ID).Updates(map[string]interface{}{
+		"name":        zone.Name,
+		"description": zone.Description,
+		"sort_order":  zone.SortOrder,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteZone 删除片区（用户默认片区由外键置空）
+func (r *campusRepo) DeleteZone(ctx context.Context, id int64) error {
+	return scopeTenant(ctx, r.db.WithContext(ctx), "campus_zones").Delete(&model.CampusZone{}, id).Error
+}
+
+// ExistsZoneName 判断片区名称是否已被其他片区使用
+func (r *campusRepo) ExistsZoneName(ctx context.Context, name string, excludeID int64) (bool, error) {
+	var count int64
+	err := scopeTenant(ctx, r.db.WithContext(ctx), "campus_zones").Model(&model.CampusZone{}).
+		Where("name = ? AND id <> ?", name, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountPoints 统计片区下的面交地点数量
+func (r *campusRepo) CountPoints(ctx context.Context, zoneID int64) (int64, error) {
+	var count int64
+	err := scopeTenant(ctx, r.db.WithContext(ctx), "meetup_points").Model(&model.MeetupPoint{}).Where("zone_id = ?", zoneID).Count(&count).Error
+	return count, err
+}
+
+// GetPoint 根据ID获取面交地点
+func (r *campusRepo) GetPoint(ctx context.Context, id int64) (*model.MeetupPoint, error) {
+	var point model.MeetupPoint
+	if err := scopeTenant(ctx, r.db.WithContext(ctx), "meetup_points").First(&point, id).Error; err != nil {
+		return nil, err
+	}
+	return &point, nil
+}
+
+// GetPointDetail 获取面交地点及所属片区名称
+func (r *campusRepo) GetPointDetail(ctx context.Context, id int64) (*model.MeetupPointDTO, error) {
+	var detail model.MeetupPointDTO
+	result := r.db.WithContext(ctx).Raw(`SELECT mp.id, mp.name, mp.zone_id, cz.name AS zone_name
+		FROM meetup_points mp
+		JOIN campus_zones cz ON cz.id = mp.zone_id
+		WHERE mp.id = ? AND mp.school_id = ?`, id, tenant.SchoolID(ctx)).Scan(&detail)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &detail, nil
+}
+
+// CreatePoint 在当前学校下创建面交地点，所属片区需属于同一学校
+func (r *campusRepo) CreatePoint(ctx context.Context, point *model.MeetupPoint) error {
+	point.SchoolID = tenant.SchoolID(ctx)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureZoneInSchool(tx, point.SchoolID, point.ZoneID); err != nil {
+			return err
+		}
+		return tx.Create(point).Error
+	})
+}
+
+// UpdatePoint 更新面交地点（可调整到当前学校的其他片区）
+func (r *campusRepo) UpdatePoint(ctx context.Context, point *model.MeetupPoint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureZoneInSchool(tx, tenant.SchoolID(ctx), point.ZoneID); err != nil {
+			return err
+		}
+		result := scopeTenant(ctx, tx, "meetup_points").Model(&model.MeetupPoint{}).Where("id = ?", point.ID).Updates(map[string]interface{}{
+			"zone_id":     point.ZoneID,
+			"name":        point.Name,
+			"description": point.Description,
+			"sort_order":  point.SortOrder,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// DeletePoint 删除面交地点（已下架或已售商品上的引用由外键置空）
+func (r *campusRepo) DeletePoint(ctx context.Context, id int64) error {
+	return scopeTenant(ctx, r.db.WithContext(ctx), "meetup_points").Delete(&model.MeetupPoint{}, id).Error
+}
+
+// ExistsPointName 判断片区内是否已有同名面交地点
+func (r *campusRepo) ExistsPointName(ctx context.Context, zoneID int64, name string, excludeID int64) (bool, error) {
+	var count int64
+	err := scopeTenant(ctx, r.db.WithContext(ctx), "meetup_points").Model(&model.MeetupPoint{}).
+		Where("zone_id = ? AND name = ? AND id <> ?", zoneID, name, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountForSaleProductsByPoint 统计使用该面交地点的在售商品数量
+func (r *campusRepo) CountForSaleProductsByPoint(ctx context.Context, pointID int64) (int64, error) {
+	var count int64
+	err := scopeTenant(ctx, r.db.WithContext(ctx), "products").Model(&model.Product{}).
+		Where("meetup_point_id = ? AND status = ?", pointID, "ForSale").
+		Count(&count).Error
+	return count, err
+}
+
+// GetUserDefaultZone 获取用户的默认片区
+func (r *campusRepo) GetUserDefaultZone(ctx context.Context, userID int64) (*int64, error) {
+	var user model.User
+	if err := r.db.WithContext(ctx).Select("id", "default_zone_id").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	return user.DefaultZoneID, nil
+}
+
+// SetUserDefaultZone 设置用户的默认片区，片区需属于用户所属学校
+// 超级管理员切换管理的学校后，context 中的学校可能与其账号所属学校不同，因此按用户的学校校验
+func (r *campusRepo) SetUserDefaultZone(ctx context.Context, userID int64, zoneID *int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Select("id", "school_id").First(&user, userID).Error; err != nil {
+			return err
+		}
+		if zoneID != nil {
+			if err := ensureZoneInSchool(tx, user.SchoolID, *zoneID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.User{}).Where("id = ?", userID).Update("default_zone_id", zoneID).Error
+	})
+}
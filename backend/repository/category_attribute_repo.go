@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// categoryAncestorsSQL 递归查询某分类及其全部祖先分类，depth 为到该分类的距离（自身为0）
+const categoryAncestorsSQL = `WITH RECURSIVE category_ancestors AS (
+	SELECT id, parent_id, 0 AS depth FROM categories WHERE id = ?
+	UNION ALL
+	SELECT c.id, c.parent_id, a.depth + 1 FROM categories c JOIN category_ancestors a ON c.id = a.parent_id
+)`
+
+// CategoryAttributeRepository 分类属性定义仓库接口
+type CategoryAttributeRepository interface {
+	// ListByCategory 获取分类自身定义的属性
+	ListByCategory(ctx context.Context, categoryID int64) ([]model.CategoryAttribute, error)
+	// ListEffective 获取分类生效的属性（含从祖先分类继承的属性，同名属性取最近的定义）
+	ListEffective(ctx context.Context, categoryID int64) ([]model.CategoryAttribute, error)
+	GetByID(ctx context.Context, id int64) (*model.CategoryAttribute, error)
+	Create(ctx context.Context, attr *model.CategoryAttribute) error
+	Update(ctx context.Context, attr *model.CategoryAttribute) error
+	Delete(ctx context.Context, id int64) error
+	// ExistsKey 判断分类下是否已定义同名属性
+	ExistsKey(ctx context.Context, categoryID int64, key string) (bool, error)
+}
+
+// categoryAttributeRepo 分类属性定义仓库实现
+type categoryAttributeRepo struct {
+	db *gorm.DB
+}
+
+// NewCategoryAttributeRepository 创建分类属性定义仓库实例
+func NewCategoryAttributeRepository(db *gorm.DB) CategoryAttributeRepository {
+	return &categoryAttributeRepo{db: db}
+}
+
+// ListByCategory 获取分类自身定义的属性，按排序值与ID排序
+func (r *categoryAttributeRepo) ListByCategory(ctx context.Context, categoryID int64) ([]model.CategoryAttribute, error) {
+	var attrs []model.CategoryAttribute
+	err := r.db.WithContext(ctx).
+		Where("category_id = ?", categoryID).
+		Order("sort_order ASC, id ASC").
+		Find(&attrs).Error
+	return attrs, err
+}
+
+// ListEffective 获取分类生效的属性
+// 祖先分类的属性排在前面，同名属性只保留距离最近的分类中的定义
+func (r *categoryAttributeRepo) ListEffective(ctx context.Context, categoryID int64) ([]model.CategoryAttribute, error) {
+	var attrs []model.CategoryAttribute
+	err := r.db.WithContext(ctx).Raw(categoryAncestorsSQL+`
+		SELECT ca.* FROM (
+			SELECT DISTINCT ON (attr.attr_key) attr.*, a.depth
+			FROM category_attributes attr
+			JOIN category_ancestors a ON attr.category_id = a.id
+			ORDER BY attr.attr_key, a.depth ASC
+		) ca
+		ORDER BY ca.depth DESC, ca.sort_order ASC, ca.id ASC`, categoryID).
+		Scan(&attrs).Error
+	return attrs, err
+}
+
+// GetByID 根据ID获取属性定义
+func (r *categoryAttributeRepo) GetByID(ctx context.Context, id int64) (*model.CategoryAttribute, error) {
+	var attr model.CategoryAttribute
+	if err := r.db.WithContext(ctx).First(&attr, id).Error; err != nil {
+		return nil, err
+	}
+	return &attr, nil
+}
+
+// Create 创建属性定义
+func (r *categoryAttributeRepo) Create(ctx context.Context, attr *model.CategoryAttribute) error {
+	return r.db.WithContext(ctx).Create(attr).Error
+}
+
+// Update 更新属性定义（属性键、类型与所属分类创建后不可修改）
+func (r *categoryAttributeRepo) Update(ctx context.Context, attr *model.CategoryAttribute) error {
+	result := r.db.WithContext(ctx).Model(&model.CategoryAttribute{}).Where("id = ?", attr.ID).Updates(map[string]interface{}{
+		"label":      attr.Label,
+		"required":   attr.Required,
+		"options":    attr.Options,
+		"unit":       attr.Unit,
+		"sort_order": attr.SortOrder,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete 删除属性定义（已发布商品中的属性值保留，不再参与校验）
+func (r *categoryAttributeRepo) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.CategoryAttribute{}, id).Error
+}
+
+// ExistsKey 判断分类下是否已定义同名属性
+func (r *categoryAttributeRepo) ExistsKey(ctx context.Context, categoryID int64, key string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.CategoryAttribute{}).
+		Where("category_id = ? AND attr_key = ?", categoryID, key).
+		Count(&count).Error
+	return count > 0, err
+}
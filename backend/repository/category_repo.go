@@ -2,13 +2,31 @@ package repository
 
 import (
 	"context"
+	"errors"
 
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// 移动分类的错误
+var (
+	ErrCategoryParentNotFound = errors.New("parent category not found")
+	ErrCategoryCycle          = errors.New("category cannot be moved under itself or its descendants")
+)
+
+// categorySubtreeSQL 递归查询某分类及其全部后代分类ID的子查询，参数为根分类ID
+// 使用 UNION 去重，即使数据中已存在环也能终止
+const categorySubtreeSQL = `WITH RECURSIVE category_subtree AS (
+	SELECT id FROM categories WHERE id = ?
+	UNION
+	SELECT c.id FROM categories c JOIN category_subtree s ON c.parent_id = s.id
+) SELECT id FROM category_subtree`
+
 // CategoryRepository 分类仓库接口
+// 全部查询限定在 context 中的当前学校内
 type CategoryRepository interface {
 	ListAll(ctx context.Context) ([]model.Category, error)
 	Create(ctx context.Context, category *model.Category) error
@@ -16,6 +34,17 @@ type CategoryRepository interface {
 	Delete(ctx context.Context, id int64) error
 	CountProductsByCategory(ctx context.Context, id int64) (int64, error)
 	GetByID(ctx context.Context, id int64) (*model.Category, error)
+	// CountChildren 统计直接子分类数量
+	CountChildren(ctx context.Context, id int64) (int64, error)
+	// ListDescendantIDs 返回分类自身及全部后代分类ID
+	ListDescendantIDs(ctx context.Context, id int64) ([]int64, error)
+	// NextSortOrder 返回同级分类中下一个可用的排序值
+	NextSortOrder(ctx context.Context, parentID *int64) (int, error)
+	// Move 将分类移动到新的父分类下，sortOrder 为空时同级内保持原排序、跨级时排在末尾
+	// 父分类不存在返回 ErrCategoryParentNotFound，目标为自身或后代返回 ErrCategoryCycle
+	Move(ctx context.Context, id int64, parentID *int64, sortOrder *int) (*model.Category, error)
+	// Reorder 按给定顺序重排同一父分类下的子分类
+	Reorder(ctx context.Context, parentID *int64, orderedIDs []int64) error
 }
 
 // categoryRepo 分类仓库实现
@@ -28,41 +57,174 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 	return &categoryRepo{db: db}
 }
 
-// ListAll 获取所有分类
+// ListAll 获取所有分类，按同级排序值与ID排序
 func (r *categoryRepo) ListAll(ctx context.Context) ([]model.Category, error) {
 	var categories []model.Category
-	err := r.db.WithContext(ctx).Find(&categories).Error
+	err := scopeTenant(ctx, r.db.WithContext(ctx), "categories").Order("sort_order ASC, id ASC").Find(&categories).Error
 	return categories, err
 }
 
-// Create 创建分类
+// Create 在当前学校下创建分类
 func (r *categoryRepo) Create(ctx context.Context, category *model.Category) error {
+	category.SchoolID = tenant.SchoolID(ctx)
 	return r.db.WithContext(ctx).Create(category).Error
 }
 
-// Update 更新分类
+// Update 更新分类名称与描述（层级与排序通过 Move/Reorder 修改）
 func (r *categoryRepo) Update(ctx context.Context, category *model.Category) error {
-	return r.db.WithContext(ctx).Save(category).Error
+	result := scopeTenant(ctx, r.db.WithContext(ctx), "categories").Model(&model.Category{}).Where("id = ?", category.ID).Updates(map[string]interface{}{
+		"name":        category.Name,
+		"description": category.Description,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 // Delete 删除分类
 func (r *categoryRepo) Delete(ctx context.Context, id int64) error {
-	return r.db.WithContext(ctx).Delete(&model.Category{}, id).Error
+	return scopeTenant(ctx, r.db.WithContext(ctx), "categories").Delete(&model.Category{}, id).Error
 }
 
 // CountProductsByCategory 统计分类下的商品数量
 func (r *categoryRepo) CountProductsByCategory(ctx context.Context, id int64) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&model.Product{}).Where("category_id = ?", id).Count(&count).Error
+	err := scopeTenant(ctx, r.db.WithContext(ctx), "products").Model(&model.Product{}).Where("category_id = ?", id).Count(&count).Error
 	return count, err
 }
 
 // GetByID 根据ID获取分类
 func (r *categoryRepo) GetByID(ctx context.Context, id int64) (*model.Category, error) {
 	var category model.Category
-	err := r.db.WithContext(ctx).First(&category, id).Error
+	err := scopeTenant(ctx, r.db.WithContext(ctx), "categories").First(&category, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// CountChildren 统计直接子分类数量
+func (r *categoryRepo) CountChildren(ctx context.Context, id int64) (int64, error) {
+	var count int64
+	err := scopeTenant(ctx, r.db.WithContext(ctx), "categories").Model(&model.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// ListDescendantIDs 返回分类自身及全部后代分类ID
+func (r *categoryRepo) ListDescendantIDs(ctx context.Context, id int64) ([]int64, error) {
+	ids := make([]int64, 0)
+	err := r.db.WithContext(ctx).Raw("SELECT id FROM categories WHERE school_id = ? AND id IN ("+categorySubtreeSQL+")",
+		tenant.SchoolID(ctx), id).Scan(&ids).Error
+	return ids, err
+}
+
+// NextSortOrder 返回同级分类中下一个可用的排序值
+func (r *categoryRepo) NextSortOrder(ctx context.Context, parentID *int64) (int, error) {
+	var maxOrder *int
+	query := scopeTenant(ctx, r.db.WithContext(ctx), "categories").Model(&model.Category{}).Select("MAX(sort_order)")
+	query = whereParent(query, parentID)
+	if err := query.Scan(&maxOrder).Error; err != nil {
+		return 0, err
+	}
+	if maxOrder == nil {
+		return 0, nil
+	}
+	return *maxOrder + 1, nil
+}
+
+// Move 移动分类
+// 循环检查与更新在同一事务内完成，并锁定当前学校的全部分类行：
+// 仅锁定被移动的分类与目标父分类不足以阻止两个并发移动（A 移到 B 的子分类下、B 移到 A 的子分类下）共同形成环
+func (r *categoryRepo) Move(ctx context.Context, id int64, parentID *int64, sortOrder *int) (*model.Category, error) {
+	var category model.Category
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked []int64
+		if err := scopeTenant(ctx, tx.Model(&model.Category{}), "categories").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Order("id").Pluck("id", &locked).Error; err != nil {
+			return err
+		}
+
+		if err := scopeTenant(ctx, tx, "categories").First(&category, id).Error; err != nil {
+			return err
+		}
+
+		if parentID != nil {
+			var count int64
+			if err := scopeTenant(ctx, tx.Model(&model.Category{}), "categories").Where("id = ?", *parentID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrCategoryParentNotFound
+			}
+			if err := tx.Raw("SELECT COUNT(*) FROM ("+categorySubtreeSQL+") s WHERE id = ?", id, *parentID).Scan(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrCategoryCycle
+			}
+		}
+
+		order := category.SortOrder
+		if sortOrder != nil {
+			order = *sortOrder
+		} else if !sameParentID(category.ParentID, parentID) {
+			var maxOrder *int
+			query := scopeTenant(ctx, tx.Model(&model.Category{}), "categories").Select("MAX(sort_order)")
+			if err := whereParent(query, parentID).Scan(&maxOrder).Error; err != nil {
+				return err
+			}
+			order = 0
+			if maxOrder != nil {
+				order = *maxOrder + 1
+			}
+		}
+
+		if err := tx.Model(&model.Category{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"parent_id":  parentID,
+			"sort_order": order,
+		}).Error; err != nil {
+			return err
+		}
+		category.ParentID = parentID
+		category.SortOrder = order
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	return &category, nil
 }
+
+// Reorder 按给定顺序重排同一父分类下的子分类，排序值从0开始连续分配
+func (r *categoryRepo) Reorder(ctx context.Context, parentID *int64, orderedIDs []int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range orderedIDs {
+			query := scopeTenant(ctx, tx.Model(&model.Category{}), "categories").Where("id = ?", id)
+			if err := whereParent(query, parentID).Update("sort_order", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// sameParentID 判断两个父分类ID是否相同（均为空视为相同）
+func sameParentID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// whereParent 按父分类过滤，parentID 为空时匹配顶级分类
+func whereParent(query *gorm.DB, parentID *int64) *gorm.DB {
+	if parentID == nil {
+		return query.Where("parent_id IS NULL")
+	}
+	return query.Where("parent_id = ?", *parentID)
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// staleRunError 实例异常退出、执行锁过期的执行记录写入的错误信息
+const staleRunError = "执行锁已过期（实例退出或执行超时）"
+
+// JobResult 一次执行的结果，用于更新任务状态与执行记录
+type JobResult struct {
+	Status     string
+	Error      *string
+	FinishedAt time.Time
+	DurationMs int64
+	NextRunAt  time.Time // 下次执行时间（成功或重试用尽时为下一个计划时间，否则为重试时间）
+	Attempt    int       // 下次执行的重试序号
+}
+
+// JobRepository 定时任务仓库接口
+// 定时任务为全局配置，不按租户过滤
+type JobRepository interface {
+	// Sync 同步代码中注册的任务：不存在时创建；已存在时更新描述与重试次数，
+	// 计划变更时同时使用新的下次执行时间
+	Sync(ctx context.Context, job *model.ScheduledJob) error
+	// Claim 尝试获取到期（或被请求立即执行）任务的执行锁，成功时创建执行记录并返回；
+	// 任务未到期或锁被其他实例持有时返回 nil
+	Claim(ctx context.Context, name, instance string, now, lockUntil time.Time) (*model.JobRun, error)
+	// Finish 记录执行结果并释放执行锁
+	Finish(ctx context.Context, run *model.JobRun, result JobResult) error
+	// List 获取全部任务（按名称排序）
+	List(ctx context.Context) ([]model.ScheduledJob, error)
+	// ListRuns 获取任务最近的执行记录
+	ListRuns(ctx context.Context, name string, limit int) ([]model.JobRun, error)
+	// RequestRun 请求立即执行任务，任务不存在时返回 gorm.ErrRecordNotFound
+	RequestRun(ctx context.Context, name string, userID int64) error
+	// PruneRuns 删除早于 before 的已结束执行记录
+	PruneRuns(ctx context.Context, before time.Time) (int64, error)
+}
+
+// jobRepo 仓库实现
+type jobRepo struct {
+	db *gorm.DB
+}
+
+// NewJobRepository 创建定时任务仓库
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepo{db: db}
+}
+
+// Sync 同步任务定义
+func (r *jobRepo) Sync(ctx context.Context, job *model.ScheduledJob) error {
+	return r.db.WithContext(ctx).Exec(`
+INSERT INTO scheduled_jobs (name, description, schedule, max_retries, next_run_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (name) DO UPDATE SET
+	description = EXCLUDED.description,
+	max_retries = EXCLUDED.max_retries,
+	schedule = EXCLUDED.schedule,
+	next_run_at = CASE WHEN scheduled_jobs.schedule <> EXCLUDED.schedule
+		THEN EXCLUDED.next_run_at ELSE scheduled_jobs.next_run_at END,
+	attempt = CASE WHEN scheduled_jobs.schedule <> EXCLUDED.schedule
+		THEN 0 ELSE scheduled_jobs.attempt END`,
+		job.Name, job.Description, job.Schedule, job.MaxRetries, job.NextRunAt).Error
+}
+
+// Claim 获取执行锁
+// 行锁使用 SKIP LOCKED，多个实例同时轮询时只有一个能拿到任务
+func (r *jobRepo) Claim(ctx context.Context, name, instance string, now, lockUntil time.Time) (*model.JobRun, error) {
+	var run *model.JobRun
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job model.ScheduledJob
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("name = ?", name).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Where("next_run_at <= ? OR run_requested_at IS NOT NULL", now).
+			Take(&job).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+
+		// 上一次执行的实例未能释放锁（退出或超时），将其执行记录标记为失败
+		if err := tx.Model(&model.JobRun{}).
+			Where("job_name = ? AND status = ?", name, model.JobStatusRunning).
+			Updates(map[string]interface{}{
+				"status":      model.JobStatusFailed,
+				"error":       staleRunError,
+				"finished_at": now,
+			}).Error; err != nil {
+			return err
+		}
+
+		run = &model.JobRun{
+			JobName:   name,
+			Trigger:   model.JobTriggerSchedule,
+			Attempt:   job.Attempt,
+			Status:    model.JobStatusRunning,
+			Instance:  instance,
+			StartedAt: now,
+		}
+		if job.RunRequestedAt != nil {
+			run.Trigger = model.JobTriggerManual
+			run.TriggeredBy = job.RunRequestedBy
+		} else if job.Attempt > 0 {
+			run.Trigger = model.JobTriggerRetry
+		}
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.ScheduledJob{}).Where("name = ?", name).Updates(map[string]interface{}{
+			"locked_by":        instance,
+			"locked_until":     lockUntil,
+			"run_requested_at": nil,
+			"run_requested_by": nil,
+			"last_status":      model.JobStatusRunning,
+			"last_started_at":  now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// Finish 记录执行结果并释放执行锁
+// 锁已被其他实例接管时只更新执行记录，不覆盖任务状态
+func (r *jobRepo) Finish(ctx context.Context, run *model.JobRun, result JobResult) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.JobRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+			"status":      result.Status,
+			"error":       result.Error,
+			"finished_at": result.FinishedAt,
+			"duration_ms": result.DurationMs,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.ScheduledJob{}).
+			Where("name = ? AND locked_by = ?", run.JobName, run.Instance).
+			Updates(map[string]interface{}{
+				"locked_by":        nil,
+				"locked_until":     nil,
+				"last_status":      result.Status,
+				"last_finished_at": result.FinishedAt,
+				"last_duration_ms": result.DurationMs,
+				"last_error":       result.Error,
+				"next_run_at":      result.NextRunAt,
+				"attempt":          result.Attempt,
+			}).Error
+	})
+}
+
+// List 获取全部任务
+func (r *jobRepo) List(ctx context.Context) ([]model.ScheduledJob, error) {
+	var jobs []model.ScheduledJob
+	err := r.db.WithContext(ctx).Order("name").Find(&jobs).Error
+	return jobs, err
+}
+
+// ListRuns 获取任务最近的执行记录
+func (r *jobRepo) ListRuns(ctx context.Context, name string, limit int) ([]model.JobRun, error) {
+	var runs []model.JobRun
+	err := r.db.WithContext(ctx).
+		Where("job_name = ?", name).
+		Order("started_at DESC, id DESC").
+		Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
+
+// RequestRun 请求立即执行任务
+func (r *jobRepo) RequestRun(ctx context.Context, name string, userID int64) error {
+	result := r.db.WithContext(ctx).Model(&model.ScheduledJob{}).
+		Where("name = ?", name).
+		Updates(map[string]interface{}{
+			"run_requested_at": gorm.Expr("NOW()"),
+			"run_requested_by": userID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PruneRuns 删除过期的执行记录
+func (r *jobRepo) PruneRuns(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("started_at < ? AND status <> ?", before, model.JobStatusRunning).
+		Delete(&model.JobRun{})
+	return result.RowsAffected, result.Error
+}
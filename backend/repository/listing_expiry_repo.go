@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// listingActiveAt 商品最近活动时间：续期时间与卖家最近登录时间中较晚者（GREATEST 忽略 NULL）
+const listingActiveAt = "GREATEST(p.renewed_at, u.last_login_at)"
+
+// ExpiringListing 被提醒或被自动下架的商品
+type ExpiringListing struct {
+	ID       int64
+	SellerID int64
+	Title    string
+	ActiveAt time.Time // 最近活动时间
+}
+
+// ListingExpiryRepository 商品自动过期仓库接口
+// 定时任务跨学校执行，不按租户过滤
+type ListingExpiryRepository interface {
+	// MarkReminders 将最近活动早于 inactiveBefore、且在该次活动后尚未提醒过的在售商品标记为已提醒，返回被标记的商品
+	MarkReminders(ctx context.Context, inactiveBefore time.Time) ([]ExpiringListing, error)
+	// ExpireListings 将最近活动早于 inactiveBefore 的在售商品下架并标记为自动下架，返回被下架的商品
+	// remindedBefore 非零时只下架在该次活动后、且在 remindedBefore 之前已提醒过的商品，保证卖家有足够时间续期
+	ExpireListings(ctx context.Context, inactiveBefore, remindedBefore time.Time) ([]ExpiringListing, error)
+}
+
+// listingExpiryRepo 仓库实现
+type listingExpiryRepo struct {
+	db *gorm.DB
+}
+
+// NewListingExpiryRepository 创建商品自动过期仓库
+func NewListingExpiryRepository(db *gorm.DB) ListingExpiryRepository {
+	return &listingExpiryRepo{db: db}
+}
+
+// MarkReminders 标记需要提醒的商品
+// 使用 SKIP LOCKED 避免与卖家操作或其他实例互相阻塞
+func (r *listingExpiryRepo) MarkReminders(ctx context.Context, inactiveBefore time.Time) ([]ExpiringListing, error) {
+	var listings []ExpiringListing
+	err := r.db.WithContext(ctx).Raw(`
+WITH due AS (
+	SELECT p.id, `+listingActiveAt+` AS active_at
+	FROM products p
+	JOIN users u ON u.id = p.seller_id
+	WHERE p.status = 'ForSale'
+	  AND `+listingActiveAt+` < ?
+	  AND (p.expiry_reminded_at IS NULL OR p.expiry_reminded_at < `+listingActiveAt+`)
+	FOR UPDATE OF p SKIP LOCKED
+)
+UPDATE products p
+SET expiry_reminded_at = NOW()
+FROM due
+WHERE p.id = due.id
+RETURNING p.id, p.seller_id, p.title, due.active_at`, inactiveBefore).Scan(&listings).Error
+	return listings, err
+}
+
+// ExpireListings 下架长期无活动的商品
+func (r *listingExpiryRepo) ExpireListings(ctx context.Context, inactiveBefore, remindedBefore time.Time) ([]ExpiringListing, error) {
+	reminded := ""
+	args := []interface{}{inactiveBefore}
+	if !remindedBefore.IsZero() {
+		reminded = `
+	  AND p.expiry_reminded_at >= ` + listingActiveAt + `
+	  AND p.expiry_reminded_at < ?`
+		args = append(args, remindedBefore)
+	}
+
+	var listings []ExpiringListing
+	err := r.db.WithContext(ctx).Raw(`
+WITH due AS (
+	SELECT p.id, `+listingActiveAt+` AS active_at
+	FROM products p
+	JOIN users u ON u.id = p.seller_id
+	WHERE p.status = 'ForSale'
+	  AND `+listingActiveAt+` < ?`+reminded+`
+	FOR UPDATE OF p SKIP LOCKED
+)
+UPDATE products p
+SET status = 'Delisted', expired_at = NOW()
+FROM due
+WHERE p.id = due.id
+RETURNING p.id, p.seller_id, p.title, due.active_at`, args...).Scan(&listings).Error
+	return listings, err
+}
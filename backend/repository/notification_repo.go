@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// NotificationRepository 站内通知仓库接口
+type NotificationRepository interface {
+	// Create 创建通知
+	Create(ctx context.Context, notification *model.Notification) error
+	// ListByUser 分页获取用户的通知（按时间倒序），unreadOnly 为 true 时只返回未读通知
+	ListByUser(ctx context.Context, userID int64, unreadOnly bool, page, pageSize int) ([]model.Notification, int64, error)
+	// CountUnread 统计用户的未读通知数
+	CountUnread(ctx context.Context, userID int64) (int64, error)
+	// MarkRead 将用户的指定通知标记为已读，不存在时返回 gorm.ErrRecordNotFound
+	MarkRead(ctx context.Context, userID, id int64) error
+	// MarkAllRead 将用户的全部通知标记为已读，返回更新数量
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+}
+
+// notificationRepo 仓库实现
+type notificationRepo struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 创建站内通知仓库
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepo{db: db}
+}
+
+// Create 创建通知
+func (r *notificationRepo) Create(ctx context.Context, notification *model.Notification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+// ListByUser 分页获取用户的通知
+func (r *notificationRepo) ListByUser(ctx context.Context, userID int64, unreadOnly bool, page, pageSize int) ([]model.Notification, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []model.Notification
+	err := query.Order("created_at DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+// CountUnread 统计用户的未读通知数
+func (r *notificationRepo) CountUnread(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead 将用户的指定通知标记为已读（已读通知保持原已读时间）
+func (r *notificationRepo) MarkRead(ctx context.Context, userID, id int64) error {
+	result := r.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, NOW())"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MarkAllRead 将用户的全部未读通知标记为已读
+func (r *notificationRepo) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", gorm.Expr("NOW()"))
+	return result.RowsAffected, result.Error
+}
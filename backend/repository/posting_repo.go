@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// PostingRepository 发布限制仓库接口：屏蔽词、用户配额覆盖与发布计数
+type PostingRepository interface {
+	// ListKeywords 获取当前学校的全部屏蔽词
+	ListKeywords(ctx context.Context) ([]model.BlockedKeyword, error)
+	// CreateKeyword 在当前学校添加屏蔽词
+	CreateKeyword(ctx context.Context, keyword *model.BlockedKeyword) error
+	// DeleteKeyword 删除当前学校的屏蔽词，不存在时返回 gorm.ErrRecordNotFound
+	DeleteKeyword(ctx context.Context, id int64) error
+	// KeywordExists 判断当前学校是否已有该屏蔽词（不区分大小写）
+	KeywordExists(ctx context.Context, keyword string) (bool, error)
+
+	// GetQuota 获取用户的配额覆盖，未设置时返回 gorm.ErrRecordNotFound
+	GetQuota(ctx context.Context, userID int64) (*model.UserPostingQuota, error)
+	// SaveQuota 新建或更新用户的配额覆盖
+	SaveQuota(ctx context.Context, quota *model.UserPostingQuota) error
+	// DeleteQuota 删除用户的配额覆盖
+	DeleteQuota(ctx context.Context, userID int64) error
+
+	// CountForSale 统计用户当前在售的商品数
+	CountForSale(ctx context.Context, sellerID int64) (int64, error)
+	// CountCreatedSince 统计用户自 since 起发布的商品数（含已下架与已售）
+	CountCreatedSince(ctx context.Context, sellerID int64, since time.Time) (int64, error)
+	// LockSellerCounts 在调用方事务内锁定用户行，再统计在售商品数与自 since 起发布的商品数
+	// 锁持有到事务结束，同一用户的并发发布依次执行，计数包含本事务内已插入的商品
+	LockSellerCounts(tx *gorm.DB, sellerID int64, since time.Time) (forSale, created int64, err error)
+}
+
+// postingRepo 仓库实现
+type postingRepo struct {
+	db *gorm.DB
+}
+
+// NewPostingRepository 创建仓库实例
+func NewPostingRepository(db *gorm.DB) PostingRepository {
+	return &postingRepo{db: db}
+}
+
+// ListKeywords 获取当前学校的全部屏蔽词
+func (r *postingRepo) ListKeywords(ctx context.Context) ([]model.BlockedKeyword, error) {
+	var keywords []model.BlockedKeyword
+	err := scopeTenant(ctx, r.db.WithContext(ctx), "blocked_keywords").
+		Order("keyword ASC").
+		Find(&keywords).Error
+	return keywords, err
+}
+
+// CreateKeyword 在当前学校添加屏蔽词
+func (r *postingRepo) CreateKeyword(ctx context.Context, keyword *model.BlockedKeyword) error {
+	keyword.SchoolID = tenant.SchoolID(ctx)
+	return r.db.WithContext(ctx).Create(keyword).Error
+}
+
+// DeleteKeyword 删除当前学校的屏蔽词
+func (r *postingRepo) DeleteKeyword(ctx context.Context, id int64) error {
+	result := scopeTenant(ctx, r.db.WithContext(ctx), "blocked_keywords").
+		Where("id = ?", id).
+		Delete(&model.BlockedKeyword{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// KeywordExists 判断当前学校是否已有该屏蔽词
+func (r *postingRepo) KeywordExists(ctx context.Context, keyword string) (bool, error) {
+	var count int64
+	err := scopeTenant(ctx, r.db.WithContext(ctx).Model(&model.BlockedKeyword{}), "blocked_keywords").
+		Where("lower(keyword) = lower(?)", keyword).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetQuota 获取用户的配额覆盖
+func (r *postingRepo) GetQuota(ctx context.Context, userID int64) (*model.UserPostingQuota, error) {
+	var quota model.UserPostingQuota
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&quota).Error; err != nil {
+		return nil, err
+	}
+	return &quota, nil
+}
+
+// SaveQuota 新建或更新用户的配额覆盖
+func (r *postingRepo) SaveQuota(ctx context.Context, quota *model.UserPostingQuota) error {
+	quota.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_for_sale", "max_daily", "note", "updated_by", "updated_at"}),
+	}).Create(quota).Error
+}
+
+// DeleteQuota 删除用户的配额覆盖
+func (r *postingRepo) DeleteQuota(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.UserPostingQuota{}).Error
+}
+
+// CountForSale 统计用户当前在售的商品数
+func (r *postingRepo) CountForSale(ctx context.Context, sellerID int64) (int64, error) {
+	return countForSale(r.db.WithContext(ctx), sellerID)
+}
+
+// CountCreatedSince 统计用户自 since 起发布的商品数
+func (r *postingRepo) CountCreatedSince(ctx context.Context, sellerID int64, since time.Time) (int64, error) {
+	return countCreatedSince(r.db.WithContext(ctx), sellerID, since)
+}
+
+// LockSellerCounts 锁定用户行后统计发布数量
+func (r *postingRepo) LockSellerCounts(tx *gorm.DB, sellerID int64, since time.Time) (forSale, created int64, err error) {
+	var locked []int64
+	if err = tx.Model(&model.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", sellerID).Pluck("id", &locked).Error; err != nil {
+		return 0, 0, err
+	}
+	if len(locked) == 0 {
+		return 0, 0, gorm.ErrRecordNotFound
+	}
+	if forSale, err = countForSale(tx, sellerID); err != nil {
+		return 0, 0, err
+	}
+	if created, err = countCreatedSince(tx, sellerID, since); err != nil {
+		return 0, 0, err
+	}
+	return forSale, created, nil
+}
+
+// countForSale 统计用户当前在售的商品数
+func countForSale(db *gorm.DB, sellerID int64) (int64, error) {
+	var count int64
+	err := db.Model(&model.Product{}).
+		Where("seller_id = ? AND status = ?", sellerID, "ForSale").
+		Count(&count).Error
+	return count, err
+}
+
+// countCreatedSince 统计用户自 since 起发布的商品数
+func countCreatedSince(db *gorm.DB, sellerID int64, since time.Time) (int64, error) {
+	var count int64
+	err := db.Model(&model.Product{}).
+		Where("seller_id = ? AND created_at >= ?", sellerID, since).
+		Count(&count).Error
+	return count, err
+}
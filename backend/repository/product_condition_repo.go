@@ -11,8 +11,24 @@ import (
 // ProductConditionRepository 新旧程度仓库接口
 type ProductConditionRepository interface {
 	ListAll(ctx context.Context) ([]model.ProductCondition, error)
+	GetByID(ctx context.Context, id int64) (*model.ProductCondition, error)
+	Create(ctx context.Context, condition *model.ProductCondition) error
+	// Update 更新新旧程度名称（编码创建后不可修改，排序通过 Reorder 修改）
+	Update(ctx context.Context, condition *model.ProductCondition) error
+	Delete(ctx context.Context, id int64) error
+	// CountProducts 统计引用该新旧程度的商品数量（含已下架与已售商品）
+	CountProducts(ctx context.Context, id int64) (int64, error)
+	// ExistsCodeOrName 判断编码或名称是否已被其他新旧程度使用
+	ExistsCodeOrName(ctx context.Context, code, name string, excludeID int64) (codeExists, nameExists bool, err error)
+	// NextSortOrder 获取新建项的排序值
+	NextSortOrder(ctx context.Context) (int32, error)
+	// Reorder 按给定顺序重排全部新旧程度
+	Reorder(ctx context.Context, orderedIDs []int64) error
 }
 
+// conditionSortStep 排序值间隔，与初始数据（10、20、30…）保持一致，便于手工插入
+const conditionSortStep = 10
+
 // productConditionRepo 仓库实现
 type productConditionRepo struct {
 	db *gorm.DB
@@ -29,3 +45,90 @@ func (r *productConditionRepo) ListAll(ctx context.Context) ([]model.ProductCond
 	err := r.db.WithContext(ctx).Order("sort_order ASC, id ASC").Find(&conditions).Error
 	return conditions, err
 }
+
+// GetByID 根据ID获取新旧程度
+func (r *productConditionRepo) GetByID(ctx context.Context, id int64) (*model.ProductCondition, error) {
+	var condition model.ProductCondition
+	if err := r.db.WithContext(ctx).First(&condition, id).Error; err != nil {
+		return nil, err
+	}
+	return &condition, nil
+}
+
+// Create 创建新旧程度
+func (r *productConditionRepo) Create(ctx context.Context, condition *model.ProductCondition) error {
+	return r.db.WithContext(ctx).Create(condition).Error
+}
+
+// Update 更新新旧程度名称
+func (r *productConditionRepo) Update(ctx context.Context, condition *model.ProductCondition) error {
+	result := r.db.WithContext(ctx).Model(&model.ProductCondition{}).
+		Where("id = ?", condition.ID).
+		Update("name", condition.Name)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete 删除新旧程度
+func (r *productConditionRepo) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.ProductCondition{}, id).Error
+}
+
+// CountProducts 统计引用该新旧程度的商品数量
+func (r *productConditionRepo) CountProducts(ctx context.Context, id int64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Product{}).Where("condition_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// ExistsCodeOrName 判断编码或名称是否已被其他新旧程度使用
+func (r *productConditionRepo) ExistsCodeOrName(ctx context.Context, code, name string, excludeID int64) (bool, bool, error) {
+	var rows []model.ProductCondition
+	err := r.db.WithContext(ctx).
+		Where("(code = ? OR name = ?) AND id <> ?", code, name, excludeID).
+		Find(&rows).Error
+	if err != nil {
+		return false, false, err
+	}
+	codeExists, nameExists := false, false
+	for _, row := range rows {
+		if row.Code == code {
+			codeExists = true
+		}
+		if row.Name == name {
+			nameExists = true
+		}
+	}
+	return codeExists, nameExists, nil
+}
+
+// NextSortOrder 获取新建项的排序值，排在现有项之后
+func (r *productConditionRepo) NextSortOrder(ctx context.Context) (int32, error) {
+	var maxOrder *int32
+	if err := r.db.WithContext(ctx).Model(&model.ProductCondition{}).Select("MAX(sort_order)").Scan(&maxOrder).Error; err != nil {
+		return 0, err
+	}
+	if maxOrder == nil {
+		return conditionSortStep, nil
+	}
+	return *maxOrder + conditionSortStep, nil
+}
+
+// Reorder 按给定顺序重排全部新旧程度，排序值按固定间隔重新分配
+func (r *productConditionRepo) Reorder(ctx context.Context, orderedIDs []int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range orderedIDs {
+			if err := tx.Model(&model.ProductCondition{}).
+				Where("id = ?", id).
+				Update("sort_order", (i+1)*conditionSortStep).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -5,19 +5,45 @@ import (
 	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
 )
 
+// TxHook 在仓库事务内、数据写入完成后执行的附加操作（如记录商品修订），返回错误时整个事务回滚
+type TxHook func(tx *gorm.DB) error
+
+// ChainTxHooks 按顺序组合多个钩子，忽略 nil，任一钩子失败即返回
+func ChainTxHooks(hooks ...TxHook) TxHook {
+	return func(tx *gorm.DB) error {
+		for _, hook := range hooks {
+			if hook == nil {
+				continue
+			}
+			if err := hook(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
 // ProductRepository 商品仓库接口
+// 全部查询限定在 context 中的当前学校内
 type ProductRepository interface {
-	Create(ctx context.Context, product *model.Product, images []model.ProductImage, tagIDs []int64) (int64, error)
-	Update(ctx context.Context, product *model.Product, images []model.ProductImage, tagIDs []int64, isAdmin bool) error
+	// Create 创建商品，hook 可为 nil
+	Create(ctx context.Context, product *model.Product, images []model.ProductImage, tagIDs []int64, hook TxHook) (int64, error)
+	// Update 更新商品，hook 可为 nil
+	Update(ctx context.Context, product *model.Product, images []model.ProductImage, tagIDs []int64, isAdmin bool, hook TxHook) error
 	GetByID(ctx context.Context, id int64) (*model.Product, []model.ProductImage, []int64, error)
 	ListBySeller(ctx context.Context, sellerID int64, keyword string, page, pageSize int) ([]model.Product, int64, error)
 	UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error
+	// Renew 续期在售商品（擦亮），重新计算自动过期时间；商品不存在或不在售时返回 gorm.ErrRecordNotFound
+	Renew(ctx context.Context, id int64) error
 	Search(ctx context.Context, params SearchParams) ([]model.Product, int64, error)
-	ListLatestForSale(ctx context.Context, excludeIDs []int64, page, pageSize int) ([]model.Product, int64, error)
+	// ListLatestForSale 获取最新在售商品，boostZoneID 大于0时该片区面交的商品排序适当靠前
+	ListLatestForSale(ctx context.Context, excludeIDs []int64, boostZoneID int64, page, pageSize int) ([]model.Product, int64, error)
 	ListByCategory(ctx context.Context, categoryID int64, params SearchParams) ([]model.Product, int64, error)
 }
 
@@ -28,11 +54,42 @@ type SearchParams struct {
 	PriceMax     float64
 	ConditionID  int64
 	ConditionIDs []int64
-	Sort         string
-	Page         int
-	PageSize     int
+	CategoryID   int64 // 包含该分类的全部后代分类
+	TagID        int64
+	ZoneID       int64 // 面交地点所在的校园片区
+	// CourseCode 关键词规范化后的课程代码，非空时关键词同时匹配该课程关联教材的ISBN
+	CourseCode string
+	// AttributeFilters 分类属性筛选条件，多个条件之间为 AND 关系
+	AttributeFilters []AttributeFilter
+	Sort             string
+	Page             int
+	PageSize         int
 }
 
+// 属性筛选比较运算符
+const (
+	AttributeOpEq  = "=="
+	AttributeOpNe  = "!="
+	AttributeOpGt  = ">"
+	AttributeOpGte = ">="
+	AttributeOpLt  = "<"
+	AttributeOpLte = "<="
+)
+
+// AttributeFilter 单个属性筛选条件
+// Value 的Go类型决定比较方式：float64 按数值比较，bool 按布尔比较，其余按字符串比较
+type AttributeFilter struct {
+	Key   string // 已校验的属性键（仅含小写字母、数字和下划线）
+	Op    string
+	Value interface{}
+}
+
+// zoneMeetupPointsSQL 查询某校园片区下全部面交地点ID
+const zoneMeetupPointsSQL = "SELECT id FROM meetup_points WHERE zone_id = ?"
+
+// nearbyBoostInterval 首页就近推荐时附近商品在排序上获得的时间加成
+const nearbyBoostInterval = "3 days"
+
 // productRepository 商品仓库实现
 type productRepository struct {
 	db *gorm.DB
@@ -46,13 +103,18 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 }
 
 // Create 在事务中创建商品，包括商品基本信息、图片和标签
-func (r *productRepository) Create(ctx context.Context, product *model.Product, images []model.ProductImage, tagIDs []int64) (int64, error) {
+func (r *productRepository) Create(ctx context.Context, product *model.Product, images []model.ProductImage, tagIDs []int64, hook TxHook) (int64, error) {
 	if r.db == nil {
 		return 0, fmt.Errorf("db is nil")
 	}
 
+	product.SchoolID = tenant.SchoolID(ctx)
 	var createdID int64
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureSameSchool(tx, product.SchoolID, product.CategoryID, tagIDs, product.MeetupPointID); err != nil {
+			return err
+		}
+
 		// 插入商品基本信息
 		if err := tx.Create(product).Error; err != nil {
 			return fmt.Errorf("create product failed: %w", err)
@@ -98,6 +160,9 @@ func (r *productRepository) Create(ctx context.Context, product *model.Product,
 			}
 		}
 
+		if hook != nil {
+			return hook(tx)
+		}
 		return nil
 	})
 
@@ -109,11 +174,11 @@ func (r *productRepository) Create(ctx context.Context, product *model.Product,
 }
 
 // Update 更新商品信息，包含权限控制
-func (r *productRepository) Update(ctx context.Context, product *model.Product, images []model.ProductImage, tagIDs []int64, isAdmin bool) error {
+func (r *productRepository) Update(ctx context.Context, product *model.Product, images []model.ProductImage, tagIDs []int64, isAdmin bool, hook TxHook) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 获取原商品信息进行权限检查
 		var originalProduct model.Product
-		if err := tx.First(&originalProduct, product.ID).Error; err != nil {
+		if err := scopeTenant(ctx, tx, "products").First(&originalProduct, product.ID).Error; err != nil {
 			return fmt.Errorf("get product failed: %w", err)
 		}
 
@@ -127,14 +192,28 @@ func (r *productRepository) Update(ctx context.Context, product *model.Product,
 			return fmt.Errorf("admin cannot change status of sold product")
 		}
 
+		if err := ensureSameSchool(tx, originalProduct.SchoolID, product.CategoryID, tagIDs, product.MeetupPointID); err != nil {
+			return err
+		}
+
 		// 更新商品基本信息（仅更新需要的字段，避免覆盖CreatedAt等系统字段）
 		updateFields := map[string]interface{}{
-			"title":        product.Title,
-			"description":  product.Description,
-			"price":        product.Price,
-			"category_id":  product.CategoryID,
-			"condition_id": product.ConditionID,
-			"status":       product.Status,
+			"title":           product.Title,
+			"description":     product.Description,
+			"price":           product.Price,
+			"category_id":     product.CategoryID,
+			"condition_id":    product.ConditionID,
+			"status":          product.Status,
+			"isbn":            product.ISBN,
+			"meetup_point_id": product.MeetupPointID,
+		}
+		if product.Attributes != "" {
+			updateFields["attributes"] = product.Attributes
+		}
+		// 卖家编辑视为一次活动，重新计算过期时间（管理员纠错不影响）
+		if !isAdmin {
+			updateFields["renewed_at"] = gorm.Expr("NOW()")
+			updateFields["expiry_reminded_at"] = nil
 		}
 		if err := tx.Model(&model.Product{}).Where("id = ?", product.ID).Updates(updateFields).Error; err != nil {
 			return fmt.Errorf("update product failed: %w", err)
@@ -195,6 +274,9 @@ func (r *productRepository) Update(ctx context.Context, product *model.Product,
 			}
 		}
 
+		if hook != nil {
+			return hook(tx)
+		}
 		return nil
 	})
 }
@@ -203,7 +285,7 @@ func (r *productRepository) Update(ctx context.Context, product *model.Product,
 func (r *productRepository) GetByID(ctx context.Context, id int64) (*model.Product, []model.ProductImage, []int64, error) {
 	// 查询商品基本信息
 	var product model.Product
-	if err := r.db.WithContext(ctx).First(&product, id).Error; err != nil {
+	if err := scopeTenant(ctx, r.db.WithContext(ctx), "products").First(&product, id).Error; err != nil {
 		return nil, nil, nil, fmt.Errorf("get product failed: %w", err)
 	}
 
@@ -234,7 +316,7 @@ func (r *productRepository) GetByID(ctx context.Context, id int64) (*model.Produ
 // ListBySeller 获取卖家发布的商品列表，支持关键词搜索和分页
 func (r *productRepository) ListBySeller(ctx context.Context, sellerID int64, keyword string, page, pageSize int) ([]model.Product, int64, error) {
 	// 构建查询
-	query := r.db.WithContext(ctx).Model(&model.Product{}).Where("seller_id = ?", sellerID)
+	query := scopeTenant(ctx, r.db.WithContext(ctx).Model(&model.Product{}), "products").Where("seller_id = ?", sellerID)
 
 	// 添加关键词搜索
 	if keyword != "" {
@@ -257,14 +339,42 @@ func (r *productRepository) ListBySeller(ctx context.Context, sellerID int64, ke
 	return products, total, nil
 }
 
+// Renew 续期在售商品
+func (r *productRepository) Renew(ctx context.Context, id int64) error {
+	result := scopeTenant(ctx, r.db.WithContext(ctx).Model(&model.Product{}), "products").
+		Where("id = ? AND status = ?", id, "ForSale").
+		Updates(map[string]interface{}{
+			"renewed_at":         gorm.Expr("NOW()"),
+			"expiry_reminded_at": nil,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("renew product failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 // UpdateStatus 更新商品状态，带where条件确保状态流转的合法性
 // 依赖数据库触发器防止非法流转
 func (r *productRepository) UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error {
 	// 使用where条件确保只有当前状态为fromStatus的商品才会被更新
 	// 这样可以在数据库层面保证状态流转的合法性
-	result := r.db.WithContext(ctx).Model(&model.Product{}).
+	updates := map[string]interface{}{"status": toStatus}
+	// 标记售出时记录成交时间，用于统计成交周期
+	if toStatus == "Sold" {
+		updates["sold_at"] = gorm.Expr("NOW()")
+	}
+	// 重新上架时续期，并清除自动下架标记
+	if toStatus == "ForSale" {
+		updates["renewed_at"] = gorm.Expr("NOW()")
+		updates["expiry_reminded_at"] = nil
+		updates["expired_at"] = nil
+	}
+	result := scopeTenant(ctx, r.db.WithContext(ctx).Model(&model.Product{}), "products").
 		Where("id = ? AND status = ?", id, fromStatus).
-		Update("status", toStatus)
+		Updates(updates)
 
 	// 检查是否有行被更新
 	if result.Error != nil {
@@ -275,7 +385,7 @@ func (r *productRepository) UpdateStatus(ctx context.Context, id int64, fromStat
 	if result.RowsAffected == 0 {
 		// 检查商品是否存在
 		var count int64
-		if err := r.db.WithContext(ctx).Model(&model.Product{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		if err := scopeTenant(ctx, r.db.WithContext(ctx).Model(&model.Product{}), "products").Where("id = ?", id).Count(&count).Error; err != nil {
 			return fmt.Errorf("check product existence failed: %w", err)
 		}
 
@@ -293,12 +403,10 @@ func (r *productRepository) UpdateStatus(ctx context.Context, id int64, fromStat
 // Search 实现关键词+条件组合搜索，仅status=ForSale
 func (r *productRepository) Search(ctx context.Context, params SearchParams) ([]model.Product, int64, error) {
 	// 构建查询
-	query := r.db.WithContext(ctx).Model(&model.Product{}).Where("status = ?", "ForSale")
+	query := scopeTenant(ctx, r.db.WithContext(ctx).Model(&model.Product{}), "products").Where("status = ?", "ForSale")
 
 	// 添加搜索条件
-	if params.Keyword != "" {
-		query = query.Where("title LIKE ? OR description LIKE ?", "%"+params.Keyword+"%", "%"+params.Keyword+"%")
-	}
+	query = applyKeyword(query, tenant.SchoolID(ctx), params.Keyword, params.CourseCode)
 
 	if len(params.ConditionIDs) > 0 {
 		query = query.Where("condition_id IN ?", params.ConditionIDs)
@@ -306,6 +414,20 @@ func (r *productRepository) Search(ctx context.Context, params SearchParams) ([]
 		query = query.Where("condition_id = ?", params.ConditionID)
 	}
 
+	if params.CategoryID > 0 {
+		query = query.Where("category_id IN ("+categorySubtreeSQL+")", params.CategoryID)
+	}
+
+	if params.TagID > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM product_tags pt WHERE pt.product_id = products.id AND pt.tag_id = ?)", params.TagID)
+	}
+
+	if params.ZoneID > 0 {
+		query = query.Where("meetup_point_id IN ("+zoneMeetupPointsSQL+")", params.ZoneID)
+	}
+
+	query = applyAttributeFilters(query, params.AttributeFilters)
+
 	if params.PriceMin > 0 {
 		query = query.Where("price >= ?", params.PriceMin)
 	}
@@ -338,9 +460,11 @@ func (r *productRepository) Search(ctx context.Context, params SearchParams) ([]
 }
 
 // ListLatestForSale 获取最新上架的商品，可排除指定ID
-func (r *productRepository) ListLatestForSale(ctx context.Context, excludeIDs []int64, page, pageSize int) ([]model.Product, int64, error) {
+// boostZoneID 大于0时，在该片区面交的商品按发布时间加上 nearbyBoostInterval 参与排序，
+// 既让附近的商品靠前，又不会让很久以前发布的附近商品压过全部新商品
+func (r *productRepository) ListLatestForSale(ctx context.Context, excludeIDs []int64, boostZoneID int64, page, pageSize int) ([]model.Product, int64, error) {
 	// 构建查询
-	query := r.db.WithContext(ctx).Model(&model.Product{}).Where("status = ?", "ForSale")
+	query := scopeTenant(ctx, r.db.WithContext(ctx).Model(&model.Product{}), "products").Where("status = ?", "ForSale")
 
 	// 添加排除条件
 	if len(excludeIDs) > 0 {
@@ -356,22 +480,30 @@ func (r *productRepository) ListLatestForSale(ctx context.Context, excludeIDs []
 	// 分页查询
 	var products []model.Product
 	offset := (page - 1) * pageSize
-	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&products).Error; err != nil {
+	var orderBy interface{} = "created_at DESC"
+	if boostZoneID > 0 {
+		orderBy = clause.OrderBy{Expression: clause.Expr{
+			SQL: "created_at + CASE WHEN meetup_point_id IN (" + zoneMeetupPointsSQL + ") THEN interval '" + nearbyBoostInterval +
+				"' ELSE interval '0' END DESC, created_at DESC",
+			Vars: []interface{}{boostZoneID},
+		}}
+	}
+	if err := query.Order(orderBy).Offset(offset).Limit(pageSize).Find(&products).Error; err != nil {
 		return nil, 0, fmt.Errorf("list latest products failed: %w", err)
 	}
 
 	return products, total, nil
 }
 
-// ListByCategory 获取指定分类的商品
+// ListByCategory 获取指定分类（含全部后代分类）的商品
 func (r *productRepository) ListByCategory(ctx context.Context, categoryID int64, params SearchParams) ([]model.Product, int64, error) {
 	// 构建查询
-	query := r.db.WithContext(ctx).Model(&model.Product{}).Where("status = ? AND category_id = ?", "ForSale", categoryID)
+	query := scopeTenant(ctx, r.db.WithContext(ctx).Model(&model.Product{}), "products").
+		Where("status = ?", "ForSale").
+		Where("category_id IN ("+categorySubtreeSQL+")", categoryID)
 
 	// 添加搜索条件
-	if params.Keyword != "" {
-		query = query.Where("title LIKE ? OR description LIKE ?", "%"+params.Keyword+"%", "%"+params.Keyword+"%")
-	}
+	query = applyKeyword(query, tenant.SchoolID(ctx), params.Keyword, params.CourseCode)
 
 	if len(params.ConditionIDs) > 0 {
 		query = query.Where("condition_id IN ?", params.ConditionIDs)
@@ -379,6 +511,16 @@ func (r *productRepository) ListByCategory(ctx context.Context, categoryID int64
 		query = query.Where("condition_id = ?", params.ConditionID)
 	}
 
+	if params.TagID > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM product_tags pt WHERE pt.product_id = products.id AND pt.tag_id = ?)", params.TagID)
+	}
+
+	if params.ZoneID > 0 {
+		query = query.Where("meetup_point_id IN ("+zoneMeetupPointsSQL+")", params.ZoneID)
+	}
+
+	query = applyAttributeFilters(query, params.AttributeFilters)
+
 	if params.PriceMin > 0 {
 		query = query.Where("price >= ?", params.PriceMin)
 	}
@@ -409,3 +551,49 @@ func (r *productRepository) ListByCategory(ctx context.Context, categoryID int64
 
 	return products, total, nil
 }
+
+// applyKeyword 追加关键词条件
+//
+// 匹配规则：
+//   - 标题或描述包含关键词
+//   - 关键词与某标签的名称或别名相同时，匹配带有该标签的商品，以及标题或描述包含该标签任一同义词的商品
+//   - 关键词为课程代码时，匹配该课程关联教材的ISBN
+//
+// 标签匹配限定在 schoolID 对应学校的标签内
+func applyKeyword(query *gorm.DB, schoolID int64, keyword, courseCode string) *gorm.DB {
+	if keyword == "" {
+		return query
+	}
+	like := "%" + keyword + "%"
+	conditions := `title LIKE ? OR description LIKE ?
+		OR EXISTS (SELECT 1 FROM product_tags pt WHERE pt.product_id = products.id AND pt.tag_id IN (` + tagKeywordMatchSQL + `))
+		OR EXISTS (SELECT 1 FROM (` + tagSynonymTermsSQL + `) syn
+			WHERE products.title LIKE '%' || syn.term || '%' OR products.description LIKE '%' || syn.term || '%')`
+	args := []interface{}{like, like}
+	args = append(args, tagKeywordArgs(schoolID, keyword)...)
+	args = append(args, tagKeywordArgs(schoolID, keyword)...)
+	args = append(args, tagKeywordArgs(schoolID, keyword)...)
+	if courseCode != "" {
+		conditions += ` OR isbn IN (
+		SELECT b.isbn FROM books b JOIN book_courses bc ON bc.book_id = b.id WHERE bc.course_code = ?)`
+		args = append(args, courseCode)
+	}
+	return query.Where(conditions, args...)
+}
+
+// applyAttributeFilters 追加属性筛选条件
+// 使用 jsonpath 比较，类型不匹配的值（如字符串与数值比较）不会命中，也不会导致类型转换错误
+func applyAttributeFilters(query *gorm.DB, filters []AttributeFilter) *gorm.DB {
+	for _, f := range filters {
+		path := fmt.Sprintf(`$.%s ? (@ %s $v)`, f.Key, f.Op)
+		switch v := f.Value.(type) {
+		case float64:
+			query = query.Where("jsonb_path_exists(products.attributes, ?::jsonpath, jsonb_build_object('v', ?::numeric))", path, v)
+		case bool:
+			query = query.Where("jsonb_path_exists(products.attributes, ?::jsonpath, jsonb_build_object('v', ?::boolean))", path, v)
+		default:
+			query = query.Where("jsonb_path_exists(products.attributes, ?::jsonpath, jsonb_build_object('v', ?::text))", path, fmt.Sprint(v))
+		}
+	}
+	return query
+}
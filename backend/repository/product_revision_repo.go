@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// ProductRevisionRepository 商品修订记录仓库接口
+type ProductRevisionRepository interface {
+	// Snapshot 在调用方事务内读取商品当前内容生成修订快照（不写入），editorID 为 0 时记为卖家本人
+	Snapshot(tx *gorm.DB, productID, editorID int64, reason string) (*model.ProductRevision, error)
+	// RecordInTx 在调用方事务内追加一条修订记录，版本号自动分配，内容与最新版本相同时不产生新版本；
+	// baseline 非空且商品尚无任何修订记录时，先将其补录为基线版本
+	RecordInTx(tx *gorm.DB, baseline, revision *model.ProductRevision) error
+	// ListByProduct 按版本号升序返回商品的全部修订记录
+	ListByProduct(ctx context.Context, productID int64) ([]model.ProductRevision, error)
+	// GetByVersion 获取商品指定版本的修订记录
+	GetByVersion(ctx context.Context, productID int64, version int) (*model.ProductRevision, error)
+}
+
+// productRevisionRepository 商品修订记录仓库实现
+type productRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewProductRevisionRepository 创建商品修订记录仓库实例
+func NewProductRevisionRepository(db *gorm.DB) ProductRevisionRepository {
+	return &productRevisionRepository{db: db}
+}
+
+// Snapshot 读取商品当前内容生成修订快照
+// 在写入商品的同一事务内调用，快照包含本事务中尚未提交的修改
+func (r *productRevisionRepository) Snapshot(tx *gorm.DB, productID, editorID int64, reason string) (*model.ProductRevision, error) {
+	var product model.Product
+	if err := tx.First(&product, productID).Error; err != nil {
+		return nil, fmt.Errorf("get product failed: %w", err)
+	}
+
+	var images []model.ProductImage
+	if err := tx.Where("product_id = ?", productID).Order("sort_order ASC").Find(&images).Error; err != nil {
+		return nil, fmt.Errorf("get product images failed: %w", err)
+	}
+
+	var tagIDs []int64
+	if err := tx.Table("product_tags").Where("product_id = ?", productID).Order("tag_id").Pluck("tag_id", &tagIDs).Error; err != nil {
+		return nil, fmt.Errorf("get product tags failed: %w", err)
+	}
+
+	if editorID == 0 {
+		editorID = product.SellerID
+	}
+	return model.NewProductRevision(&product, images, tagIDs, editorID, reason), nil
+}
+
+// RecordInTx 在调用方事务内追加修订记录
+// 基线版本用于在功能上线前发布的商品第一次被编辑时保留其原始内容
+func (r *productRevisionRepository) RecordInTx(tx *gorm.DB, baseline, revision *model.ProductRevision) error {
+	if baseline != nil {
+		if err := appendRevision(tx, baseline, true); err != nil {
+			return err
+		}
+	}
+	return appendRevision(tx, revision, false)
+}
+
+// appendRevision 在事务内写入修订记录
+// onlyIfEmpty 为 true 时仅在商品没有任何修订记录时写入
+func appendRevision(tx *gorm.DB, revision *model.ProductRevision, onlyIfEmpty bool) error {
+	if err := tx.Exec("SELECT id FROM products WHERE id = ? FOR UPDATE", revision.ProductID).Error; err != nil {
+		return fmt.Errorf("lock product failed: %w", err)
+	}
+
+	var latest model.ProductRevision
+	err := tx.Where("product_id = ?", revision.ProductID).Order("version DESC").Limit(1).Find(&latest).Error
+	if err != nil {
+		return fmt.Errorf("get latest product revision failed: %w", err)
+	}
+
+	if latest.ID != 0 {
+		if onlyIfEmpty || latest.SameContent(revision) {
+			return nil
+		}
+	}
+
+	revision.Version = latest.Version + 1
+	if err := tx.Create(revision).Error; err != nil {
+		return fmt.Errorf("create product revision failed: %w", err)
+	}
+	return nil
+}
+
+// ListByProduct 按版本号升序返回商品的全部修订记录
+func (r *productRevisionRepository) ListByProduct(ctx context.Context, productID int64) ([]model.ProductRevision, error) {
+	var revisions []model.ProductRevision
+	if err := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("version ASC").
+		Find(&revisions).Error; err != nil {
+		return nil, fmt.Errorf("list product revisions failed: %w", err)
+	}
+	return revisions, nil
+}
+
+// GetByVersion 获取商品指定版本的修订记录
+func (r *productRevisionRepository) GetByVersion(ctx context.Context, productID int64, version int) (*model.ProductRevision, error) {
+	var revision model.ProductRevision
+	if err := r.db.WithContext(ctx).
+		Where("product_id = ? AND version = ?", productID, version).
+		First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// SchoolRepository 学校（租户）仓库接口
+// 学校是租户本身，查询不做租户过滤
+type SchoolRepository interface {
+	List(ctx context.Context, activeOnly bool) ([]model.School, error)
+	GetByID(ctx context.Context, id int64) (*model.School, error)
+	GetByCode(ctx context.Context, code string) (*model.School, error)
+	Create(ctx context.Context, school *model.School) error
+	// Update 更新学校名称与启用状态（编码创建后不可修改）
+	Update(ctx context.Context, school *model.School) error
+}
+
+// schoolRepo 学校仓库实现
+type schoolRepo struct {
+	db *gorm.DB
+}
+
+// NewSchoolRepository 创建学校仓库实例
+func NewSchoolRepository(db *gorm.DB) SchoolRepository {
+	return &schoolRepo{db: db}
+}
+
+// List 获取学校列表，activeOnly 为true时仅返回启用的学校
+func (r *schoolRepo) List(ctx context.Context, activeOnly bool) ([]model.School, error) {
+	var schools []model.School
+	query := r.db.WithContext(ctx).Order("id ASC")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Find(&schools).Error
+	return schools, err
+}
+
+// GetByID 根据ID获取学校
+func (r *schoolRepo) GetByID(ctx context.Context, id int64) (*model.School, error) {
+	var school model.School
+	if err := r.db.WithContext(ctx).First(&school, id).Error; err != nil {
+		return nil, err
+	}
+	return &school, nil
+}
+
+// GetByCode 根据编码获取学校
+func (r *schoolRepo) GetByCode(ctx context.Context, code string) (*model.School, error) {
+	var school model.School
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&school).Error; err != nil {
+		return nil, err
+	}
+	return &school, nil
+}
+
+// Create 创建学校
+func (r *schoolRepo) Create(ctx context.Context, school *model.School) error {
+	return r.db.WithContext(ctx).Create(school).Error
+}
+
+// Update 更新学校名称与启用状态
+func (r *schoolRepo) Update(ctx context.Context, school *model.School) error {
+	result := r.db.WithContext(ctx).Model(&model.School{}).Where("id = ?", school.ID).Updates(map[string]interface{}{
+		"name":      school.Name,
+		"is_active": school.IsActive,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// SessionRepository 登录会话与登录记录仓库接口
+type SessionRepository interface {
+	// CreateSession 创建登录会话
+	CreateSession(ctx context.Context, session *model.Session) error
+	// GetSession 根据会话标识获取会话，不存在时返回 gorm.ErrRecordNotFound
+	GetSession(ctx context.Context, token string) (*model.Session, error)
+	// ListActiveSessions 获取用户未过期的会话，按最近活跃时间倒序
+	ListActiveSessions(ctx context.Context, userID int64) ([]model.Session, error)
+	// ExtendSession 重新签发令牌时延长会话有效期
+	ExtendSession(ctx context.Context, token string, expiredAt time.Time) error
+	// TouchSession 更新会话最近活跃时间，距上次更新不足 interval 时不写库
+	TouchSession(ctx context.Context, id int64, interval time.Duration) error
+	// DeleteSession 删除用户的指定会话，不存在时返回 gorm.ErrRecordNotFound
+	DeleteSession(ctx context.Context, userID, id int64) error
+	// DeleteOtherSessions 删除用户除 keepToken 之外的全部会话，返回删除数量
+	DeleteOtherSessions(ctx context.Context, userID int64, keepToken string) (int64, error)
+	// DeleteExpiredSessions 删除在 before 之前过期的会话（全部用户），返回删除数量
+	DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error)
+
+	// CreateLoginRecord 记录一次登录尝试
+	CreateLoginRecord(ctx context.Context, record *model.LoginRecord) error
+	// ListLoginRecords 获取用户最近的登录记录
+	ListLoginRecords(ctx context.Context, userID int64, limit int) ([]model.LoginRecord, error)
+	// HasSuccessfulLogin 判断用户是否有成功登录记录，deviceHash 非空时只统计该设备
+	HasSuccessfulLogin(ctx context.Context, userID int64, deviceHash string) (bool, error)
+}
+
+// sessionRepo 仓库实现
+type sessionRepo struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository 创建登录会话仓库
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepo{db: db}
+}
+
+// CreateSession 创建登录会话
+func (r *sessionRepo) CreateSession(ctx context.Context, session *model.Session) error {
+	if session.LastSeenAt.IsZero() {
+		session.LastSeenAt = time.Now()
+	}
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// GetSession 根据会话标识获取会话
+func (r *sessionRepo) GetSession(ctx context.Context, token string) (*model.Session, error) {
+	var session model.Session
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveSessions 获取用户未过期的会话
+func (r *sessionRepo) ListActiveSessions(ctx context.Context, userID int64) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND expired_at > NOW()", userID).
+		Order("last_seen_at DESC, id DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// ExtendSession 延长会话有效期（只延长，不缩短）
+func (r *sessionRepo) ExtendSession(ctx context.Context, token string, expiredAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.Session{}).
+		Where("token = ? AND expired_at < ?", token, expiredAt).
+		Updates(map[string]interface{}{"expired_at": expiredAt, "last_seen_at": time.Now()}).Error
+}
+
+// TouchSession 更新会话最近活跃时间
+func (r *sessionRepo) TouchSession(ctx context.Context, id int64, interval time.Duration) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&model.Session{}).
+		Where("id = ? AND last_seen_at < ?", id, now.Add(-interval)).
+		Update("last_seen_at", now).Error
+}
+
+// DeleteSession 删除用户的指定会话
+func (r *sessionRepo) DeleteSession(ctx context.Context, userID, id int64) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&model.Session{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteOtherSessions 删除用户除 keepToken 之外的全部会话
+func (r *sessionRepo) DeleteOtherSessions(ctx context.Context, userID int64, keepToken string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND token <> ?", userID, keepToken).Delete(&model.Session{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteExpiredSessions 删除过期会话
+func (r *sessionRepo) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expired_at < ?", before).Delete(&model.Session{})
+	return result.RowsAffected, result.Error
+}
+
+// CreateLoginRecord 记录一次登录尝试
+func (r *sessionRepo) CreateLoginRecord(ctx context.Context, record *model.LoginRecord) error {
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+// ListLoginRecords 获取用户最近的登录记录
+func (r *sessionRepo) ListLoginRecords(ctx context.Context, userID int64, limit int) ([]model.LoginRecord, error) {
+	var records []model.LoginRecord
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&records).Error
+	return records, err
+}
+
+// HasSuccessfulLogin 判断用户是否有成功登录记录
+func (r *sessionRepo) HasSuccessfulLogin(ctx context.Context, userID int64, deviceHash string) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&model.LoginRecord{}).Where("user_id = ? AND success", userID)
+	if deviceHash != "" {
+		query = query.Where("device_hash = ?", deviceHash)
+	}
+	var count int64
+	err := query.Limit(1).Count(&count).Error
+	return count > 0, err
+}
@@ -2,20 +2,68 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// tagKeywordMatchSQL 查询学校内名称或别名与关键词相同（不区分大小写）的已通过标签ID
+// 参数依次为：学校ID、关键词、学校ID、关键词
+// 别名只会挂在已通过的标签上，因此无需再次过滤状态
+const tagKeywordMatchSQL = `SELECT id FROM tags WHERE school_id = ? AND status = 'Approved' AND lower(name) = lower(?)
+	UNION SELECT ta.tag_id FROM tag_aliases ta JOIN tags tg ON tg.id = ta.tag_id
+	WHERE tg.school_id = ? AND lower(ta.alias) = lower(?)`
+
+// tagNameMatchSQL 查询学校内名称或别名与给定名称相同（不区分大小写）的任意状态标签ID
+// 参数依次为：学校ID、名称、学校ID、名称
+const tagNameMatchSQL = `SELECT id FROM tags WHERE school_id = ? AND lower(name) = lower(?)
+	UNION SELECT ta.tag_id FROM tag_aliases ta JOIN tags tg ON tg.id = ta.tag_id
+	WHERE tg.school_id = ? AND lower(ta.alias) = lower(?)`
+
+// tagKeywordArgs 返回 tagKeywordMatchSQL 与 tagNameMatchSQL 所需的参数
+func tagKeywordArgs(schoolID int64, keyword string) []interface{} {
+	return []interface{}{schoolID, keyword, schoolID, keyword}
+}
+
+// tagSynonymTermsSQL 查询关键词对应标签的全部同义词（标签名称及其别名），参数为两组 tagKeywordArgs
+const tagSynonymTermsSQL = `SELECT name AS term FROM tags WHERE id IN (` + tagKeywordMatchSQL + `)
+	UNION SELECT alias AS term FROM tag_aliases WHERE tag_id IN (` + tagKeywordMatchSQL + `)`
+
 // TagRepository 标签仓库接口
+// 全部查询限定在 context 中的当前学校内
 type TagRepository interface {
+	// ListAll 获取所有已通过的标签
 	ListAll(ctx context.Context) ([]model.Tag, error)
+	// ListByCategory 获取适用于某分类的已通过标签（关联到该分类或其任一祖先分类）
+	ListByCategory(ctx context.Context, categoryID int64) ([]model.Tag, error)
+	// ListWithUsage 分页获取指定状态的标签及其使用次数，按使用次数降序
+	ListWithUsage(ctx context.Context, status string, page, pageSize int) ([]model.Tag, int64, error)
+	// Create 创建标签及其分类关联
 	Create(ctx context.Context, tag *model.Tag) error
+	// Update 更新标签名称、主分类并替换分类关联
 	Update(ctx context.Context, tag *model.Tag) error
 	Delete(ctx context.Context, id int64) error
 	CountProductsByTag(ctx context.Context, id int64) (int64, error)
 	GetByID(ctx context.Context, id int64) (*model.Tag, error)
+	// FindByNameOrAlias 查找名称或别名与给定名称相同（不区分大小写）的标签
+	FindByNameOrAlias(ctx context.Context, name string) (*model.Tag, error)
+	// ReplaceAliases 替换标签的别名
+	ReplaceAliases(ctx context.Context, tagID int64, aliases []string) error
+	// Merge 在一个事务中将 sourceID 标签合并到 targetID 标签
+	Merge(ctx context.Context, sourceID, targetID int64) error
+	// CountCategories 统计给定ID中实际存在的分类数量
+	CountCategories(ctx context.Context, categoryIDs []int64) (int64, error)
+	// ResolveProposed 将卖家提议的标签名称解析为标签ID，不存在的名称创建为待审核标签
+	ResolveProposed(ctx context.Context, names []string, categoryID, userID int64) ([]int64, error)
+	// UpdateStatus 在标签当前状态为 fromStatus 时更新为 toStatus
+	UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error
+	// Reject 在一个事务中驳回待审核标签并移除其商品关联
+	Reject(ctx context.Context, id int64) error
 }
 
 // tagRepo 标签仓库实现
@@ -31,39 +79,363 @@ func NewTagRepository(db *gorm.DB) TagRepository {
 // ListAll 获取所有标签
 func (r *tagRepo) ListAll(ctx context.Context) ([]model.Tag, error) {
 	var tags []model.Tag
-	err := r.db.WithContext(ctx).Find(&tags).Error
-	return tags, err
+	if err := scopeTenant(ctx, r.db.WithContext(ctx), "tags").Where("status = ?", model.TagStatusApproved).Order("id ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, r.loadRelations(ctx, tags)
 }
 
-// Create 创建标签
+// ListByCategory 获取适用于某分类的标签
+// 挂在父分类上的标签同样适用于其子分类
+func (r *tagRepo) ListByCategory(ctx context.Context, categoryID int64) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.WithContext(ctx).Raw(categoryAncestorsSQL+`
+		SELECT t.* FROM tags t
+		WHERE t.school_id = ? AND t.status = 'Approved' AND EXISTS (
+			SELECT 1 FROM tag_categories tc JOIN category_ancestors a ON a.id = tc.category_id
+			WHERE tc.tag_id = t.id
+		)
+		ORDER BY t.id ASC`, categoryID, tenant.SchoolID(ctx)).
+		Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, r.loadRelations(ctx, tags)
+}
+
+// ListWithUsage 分页获取指定状态的标签及其使用次数
+// 使用次数相同时先提议的排在前面，便于管理员优先处理热门的待审核标签
+func (r *tagRepo) ListWithUsage(ctx context.Context, status string, page, pageSize int) ([]model.Tag, int64, error) {
+	query := scopeTenant(ctx, r.db.WithContext(ctx).Model(&model.Tag{}), "tags").Where("status = ?", status)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count tags failed: %w", err)
+	}
+
+	var tags []model.Tag
+	offset := (page - 1) * pageSize
+	if err := query.
+		Select("tags.*, (SELECT COUNT(*) FROM product_tags pt WHERE pt.tag_id = tags.id) AS usage_count").
+		Order("usage_count DESC, created_at ASC, id ASC").
+		Offset(offset).Limit(pageSize).
+		Find(&tags).Error; err != nil {
+		return nil, 0, fmt.Errorf("list tags failed: %w", err)
+	}
+	return tags, total, r.loadRelations(ctx, tags)
+}
+
+// Create 在当前学校下创建标签及其分类关联
 func (r *tagRepo) Create(ctx context.Context, tag *model.Tag) error {
-	return r.db.WithContext(ctx).Create(tag).Error
+	tag.SchoolID = tenant.SchoolID(ctx)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(tag).Error; err != nil {
+			return err
+		}
+		return replaceTagCategories(tx, tag.ID, tag.CategoryIDs)
+	})
 }
 
-// Update 更新标签
+// Update 更新标签名称、主分类并替换分类关联
+// 若新名称与该标签自身的某个别名相同，则同时移除该别名
 func (r *tagRepo) Update(ctx context.Context, tag *model.Tag) error {
-	return r.db.WithContext(ctx).Save(tag).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := scopeTenant(ctx, tx.Model(&model.Tag{}), "tags").Where("id = ?", tag.ID).Updates(map[string]interface{}{
+			"name":        tag.Name,
+			"category_id": tag.CategoryID,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("tag_id = ? AND lower(alias) = lower(?)", tag.ID, tag.Name).Delete(&model.TagAlias{}).Error; err != nil {
+			return err
+		}
+		return replaceTagCategories(tx, tag.ID, tag.CategoryIDs)
+	})
+}
+
+// replaceTagCategories 替换标签的分类关联
+func replaceTagCategories(tx *gorm.DB, tagID int64, categoryIDs []int64) error {
+	if err := tx.Where("tag_id = ?", tagID).Delete(&model.TagCategory{}).Error; err != nil {
+		return err
+	}
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+	relations := make([]model.TagCategory, 0, len(categoryIDs))
+	for _, categoryID := range categoryIDs {
+		relations = append(relations, model.TagCategory{TagID: tagID, CategoryID: categoryID})
+	}
+	return tx.Create(&relations).Error
 }
 
 // Delete 删除标签
 func (r *tagRepo) Delete(ctx context.Context, id int64) error {
-	return r.db.WithContext(ctx).Delete(&model.Tag{}, id).Error
+	return scopeTenant(ctx, r.db.WithContext(ctx), "tags").Delete(&model.Tag{}, id).Error
 }
 
 // CountProductsByTag 统计标签关联的商品数量
 func (r *tagRepo) CountProductsByTag(ctx context.Context, id int64) (int64, error) {
 	var count int64
 	// 通过product_tags关联表查询关联的商品数量
-	err := r.db.WithContext(ctx).Table("product_tags").Where("tag_id = ?", id).Count(&count).Error
+	err := r.db.WithContext(ctx).Table("product_tags").
+		Joins("JOIN tags ON tags.id = product_tags.tag_id").
+		Where("product_tags.tag_id = ? AND tags.school_id = ?", id, tenant.SchoolID(ctx)).
+		Count(&count).Error
 	return count, err
 }
 
 // GetByID 根据ID获取标签
 func (r *tagRepo) GetByID(ctx context.Context, id int64) (*model.Tag, error) {
 	var tag model.Tag
-	err := r.db.WithContext(ctx).First(&tag, id).Error
+	err := scopeTenant(ctx, r.db.WithContext(ctx), "tags").First(&tag, id).Error
+	if err != nil {
+		return nil, err
+	}
+	tags := []model.Tag{tag}
+	if err := r.loadRelations(ctx, tags); err != nil {
+		return nil, err
+	}
+	return &tags[0], nil
+}
+
+// FindByNameOrAlias 查找名称或别名与给定名称相同（不区分大小写）的标签
+func (r *tagRepo) FindByNameOrAlias(ctx context.Context, name string) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.WithContext(ctx).
+		Where("id IN ("+tagNameMatchSQL+")", tagKeywordArgs(tenant.SchoolID(ctx), name)...).
+		Order("id ASC").
+		First(&tag).Error
 	if err != nil {
 		return nil, err
 	}
 	return &tag, nil
 }
+
+// ReplaceAliases 替换标签的别名
+func (r *tagRepo) ReplaceAliases(ctx context.Context, tagID int64, aliases []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", tagID).Delete(&model.TagAlias{}).Error; err != nil {
+			return err
+		}
+		if len(aliases) == 0 {
+			return nil
+		}
+		rows := make([]model.TagAlias, 0, len(aliases))
+		for _, alias := range aliases {
+			rows = append(rows, model.TagAlias{TagID: tagID, Alias: alias})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// Merge 在一个事务中将 sourceID 标签合并到 targetID 标签
+//
+// 合并步骤：
+//   - 锁定两个标签，防止并发合并或删除
+//   - 商品标签关联改挂到目标标签（商品已有目标标签时直接去重）
+//   - 商品历史版本中保存的标签ID同步替换，保证回滚版本时不引用已删除的标签
+//   - 分类关联与别名并入目标标签，源标签名称成为目标标签的别名
+//   - 删除源标签
+func (r *tagRepo) Merge(ctx context.Context, sourceID, targetID int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tags []model.Tag
+		if err := scopeTenant(ctx, tx.Clauses(clause.Locking{Strength: "UPDATE"}), "tags").
+			Where("id IN ?", []int64{sourceID, targetID}).
+			Order("id ASC").
+			Find(&tags).Error; err != nil {
+			return err
+		}
+		if len(tags) != 2 {
+			return gorm.ErrRecordNotFound
+		}
+		var source model.Tag
+		for _, t := range tags {
+			if t.ID == sourceID {
+				source = t
+			}
+		}
+
+		if err := tx.Exec(`INSERT INTO product_tags (product_id, tag_id)
+			SELECT product_id, ? FROM product_tags WHERE tag_id = ?
+			ON CONFLICT (product_id, tag_id) DO NOTHING`, targetID, sourceID).Error; err != nil {
+			return fmt.Errorf("move product tags failed: %w", err)
+		}
+		if err := tx.Exec("DELETE FROM product_tags WHERE tag_id = ?", sourceID).Error; err != nil {
+			return fmt.Errorf("delete source product tags failed: %w", err)
+		}
+
+		if err := rewriteRevisionTags(tx, sourceID, &targetID); err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`INSERT INTO tag_categories (tag_id, category_id)
+			SELECT ?, category_id FROM tag_categories WHERE tag_id = ?
+			ON CONFLICT (tag_id, category_id) DO NOTHING`, targetID, sourceID).Error; err != nil {
+			return fmt.Errorf("merge tag categories failed: %w", err)
+		}
+
+		if err := tx.Model(&model.TagAlias{}).Where("tag_id = ?", sourceID).Update("tag_id", targetID).Error; err != nil {
+			return fmt.Errorf("move tag aliases failed: %w", err)
+		}
+		if err := tx.Create(&model.TagAlias{TagID: targetID, Alias: source.Name}).Error; err != nil {
+			return fmt.Errorf("create alias for merged tag failed: %w", err)
+		}
+
+		return tx.Delete(&model.Tag{}, sourceID).Error
+	})
+}
+
+// rewriteRevisionTags 将商品历史版本中的 sourceID 标签替换为 targetID，targetID 为空时直接移除
+func rewriteRevisionTags(tx *gorm.DB, sourceID int64, targetID *int64) error {
+	err := tx.Exec(`UPDATE product_revisions SET tag_ids = (
+			SELECT COALESCE(jsonb_agg(DISTINCT t.id), '[]'::jsonb) FROM (
+				SELECT CASE WHEN v::bigint = ? THEN ?::bigint ELSE v::bigint END AS id
+				FROM jsonb_array_elements_text(tag_ids) AS elem(v)
+			) t
+			WHERE t.id IS NOT NULL
+		)
+		WHERE tag_ids @> jsonb_build_array(?::bigint)`, sourceID, targetID, sourceID).Error
+	if err != nil {
+		return fmt.Errorf("rewrite revision tags failed: %w", err)
+	}
+	return nil
+}
+
+// ResolveProposed 将卖家提议的标签名称解析为标签ID
+//
+// 解析规则：
+//   - 名称或别名命中已通过或待审核的标签时复用该标签
+//   - 命中已驳回的标签时忽略该名称
+//   - 其余名称创建为待审核标签，主分类为商品所属分类
+func (r *tagRepo) ResolveProposed(ctx context.Context, names []string, categoryID, userID int64) ([]int64, error) {
+	ids := make([]int64, 0, len(names))
+	schoolID := tenant.SchoolID(ctx)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, name := range names {
+			var existing model.Tag
+			err := tx.Where("id IN ("+tagNameMatchSQL+")", tagKeywordArgs(schoolID, name)...).Order("id ASC").First(&existing).Error
+			if err == nil {
+				if existing.Status != model.TagStatusRejected {
+					ids = append(ids, existing.ID)
+				}
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			// 并发提议同名标签时以先插入者为准
+			var row struct{ ID int64 }
+			if err := tx.Raw(`INSERT INTO tags (school_id, name, category_id, status, created_by)
+				VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (school_id, name) DO UPDATE SET name = EXCLUDED.name
+				RETURNING id`, schoolID, name, categoryID, model.TagStatusPending, userID).Scan(&row).Error; err != nil {
+				return fmt.Errorf("create proposed tag failed: %w", err)
+			}
+			if err := tx.Exec(`INSERT INTO tag_categories (tag_id, category_id) VALUES (?, ?)
+				ON CONFLICT (tag_id, category_id) DO NOTHING`, row.ID, categoryID).Error; err != nil {
+				return fmt.Errorf("create proposed tag category failed: %w", err)
+			}
+			ids = append(ids, row.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpdateStatus 在标签当前状态为 fromStatus 时更新为 toStatus
+func (r *tagRepo) UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error {
+	result := scopeTenant(ctx, r.db.WithContext(ctx).Model(&model.Tag{}), "tags").
+		Where("id = ? AND status = ?", id, fromStatus).
+		Update("status", toStatus)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Reject 在一个事务中驳回待审核标签
+// 移除商品关联与历史版本中的引用，标签行保留为已驳回状态以忽略后续同名提议
+func (r *tagRepo) Reject(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := scopeTenant(ctx, tx.Model(&model.Tag{}), "tags").
+			Where("id = ? AND status = ?", id, model.TagStatusPending).
+			Update("status", model.TagStatusRejected)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Exec("DELETE FROM product_tags WHERE tag_id = ?", id).Error; err != nil {
+			return fmt.Errorf("delete product tags failed: %w", err)
+		}
+		return rewriteRevisionTags(tx, id, nil)
+	})
+}
+
+// CountCategories 统计给定ID中实际存在的分类数量
+func (r *tagRepo) CountCategories(ctx context.Context, categoryIDs []int64) (int64, error) {
+	var count int64
+	if len(categoryIDs) == 0 {
+		return 0, nil
+	}
+	err := scopeTenant(ctx, r.db.WithContext(ctx).Model(&model.Category{}), "categories").Where("id IN ?", categoryIDs).Count(&count).Error
+	return count, err
+}
+
+// loadRelations 批量填充标签的分类关联与别名
+func (r *tagRepo) loadRelations(ctx context.Context, tags []model.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(tags))
+	for _, t := range tags {
+		ids = append(ids, t.ID)
+	}
+
+	var relations []model.TagCategory
+	if err := r.db.WithContext(ctx).
+		Where("tag_id IN ?", ids).
+		Order("category_id ASC").
+		Find(&relations).Error; err != nil {
+		return err
+	}
+	var aliases []model.TagAlias
+	if err := r.db.WithContext(ctx).
+		Where("tag_id IN ?", ids).
+		Order("id ASC").
+		Find(&aliases).Error; err != nil {
+		return err
+	}
+
+	categoryMap := make(map[int64][]int64, len(tags))
+	for _, rel := range relations {
+		categoryMap[rel.TagID] = append(categoryMap[rel.TagID], rel.CategoryID)
+	}
+	aliasMap := make(map[int64][]string, len(tags))
+	for _, a := range aliases {
+		aliasMap[a.TagID] = append(aliasMap[a.TagID], a.Alias)
+	}
+	for i := range tags {
+		tags[i].CategoryIDs = categoryMap[tags[i].ID]
+		if tags[i].CategoryIDs == nil {
+			tags[i].CategoryIDs = []int64{}
+		}
+		tags[i].Aliases = aliasMap[tags[i].ID]
+		if tags[i].Aliases == nil {
+			tags[i].Aliases = []string{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// scopeTenant 为查询追加当前学校的过滤条件
+// table 为列所属的表名或别名，避免联表查询时 school_id 列名歧义
+func scopeTenant(ctx context.Context, query *gorm.DB, table string) *gorm.DB {
+	return query.Where(table+".school_id = ?", tenant.SchoolID(ctx))
+}
+
+// ErrCrossTenant 引用的分类、标签、片区或面交地点不属于当前学校
+var ErrCrossTenant = errors.New("referenced record belongs to another school")
+
+// ensureSameSchool 校验分类、标签与面交地点均属于指定学校，防止商品引用其他学校的数据
+// meetupPointID 为 nil 表示商品未指定面交地点
+func ensureSameSchool(tx *gorm.DB, schoolID, categoryID int64, tagIDs []int64, meetupPointID *int64) error {
+	var count int64
+	if err := tx.Model(&model.Category{}).Where("id = ? AND school_id = ?", categoryID, schoolID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrCrossTenant
+	}
+	if meetupPointID != nil {
+		if err := tx.Model(&model.MeetupPoint{}).Where("id = ? AND school_id = ?", *meetupPointID, schoolID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrCrossTenant
+		}
+	}
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	if err := tx.Model(&model.Tag{}).Where("id IN ? AND school_id <> ?", tagIDs, schoolID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCrossTenant
+	}
+	return nil
+}
+
+// ensureZoneInSchool 校验片区属于指定学校，防止面交地点或用户默认片区引用其他学校的片区
+func ensureZoneInSchool(tx *gorm.DB, schoolID, zoneID int64) error {
+	var count int64
+	if err := tx.Model(&model.CampusZone{}).Where("id = ? AND school_id = ?", zoneID, schoolID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrCrossTenant
+	}
+	return nil
+}
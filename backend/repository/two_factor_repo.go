@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// TwoFactorRepository 两步验证仓库接口：TOTP 密钥与备用码
+type TwoFactorRepository interface {
+	// Get 获取用户的两步验证设置，未设置时返回 gorm.ErrRecordNotFound
+	Get(ctx context.Context, userID int64) (*model.UserTwoFactor, error)
+	// IsEnabled 判断用户是否已启用两步验证
+	IsEnabled(ctx context.Context, userID int64) (bool, error)
+	// SavePending 保存待确认的密钥，已启用时不做修改并返回 false
+	SavePending(ctx context.Context, userID int64, secret string) (bool, error)
+	// Enable 确认启用两步验证并写入备用码，记录首次验证通过的时间步；已启用时返回 false
+	Enable(ctx context.Context, userID int64, step int64, codeHashes []string) (bool, error)
+	// ConsumeStep 记录验证通过的时间步，时间步不大于上次记录时返回 false（验证码已使用）
+	ConsumeStep(ctx context.Context, userID int64, step int64) (bool, error)
+	// UseBackupCode 使用备用码，备用码不存在或已使用时返回 false
+	UseBackupCode(ctx context.Context, userID int64, codeHash string) (bool, error)
+	// ReplaceBackupCodes 作废全部旧备用码并写入新备用码
+	ReplaceBackupCodes(ctx context.Context, userID int64, codeHashes []string) error
+	// CountBackupCodes 统计未使用的备用码数量
+	CountBackupCodes(ctx context.Context, userID int64) (int64, error)
+	// Delete 关闭两步验证，删除密钥与备用码
+	Delete(ctx context.Context, userID int64) error
+}
+
+// twoFactorRepo 仓库实现
+type twoFactorRepo struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository 创建两步验证仓库
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepo{db: db}
+}
+
+// Get 获取用户的两步验证设置
+func (r *twoFactorRepo) Get(ctx context.Context, userID int64) (*model.UserTwoFactor, error) {
+	var setting model.UserTwoFactor
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&setting).Error; err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// IsEnabled 判断用户是否已启用两步验证
+func (r *twoFactorRepo) IsEnabled(ctx context.Context, userID int64) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.UserTwoFactor{}).
+		Where("user_id = ? AND enabled_at IS NOT NULL", userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SavePending 保存待确认的密钥，覆盖此前未确认的密钥
+func (r *twoFactorRepo) SavePending(ctx context.Context, userID int64, secret string) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"secret":         secret,
+			"last_used_step": 0,
+			"updated_at":     now,
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "user_two_factor.enabled_at IS NULL"}}},
+	}).Create(&model.UserTwoFactor{UserID: userID, Secret: secret, CreatedAt: now, UpdatedAt: now})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Enable 确认启用两步验证并写入备用码
+func (r *twoFactorRepo) Enable(ctx context.Context, userID int64, step int64, codeHashes []string) (bool, error) {
+	enabled := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.UserTwoFactor{}).
+			Where("user_id = ? AND enabled_at IS NULL", userID).
+			Updates(map[string]interface{}{
+				"enabled_at":     gorm.Expr("NOW()"),
+				"last_used_step": step,
+				"updated_at":     time.Now(),
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		enabled = true
+		return replaceBackupCodes(tx, userID, codeHashes)
+	})
+	return enabled, err
+}
+
+// ConsumeStep 记录验证通过的时间步，同一时间步（及更早的时间步）的验证码不能再次使用
+func (r *twoFactorRepo) ConsumeStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Updates(map[string]interface{}{
+			"last_used_step": step,
+			"updated_at":     time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// UseBackupCode 使用备用码
+func (r *twoFactorRepo) UseBackupCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.TwoFactorBackupCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", gorm.Expr("NOW()"))
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReplaceBackupCodes 作废全部旧备用码并写入新备用码
+func (r *twoFactorRepo) ReplaceBackupCodes(ctx context.Context, userID int64, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceBackupCodes(tx, userID, codeHashes)
+	})
+}
+
+// CountBackupCodes 统计未使用的备用码数量
+func (r *twoFactorRepo) CountBackupCodes(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.TwoFactorBackupCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// Delete 关闭两步验证
+func (r *twoFactorRepo) Delete(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.TwoFactorBackupCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.UserTwoFactor{}).Error
+	})
+}
+
+// replaceBackupCodes 在事务中替换用户的备用码
+func replaceBackupCodes(tx *gorm.DB, userID int64, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.TwoFactorBackupCode{}).Error; err != nil {
+		return err
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+	codes := make([]model.TwoFactorBackupCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, model.TwoFactorBackupCode{UserID: userID, CodeHash: hash})
+	}
+	return tx.Create(&codes).Error
+}
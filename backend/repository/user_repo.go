@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
 	"gorm.io/gorm"
 )
@@ -17,8 +18,22 @@ type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	// UpdateProfile updates user profile (nickname/avatar/wechat)
 	UpdateProfile(ctx context.Context, user *model.User) error
-	// UpdatePassword updates user password
+	// UpdatePassword updates user password and clears the forced-reset flag
 	UpdatePassword(ctx context.Context, userID int64, newHash string) error
+	// TouchLastLogin records the time of a successful login
+	TouchLastLogin(ctx context.Context, userID int64) error
+	// GetByEmail retrieves a user by email (case-insensitive)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	// UpdateEmail sets a new email and marks it unverified
+	UpdateEmail(ctx context.Context, userID int64, email string) error
+	// MarkEmailVerified marks the email verified if it still matches the user's current email
+	MarkEmailVerified(ctx context.Context, userID int64, email string) error
+	// TouchEmailSent records the time a verification email was sent
+	TouchEmailSent(ctx context.Context, userID int64) error
+	// CreatePasswordResetToken stores a new reset token and discards the user's unused ones
+	CreatePasswordResetToken(ctx context.Context, token *model.PasswordResetToken) error
+	// ResetPasswordByToken consumes a reset token, updates the password and revokes existing sessions
+	ResetPasswordByToken(ctx context.Context, tokenHash, newHash string) (int64, error)
 }
 
 // userRepo implements UserRepository
@@ -53,8 +68,11 @@ func (r *userRepo) GetByAccount(ctx context.Context, account string) (*model.Use
 	return &user, nil
 }
 
-// Create creates a new user
+// Create creates a new user in the school carried by ctx unless SchoolID is already set
 func (r *userRepo) Create(ctx context.Context, user *model.User) error {
+	if user.SchoolID == 0 {
+		user.SchoolID = tenant.SchoolID(ctx)
+	}
 	return r.db.WithContext(ctx).Create(user).Error
 }
 
@@ -74,7 +92,109 @@ func (r *userRepo) UpdateProfile(ctx context.Context, user *model.User) error {
 	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", user.ID).Updates(updates).Error
 }
 
-// UpdatePassword updates user password
+// UpdatePassword updates user password and clears the forced-reset flag
 func (r *userRepo) UpdatePassword(ctx context.Context, userID int64, newHash string) error {
-	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("password_hash", newHash).Error
+	updates := map[string]interface{}{
+		"password_hash":       newHash,
+		"must_reset_password": false,
+	}
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(updates).Error
+}
+
+// TouchLastLogin records the time of a successful login
+func (r *userRepo) TouchLastLogin(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).
+		UpdateColumn("last_login_at", gorm.Expr("NOW()")).Error
+}
+
+// GetByEmail retrieves a user by email (case-insensitive)
+func (r *userRepo) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	var user model.User
+	result := r.db.WithContext(ctx).Where("lower(email) = lower(?)", email).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+// UpdateEmail sets a new email and marks it unverified
+func (r *userRepo) UpdateEmail(ctx context.Context, userID int64, email string) error {
+	result := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"email":             email,
+		"email_verified_at": nil,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MarkEmailVerified marks the email verified if it still matches the user's current email
+// Returns gorm.ErrRecordNotFound when the user no longer has that email
+func (r *userRepo) MarkEmailVerified(ctx context.Context, userID int64, email string) error {
+	result := r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND lower(email) = lower(?)", userID, email).
+		Update("email_verified_at", gorm.Expr("COALESCE(email_verified_at, NOW())"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TouchEmailSent records the time a verification email was sent
+func (r *userRepo) TouchEmailSent(ctx context.Context, userID int64) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).
+		UpdateColumn("email_sent_at", gorm.Expr("NOW()")).Error
+}
+
+// CreatePasswordResetToken stores a new reset token and discards the user's unused ones
+// Only the latest reset email stays valid
+func (r *userRepo) CreatePasswordResetToken(ctx context.Context, token *model.PasswordResetToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Delete(&model.PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// ResetPasswordByToken consumes a reset token, updates the password and revokes existing sessions
+// The token is marked used atomically so it can only succeed once; returns gorm.ErrRecordNotFound
+// when the token is unknown, expired or already used
+func (r *userRepo) ResetPasswordByToken(ctx context.Context, tokenHash, newHash string) (int64, error) {
+	var userID int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var consumed []model.PasswordResetToken
+		result := tx.Raw(`UPDATE password_reset_tokens SET used_at = NOW()
+			WHERE token_hash = ? AND used_at IS NULL AND expires_at > NOW()
+			RETURNING id, user_id`, tokenHash).Scan(&consumed)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(consumed) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		userID = consumed[0].UserID
+
+		if err := tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"password_hash":       newHash,
+			"must_reset_password": false,
+			"tokens_revoked_at":   gorm.Expr("NOW()"),
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND used_at IS NULL", userID).
+			Delete(&model.PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM sessions WHERE user_id = ?", userID).Error
+	})
+	return userID, err
 }
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/account"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
+)
+
+// SetupAccountRoutes 设置个人数据导出与账号注销路由
+func SetupAccountRoutes(engine *gin.Engine, controller *account.Controller) {
+	me := engine.Group("/api/v1/users/me")
+	me.Use(middleware.AuthMiddleware())
+	{
+		me.GET("/export", middleware.RateLimitMiddleware(middleware.RateLimitDataExport), middleware.ExportWriteDeadline(), controller.Export)
+		me.POST("/delete", middleware.RateLimitMiddleware(middleware.RateLimitAuth), controller.Delete)
+	}
+}
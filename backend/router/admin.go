@@ -20,11 +20,13 @@ import (
 //   - dashboardController: 仪表盘控制器实例
 //   - userController: 用户管理控制器实例
 //   - productController: 商品管理控制器实例
+//   - auditController: 审计日志控制器实例
 //   - adminMiddleware: 管理员权限验证中间件
 func RegisterAdminRoutes(api *gin.RouterGroup,
 	dashboardController *admin.DashboardController,
 	userController *admin.UserController,
 	productController *admin.ProductController,
+	auditController *admin.AuditController,
 	adminMiddleware gin.HandlerFunc) {
 	// 创建管理员路由组
 	// 路径前缀：/api/v1/admin
@@ -39,16 +41,38 @@ func RegisterAdminRoutes(api *gin.RouterGroup,
 	// 注册仪表盘相关接口
 	// GET /api/v1/admin/dashboard - 获取仪表盘统计数据
 	adminGroup.GET("/dashboard", dashboardController.GetDashboard)
+	// GET /api/v1/admin/dashboard/metrics - 获取仪表盘时间序列数据
+	adminGroup.GET("/dashboard/metrics", dashboardController.GetDashboardMetrics)
 
 	// 注册用户管理相关接口
 	// GET /api/v1/admin/users - 获取用户列表
 	adminGroup.GET("/users", userController.ListUsers)
+	// GET /api/v1/admin/users/export - 导出用户列表（CSV/XLSX）
+	adminGroup.GET("/users/export", middleware.ExportWriteDeadline(), userController.ExportUsers)
+	// GET /api/v1/admin/users/:id - 获取用户详情（商品统计、最近登录）
+	adminGroup.GET("/users/:id", userController.GetUserDetail)
+	// PUT /api/v1/admin/users/:id/admin - 授予/撤销管理员权限
+	adminGroup.PUT("/users/:id/admin", userController.SetAdmin)
+	// POST /api/v1/admin/users/:id/reset-password - 强制重置密码
+	adminGroup.POST("/users/:id/reset-password", userController.ResetPassword)
+	// POST /api/v1/admin/users/:id/unlock - 解除连续登录失败导致的锁定
+	adminGroup.POST("/users/:id/unlock", userController.UnlockLogin)
+	// DELETE /api/v1/admin/users/:id/wechat - 清空微信号
+	adminGroup.DELETE("/users/:id/wechat", userController.ClearWechat)
 
 	// 注册商品管理相关接口
 	// GET /api/v1/admin/products - 获取商品列表
 	adminGroup.GET("/products", productController.ListProducts)
+	// GET /api/v1/admin/products/export - 导出商品列表（CSV/XLSX）
+	adminGroup.GET("/products/export", middleware.ExportWriteDeadline(), productController.ExportProducts)
 	// PUT /api/v1/admin/products/:id - 更新商品信息
 	adminGroup.PUT("/products/:id", productController.UpdateProduct)
+	// POST /api/v1/admin/products/bulk - 批量商品操作（下架/改分类/增删标签/删除）
+	adminGroup.POST("/products/bulk", productController.BulkAction)
+
+	// 注册审计日志接口
+	// GET /api/v1/admin/audit-logs - 查询管理员操作审计日志
+	adminGroup.GET("/audit-logs", auditController.ListAuditLogs)
 
 	// 注意: 分类和标签的管理路由已在 SetupCategoryRoutes 和 SetupTagRoutes 中注册
 }
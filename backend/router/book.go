@@ -0,0 +1,45 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/book"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
+)
+
+// SetupBookRoutes 设置图书目录与选修课程相关路由
+func SetupBookRoutes(engine *gin.Engine, bookController *book.BookController) {
+	// API路由组
+	api := engine.Group("/api/v1")
+
+	// 前台公开接口（无需认证）
+	public := api.Group("/")
+	{
+		// 根据ISBN查询图书
+		public.GET("/books/isbn/:isbn", bookController.LookupISBN)
+	}
+
+	// 需要认证的接口
+	auth := api.Group("/users")
+	auth.Use(middleware.AuthMiddleware())
+	{
+		// 获取/设置选修课程
+		auth.GET("/courses", bookController.GetMyCourses)
+		auth.PUT("/courses", bookController.UpdateMyCourses)
+	}
+
+	// 管理员接口（只读）
+	admin := api.Group("/admin/books")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		admin.GET("", bookController.ListBooks)
+	}
+
+	// 超级管理员接口
+	// 图书目录由全部学校共用，导入仅限超级管理员
+	super := api.Group("/super/books")
+	super.Use(middleware.AuthMiddleware(), middleware.SuperAdminMiddleware())
+	{
+		super.POST("/import", bookController.ImportBooks)
+	}
+}
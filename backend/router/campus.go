@@ -0,0 +1,39 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/campus"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
+)
+
+// SetupCampusRoutes 设置校园片区与面交地点相关路由
+func SetupCampusRoutes(engine *gin.Engine, controller *campus.Controller) {
+	api := engine.Group("/api/v1")
+
+	// 前台公开接口（无需认证）
+	public := api.Group("/")
+	{
+		public.GET("/campus/zones", controller.ListZones)
+	}
+
+	// 需要认证的接口
+	auth := api.Group("/users")
+	auth.Use(middleware.AuthMiddleware())
+	{
+		// 设置默认片区
+		auth.PUT("/default-zone", controller.UpdateDefaultZone)
+	}
+
+	// 管理员接口
+	admin := api.Group("/admin/campus")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		admin.POST("/zones", controller.CreateZone)
+		admin.PUT("/zones/:id", controller.UpdateZone)
+		admin.DELETE("/zones/:id", controller.DeleteZone)
+		admin.POST("/zones/:id/points", controller.CreatePoint)
+		admin.PUT("/points/:id", controller.UpdatePoint)
+		admin.DELETE("/points/:id", controller.DeletePoint)
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/category"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
 )
 
 // SetupCategoryRoutes 设置分类相关路由
-func SetupCategoryRoutes(engine *gin.Engine, categoryController *category.CategoryController) {
+func SetupCategoryRoutes(engine *gin.Engine, categoryController *category.CategoryController, attributeController *category.AttributeController) {
 	// API路由组
 	api := engine.Group("/api/v1")
 
@@ -16,5 +17,24 @@ func SetupCategoryRoutes(engine *gin.Engine, categoryController *category.Catego
 	{
 		// 获取所有分类
 		public.GET("/categories", categoryController.ListCategories)
+		// 获取分类属性定义
+		public.GET("/categories/:id/attributes", attributeController.ListAttributes)
+	}
+
+	// 管理员接口
+	admin := api.Group("/admin/categories")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		admin.POST("", categoryController.CreateCategory)
+		// 静态路径需在 /:id 之前注册
+		admin.PUT("/reorder", categoryController.ReorderCategories)
+		admin.PUT("/:id", categoryController.UpdateCategory)
+		admin.PUT("/:id/move", categoryController.MoveCategory)
+		admin.DELETE("/:id", categoryController.DeleteCategory)
+
+		// 分类属性定义
+		admin.POST("/:id/attributes", attributeController.CreateAttribute)
+		admin.PUT("/:id/attributes/:attrId", attributeController.UpdateAttribute)
+		admin.DELETE("/:id/attributes/:attrId", attributeController.DeleteAttribute)
 	}
 }
@@ -0,0 +1,61 @@
+package router
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// readinessTimeout 就绪检查中数据库 Ping 的超时时间
+const readinessTimeout = 2 * time.Second
+
+// shuttingDown 进程是否已开始关闭；关闭开始后就绪探针返回 503，存活探针不受影响
+var shuttingDown atomic.Bool
+
+// MarkShuttingDown 标记进程开始关闭，在停止 HTTP 服务器之前调用，使负载均衡器先摘除本实例
+func MarkShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// SetupHealthRoutes 设置健康检查路由
+//
+// 用途：
+//   - /health/live：存活探针，进程能响应即返回 200，失败时应重启进程
+//   - /health/ready：就绪探针，数据库不可用或进程正在关闭时返回 503，负载均衡器应暂停转发流量而不是重启进程
+//   - /health：保留的旧端点，等同于存活探针
+//
+// 返回格式：{"status":"ok"}，不可用时为 {"status":"unavailable"}，正在关闭时为 {"status":"shutting_down"}
+// （错误详情只写入日志，避免向外暴露内部信息）
+func SetupHealthRoutes(engine *gin.Engine, db *gorm.DB) {
+	live := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+	engine.GET("/health", live)
+	engine.GET("/health/live", live)
+
+	engine.GET("/health/ready", func(c *gin.Context) {
+		if shuttingDown.Load() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting_down"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		defer cancel()
+
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.PingContext(ctx)
+		}
+		if err != nil {
+			log.Printf("health: database not ready: %v", err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "database": "ok"})
+	})
+}
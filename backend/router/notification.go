@@ -0,0 +1,20 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/notification"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
+)
+
+// SetupNotificationRoutes 设置站内通知路由
+func SetupNotificationRoutes(engine *gin.Engine, controller *notification.Controller) {
+	notifications := engine.Group("/api/v1/notifications")
+	notifications.Use(middleware.AuthMiddleware())
+	{
+		notifications.GET("", controller.List)
+		notifications.GET("/unread-count", controller.UnreadCount)
+		notifications.POST("/read-all", controller.MarkAllRead)
+		notifications.POST("/:id/read", controller.MarkRead)
+	}
+}
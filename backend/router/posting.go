@@ -0,0 +1,23 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/posting"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
+)
+
+// SetupPostingRoutes 设置发布限制管理路由（屏蔽词与用户发布配额）
+func SetupPostingRoutes(engine *gin.Engine, controller *posting.Controller) {
+	admin := engine.Group("/api/v1/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		admin.GET("/posting/keywords", controller.ListKeywords)
+		admin.POST("/posting/keywords", controller.AddKeyword)
+		admin.DELETE("/posting/keywords/:id", controller.DeleteKeyword)
+
+		admin.GET("/users/:id/posting-quota", controller.GetUserQuota)
+		admin.PUT("/users/:id/posting-quota", controller.SetUserQuota)
+		admin.DELETE("/users/:id/posting-quota", controller.ClearUserQuota)
+	}
+}
@@ -18,11 +18,13 @@ func SetupProductRoutes(engine *gin.Engine, productController *product.ProductCo
 		// 获取商品详情 - 使用可选认证中间件以便记录浏览
 		public.GET("/products/:id", middleware.OptionalAuthMiddleware(), productController.GetProductDetail)
 		// 获取卖家联系方式 - 可选登录，未登录返回提示
-		public.GET("/products/:id/contact", middleware.OptionalAuthMiddleware(), productController.GetProductContact)
+		public.GET("/products/:id/contact", middleware.OptionalAuthMiddleware(), middleware.RequireVerifiedEmail(), productController.GetProductContact)
 		// 搜索商品
 		public.GET("/products/search", productController.SearchProducts)
 		// 获取分类商品
 		public.GET("/products/category/:categoryId", productController.GetProductsByCategory)
+		// 获取商品价格历史 - 可选登录，卖家本人与管理员可查看已下架商品
+		public.GET("/products/:id/price-history", middleware.OptionalAuthMiddleware(), productController.GetPriceHistory)
 	}
 
 	// 需要认证的接口
@@ -30,20 +32,32 @@ func SetupProductRoutes(engine *gin.Engine, productController *product.ProductCo
 	auth.Use(middleware.AuthMiddleware()) // 使用认证中间件
 	{
 		// 创建商品
-		auth.POST("/products", productController.CreateProduct)
+		auth.POST("/products", middleware.RateLimitMiddleware(middleware.RateLimitProductCreate), middleware.RequireVerifiedEmail(), productController.CreateProduct)
 		// 更新商品
 		auth.PUT("/products/:id", productController.UpdateProduct)
 		// 变更商品状态
 		auth.POST("/products/:id/status", productController.ChangeProductStatus)
 		// 撤销状态变更
 		auth.POST("/products/:id/status/undo", productController.UndoLastStatusChange)
+		// 擦亮在售商品（续期，避免长期无活动被自动下架）
+		auth.POST("/products/:id/bump", productController.BumpProduct)
 		// 获取我的商品列表
 		auth.GET("/products/my", productController.ListMyProducts)
+		// 获取商品修订历史（卖家本人或管理员）
+		auth.GET("/products/:id/revisions", productController.ListRevisions)
 
 		// 图片管理接口
-		auth.POST("/products/:id/images", imageController.UploadProductImage)
+		auth.POST("/products/:id/images", middleware.RateLimitMiddleware(middleware.RateLimitUpload), imageController.UploadProductImage)
 		auth.PUT("/products/:id/images/:imageId/primary", imageController.SetPrimaryImage)
 		auth.PATCH("/products/:id/images/:imageId", imageController.UpdateImageSortOrder)
 		auth.DELETE("/products/:id/images/:imageId", imageController.DeleteProductImage)
 	}
+
+	// 管理员接口
+	admin := api.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		// 回滚商品到指定版本
+		admin.POST("/products/:id/revisions/:version/revert", productController.RevertRevision)
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/product_condition"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
 )
 
 // SetupProductConditionRoutes 设置新旧程度相关路由
@@ -13,4 +14,16 @@ func SetupProductConditionRoutes(engine *gin.Engine, controller *productconditio
 	{
 		public.GET("/product-conditions", controller.ListProductConditions)
 	}
+
+	// 超级管理员接口
+	// 新旧程度由全部学校共用，学校管理员不能修改
+	super := api.Group("/super/product-conditions")
+	super.Use(middleware.AuthMiddleware(), middleware.SuperAdminMiddleware())
+	{
+		super.POST("", controller.CreateProductCondition)
+		// 静态路径需在 /:id 之前注册
+		super.PUT("/reorder", controller.ReorderProductConditions)
+		super.PUT("/:id", controller.UpdateProductCondition)
+		super.DELETE("/:id", controller.DeleteProductCondition)
+	}
 }
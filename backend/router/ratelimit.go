@@ -0,0 +1,35 @@
+package router
+
+import (
+	"log"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cache"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/config"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
+)
+
+// setupRateLimits 解析各分组限流配额并注入限流中间件，配置错误时终止启动
+func setupRateLimits(cfg *config.Config, memCache *cache.MemoryCache) {
+	if !cfg.RateLimitEnabled {
+		return
+	}
+
+	groups := map[string]string{
+		middleware.RateLimitGlobal:        cfg.RateLimitGlobal,
+		middleware.RateLimitAuth:          cfg.RateLimitAuth,
+		middleware.RateLimitUpload:        cfg.RateLimitUpload,
+		middleware.RateLimitProductCreate: cfg.RateLimitProductCreate,
+		middleware.RateLimitDataExport:    cfg.RateLimitDataExport,
+	}
+	limits := make(map[string]middleware.RateLimit, len(groups))
+	for group, raw := range groups {
+		limit, enabled, err := middleware.ParseRateLimit(raw)
+		if err != nil {
+			log.Fatalf("限流配置错误（%s）: %v", group, err)
+		}
+		if enabled {
+			limits[group] = limit
+		}
+	}
+	middleware.SetRateLimiter(middleware.NewMemoryRateLimitStore(memCache), limits)
+}
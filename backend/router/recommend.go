@@ -17,6 +17,7 @@ import (
 //  1. 注册首页数据接口（公开）
 //  2. 注册最近浏览接口（需要登录）
 //  3. 注册记录浏览接口（需要登录）
+//  4. 注册课程教材推荐接口（需要登录）
 func SetupRecommendRoutes(r *gin.Engine, recommendController *recommend.RecommendController) {
 	api := r.Group("/api/v1")
 	{
@@ -29,6 +30,8 @@ func SetupRecommendRoutes(r *gin.Engine, recommendController *recommend.Recommen
 		{
 			// 获取最近浏览记录
 			users.GET("/recent-views", recommendController.GetRecentViews)
+			// 获取选修课程的教材推荐
+			users.GET("/courses/books", recommendController.GetCourseBooks)
 		}
 
 		// 记录商品浏览（需要登录）
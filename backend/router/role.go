@@ -0,0 +1,124 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+// userRoleResolver 基于用户表解析请求用户的角色与所属学校，供鉴权中间件使用
+type userRoleResolver struct {
+	userRepo repository.UserRepository
+}
+
+// ResolveRole 实现 middleware.RoleResolver
+func (r *userRoleResolver) ResolveRole(ctx context.Context, userID int64) (string, int64, error) {
+	user, err := r.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", 0, middleware.ErrUserNotFound
+		}
+		return "", 0, err
+	}
+	switch {
+	case user.IsSuperAdmin:
+		return middleware.RoleSuperAdmin, user.SchoolID, nil
+	case user.IsAdmin:
+		return middleware.RoleAdmin, user.SchoolID, nil
+	default:
+		return middleware.RoleUser, user.SchoolID, nil
+	}
+}
+
+// schoolCodeResolver 基于学校表解析学校编码，供租户中间件使用
+type schoolCodeResolver struct {
+	schoolRepo repository.SchoolRepository
+}
+
+// ResolveSchool 实现 middleware.SchoolResolver
+func (r *schoolCodeResolver) ResolveSchool(ctx context.Context, code string) (int64, bool, error) {
+	school, err := r.schoolRepo.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, false, middleware.ErrSchoolNotFound
+		}
+		return 0, false, err
+	}
+	return school.ID, school.IsActive, nil
+}
+
+// userEmailChecker 基于用户表判断邮箱是否已验证，供邮箱验证中间件使用
+type userEmailChecker struct {
+	userRepo repository.UserRepository
+}
+
+// IsEmailVerified 实现 middleware.EmailVerifiedChecker
+func (r *userEmailChecker) IsEmailVerified(ctx context.Context, userID int64) (bool, error) {
+	user, err := r.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user.EmailVerifiedAt != nil, nil
+}
+
+// userTokenRevocationChecker 基于用户表的令牌作废时间判断登录令牌是否失效
+type userTokenRevocationChecker struct {
+	userRepo repository.UserRepository
+}
+
+// IsTokenRevoked 实现 middleware.TokenRevocationChecker
+// JWT 签发时间只精确到秒，与作废时间同一秒内签发的令牌无法区分先后，一律视为已作废
+// （重置密码同一秒内的重新登录需再登录一次）；用户已删除时令牌同样作废
+func (r *userTokenRevocationChecker) IsTokenRevoked(ctx context.Context, userID int64, issuedAt time.Time) (bool, error) {
+	user, err := r.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	if user.TokensRevokedAt == nil {
+		return false, nil
+	}
+	return !issuedAt.After(user.TokensRevokedAt.Truncate(time.Second)), nil
+}
+
+// userTwoFactorChecker 基于两步验证表判断用户是否已启用两步验证，供管理员中间件使用
+type userTwoFactorChecker struct {
+	twoFactorRepo repository.TwoFactorRepository
+}
+
+// IsTwoFactorEnabled 实现 middleware.TwoFactorChecker
+func (r *userTwoFactorChecker) IsTwoFactorEnabled(ctx context.Context, userID int64) (bool, error) {
+	return r.twoFactorRepo.IsEnabled(ctx, userID)
+}
+
+// userSessionValidator 基于会话表判断登录会话是否有效，供鉴权中间件使用
+type userSessionValidator struct {
+	sessionRepo repository.SessionRepository
+}
+
+// sessionTouchInterval 会话最近活跃时间的最小更新间隔，避免每个请求都写库
+const sessionTouchInterval = 5 * time.Minute
+
+// IsSessionActive 实现 middleware.SessionValidator
+// 会话被移除（用户移除设备、重置密码）或不属于该用户时视为无效
+func (r *userSessionValidator) IsSessionActive(ctx context.Context, userID int64, sessionID string) (bool, error) {
+	session, err := r.sessionRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if session.UserID != userID {
+		return false, nil
+	}
+	_ = r.sessionRepo.TouchSession(ctx, session.ID, sessionTouchInterval)
+	return true, nil
+}
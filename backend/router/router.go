@@ -3,27 +3,48 @@
 package router
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
 	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cache"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/loginguard"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/mailer"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/common/util"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/config"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/account"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/admin"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/book"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/campus"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/category"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/notification"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/posting"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/product"
 	productconditioncontroller "github.com/yycy134679/school-secondhand-trading-system/backend/controller/product_condition"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/recommend"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/scheduler"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/school"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/tag"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/upload"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/user"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+	accountservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/account"
 	adminservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/admin"
+	bookservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/book"
+	campusservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/campus"
 	categoryservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/category"
+	expiryservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/expiry"
+	notificationservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/notification"
+	postingservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/posting"
 	productservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/product"
 	productconditionservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/product_condition"
 	recommendservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/recommend"
+	schedulerservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/scheduler"
+	schoolservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/school"
 	tagservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/tag"
 	userservice "github.com/yycy134679/school-secondhand-trading-system/backend/service/user"
 )
@@ -40,25 +61,33 @@ import (
 //   - db: GORM数据库连接实例，用于数据持久化操作
 //   - memCache: 内存缓存服务实例，用于缓存和状态管理
 //   - cfg: 应用配置对象，包含JWT密钥、文件存储路径等
+//   - jobScheduler: 定时任务调度器，各模块的后台任务注册到其中，由调用方启动和停止
 //
 // 返回值：
 //   - *gin.Engine: 配置好的Gin引擎实例，可直接用于启动HTTP服务器
 //
 // 路由结构：
 //
-//	/health              - 健康检查端点（用于负载均衡器和监控）
+//	/health/live         - 存活探针（进程可响应请求）
+//	/health/ready        - 就绪探针（数据库可用，可以接收流量）
 //	/api/v1/users/*      - 用户相关接口（注册、登录、个人信息等）
 //	/api/v1/products/*   - 商品相关接口（发布、搜索、详情等）
 //	/api/v1/categories/* - 分类管理接口（待实现）
 //	/api/v1/tags/*       - 标签管理接口（待实现）
 //	/api/v1/admin/*      - 后台管理接口（待实现）
-func SetupRouter(db *gorm.DB, memCache *cache.MemoryCache, cfg *config.Config) *gin.Engine {
+func SetupRouter(db *gorm.DB, memCache *cache.MemoryCache, cfg *config.Config, jobScheduler *schedulerservice.Scheduler) *gin.Engine {
 	// 创建Gin引擎实例
 	// gin.Default() 会自动附加两个中间件：
 	// 1. Logger() - 记录每个HTTP请求的日志（方法、路径、状态码、耗时等）
 	// 2. Recovery() - 捕获panic并返回500错误，防止服务器崩溃
 	r := gin.Default()
 
+	// 只信任配置的反向代理转发的客户端 IP，c.ClientIP() 用于限流与登录防暴力破解，不能被请求头伪造
+	// 默认（未配置）不信任任何代理；部署在 Nginx 等反向代理之后时需配置 TRUSTED_PROXIES
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// 同步文件存储目录配置，确保静态托管与保存路径一致
 	util.FileStorageDir = cfg.FileStorageDir
 
@@ -66,18 +95,22 @@ func SetupRouter(db *gorm.DB, memCache *cache.MemoryCache, cfg *config.Config) *
 	// 注意：在生产环境中，建议配置具体的允许来源，而不是使用通配符
 	r.Use(middleware.CORSMiddleware())
 
+	// 注册全局限流中间件（按IP），各分组配额见 setupRateLimits
+	setupRateLimits(cfg, memCache)
+	r.Use(middleware.RateLimitMiddleware(middleware.RateLimitGlobal))
+
+	// 流式导出接口使用单独的写出超时，避免大文件被服务器级 WriteTimeout 截断
+	middleware.SetExportWriteTimeout(cfg.HTTPExportWriteTimeout)
+
+	// 注册租户中间件，按请求头或子域名解析学校
+	// 学校解析器在下方创建仓库后注入
+	r.Use(middleware.TenantMiddleware(cfg.TenantBaseDomain))
+
 	// 静态托管上传目录，确保返回的上传 URL 可直接访问
 	r.Static("/uploads", util.FileStorageDir)
 
-	// 注册健康检查端点
-	// 用途：
-	// - 负载均衡器（如Nginx、K8s）用于检测服务是否存活
-	// - 监控系统用于健康状态检查
-	// - 开发调试用于快速验证服务是否启动
-	// 返回格式：{"status":"ok"}
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	// 注册健康检查端点（存活与就绪探针）
+	SetupHealthRoutes(r, db)
 
 	// 创建API v1路由组
 	// 路由组的优势：
@@ -89,9 +122,52 @@ func SetupRouter(db *gorm.DB, memCache *cache.MemoryCache, cfg *config.Config) *
 		// 初始化用户相关组件
 		// 创建用户仓库实例
 		userRepo := repository.NewUserRepository(db)
+		// 鉴权中间件通过用户表实时解析角色（管理员权限变更立即生效）
+		middleware.SetRoleResolver(&userRoleResolver{userRepo: userRepo})
+		// 重置密码后，此前签发的登录令牌全部失效
+		middleware.SetTokenRevocationChecker(&userTokenRevocationChecker{userRepo: userRepo})
+		// 租户中间件通过学校表解析学校编码
+		schoolRepo := repository.NewSchoolRepository(db)
+		middleware.SetSchoolResolver(&schoolCodeResolver{schoolRepo: schoolRepo})
 		productRepo := repository.NewProductRepository(db)
+		// 登录防暴力破解：按账号与IP统计失败次数，计数保存在内存缓存中
+		loginGuard := loginguard.NewGuard(loginguard.NewMemoryStore(memCache), loginguard.DefaultAccountPolicy(), loginguard.DefaultIPPolicy())
+		// 开启校园邮箱验证时，未验证用户不能发布商品或查看联系方式
+		if cfg.EmailVerifyEnabled {
+			middleware.SetEmailVerifiedChecker(&userEmailChecker{userRepo: userRepo})
+		}
+		// 创建邮件发送器（开发环境可使用 log/file 驱动）
+		mail, err := mailer.New(mailer.Config{
+			Driver:       cfg.MailDriver,
+			From:         cfg.MailFrom,
+			FileDir:      cfg.MailFileDir,
+			SMTPHost:     cfg.SMTPHost,
+			SMTPPort:     cfg.SMTPPort,
+			SMTPUsername: cfg.SMTPUsername,
+			SMTPPassword: cfg.SMTPPassword,
+		})
+		if err != nil {
+			log.Fatalf("初始化邮件发送器失败: %v", err)
+		}
+		// 两步验证：管理员必须启用并在有效期内通过验证才能访问管理后台
+		twoFactorRepo := repository.NewTwoFactorRepository(db)
+		if cfg.TwoFactorAdminRequired {
+			middleware.SetTwoFactorChecker(&userTwoFactorChecker{twoFactorRepo: twoFactorRepo}, cfg.TwoFactorStepUpTTL)
+		}
+		// 登录会话：令牌绑定会话，用户移除设备后对应令牌立即失效
+		sessionRepo := repository.NewSessionRepository(db)
+		middleware.SetSessionValidator(&userSessionValidator{sessionRepo: sessionRepo})
+		// 站内通知（新设备登录提醒等）
+		notificationService := notificationservice.NewService(repository.NewNotificationRepository(db))
+		SetupNotificationRoutes(r, notification.NewController(notificationService))
 		// 创建用户服务实例
-		userService := userservice.NewUserService(userRepo)
+		userService := userservice.NewUserService(userRepo, twoFactorRepo, sessionRepo, memCache, loginGuard, mail, notificationService, userservice.EmailVerificationConfig{
+			Required:       cfg.EmailVerifyEnabled,
+			AllowedDomains: cfg.EmailAllowedDomains,
+			TTL:            cfg.EmailVerifyTTL,
+			LinkBaseURL:    cfg.AppBaseURL,
+			ResetTTL:       cfg.PasswordResetTTL,
+		}, cfg.TwoFactorIssuer)
 
 		// 注册用户模块路由
 		// 包含的接口：
@@ -100,11 +176,24 @@ func SetupRouter(db *gorm.DB, memCache *cache.MemoryCache, cfg *config.Config) *
 		// GET  /api/v1/users/profile   - 获取个人信息
 		// PUT  /api/v1/users/profile   - 更新个人信息
 		// PUT  /api/v1/users/password  - 修改密码
+		// PUT  /api/v1/users/email     - 设置邮箱并发送验证邮件
+		// POST /api/v1/users/password/forgot - 找回密码（发送重置邮件）
+		// POST /api/v1/users/password/reset  - 通过重置令牌设置新密码
+		// POST /api/v1/users/login/2fa       - 提交两步验证码完成登录
+		// /api/v1/users/2fa/*                - 两步验证绑定、备用码与二次验证
+		// /api/v1/users/sessions             - 已登录设备查看与移除
+		// GET  /api/v1/users/login-history   - 登录记录
 		user.RegisterRoutes(api, userService)
 
+		// 个人数据导出与账号注销
+		// GET  /api/v1/users/me/export - 下载我的数据（ZIP）
+		// POST /api/v1/users/me/delete - 注销账号
+		accountService := accountservice.NewService(userRepo, repository.NewAccountRepository(db), sessionRepo)
+		SetupAccountRoutes(r, account.NewController(accountService))
+
 		// 通用上传接口
 		uploadController := upload.NewUploadController()
-		api.POST("/upload", middleware.AuthMiddleware(), uploadController.UploadImage)
+		api.POST("/upload", middleware.AuthMiddleware(), middleware.RateLimitMiddleware(middleware.RateLimitUpload), uploadController.UploadImage)
 
 		// 注册商品模块路由
 		// 包含的接口（示例）：
@@ -114,30 +203,79 @@ func SetupRouter(db *gorm.DB, memCache *cache.MemoryCache, cfg *config.Config) *
 		// GET  /api/v1/products/search  - 搜索商品
 		// GET  /api/v1/products/my      - 我的发布
 		// 创建商品相关组件
-		productService := productservice.NewProductService(db, productRepo, userRepo, memCache)
+		productRevisionRepo := repository.NewProductRevisionRepository(db)
+		categoryAttributeRepo := repository.NewCategoryAttributeRepository(db)
+		bookRepo := repository.NewBookRepository(db)
+		tagRepo := repository.NewTagRepository(db)
+		campusRepo := repository.NewCampusRepository(db)
+		// 发布限制：在售/每日发布配额与屏蔽词，管理员可维护屏蔽词与用户配额覆盖
+		postingService := postingservice.NewService(repository.NewPostingRepository(db), userRepo, memCache, postingservice.QuotaConfig{
+			MaxForSale:           cfg.QuotaMaxForSale,
+			MaxDaily:             cfg.QuotaMaxDaily,
+			NewAccountDays:       cfg.QuotaNewAccountDays,
+			NewAccountMaxForSale: cfg.QuotaNewAccountMaxForSale,
+			NewAccountMaxDaily:   cfg.QuotaNewAccountMaxDaily,
+		})
+		SetupPostingRoutes(r, posting.NewController(postingService))
+
+		productService := productservice.NewProductService(db, productRepo, userRepo, productRevisionRepo, categoryAttributeRepo, bookRepo, tagRepo, campusRepo, postingService, memCache)
 		productController := product.NewProductController(productService)
 		imageController := product.NewImageController(productService)
 		SetupProductRoutes(r, productController, imageController)
 
+		// 商品自动过期：卖家长期无活动的在售商品先提醒、再自动下架，卖家可擦亮续期
+		expiryService := expiryservice.NewService(repository.NewListingExpiryRepository(db), notificationService, expiryservice.Config{
+			ExpireAfter:  time.Duration(cfg.ListingExpireDays) * 24 * time.Hour,
+			RemindBefore: time.Duration(cfg.ListingExpireRemindDays) * 24 * time.Hour,
+		})
+		if expiryService.Enabled() {
+			mustRegisterJob(jobScheduler, schedulerservice.JobSpec{
+				Name:        "listing_expiry",
+				Description: "提醒即将过期的在售商品，并下架已过期的商品",
+				Schedule:    cfg.ListingExpirySchedule,
+				MaxRetries:  3,
+				Handler: func(ctx context.Context) error {
+					_, err := expiryService.Run(ctx)
+					return err
+				},
+			})
+		}
+
 		// 初始化推荐服务和浏览记录相关组件
 		viewRecordRepo := repository.NewViewRecordRepository(db)
-		recommendService := recommendservice.NewRecommendService(viewRecordRepo, productRepo, db, nil) // Redis设为nil,可选
+		recommendService := recommendservice.NewRecommendService(viewRecordRepo, productRepo, bookRepo, campusRepo, db, nil) // Redis设为nil,可选
 		recommendController := recommend.NewRecommendController(recommendService)
 		SetupRecommendRoutes(r, recommendController)
 
+		// 初始化图书目录相关组件（ISBN查询、CSV导入、选修课程）
+		bookService := bookservice.NewBookService(bookRepo)
+		bookController := book.NewBookController(bookService)
+		SetupBookRoutes(r, bookController)
+
+		// 初始化学校（租户）相关组件
+		schoolService := schoolservice.NewService(schoolRepo)
+		schoolController := school.NewController(schoolService)
+		SetupSchoolRoutes(r, schoolController)
+
+		// 初始化校园片区与面交地点相关组件
+		campusService := campusservice.NewService(campusRepo)
+		campusController := campus.NewController(campusService)
+		SetupCampusRoutes(r, campusController)
+
 		// 初始化分类、标签、新旧程度相关组件
 		// 创建仓库层实例
 		categoryRepo := repository.NewCategoryRepository(db)
-		tagRepo := repository.NewTagRepository(db)
 		productConditionRepo := repository.NewProductConditionRepository(db)
 
 		// 创建服务层实例
 		categoryService := categoryservice.NewCategoryService(categoryRepo)
+		attributeService := categoryservice.NewAttributeService(categoryRepo, categoryAttributeRepo)
 		tagService := tagservice.NewTagService(tagRepo)
-		productConditionService := productconditionservice.NewService(productConditionRepo)
+		productConditionService := productconditionservice.NewService(productConditionRepo, memCache)
 
 		// 创建控制器实例
 		categoryController := category.NewCategoryController(categoryService)
+		attributeController := category.NewAttributeController(attributeService)
 		tagController := tag.NewTagController(tagService)
 		productConditionController := productconditioncontroller.NewController(productConditionService)
 
@@ -145,7 +283,7 @@ func SetupRouter(db *gorm.DB, memCache *cache.MemoryCache, cfg *config.Config) *
 		adminMiddleware := middleware.AdminMiddleware()
 
 		// 注册分类模块路由
-		SetupCategoryRoutes(r, categoryController)
+		SetupCategoryRoutes(r, categoryController, attributeController)
 
 		// 注册新旧程度路由
 		SetupProductConditionRoutes(r, productConditionController)
@@ -155,18 +293,50 @@ func SetupRouter(db *gorm.DB, memCache *cache.MemoryCache, cfg *config.Config) *
 
 		// 初始化管理后台相关组件
 		// 创建服务层实例
-		adminService := adminservice.NewAdminService(db)
+		adminService := adminservice.NewAdminService(db, productRepo, productRevisionRepo, memCache, loginGuard, sessionRepo)
 
 		// 创建其他管理后台控制器实例
 		dashboardController := admin.NewDashboardController(adminService)
 		userController := admin.NewUserController(adminService)
 		adminProductController := admin.NewProductController(adminService)
+		auditController := admin.NewAuditController(adminService)
 
 		// 注册管理后台路由（不包括分类和标签，因为已经在上面注册了）
-		RegisterAdminRoutes(api, dashboardController, userController, adminProductController, adminMiddleware)
+		RegisterAdminRoutes(api, dashboardController, userController, adminProductController, auditController, adminMiddleware)
+
+		// 过期数据清理：过期的登录会话与超过保留期的任务执行记录
+		jobRunRetention := time.Duration(cfg.JobRunRetentionDays) * 24 * time.Hour
+		mustRegisterJob(jobScheduler, schedulerservice.JobSpec{
+			Name:        "cleanup",
+			Description: "清理过期的登录会话与任务执行记录",
+			Schedule:    cfg.CleanupSchedule,
+			MaxRetries:  3,
+			Handler: func(ctx context.Context) error {
+				sessions, err := sessionRepo.DeleteExpiredSessions(ctx, time.Now())
+				if err != nil {
+					return err
+				}
+				runs, err := jobScheduler.PruneRuns(ctx, jobRunRetention)
+				if err != nil {
+					return err
+				}
+				log.Printf("cleanup: removed %d expired session(s), %d job run(s)", sessions, runs)
+				return nil
+			},
+		})
+
+		// 定时任务管理（超级管理员）
+		SetupJobRoutes(r, scheduler.NewController(jobScheduler))
 	}
 
 	// 返回配置好的Gin引擎实例
 	// 调用方可以直接使用 engine.Run(":8080") 启动服务器
 	return r
 }
+
+// mustRegisterJob 注册定时任务，任务定义（如 cron 表达式）无效时终止启动
+func mustRegisterJob(jobScheduler *schedulerservice.Scheduler, spec schedulerservice.JobSpec) {
+	if err := jobScheduler.Register(spec); err != nil {
+		log.Fatalf("注册定时任务失败: %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/scheduler"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
+)
+
+// SetupJobRoutes 设置定时任务管理路由
+// 定时任务跨学校执行，仅超级管理员可查看与触发
+func SetupJobRoutes(engine *gin.Engine, controller *scheduler.Controller) {
+	super := engine.Group("/api/v1/super/jobs")
+	super.Use(middleware.AuthMiddleware(), middleware.SuperAdminMiddleware())
+	{
+		super.GET("", controller.ListJobs)
+		super.GET("/:name/runs", controller.ListRuns)
+		super.POST("/:name/run", controller.Trigger)
+	}
+}
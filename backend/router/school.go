@@ -0,0 +1,29 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/school"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
+)
+
+// SetupSchoolRoutes 设置学校（租户）相关路由
+func SetupSchoolRoutes(engine *gin.Engine, controller *school.Controller) {
+	api := engine.Group("/api/v1")
+
+	// 前台公开接口（无需认证），供注册时选择学校
+	public := api.Group("/")
+	{
+		public.GET("/schools", controller.ListActive)
+	}
+
+	// 超级管理员接口
+	// 管理某个学校内的数据时，超级管理员通过 X-School-Code 请求头指定学校后调用 /api/v1/admin 下的接口
+	super := api.Group("/super/schools")
+	super.Use(middleware.AuthMiddleware(), middleware.SuperAdminMiddleware())
+	{
+		super.GET("", controller.ListAll)
+		super.POST("", controller.Create)
+		super.PUT("/:id", controller.Update)
+	}
+}
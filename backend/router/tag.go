@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/yycy134679/school-secondhand-trading-system/backend/controller/tag"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/middleware"
 )
 
 // SetupTagRoutes 设置标签相关路由
@@ -14,7 +15,24 @@ func SetupTagRoutes(engine *gin.Engine, tagController *tag.TagController) {
 	// 前台公开接口（无需认证）
 	public := api.Group("/")
 	{
-		// 获取所有标签
+		// 获取标签，支持 categoryId 过滤
 		public.GET("/tags", tagController.ListTags)
 	}
+
+	// 管理员接口
+	admin := api.Group("/admin/tags")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		admin.GET("", tagController.ListReviewTags)
+		admin.POST("", tagController.CreateTag)
+		admin.PUT("/:id", tagController.UpdateTag)
+		admin.DELETE("/:id", tagController.DeleteTag)
+		admin.PUT("/:id/aliases", tagController.SetTagAliases)
+		admin.POST("/:id/merge", tagController.MergeTag)
+
+		// 卖家提议标签审核
+		admin.POST("/:id/approve", tagController.ApproveTag)
+		admin.POST("/:id/reject", tagController.RejectTag)
+		admin.POST("/:id/map", tagController.MapTag)
+	}
 }
@@ -0,0 +1,12 @@
+package account
+
+import "errors"
+
+// 错误定义
+var (
+	ErrUserNotFound      = errors.New("用户不存在")
+	ErrAccountDeleted    = errors.New("账号已注销")
+	ErrPasswordRequired  = errors.New("请输入登录密码以确认注销")
+	ErrIncorrectPassword = errors.New("密码错误")
+	ErrAdminAccount      = errors.New("管理员账号不能自助注销，请先由超级管理员取消管理员权限")
+)
@@ -0,0 +1,251 @@
+package account
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/auth"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/util"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+// deletedPasswordHash 注销账号写入的密码哈希，不是合法的 bcrypt 哈希，任何密码都无法通过校验
+const deletedPasswordHash = "!deleted-account"
+
+// exportLoginHistoryLimit 导出的登录记录条数上限
+const exportLoginHistoryLimit = 1000
+
+// ExportImage 导出的商品图片，File 为压缩包内的路径，图片不在本地存储时为空
+type ExportImage struct {
+	URL       string `json:"url"`
+	IsPrimary bool   `json:"isPrimary"`
+	SortOrder int    `json:"sortOrder"`
+	File      string `json:"file,omitempty"`
+}
+
+// ExportProduct 导出的商品
+type ExportProduct struct {
+	model.Product
+	Attributes map[string]interface{} `json:"attributes"`
+	Images     []ExportImage          `json:"images"`
+}
+
+// DeleteResult 注销结果
+type DeleteResult struct {
+	DelistedProducts int64 `json:"delistedProducts"`
+}
+
+// Service 账号数据导出与注销服务接口
+type Service interface {
+	// Export 将用户的个人数据打包为 ZIP 写入 w
+	// 数据全部查询完成后才开始写入，查询失败时 w 不会被写入任何内容
+	Export(ctx context.Context, userID int64, w io.Writer) error
+	// Delete 校验密码后注销账号：下架在售商品、删除浏览记录等个人数据并匿名化资料，已售记录保留
+	Delete(ctx context.Context, userID int64, password string) (*DeleteResult, error)
+}
+
+type service struct {
+	userRepo    repository.UserRepository
+	accountRepo repository.AccountRepository
+	sessionRepo repository.SessionRepository
+}
+
+// NewService 创建服务实例
+func NewService(userRepo repository.UserRepository, accountRepo repository.AccountRepository, sessionRepo repository.SessionRepository) Service {
+	return &service{userRepo: userRepo, accountRepo: accountRepo, sessionRepo: sessionRepo}
+}
+
+// exportFile 压缩包内的一个文件，data 与 source 二选一
+type exportFile struct {
+	name   string
+	data   []byte
+	source string
+}
+
+// Export 导出个人数据
+//
+// 压缩包内容：
+//   - profile.json: 个人资料（不含密码）
+//   - products.json: 发布的全部商品及图片
+//   - images/: 商品图片与头像原图（仅本地存储的图片）
+//   - views.json: 浏览记录
+//   - login_history.json: 登录记录
+func (s *service) Export(ctx context.Context, userID int64, w io.Writer) error {
+	user, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	products, err := s.accountRepo.ListProducts(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("查询商品失败: %w", err)
+	}
+	productIDs := make([]int64, 0, len(products))
+	for _, p := range products {
+		productIDs = append(productIDs, p.ID)
+	}
+	images, err := s.accountRepo.ListProductImages(ctx, productIDs)
+	if err != nil {
+		return fmt.Errorf("查询商品图片失败: %w", err)
+	}
+	views, err := s.accountRepo.ListViewHistory(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("查询浏览记录失败: %w", err)
+	}
+	logins, err := s.sessionRepo.ListLoginRecords(ctx, userID, exportLoginHistoryLimit)
+	if err != nil {
+		return fmt.Errorf("查询登录记录失败: %w", err)
+	}
+
+	var files []exportFile
+	if source, ok := util.LocalUploadPath(user.AvatarUrl); ok {
+		files = append(files, exportFile{name: "images/avatar" + filepath.Ext(source), source: source})
+	}
+
+	imagesByProduct := make(map[int64][]ExportImage, len(products))
+	for _, img := range images {
+		item := ExportImage{URL: img.URL, IsPrimary: img.IsPrimary, SortOrder: img.SortOrder}
+		if source, ok := util.LocalUploadPath(img.URL); ok {
+			item.File = path.Join("images", fmt.Sprintf("product-%d", img.ProductID), fmt.Sprintf("%d%s", img.ID, filepath.Ext(source)))
+			files = append(files, exportFile{name: item.File, source: source})
+		}
+		imagesByProduct[img.ProductID] = append(imagesByProduct[img.ProductID], item)
+	}
+	exported := make([]ExportProduct, 0, len(products))
+	for _, p := range products {
+		item := ExportProduct{Product: p, Attributes: p.AttributeMap(), Images: imagesByProduct[p.ID]}
+		if item.Images == nil {
+			item.Images = []ExportImage{}
+		}
+		exported = append(exported, item)
+	}
+	if views == nil {
+		views = []repository.ViewHistoryRecord{}
+	}
+	if logins == nil {
+		logins = []model.LoginRecord{}
+	}
+
+	documents := []struct {
+		name  string
+		value interface{}
+	}{
+		{"profile.json", user},
+		{"products.json", exported},
+		{"views.json", views},
+		{"login_history.json", logins},
+	}
+	jsonFiles := make([]exportFile, 0, len(documents))
+	for _, doc := range documents {
+		data, err := json.MarshalIndent(doc.value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("生成 %s 失败: %w", doc.name, err)
+		}
+		jsonFiles = append(jsonFiles, exportFile{name: doc.name, data: data})
+	}
+
+	return writeZip(w, append(jsonFiles, files...))
+}
+
+// writeZip 写入压缩包，本地图片文件缺失时跳过
+func writeZip(w io.Writer, files []exportFile) error {
+	zw := zip.NewWriter(w)
+	modified := time.Now()
+	for _, file := range files {
+		if file.source != "" {
+			if err := addLocalFile(zw, file.name, file.source, modified); err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					log.Printf("导出个人数据: 图片文件不存在，已跳过 %s", file.source)
+					continue
+				}
+				return err
+			}
+			continue
+		}
+		entry, err := zw.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return err
+		}
+		if _, err := entry.Write(file.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// addLocalFile 将本地文件写入压缩包（图片已压缩，直接存储）
+func addLocalFile(zw *zip.Writer, name, source string, modified time.Time) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	entry, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: modified})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, f)
+	return err
+}
+
+// Delete 注销账号
+func (s *service) Delete(ctx context.Context, userID int64, password string) (*DeleteResult, error) {
+	if strings.TrimSpace(password) == "" {
+		return nil, ErrPasswordRequired
+	}
+	user, err := s.getActiveUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsAdmin || user.IsSuperAdmin {
+		return nil, ErrAdminAccount
+	}
+	if err := auth.ComparePassword(user.Password, password); err != nil {
+		return nil, ErrIncorrectPassword
+	}
+
+	delisted, err := s.accountRepo.Anonymize(ctx, userID, deletedPasswordHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountDeleted
+		}
+		return nil, fmt.Errorf("注销账号失败: %w", err)
+	}
+
+	// 头像不再被引用，删除本地文件；商品图片随商品记录保留
+	if source, ok := util.LocalUploadPath(user.AvatarUrl); ok {
+		if err := util.DeleteFile(source); err != nil {
+			log.Printf("注销账号: 删除头像文件失败 user=%d: %v", userID, err)
+		}
+	}
+	return &DeleteResult{DelistedProducts: delisted}, nil
+}
+
+// getActiveUser 获取未注销的用户
+func (s *service) getActiveUser(ctx context.Context, userID int64) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+	if user.DeletedAt != nil {
+		return nil, ErrAccountDeleted
+	}
+	return user, nil
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// AuditLogQuery 审计日志查询条件
+type AuditLogQuery struct {
+	AdminID    int64
+	Action     string
+	TargetType string
+	TargetID   int64
+	Page       int
+	PageSize   int
+}
+
+// AuditLogListResponse 审计日志列表响应
+type AuditLogListResponse struct {
+	Total int64                    `json:"total"`
+	Logs  []model.AdminAuditLogDTO `json:"logs"`
+}
+
+// ListAuditLogs 分页查询审计日志，按时间倒序
+// 仅返回当前学校管理员的操作记录
+func (s *AdminService) ListAuditLogs(ctx context.Context, q AuditLogQuery) (*AuditLogListResponse, error) {
+	query := s.db.WithContext(ctx).Model(&model.AdminAuditLog{}).
+		Where("admin_id IN (SELECT id FROM users WHERE school_id = ?)", tenant.SchoolID(ctx))
+	if q.AdminID > 0 {
+		query = query.Where("admin_id = ?", q.AdminID)
+	}
+	if q.Action != "" {
+		query = query.Where("action = ?", q.Action)
+	}
+	if q.TargetType != "" {
+		query = query.Where("target_type = ?", q.TargetType)
+	}
+	if q.TargetID > 0 {
+		query = query.Where("target_id = ?", q.TargetID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var logs []model.AdminAuditLog
+	offset := (q.Page - 1) * q.PageSize
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(q.PageSize).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	dtos := make([]model.AdminAuditLogDTO, 0, len(logs))
+	for i := range logs {
+		dtos = append(dtos, logs[i].ToDTO())
+	}
+	return &AuditLogListResponse{Total: total, Logs: dtos}, nil
+}
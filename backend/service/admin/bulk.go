@@ -0,0 +1,351 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// 批量操作类型
+const (
+	BulkActionDelist       = "delist"
+	BulkActionRecategorize = "recategorize"
+	BulkActionAddTags      = "add_tags"
+	BulkActionRemoveTags   = "remove_tags"
+	BulkActionDelete       = "delete"
+)
+
+const (
+	// BulkMaxItems 单次批量操作允许处理的最大商品数
+	BulkMaxItems = 1000
+	// bulkChunkSize 每个事务处理的商品数
+	bulkChunkSize = 100
+)
+
+// BulkProductFilter 批量操作的过滤条件，与商品列表接口一致
+type BulkProductFilter struct {
+	Status   string `json:"status"`
+	SellerID int64  `json:"sellerId"`
+	Keyword  string `json:"keyword"`
+}
+
+// BulkProductActionRequest 批量商品操作请求
+// ProductIDs 与 Filter 二选一
+type BulkProductActionRequest struct {
+	Action     string             `json:"action"`
+	ProductIDs []int64            `json:"productIds"`
+	Filter     *BulkProductFilter `json:"filter"`
+	CategoryID int64              `json:"categoryId"` // recategorize 时必填
+	TagIDs     []int64            `json:"tagIds"`     // add_tags/remove_tags 时必填
+	Reason     string             `json:"reason"`     // 操作原因，写入审计日志
+}
+
+// BulkItemResult 单个商品的处理结果
+type BulkItemResult struct {
+	ProductID int64  `json:"productId"`
+	Success   bool   `json:"success"`
+	Changed   bool   `json:"changed"` // 成功但无实际变更时为false（如已下架的商品再次下架）
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkProductActionResponse 批量操作结果
+type BulkProductActionResponse struct {
+	Action    string           `json:"action"`
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// bulkProductRow 批量处理时锁定读取的商品字段
+type bulkProductRow struct {
+	ID         int64
+	Status     string
+	CategoryID int64
+	SellerID   int64
+	Title      string
+}
+
+// BulkProductAction 对一组商品执行批量操作
+//
+// 功能说明：
+//   - 目标商品可以是ID列表，也可以是与列表接口相同的过滤条件
+//   - 按 bulkChunkSize 分块，每块一个事务；块内每个商品使用保存点隔离，
+//     单个商品失败不影响同块其他商品
+//   - 每个发生变更的商品写入一条审计日志，与变更在同一事务内提交
+func (s *AdminService) BulkProductAction(ctx context.Context, adminID int64, req BulkProductActionRequest) (*BulkProductActionResponse, error) {
+	if err := s.validateBulkRequest(ctx, &req); err != nil {
+		return nil, err
+	}
+
+	ids, err := s.resolveBulkTargets(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkProductActionResponse{
+		Action:  req.Action,
+		Total:   len(ids),
+		Results: make([]BulkItemResult, 0, len(ids)),
+	}
+
+	for start := 0; start < len(ids); start += bulkChunkSize {
+		end := start + bulkChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk := ids[start:end]
+
+		chunkResults := make([]BulkItemResult, 0, len(chunk))
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for i, id := range chunk {
+				savepoint := fmt.Sprintf("bulk_item_%d", i)
+				if err := tx.SavePoint(savepoint).Error; err != nil {
+					return err
+				}
+				changed, itemErr := s.applyBulkItem(tx, adminID, id, req)
+				if itemErr != nil {
+					if err := tx.RollbackTo(savepoint).Error; err != nil {
+						return err
+					}
+					chunkResults = append(chunkResults, BulkItemResult{ProductID: id, Error: itemErr.Error()})
+					continue
+				}
+				chunkResults = append(chunkResults, BulkItemResult{ProductID: id, Success: true, Changed: changed})
+			}
+			return nil
+		})
+		if err != nil {
+			// 整块提交失败，块内所有商品均视为失败
+			chunkResults = chunkResults[:0]
+			for _, id := range chunk {
+				chunkResults = append(chunkResults, BulkItemResult{ProductID: id, Error: "事务提交失败: " + err.Error()})
+			}
+		}
+
+		for _, r := range chunkResults {
+			if r.Success {
+				result.Succeeded++
+				if r.Changed {
+					s.invalidateProductDetail(ctx, r.ProductID)
+				}
+			} else {
+				result.Failed++
+			}
+		}
+		result.Results = append(result.Results, chunkResults...)
+	}
+
+	return result, nil
+}
+
+// validateBulkRequest 校验批量操作参数
+func (s *AdminService) validateBulkRequest(ctx context.Context, req *BulkProductActionRequest) error {
+	hasIDs := len(req.ProductIDs) > 0
+	hasFilter := req.Filter != nil
+	if hasIDs == hasFilter {
+		return newParamError("productIds与filter必须且只能提供一个")
+	}
+	if hasFilter && req.Filter.Status == "" && req.Filter.SellerID <= 0 && req.Filter.Keyword == "" {
+		// 防止误操作全部商品
+		return newParamError("过滤条件不能为空")
+	}
+	if len(req.ProductIDs) > BulkMaxItems {
+		return newParamError(fmt.Sprintf("单次最多操作%d个商品", BulkMaxItems))
+	}
+
+	switch req.Action {
+	case BulkActionDelist, BulkActionDelete:
+	case BulkActionRecategorize:
+		if req.CategoryID <= 0 {
+			return newParamError("请指定目标分类")
+		}
+		var count int64
+		if err := s.db.WithContext(ctx).Model(&model.Category{}).
+			Where("id = ? AND school_id = ?", req.CategoryID, tenant.SchoolID(ctx)).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return newParamError("目标分类不存在")
+		}
+	case BulkActionAddTags, BulkActionRemoveTags:
+		req.TagIDs = uniqueInt64s(req.TagIDs)
+		if len(req.TagIDs) == 0 {
+			return newParamError("请指定标签")
+		}
+		if req.Action == BulkActionAddTags {
+			var count int64
+			// 只允许添加已审核通过的标签，待审核/已拒绝的用户提议标签不能借批量操作上架
+			if err := s.db.WithContext(ctx).Model(&model.Tag{}).
+				Where("id IN ? AND school_id = ? AND status = ?", req.TagIDs, tenant.SchoolID(ctx), model.TagStatusApproved).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count != int64(len(req.TagIDs)) {
+				return newParamError("部分标签不存在或未审核通过")
+			}
+		}
+	default:
+		return newParamError("不支持的批量操作: " + req.Action)
+	}
+	return nil
+}
+
+// resolveBulkTargets 解析目标商品ID列表
+func (s *AdminService) resolveBulkTargets(ctx context.Context, req BulkProductActionRequest) ([]int64, error) {
+	if len(req.ProductIDs) > 0 {
+		return uniqueInt64s(req.ProductIDs), nil
+	}
+
+	whereClause, args := buildAdminProductFilter(tenant.SchoolID(ctx), req.Filter.Status, req.Filter.SellerID, req.Filter.Keyword)
+	args = append(args, BulkMaxItems+1)
+
+	ids := make([]int64, 0)
+	if err := s.db.WithContext(ctx).
+		Raw("SELECT p.id FROM products p"+whereClause+" ORDER BY p.id LIMIT ?", args...).
+		Scan(&ids).Error; err != nil {
+		return nil, fmt.Errorf("查询目标商品失败: %w", err)
+	}
+	if len(ids) > BulkMaxItems {
+		return nil, newParamError(fmt.Sprintf("匹配的商品超过%d个，请缩小过滤范围", BulkMaxItems))
+	}
+	return ids, nil
+}
+
+// applyBulkItem 在事务内对单个商品执行操作，返回是否产生了变更
+// 其他学校的商品视为不存在；修改分类或标签时同时写入商品修订记录
+func (s *AdminService) applyBulkItem(tx *gorm.DB, adminID, productID int64, req BulkProductActionRequest) (bool, error) {
+	var row bulkProductRow
+	err := tx.Model(&model.Product{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id, status, category_id, seller_id, title").
+		Where("id = ? AND school_id = ?", productID, tenant.SchoolID(tx.Statement.Context)).
+		Take(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, fmt.Errorf("商品不存在")
+		}
+		return false, err
+	}
+
+	detail := map[string]interface{}{"title": row.Title, "sellerId": row.SellerID}
+	if req.Reason != "" {
+		detail["reason"] = req.Reason
+	}
+
+	// 修改商品内容的操作需记录修订，编辑前的快照作为历史商品的基线版本
+	var before *model.ProductRevision
+	switch req.Action {
+	case BulkActionRecategorize, BulkActionAddTags, BulkActionRemoveTags:
+		if before, err = s.snapshotBeforeEdit(tx, productID); err != nil {
+			return false, err
+		}
+	}
+
+	switch req.Action {
+	case BulkActionDelist:
+		switch row.Status {
+		case "Delisted":
+			return false, nil
+		case "Sold":
+			return false, fmt.Errorf("已售商品不可下架")
+		}
+		if err := tx.Model(&model.Product{}).Where("id = ?", productID).Update("status", "Delisted").Error; err != nil {
+			return false, err
+		}
+		detail["fromStatus"] = row.Status
+		detail["toStatus"] = "Delisted"
+
+	case BulkActionRecategorize:
+		if row.CategoryID == req.CategoryID {
+			return false, nil
+		}
+		if err := tx.Model(&model.Product{}).Where("id = ?", productID).Update("category_id", req.CategoryID).Error; err != nil {
+			return false, err
+		}
+		detail["fromCategoryId"] = row.CategoryID
+		detail["toCategoryId"] = req.CategoryID
+
+	case BulkActionAddTags:
+		result := tx.Exec(`INSERT INTO product_tags (product_id, tag_id)
+			SELECT ?, t.id FROM tags t WHERE t.id IN ? AND t.status = ?
+			ON CONFLICT DO NOTHING`, productID, req.TagIDs, model.TagStatusApproved)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return false, nil
+		}
+		detail["tagIds"] = req.TagIDs
+
+	case BulkActionRemoveTags:
+		result := tx.Exec("DELETE FROM product_tags WHERE product_id = ? AND tag_id IN ?", productID, req.TagIDs)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return false, nil
+		}
+		detail["tagIds"] = req.TagIDs
+
+	case BulkActionDelete:
+		// 与下架一致，已售商品需保留交易记录，不可删除
+		if row.Status == "Sold" {
+			return false, fmt.Errorf("已售商品不可删除")
+		}
+		// 图片、标签关联、修订记录、浏览记录均通过外键级联删除
+		if err := tx.Exec("DELETE FROM products WHERE id = ?", productID).Error; err != nil {
+			return false, err
+		}
+		detail["status"] = row.Status
+		detail["categoryId"] = row.CategoryID
+	}
+
+	switch req.Action {
+	case BulkActionRecategorize, BulkActionAddTags, BulkActionRemoveTags:
+		if err := s.recordAdminRevision(tx, adminID, productID, before); err != nil {
+			return false, err
+		}
+	}
+
+	auditLog := model.NewAdminAuditLog(adminID, "product."+req.Action, model.AuditTargetProduct, productID, detail)
+	if err := tx.Create(auditLog).Error; err != nil {
+		return false, fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return true, nil
+}
+
+// invalidateProductDetail 清除商品详情缓存（键格式与商品服务一致）
+func (s *AdminService) invalidateProductDetail(ctx context.Context, productID int64) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Delete(ctx, fmt.Sprintf("product:detail:%d:%d", tenant.SchoolID(ctx), productID)); err != nil {
+		log.Printf("warn: invalidate product detail cache %d failed: %v", productID, err)
+	}
+}
+
+// uniqueInt64s 去重并去除非正数，保持原有顺序
+func uniqueInt64s(values []int64) []int64 {
+	seen := make(map[int64]struct{}, len(values))
+	result := make([]int64, 0, len(values))
+	for _, v := range values {
+		if v <= 0 {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,215 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
+)
+
+const (
+	// dashboardStatsCacheKey 仪表盘汇总数据缓存键，参数为学校ID
+	dashboardStatsCacheKey = "admin:dashboard:stats:%d"
+	// dashboardStatsCacheTTL 仪表盘汇总数据缓存时间
+	dashboardStatsCacheTTL = 1 * time.Minute
+	// dashboardMetricsCacheTTL 仪表盘时间序列缓存时间
+	dashboardMetricsCacheTTL = 5 * time.Minute
+
+	// DashboardMaxRangeDays 时间序列允许查询的最大天数
+	DashboardMaxRangeDays = 366
+	// dashboardTopCategoryLimit 热门分类返回数量
+	dashboardTopCategoryLimit = 10
+	// dashboardDateLayout 日期格式
+	dashboardDateLayout = "2006-01-02"
+
+	// soldAtExpr 成交时间表达式，历史数据缺少 sold_at 时以 updated_at 近似
+	soldAtExpr = "COALESCE(p.sold_at, p.updated_at)"
+)
+
+// 时间序列名称，前端据此绑定图表
+const (
+	SeriesNewUsers        = "newUsers"
+	SeriesListingsCreated = "listingsCreated"
+	SeriesItemsSold       = "itemsSold"
+	SeriesGMV             = "gmv"
+)
+
+// DashboardSeries 单条时间序列，Data 与 DashboardMetrics.Labels 一一对应
+type DashboardSeries struct {
+	Name string    `json:"name"`
+	Data []float64 `json:"data"`
+}
+
+// CategoryMetric 分类维度统计
+type CategoryMetric struct {
+	CategoryID   int64   `json:"categoryId"`
+	CategoryName string  `json:"categoryName"`
+	Listings     int64   `json:"listings"` // 区间内新发布数
+	Sold         int64   `json:"sold"`     // 区间内成交数
+	GMV          float64 `json:"gmv"`      // 区间内成交额
+}
+
+// DashboardMetrics 仪表盘时间序列数据（图表可直接使用）
+type DashboardMetrics struct {
+	From                  string            `json:"from"`
+	To                    string            `json:"to"`
+	Labels                []string          `json:"labels"` // 按天的横轴标签，格式 YYYY-MM-DD
+	Series                []DashboardSeries `json:"series"`
+	TotalGMV              float64           `json:"totalGmv"`
+	MedianTimeToSellHours float64           `json:"medianTimeToSellHours"` // 区间内成交商品从发布到售出的中位时长（小时）
+	TopCategories         []CategoryMetric  `json:"topCategories"`
+}
+
+// dailyValue 按天聚合的查询结果
+type dailyValue struct {
+	Day   string
+	Value float64
+}
+
+// GetDashboardMetrics 获取指定日期区间（含首尾）的时间序列统计
+//
+// 功能说明：
+//   - 每日新增用户、新发布商品、成交商品数与成交额（GMV）
+//   - 区间内成交商品的发布到售出中位时长
+//   - 按成交数排序的热门分类
+//   - 结果按区间缓存，缺少数据的日期补0，便于直接绘制图表
+func (s *AdminService) GetDashboardMetrics(ctx context.Context, from, to time.Time) (*DashboardMetrics, error) {
+	from = truncateDay(from)
+	to = truncateDay(to)
+	if to.Before(from) {
+		return nil, fmt.Errorf("结束日期不能早于开始日期")
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > DashboardMaxRangeDays {
+		return nil, fmt.Errorf("日期区间不能超过%d天", DashboardMaxRangeDays)
+	}
+
+	schoolID := tenant.SchoolID(ctx)
+	cacheKey := fmt.Sprintf("admin:dashboard:metrics:%d:%s:%s", schoolID, from.Format(dashboardDateLayout), to.Format(dashboardDateLayout))
+	if s.cache != nil {
+		if cached, err := s.cache.Get(ctx, cacheKey); err == nil {
+			if metrics, ok := cached.(*DashboardMetrics); ok {
+				return metrics, nil
+			}
+		}
+	}
+
+	// 查询区间为 [from, to+1天)
+	end := to.AddDate(0, 0, 1)
+
+	labels := make([]string, 0, days)
+	for d := from; d.Before(end); d = d.AddDate(0, 0, 1) {
+		labels = append(labels, d.Format(dashboardDateLayout))
+	}
+
+	newUsers, err := s.queryDaily(ctx,
+		`SELECT to_char(created_at, 'YYYY-MM-DD') AS day, COUNT(*) AS value
+		FROM users WHERE school_id = ? AND created_at >= ? AND created_at < ? GROUP BY day`, schoolID, from, end)
+	if err != nil {
+		return nil, fmt.Errorf("统计新增用户失败: %w", err)
+	}
+
+	listings, err := s.queryDaily(ctx,
+		`SELECT to_char(created_at, 'YYYY-MM-DD') AS day, COUNT(*) AS value
+		FROM products WHERE school_id = ? AND created_at >= ? AND created_at < ? GROUP BY day`, schoolID, from, end)
+	if err != nil {
+		return nil, fmt.Errorf("统计新发布商品失败: %w", err)
+	}
+
+	soldWhere := ` FROM products p WHERE p.school_id = ? AND p.status = 'Sold' AND ` + soldAtExpr + ` >= ? AND ` + soldAtExpr + ` < ?`
+
+	sold, err := s.queryDaily(ctx,
+		`SELECT to_char(`+soldAtExpr+`, 'YYYY-MM-DD') AS day, COUNT(*) AS value`+soldWhere+` GROUP BY day`, schoolID, from, end)
+	if err != nil {
+		return nil, fmt.Errorf("统计成交商品失败: %w", err)
+	}
+
+	gmv, err := s.queryDaily(ctx,
+		`SELECT to_char(`+soldAtExpr+`, 'YYYY-MM-DD') AS day, COALESCE(SUM(p.price), 0) AS value`+soldWhere+` GROUP BY day`, schoolID, from, end)
+	if err != nil {
+		return nil, fmt.Errorf("统计成交额失败: %w", err)
+	}
+
+	var medianSeconds *float64
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (`+soldAtExpr+` - p.created_at)))`+soldWhere,
+		schoolID, from, end).Scan(&medianSeconds).Error; err != nil {
+		return nil, fmt.Errorf("统计成交时长失败: %w", err)
+	}
+
+	topCategories := make([]CategoryMetric, 0)
+	if err := s.db.WithContext(ctx).Raw(
+		`SELECT p.category_id, c.name AS category_name,
+			COUNT(*) FILTER (WHERE p.created_at >= ? AND p.created_at < ?) AS listings,
+			COUNT(*) FILTER (WHERE p.status = 'Sold' AND `+soldAtExpr+` >= ? AND `+soldAtExpr+` < ?) AS sold,
+			COALESCE(SUM(p.price) FILTER (WHERE p.status = 'Sold' AND `+soldAtExpr+` >= ? AND `+soldAtExpr+` < ?), 0) AS gmv
+		FROM products p
+		LEFT JOIN categories c ON p.category_id = c.id
+		WHERE p.school_id = ? AND ((p.created_at >= ? AND p.created_at < ?)
+			OR (p.status = 'Sold' AND `+soldAtExpr+` >= ? AND `+soldAtExpr+` < ?))
+		GROUP BY p.category_id, c.name
+		ORDER BY sold DESC, gmv DESC, listings DESC
+		LIMIT ?`,
+		from, end, from, end, from, end, schoolID, from, end, from, end, dashboardTopCategoryLimit).Scan(&topCategories).Error; err != nil {
+		return nil, fmt.Errorf("统计热门分类失败: %w", err)
+	}
+
+	gmvSeries := fillSeries(labels, gmv)
+	totalGMV := 0.0
+	for _, v := range gmvSeries {
+		totalGMV += v
+	}
+
+	metrics := &DashboardMetrics{
+		From:   from.Format(dashboardDateLayout),
+		To:     to.Format(dashboardDateLayout),
+		Labels: labels,
+		Series: []DashboardSeries{
+			{Name: SeriesNewUsers, Data: fillSeries(labels, newUsers)},
+			{Name: SeriesListingsCreated, Data: fillSeries(labels, listings)},
+			{Name: SeriesItemsSold, Data: fillSeries(labels, sold)},
+			{Name: SeriesGMV, Data: gmvSeries},
+		},
+		TotalGMV:      math.Round(totalGMV*100) / 100,
+		TopCategories: topCategories,
+	}
+	if medianSeconds != nil {
+		metrics.MedianTimeToSellHours = math.Round(*medianSeconds/3600*10) / 10
+	}
+
+	if s.cache != nil {
+		_ = s.cache.Set(ctx, cacheKey, metrics, dashboardMetricsCacheTTL)
+	}
+
+	return metrics, nil
+}
+
+// queryDaily 执行按天聚合查询
+func (s *AdminService) queryDaily(ctx context.Context, query string, args ...interface{}) ([]dailyValue, error) {
+	values := make([]dailyValue, 0)
+	if err := s.db.WithContext(ctx).Raw(query, args...).Scan(&values).Error; err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// fillSeries 将按天聚合结果对齐到横轴标签，缺失日期补0
+func fillSeries(labels []string, values []dailyValue) []float64 {
+	byDay := make(map[string]float64, len(values))
+	for _, v := range values {
+		byDay[v.Day] = v.Value
+	}
+	data := make([]float64, len(labels))
+	for i, label := range labels {
+		data[i] = math.Round(byDay[label]*100) / 100
+	}
+	return data
+}
+
+// truncateDay 截断到当天零点（本地时区）
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,24 @@
+package admin
+
+import "errors"
+
+// ParamError 参数校验错误，控制器据此返回参数错误码（1001）
+type ParamError struct {
+	Msg string
+}
+
+// Error 实现 error 接口
+func (e *ParamError) Error() string {
+	return e.Msg
+}
+
+// newParamError 创建参数校验错误
+func newParamError(msg string) error {
+	return &ParamError{Msg: msg}
+}
+
+// IsParamError 判断是否为参数校验错误
+func IsParamError(err error) bool {
+	var pe *ParamError
+	return errors.As(err, &pe)
+}
@@ -0,0 +1,135 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/export"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
+)
+
+// exportTimeLayout 导出文件中的时间格式
+const exportTimeLayout = "2006-01-02 15:04:05"
+
+// productExportHeader 商品导出表头
+var productExportHeader = []string{"商品ID", "标题", "价格", "状态", "卖家ID", "卖家账号", "卖家昵称", "分类ID", "分类名称", "发布时间", "更新时间"}
+
+// userExportHeader 用户导出表头（不包含密码哈希等敏感字段）
+var userExportHeader = []string{"用户ID", "账号", "昵称", "是否管理员", "注册时间", "更新时间"}
+
+// ExportProducts 按商品列表的过滤条件流式导出商品
+//
+// 功能说明：
+//   - 过滤条件与 ListProductsAdmin 一致（状态/卖家ID/关键词）
+//   - 使用数据库游标逐行读取并写出，不在内存中缓存完整结果集
+//   - 查询失败时尚未向 w 写入任何数据，调用方仍可返回普通错误响应
+func (s *AdminService) ExportProducts(ctx context.Context, status string, sellerId int64, keyword string, w export.RowWriter) error {
+	whereClause, args := buildAdminProductFilter(tenant.SchoolID(ctx), status, sellerId, keyword)
+	query := `SELECT
+		p.id, p.title, p.price, p.status, p.seller_id,
+		u.account, u.nickname,
+		p.category_id, c.name,
+		p.created_at, p.updated_at
+	FROM products p
+	LEFT JOIN users u ON p.seller_id = u.id
+	LEFT JOIN categories c ON p.category_id = c.id` + whereClause + " ORDER BY p.created_at DESC, p.id DESC"
+
+	rows, err := s.db.WithContext(ctx).Raw(query, args...).Rows()
+	if err != nil {
+		return fmt.Errorf("查询商品失败: %w", err)
+	}
+	defer rows.Close()
+
+	if err := w.WriteRow(productExportHeader); err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var (
+			id, sellerID, categoryID              int64
+			title, status                         string
+			price                                 float64
+			sellerAccount, sellerNick, categoryNm sql.NullString
+			createdAt, updatedAt                  time.Time
+		)
+		if err := rows.Scan(&id, &title, &price, &status, &sellerID,
+			&sellerAccount, &sellerNick, &categoryID, &categoryNm,
+			&createdAt, &updatedAt); err != nil {
+			return fmt.Errorf("读取商品数据失败: %w", err)
+		}
+
+		if err := w.WriteRow([]string{
+			strconv.FormatInt(id, 10),
+			title,
+			strconv.FormatFloat(price, 'f', 2, 64),
+			status,
+			strconv.FormatInt(sellerID, 10),
+			sellerAccount.String,
+			sellerNick.String,
+			strconv.FormatInt(categoryID, 10),
+			categoryNm.String,
+			createdAt.Format(exportTimeLayout),
+			updatedAt.Format(exportTimeLayout),
+		}); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("读取商品数据失败: %w", err)
+	}
+
+	return w.Close()
+}
+
+// ExportUsers 按用户列表的过滤条件流式导出用户
+// 仅选择公开字段，密码哈希不会被查询，更不会被写出
+func (s *AdminService) ExportUsers(ctx context.Context, keyword string, w export.RowWriter) error {
+	query := s.db.WithContext(ctx).Table("users").
+		Select("id, account, nickname, is_admin, created_at, updated_at")
+	query = applyAdminUserFilter(ctx, query, keyword)
+
+	rows, err := query.Order("id ASC").Rows()
+	if err != nil {
+		return fmt.Errorf("查询用户失败: %w", err)
+	}
+	defer rows.Close()
+
+	if err := w.WriteRow(userExportHeader); err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var (
+			id                   int64
+			account, nickname    string
+			isAdmin              bool
+			createdAt, updatedAt time.Time
+		)
+		if err := rows.Scan(&id, &account, &nickname, &isAdmin, &createdAt, &updatedAt); err != nil {
+			return fmt.Errorf("读取用户数据失败: %w", err)
+		}
+
+		adminFlag := "否"
+		if isAdmin {
+			adminFlag = "是"
+		}
+		if err := w.WriteRow([]string{
+			strconv.FormatInt(id, 10),
+			account,
+			nickname,
+			adminFlag,
+			createdAt.Format(exportTimeLayout),
+			updatedAt.Format(exportTimeLayout),
+		}); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("读取用户数据失败: %w", err)
+	}
+
+	return w.Close()
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cache"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/loginguard"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
 	"gorm.io/gorm"
 )
 
@@ -16,13 +20,24 @@ type UserListResponse struct {
 
 // AdminService 管理后台服务接口
 type AdminService struct {
-	db *gorm.DB
+	db           *gorm.DB
+	productRepo  repository.ProductRepository
+	revisionRepo repository.ProductRevisionRepository
+	cache        *cache.MemoryCache
+	loginGuard   *loginguard.Guard
+	sessionRepo  repository.SessionRepository
 }
 
 // NewAdminService 创建管理后台服务实例
-func NewAdminService(db *gorm.DB) *AdminService {
+// loginGuard 用于查询与解除账号登录锁定，sessionRepo 用于查询登录记录，均可为 nil
+func NewAdminService(db *gorm.DB, productRepo repository.ProductRepository, revisionRepo repository.ProductRevisionRepository, memCache *cache.MemoryCache, loginGuard *loginguard.Guard, sessionRepo repository.SessionRepository) *AdminService {
 	return &AdminService{
-		db: db,
+		db:           db,
+		productRepo:  productRepo,
+		revisionRepo: revisionRepo,
+		cache:        memCache,
+		loginGuard:   loginGuard,
+		sessionRepo:  sessionRepo,
 	}
 }
 
@@ -56,10 +71,8 @@ func (s *AdminService) ListUsers(ctx context.Context, keyword string, page, page
 	// 构建查询
 	query := s.db.WithContext(ctx).Model(&model.User{})
 
-	// 如果有关键词，添加模糊搜索条件
-	if keyword != "" {
-		query = query.Where("account LIKE ? OR nickname LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
-	}
+	// 限定当前学校，有关键词时添加模糊搜索条件
+	query = applyAdminUserFilter(ctx, query, keyword)
 
 	// 计算总数
 	if err := query.Count(&total).Error; err != nil {
@@ -82,7 +95,7 @@ func (s *AdminService) ListUsers(ctx context.Context, keyword string, page, page
 //
 // 功能说明：
 //   - 统计系统中的用户总数、商品总数、在售商品数、已售商品数
-//   - 使用COUNT聚合查询提高性能
+//   - 使用COUNT聚合查询提高性能，结果短时间缓存
 //
 // 参数：
 //   - ctx: 上下文，用于控制查询超时
@@ -91,28 +104,43 @@ func (s *AdminService) ListUsers(ctx context.Context, keyword string, page, page
 //   - DashboardStats: 包含统计数据的结构体
 //   - error: 错误信息，查询失败时返回
 func (s *AdminService) GetDashboardStats(ctx context.Context) (*DashboardStats, error) {
+	// 优先读取缓存（按学校区分）
+	schoolID := tenant.SchoolID(ctx)
+	cacheKey := fmt.Sprintf(dashboardStatsCacheKey, schoolID)
+	if s.cache != nil {
+		if cached, err := s.cache.Get(ctx, cacheKey); err == nil {
+			if stats, ok := cached.(*DashboardStats); ok {
+				return stats, nil
+			}
+		}
+	}
+
 	var stats DashboardStats
 
 	// 1. 统计用户总数
-	if err := s.db.WithContext(ctx).Model(&model.User{}).Count(&stats.UserCount).Error; err != nil {
+	if err := s.db.WithContext(ctx).Model(&model.User{}).Where("school_id = ?", schoolID).Count(&stats.UserCount).Error; err != nil {
 		return nil, err
 	}
 
 	// 2. 统计商品总数
-	if err := s.db.WithContext(ctx).Model(&model.Product{}).Count(&stats.ProductCount).Error; err != nil {
+	if err := s.db.WithContext(ctx).Model(&model.Product{}).Where("school_id = ?", schoolID).Count(&stats.ProductCount).Error; err != nil {
 		return nil, err
 	}
 
-	// 3. 统计在售商品数
-	if err := s.db.WithContext(ctx).Model(&model.Product{}).Where("status = ?", "for_sale").Count(&stats.ForSaleCount).Error; err != nil {
+	// 3. 统计在售商品数（状态值需与 product_status 枚举一致）
+	if err := s.db.WithContext(ctx).Model(&model.Product{}).Where("school_id = ? AND status = ?", schoolID, "ForSale").Count(&stats.ForSaleCount).Error; err != nil {
 		return nil, err
 	}
 
 	// 4. 统计已售商品数
-	if err := s.db.WithContext(ctx).Model(&model.Product{}).Where("status = ?", "sold").Count(&stats.SoldCount).Error; err != nil {
+	if err := s.db.WithContext(ctx).Model(&model.Product{}).Where("school_id = ? AND status = ?", schoolID, "Sold").Count(&stats.SoldCount).Error; err != nil {
 		return nil, err
 	}
 
+	if s.cache != nil {
+		_ = s.cache.Set(ctx, cacheKey, &stats, dashboardStatsCacheTTL)
+	}
+
 	return &stats, nil
 }
 
@@ -153,32 +181,8 @@ func (s *AdminService) ListProductsAdmin(ctx context.Context, status string, sel
 	LEFT JOIN categories c ON p.category_id = c.id`
 
 	countQuery := `SELECT COUNT(*) FROM products p`
-	whereClause := ""
-	args := []interface{}{}
-
 	// 添加过滤条件
-	if status != "" || sellerId > 0 || keyword != "" {
-		whereClause = " WHERE"
-		if status != "" {
-			whereClause += " p.status = ?"
-			args = append(args, status)
-		}
-		if sellerId > 0 {
-			if status != "" {
-				whereClause += " AND"
-			}
-			whereClause += " p.seller_id = ?"
-			args = append(args, sellerId)
-		}
-		if keyword != "" {
-			if status != "" || sellerId > 0 {
-				whereClause += " AND"
-			}
-			whereClause += " (p.title LIKE ? OR p.description LIKE ?)"
-			likeKeyword := "%" + keyword + "%"
-			args = append(args, likeKeyword, likeKeyword)
-		}
-	}
+	whereClause, args := buildAdminProductFilter(tenant.SchoolID(ctx), status, sellerId, keyword)
 
 	// 查询总数
 	var total int64
@@ -201,6 +205,38 @@ func (s *AdminService) ListProductsAdmin(ctx context.Context, status string, sel
 	}, nil
 }
 
+// buildAdminProductFilter 构建管理后台商品列表的过滤条件（列表、导出与批量操作共用）
+// 返回以 " WHERE" 开头的条件子句及对应参数，表别名为 p，始终限定在 schoolID 对应的学校内
+func buildAdminProductFilter(schoolID int64, status string, sellerId int64, keyword string) (string, []interface{}) {
+	whereClause := " WHERE p.school_id = ?"
+	args := []interface{}{schoolID}
+
+	if status != "" {
+		whereClause += " AND p.status = ?"
+		args = append(args, status)
+	}
+	if sellerId > 0 {
+		whereClause += " AND p.seller_id = ?"
+		args = append(args, sellerId)
+	}
+	if keyword != "" {
+		whereClause += " AND (p.title LIKE ? OR p.description LIKE ?)"
+		likeKeyword := "%" + keyword + "%"
+		args = append(args, likeKeyword, likeKeyword)
+	}
+
+	return whereClause, args
+}
+
+// applyAdminUserFilter 应用管理后台用户列表的过滤条件（列表与导出共用），限定当前学校
+func applyAdminUserFilter(ctx context.Context, query *gorm.DB, keyword string) *gorm.DB {
+	query = query.Where("school_id = ?", tenant.SchoolID(ctx))
+	if keyword != "" {
+		query = query.Where("account LIKE ? OR nickname LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	return query
+}
+
 // UpdateProductRequest 管理后台更新商品请求结构
 type UpdateProductRequest struct {
 	Title       string  `json:"title"`
@@ -214,7 +250,7 @@ type UpdateProductRequest struct {
 
 // UpdateProductAsAdmin 管理员更新商品，禁止修改status字段
 // 如果请求体携带status或试图改变状态，返回3004错误
-func (s *AdminService) UpdateProductAsAdmin(ctx context.Context, productID int64, req UpdateProductRequest) error {
+func (s *AdminService) UpdateProductAsAdmin(ctx context.Context, adminID, productID int64, req UpdateProductRequest) error {
 	// 检查请求是否携带status字段
 	if req.Status != nil {
 		return fmt.Errorf("3004:禁止修改商品状态字段")
@@ -229,13 +265,34 @@ func (s *AdminService) UpdateProductAsAdmin(ctx context.Context, productID int64
 
 	// 检查商品是否存在
 	var existingProduct model.Product
-	query := `SELECT id, status FROM products WHERE id = ?`
-	if err := tx.WithContext(ctx).Raw(query, productID).Scan(&existingProduct).Error; err != nil {
+	query := `SELECT id, status FROM products WHERE id = ? AND school_id = ?`
+	if err := tx.WithContext(ctx).Raw(query, productID, tenant.SchoolID(ctx)).Scan(&existingProduct).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return fmt.Errorf("商品不存在")
 		}
 		return fmt.Errorf("查询商品信息失败: %w", err)
 	}
+	if existingProduct.ID == 0 {
+		return fmt.Errorf("商品不存在")
+	}
+
+	// 编辑前的快照，用于修订历史
+	before, err := s.snapshotBeforeEdit(tx, productID)
+	if err != nil {
+		return err
+	}
+
+	// 分类与标签必须属于当前学校
+	var foreign int64
+	if err := tx.WithContext(ctx).Raw(`SELECT
+		(SELECT COUNT(*) FROM categories WHERE id = ? AND school_id <> ?) +
+		(SELECT COUNT(*) FROM tags WHERE id IN (?) AND school_id <> ?)`,
+		req.CategoryID, tenant.SchoolID(ctx), append([]int64{0}, req.TagIDs...), tenant.SchoolID(ctx)).Scan(&foreign).Error; err != nil {
+		return fmt.Errorf("校验分类与标签失败: %w", err)
+	}
+	if foreign > 0 {
+		return fmt.Errorf("分类或标签不属于当前学校")
+	}
 
 	// 更新商品基本信息（排除status字段）
 	updateQuery := `UPDATE products 
@@ -243,7 +300,6 @@ func (s *AdminService) UpdateProductAsAdmin(ctx context.Context, productID int64
 		    condition_id = ?, category_id = ?, updated_at = CURRENT_TIMESTAMP 
 		WHERE id = ?`
 
-	var err error
 	if err = tx.WithContext(ctx).Exec(updateQuery,
 		req.Title, req.Description, req.Price,
 		req.ConditionID, req.CategoryID, productID).Error; err != nil {
@@ -266,10 +322,42 @@ func (s *AdminService) UpdateProductAsAdmin(ctx context.Context, productID int64
 		}
 	}
 
+	// 修订记录与商品修改在同一事务内提交
+	if err := s.recordAdminRevision(tx, adminID, productID, before); err != nil {
+		return err
+	}
+
 	// 提交事务
-	if err := tx.Commit(); err != nil {
+	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("提交事务失败: %v", err)
 	}
 
 	return nil
 }
+
+// snapshotBeforeEdit 在事务内读取管理员编辑前的商品快照，作为历史商品的基线版本
+func (s *AdminService) snapshotBeforeEdit(tx *gorm.DB, productID int64) (*model.ProductRevision, error) {
+	if s.revisionRepo == nil {
+		return nil, nil
+	}
+	before, err := s.revisionRepo.Snapshot(tx, productID, 0, model.RevisionReasonInitial)
+	if err != nil {
+		return nil, fmt.Errorf("读取商品快照失败: %w", err)
+	}
+	return before, nil
+}
+
+// recordAdminRevision 在事务内记录管理员编辑产生的商品修订，写入失败时由调用方回滚整个编辑
+func (s *AdminService) recordAdminRevision(tx *gorm.DB, adminID, productID int64, before *model.ProductRevision) error {
+	if s.revisionRepo == nil {
+		return nil
+	}
+	after, err := s.revisionRepo.Snapshot(tx, productID, adminID, model.RevisionReasonAdminEdit)
+	if err != nil {
+		return fmt.Errorf("记录商品修订失败: %w", err)
+	}
+	if err := s.revisionRepo.RecordInTx(tx, before, after); err != nil {
+		return fmt.Errorf("记录商品修订失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/auth"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// 用户管理错误
+var (
+	ErrUserNotFound = errors.New("用户不存在")
+	ErrLastAdmin    = errors.New("不能撤销最后一位管理员的权限")
+)
+
+// 用户管理审计操作
+const (
+	AuditActionGrantAdmin    = "user.grant_admin"
+	AuditActionRevokeAdmin   = "user.revoke_admin"
+	AuditActionResetPassword = "user.reset_password"
+	AuditActionClearWechat   = "user.clear_wechat"
+	AuditActionUnlockLogin   = "user.unlock_login"
+)
+
+const (
+	// tempPasswordLength 强制重置时生成的临时密码长度
+	tempPasswordLength = 12
+	// tempPasswordAlphabet 临时密码字符集（去除易混淆字符）
+	tempPasswordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnpqrstuvwxyz23456789"
+	// userDetailRecentProducts 用户详情中展示的最近商品数
+	userDetailRecentProducts = 10
+	// userDetailRecentLogins 用户详情中展示的最近登录记录数
+	userDetailRecentLogins = 10
+)
+
+// AdminUserDTO 管理后台用户信息（不包含密码哈希）
+type AdminUserDTO struct {
+	ID                int64      `json:"id"`
+	Account           string     `json:"account"`
+	Nickname          string     `json:"nickname"`
+	AvatarURL         string     `json:"avatarUrl"`
+	WechatID          string     `json:"wechatId"`
+	IsAdmin           bool       `json:"isAdmin"`
+	MustResetPassword bool       `json:"mustResetPassword"`
+	LastLoginAt       *time.Time `json:"lastLoginAt"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+// UserProductSummary 用户商品简要信息
+type UserProductSummary struct {
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	Price     float64   `json:"price"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// AdminUserDetail 管理后台用户详情
+type AdminUserDetail struct {
+	User             AdminUserDTO         `json:"user"`
+	ProductsByStatus map[string]int64     `json:"productsByStatus"` // 各状态商品数，键为 ForSale/Delisted/Sold
+	RecentProducts   []UserProductSummary `json:"recentProducts"`
+	LoginLock        LoginLockStatus      `json:"loginLock"`
+	RecentLogins     []model.LoginRecord  `json:"recentLogins"` // 最近的登录尝试（含失败），按时间倒序
+}
+
+// LoginLockStatus 账号登录失败计数与锁定状态（按账号统计，不含IP维度）
+type LoginLockStatus struct {
+	Failures    int        `json:"failures"`    // 当前窗口内连续失败次数
+	LockedUntil *time.Time `json:"lockedUntil"` // 锁定截止时间，未锁定时为 null
+}
+
+// ResetPasswordResult 强制重置密码结果
+type ResetPasswordResult struct {
+	TemporaryPassword string `json:"temporaryPassword"` // 仅返回一次，由管理员线下转交用户
+}
+
+// GetUserDetail 获取用户详情：基本信息、各状态商品数与最近发布的商品
+func (s *AdminService) GetUserDetail(ctx context.Context, userID int64) (*AdminUserDetail, error) {
+	var user model.User
+	if err := s.db.WithContext(ctx).Where("school_id = ?", tenant.SchoolID(ctx)).First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	// 各状态商品数，未出现的状态补0
+	detail := &AdminUserDetail{
+		User: toAdminUserDTO(&user),
+		ProductsByStatus: map[string]int64{
+			"ForSale":  0,
+			"Delisted": 0,
+			"Sold":     0,
+		},
+		RecentProducts: make([]UserProductSummary, 0),
+		RecentLogins:   make([]model.LoginRecord, 0),
+	}
+
+	var counts []struct {
+		Status string
+		Count  int64
+	}
+	if err := s.db.WithContext(ctx).Model(&model.Product{}).
+		Select("status, COUNT(*) AS count").
+		Where("seller_id = ?", userID).
+		Group("status").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("统计用户商品失败: %w", err)
+	}
+	for _, c := range counts {
+		detail.ProductsByStatus[c.Status] = c.Count
+	}
+
+	if err := s.db.WithContext(ctx).Model(&model.Product{}).
+		Select("id, title, price, status, created_at").
+		Where("seller_id = ?", userID).
+		Order("created_at DESC").
+		Limit(userDetailRecentProducts).
+		Scan(&detail.RecentProducts).Error; err != nil {
+		return nil, fmt.Errorf("查询用户商品失败: %w", err)
+	}
+
+	if state, ok := s.loginGuard.AccountState(ctx, user.Account); ok {
+		detail.LoginLock.Failures = state.Failures
+		if time.Now().Before(state.LockedUntil) {
+			lockedUntil := state.LockedUntil
+			detail.LoginLock.LockedUntil = &lockedUntil
+		}
+	}
+
+	if s.sessionRepo != nil {
+		logins, err := s.sessionRepo.ListLoginRecords(ctx, userID, userDetailRecentLogins)
+		if err != nil {
+			return nil, fmt.Errorf("查询登录记录失败: %w", err)
+		}
+		if logins != nil {
+			detail.RecentLogins = logins
+		}
+	}
+
+	return detail, nil
+}
+
+// SetUserAdmin 授予或撤销当前学校用户的管理员权限
+// 撤销时在事务内锁定本校全部管理员行再计数，避免并发撤销导致学校没有管理员
+func (s *AdminService) SetUserAdmin(ctx context.Context, operatorID, userID int64, isAdmin bool) (*AdminUserDTO, error) {
+	var user model.User
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 已注销账号不能再被授予管理员权限
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("school_id = ? AND deleted_at IS NULL", tenant.SchoolID(ctx)).
+			First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if user.IsAdmin == isAdmin {
+			return nil
+		}
+
+		action := AuditActionGrantAdmin
+		if !isAdmin {
+			action = AuditActionRevokeAdmin
+			var adminIDs []int64
+			if err := tx.Model(&model.User{}).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("school_id = ? AND is_admin = ?", user.SchoolID, true).
+				Pluck("id", &adminIDs).Error; err != nil {
+				return err
+			}
+			if len(adminIDs) <= 1 {
+				return ErrLastAdmin
+			}
+		}
+
+		if err := tx.Model(&model.User{}).Where("id = ?", userID).Update("is_admin", isAdmin).Error; err != nil {
+			return err
+		}
+		user.IsAdmin = isAdmin
+
+		return tx.Create(model.NewAdminAuditLog(operatorID, action, model.AuditTargetUser, userID,
+			map[string]interface{}{"account": user.Account})).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dto := toAdminUserDTO(&user)
+	return &dto, nil
+}
+
+// ForcePasswordReset 强制重置用户密码
+// 生成随机临时密码并标记用户需在登录后修改密码，临时密码只在本次响应中返回
+func (s *AdminService) ForcePasswordReset(ctx context.Context, operatorID, userID int64) (*ResetPasswordResult, error) {
+	tempPassword, err := generateTempPassword()
+	if err != nil {
+		return nil, fmt.Errorf("生成临时密码失败: %w", err)
+	}
+	hash, err := auth.HashPassword(tempPassword)
+	if err != nil {
+		return nil, fmt.Errorf("生成密码哈希失败: %w", err)
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 已注销账号视为不存在，避免重置密码后重新启用
+		result := tx.Model(&model.User{}).Where("id = ? AND school_id = ? AND deleted_at IS NULL", userID, tenant.SchoolID(ctx)).Updates(map[string]interface{}{
+			"password_hash":       hash,
+			"must_reset_password": true,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return tx.Create(model.NewAdminAuditLog(operatorID, AuditActionResetPassword, model.AuditTargetUser, userID, nil)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResetPasswordResult{TemporaryPassword: tempPassword}, nil
+}
+
+// ClearUserWechat 清空用户微信号（如微信号违规或泄露他人信息）
+func (s *AdminService) ClearUserWechat(ctx context.Context, operatorID, userID int64) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("school_id = ?", tenant.SchoolID(ctx)).
+			First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if user.WechatID == "" {
+			return nil
+		}
+
+		if err := tx.Model(&model.User{}).Where("id = ?", userID).Update("wechat_id", gorm.Expr("NULL")).Error; err != nil {
+			return err
+		}
+		// 审计日志不保留被清除的微信号本身
+		return tx.Create(model.NewAdminAuditLog(operatorID, AuditActionClearWechat, model.AuditTargetUser, userID,
+			map[string]interface{}{"account": user.Account})).Error
+	})
+}
+
+// toAdminUserDTO 转换为管理后台用户DTO
+func toAdminUserDTO(user *model.User) AdminUserDTO {
+	return AdminUserDTO{
+		ID:                user.ID,
+		Account:           user.Account,
+		Nickname:          user.Nickname,
+		AvatarURL:         user.AvatarUrl,
+		WechatID:          user.WechatID,
+		IsAdmin:           user.IsAdmin,
+		MustResetPassword: user.MustResetPassword,
+		LastLoginAt:       user.LastLoginAt,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
+	}
+}
+
+// generateTempPassword 使用加密安全的随机数生成临时密码
+func generateTempPassword() (string, error) {
+	buf := make([]byte, tempPasswordLength)
+	max := big.NewInt(int64(len(tempPasswordAlphabet)))
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = tempPasswordAlphabet[n.Int64()]
+	}
+	return string(buf), nil
+}
+
+// UnlockUserLogin 解除账号因连续登录失败导致的锁定并清零失败计数
+// 只清除账号维度的计数，来源 IP 的限制不受影响
+func (s *AdminService) UnlockUserLogin(ctx context.Context, operatorID, userID int64) error {
+	var user model.User
+	if err := s.db.WithContext(ctx).Where("school_id = ?", tenant.SchoolID(ctx)).First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if err := s.loginGuard.Unlock(ctx, user.Account); err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Create(model.NewAdminAuditLog(operatorID, AuditActionUnlockLogin, model.AuditTargetUser, userID,
+		map[string]interface{}{"account": user.Account})).Error
+}
@@ -0,0 +1,11 @@
+package book
+
+import "errors"
+
+// 错误定义
+var (
+	ErrBookNotFound      = errors.New("图书目录中未找到该ISBN")
+	ErrInvalidCSV        = errors.New("无效的CSV文件")
+	ErrInvalidCourseCode = errors.New("无效的课程代码")
+	ErrTooManyCourses    = errors.New("选修课程数量超过上限")
+)
@@ -0,0 +1,208 @@
+package book
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/util"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+const (
+	// ImportMaxRows 单次导入允许的最大数据行数
+	ImportMaxRows = 5000
+	// importMaxErrors 导入结果中最多返回的错误行数
+	importMaxErrors = 100
+)
+
+// importColumnAliases CSV表头别名（不区分大小写），映射到标准列名
+var importColumnAliases = map[string]string{
+	"isbn":      "isbn",
+	"title":     "title",
+	"书名":        "title",
+	"author":    "author",
+	"作者":        "author",
+	"edition":   "edition",
+	"版次":        "edition",
+	"publisher": "publisher",
+	"出版社":       "publisher",
+	"courses":   "courses",
+	"course":    "courses",
+	"课程":        "courses",
+	"课程代码":      "courses",
+}
+
+// courseSeparators 课程列中多个课程代码的分隔符
+var courseSeparators = strings.NewReplacer("|", ";", "、", ";", "；", ";")
+
+// ImportRowError 导入失败的行
+type ImportRowError struct {
+	Line  int    `json:"line"` // CSV中的行号（表头为第1行）
+	ISBN  string `json:"isbn"`
+	Error string `json:"error"`
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Total   int              `json:"total"`   // 数据行数
+	Created int              `json:"created"` // 新增图书数
+	Updated int              `json:"updated"` // 按ISBN更新的图书数
+	Failed  int              `json:"failed"`  // 校验失败被跳过的行数
+	Errors  []ImportRowError `json:"errors"`  // 失败明细，最多返回 importMaxErrors 条
+}
+
+// ImportCSV 从CSV导入图书目录
+//
+// 功能说明：
+//   - 表头必须包含 isbn 与 title 列，可选 author/edition/publisher/courses 列（支持中文表头）
+//   - courses 列中多个课程代码以分号或竖线分隔；包含该列时覆盖图书原有的课程关联
+//   - 按ISBN新增或更新，校验失败的行被跳过并在结果中列出，其余行在同一事务内写入
+func (s *BookService) ImportCSV(ctx context.Context, r io.Reader) (*ImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: 文件为空", ErrInvalidCSV)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if canonical, ok := importColumnAliases[name]; ok {
+			columns[canonical] = i
+		}
+	}
+	if _, ok := columns["isbn"]; !ok {
+		return nil, fmt.Errorf("%w: 缺少isbn列", ErrInvalidCSV)
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("%w: 缺少title列", ErrInvalidCSV)
+	}
+	_, hasCourses := columns["courses"]
+
+	result := &ImportResult{Errors: make([]ImportRowError, 0)}
+	addError := func(line int, isbn, msg string) {
+		result.Failed++
+		if len(result.Errors) < importMaxErrors {
+			result.Errors = append(result.Errors, ImportRowError{Line: line, ISBN: isbn, Error: msg})
+		}
+	}
+
+	items := make([]repository.BookImport, 0)
+	seen := make(map[string]int)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: 第%d行: %v", ErrInvalidCSV, line, err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		result.Total++
+		if result.Total > ImportMaxRows {
+			return nil, fmt.Errorf("%w: 单次最多导入%d行", ErrInvalidCSV, ImportMaxRows)
+		}
+
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		rawISBN := get("isbn")
+		item, err := parseImportRow(get, hasCourses)
+		if err != nil {
+			addError(line, rawISBN, err.Error())
+			continue
+		}
+		if prev, dup := seen[item.Book.ISBN]; dup {
+			addError(line, rawISBN, fmt.Sprintf("与第%d行的ISBN重复", prev))
+			continue
+		}
+		seen[item.Book.ISBN] = line
+		items = append(items, *item)
+	}
+
+	if len(items) == 0 {
+		return result, nil
+	}
+	created, updated, err := s.bookRepo.Import(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	result.Created = created
+	result.Updated = updated
+	return result, nil
+}
+
+// parseImportRow 校验并解析单行数据
+func parseImportRow(get func(string) string, hasCourses bool) (*repository.BookImport, error) {
+	isbn, err := util.NormalizeISBN(get("isbn"))
+	if err != nil {
+		return nil, err
+	}
+
+	book := model.Book{
+		ISBN:      isbn,
+		Title:     get("title"),
+		Author:    get("author"),
+		Edition:   get("edition"),
+		Publisher: get("publisher"),
+	}
+	if book.Title == "" {
+		return nil, fmt.Errorf("书名不能为空")
+	}
+	for _, field := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"书名", book.Title, 200},
+		{"作者", book.Author, 200},
+		{"版次", book.Edition, 50},
+		{"出版社", book.Publisher, 100},
+	} {
+		if len([]rune(field.value)) > field.max {
+			return nil, fmt.Errorf("%s不能超过%d个字符", field.name, field.max)
+		}
+	}
+
+	item := &repository.BookImport{Book: book, ReplaceCourses: hasCourses}
+	if hasCourses {
+		var rawCodes []string
+		for _, part := range strings.Split(courseSeparators.Replace(get("courses")), ";") {
+			if part = strings.TrimSpace(part); part != "" {
+				rawCodes = append(rawCodes, part)
+			}
+		}
+		codes, err := normalizeCourseCodes(rawCodes)
+		if err != nil {
+			return nil, err
+		}
+		item.CourseCodes = codes
+	}
+	return item, nil
+}
+
+// isBlankRecord 判断是否为空行
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/util"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+// MaxUserCourses 每个用户最多登记的选修课程数
+const MaxUserCourses = 30
+
+// BookService 图书目录服务
+type BookService struct {
+	bookRepo repository.BookRepository
+}
+
+// NewBookService 创建图书目录服务实例
+func NewBookService(bookRepo repository.BookRepository) *BookService {
+	return &BookService{
+		bookRepo: bookRepo,
+	}
+}
+
+// BookListResponse 图书列表响应
+type BookListResponse struct {
+	Items []model.BookDTO `json:"items"`
+	Total int64           `json:"total"`
+}
+
+// LookupByISBN 根据ISBN查询图书，用于发布商品时自动填充
+func (s *BookService) LookupByISBN(ctx context.Context, rawISBN string) (*model.BookDTO, error) {
+	isbn, err := util.NormalizeISBN(rawISBN)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := s.bookRepo.GetByISBN(ctx, isbn)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, err
+	}
+
+	courses, err := s.bookRepo.ListCourseCodes(ctx, []int64{book.ID})
+	if err != nil {
+		return nil, err
+	}
+	dto := book.ToDTO(courses[book.ID])
+	return &dto, nil
+}
+
+// ListBooks 分页查询图书目录，关键词可以是ISBN、书名、作者或课程代码
+func (s *BookService) ListBooks(ctx context.Context, keyword string, page, pageSize int) (*BookListResponse, error) {
+	books, total, err := s.bookRepo.List(ctx, keyword, util.NormalizeCourseCode(keyword), page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(books))
+	for _, b := range books {
+		ids = append(ids, b.ID)
+	}
+	courses, err := s.bookRepo.ListCourseCodes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.BookDTO, 0, len(books))
+	for i := range books {
+		items = append(items, books[i].ToDTO(courses[books[i].ID]))
+	}
+	return &BookListResponse{Items: items, Total: total}, nil
+}
+
+// GetUserCourses 获取用户选修的课程代码
+func (s *BookService) GetUserCourses(ctx context.Context, userID int64) ([]string, error) {
+	return s.bookRepo.ListUserCourses(ctx, userID)
+}
+
+// SetUserCourses 设置用户选修的课程，返回规范化后的课程代码
+func (s *BookService) SetUserCourses(ctx context.Context, userID int64, rawCodes []string) ([]string, error) {
+	codes, err := normalizeCourseCodes(rawCodes)
+	if err != nil {
+		return nil, err
+	}
+	if len(codes) > MaxUserCourses {
+		return nil, fmt.Errorf("%w: 最多%d门", ErrTooManyCourses, MaxUserCourses)
+	}
+	if err := s.bookRepo.ReplaceUserCourses(ctx, userID, codes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// normalizeCourseCodes 规范化并去重课程代码，保持原有顺序
+func normalizeCourseCodes(rawCodes []string) ([]string, error) {
+	codes := make([]string, 0, len(rawCodes))
+	seen := make(map[string]bool, len(rawCodes))
+	for _, raw := range rawCodes {
+		code := util.NormalizeCourseCode(raw)
+		if code == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCourseCode, raw)
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
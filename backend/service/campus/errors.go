@@ -0,0 +1,21 @@
+package campus
+
+import "errors"
+
+// 错误码定义
+const (
+	ErrCodeZoneHasPoints = 4008 // 片区下存在面交地点，无法删除
+	ErrCodePointInUse    = 4009 // 面交地点被在售商品使用，无法删除
+)
+
+// 错误定义
+var (
+	ErrZoneNotFound    = errors.New("校园片区不存在")
+	ErrZoneNameExists  = errors.New("校园片区名称已存在")
+	ErrZoneHasPoints   = errors.New("该片区下仍有面交地点，无法删除")
+	ErrPointNotFound   = errors.New("面交地点不存在")
+	ErrPointNameExists = errors.New("该片区内已有同名面交地点")
+	ErrPointInUse      = errors.New("该面交地点仍被在售商品使用，无法删除")
+	ErrInvalidCampus   = errors.New("无效的片区或面交地点参数")
+	ErrUserNotFound    = errors.New("用户不存在")
+)
@@ -0,0 +1,282 @@
+package campus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+const (
+	// maxNameLength 片区与面交地点名称最大长度（字符数）
+	maxNameLength = 50
+	// maxDescriptionLength 描述最大长度（字符数）
+	maxDescriptionLength = 255
+)
+
+// ZoneInput 创建或修改片区的参数
+type ZoneInput struct {
+	Name        string
+	Description string
+	SortOrder   int32
+}
+
+// PointInput 创建或修改面交地点的参数
+type PointInput struct {
+	ZoneID      int64 // 修改时大于0表示将地点移动到该片区
+	Name        string
+	Description string
+	SortOrder   int32
+}
+
+// Service 校园片区服务接口
+type Service interface {
+	// ListZones 获取全部片区及其面交地点
+	ListZones(ctx context.Context) ([]model.CampusZone, error)
+	CreateZone(ctx context.Context, input ZoneInput) (*model.CampusZone, error)
+	UpdateZone(ctx context.Context, id int64, input ZoneInput) (*model.CampusZone, error)
+	// DeleteZone 删除片区，片区下存在面交地点时拒绝删除
+	DeleteZone(ctx context.Context, id int64) error
+	CreatePoint(ctx context.Context, zoneID int64, input PointInput) (*model.MeetupPoint, error)
+	UpdatePoint(ctx context.Context, id int64, input PointInput) (*model.MeetupPoint, error)
+	// DeletePoint 删除面交地点，仍有在售商品使用时拒绝删除
+	DeletePoint(ctx context.Context, id int64) error
+	// SetDefaultZone 设置用户默认片区，zoneID 为 nil 时清除
+	SetDefaultZone(ctx context.Context, userID int64, zoneID *int64) error
+}
+
+type service struct {
+	repo repository.CampusRepository
+}
+
+// NewService 创建服务实例
+func NewService(repo repository.CampusRepository) Service {
+	return &service{repo: repo}
+}
+
+// ListZones 获取全部片区及其面交地点
+func (s *service) ListZones(ctx context.Context) ([]model.CampusZone, error) {
+	return s.repo.ListZones(ctx)
+}
+
+// CreateZone 创建片区
+func (s *service) CreateZone(ctx context.Context, input ZoneInput) (*model.CampusZone, error) {
+	name, description, err := normalizeInput(input.Name, input.Description)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureZoneNameAvailable(ctx, name, 0); err != nil {
+		return nil, err
+	}
+
+	zone := &model.CampusZone{Name: name, Description: description, SortOrder: input.SortOrder}
+	if err := s.repo.CreateZone(ctx, zone); err != nil {
+		return nil, err
+	}
+	zone.Points = []model.MeetupPoint{}
+	return zone, nil
+}
+
+// UpdateZone 修改片区
+func (s *service) UpdateZone(ctx context.Context, id int64, input ZoneInput) (*model.CampusZone, error) {
+	if _, err := s.getZone(ctx, id); err != nil {
+		return nil, err
+	}
+	name, description, err := normalizeInput(input.Name, input.Description)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureZoneNameAvailable(ctx, name, id); err != nil {
+		return nil, err
+	}
+
+	zone := &model.CampusZone{ID: id, Name: name, Description: description, SortOrder: input.SortOrder}
+	if err := s.repo.UpdateZone(ctx, zone); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrZoneNotFound
+		}
+		return nil, err
+	}
+	return s.repo.GetZone(ctx, id)
+}
+
+// DeleteZone 删除片区
+func (s *service) DeleteZone(ctx context.Context, id int64) error {
+	if _, err := s.getZone(ctx, id); err != nil {
+		return err
+	}
+	count, err := s.repo.CountPoints(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 片区下仍有面交地点时返回错误码4008，需先删除或迁移地点
+	if count > 0 {
+		return ErrZoneHasPoints
+	}
+	return s.repo.DeleteZone(ctx, id)
+}
+
+// CreatePoint 在片区下创建面交地点
+func (s *service) CreatePoint(ctx context.Context, zoneID int64, input PointInput) (*model.MeetupPoint, error) {
+	if _, err := s.getZone(ctx, zoneID); err != nil {
+		return nil, err
+	}
+	name, description, err := normalizeInput(input.Name, input.Description)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensurePointNameAvailable(ctx, zoneID, name, 0); err != nil {
+		return nil, err
+	}
+
+	point := &model.MeetupPoint{ZoneID: zoneID, Name: name, Description: description, SortOrder: input.SortOrder}
+	if err := s.repo.CreatePoint(ctx, point); err != nil {
+		// 片区在校验后被删除或不属于当前学校
+		if errors.Is(err, repository.ErrCrossTenant) {
+			return nil, ErrZoneNotFound
+		}
+		return nil, err
+	}
+	return point, nil
+}
+
+// UpdatePoint 修改面交地点，未指定片区时保持原片区
+func (s *service) UpdatePoint(ctx context.Context, id int64, input PointInput) (*model.MeetupPoint, error) {
+	point, err := s.getPoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	zoneID := point.ZoneID
+	if input.ZoneID > 0 && input.ZoneID != zoneID {
+		if _, err := s.getZone(ctx, input.ZoneID); err != nil {
+			return nil, err
+		}
+		zoneID = input.ZoneID
+	}
+	name, description, err := normalizeInput(input.Name, input.Description)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensurePointNameAvailable(ctx, zoneID, name, id); err != nil {
+		return nil, err
+	}
+
+	point.ZoneID = zoneID
+	point.Name = name
+	point.Description = description
+	point.SortOrder = input.SortOrder
+	if err := s.repo.UpdatePoint(ctx, point); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPointNotFound
+		}
+		if errors.Is(err, repository.ErrCrossTenant) {
+			return nil, ErrZoneNotFound
+		}
+		return nil, err
+	}
+	return s.repo.GetPoint(ctx, id)
+}
+
+// DeletePoint 删除面交地点
+func (s *service) DeletePoint(ctx context.Context, id int64) error {
+	if _, err := s.getPoint(ctx, id); err != nil {
+		return err
+	}
+	count, err := s.repo.CountForSaleProductsByPoint(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 在售商品仍在使用时返回错误码4009，已下架或已售商品的引用在删除后置空
+	if count > 0 {
+		return ErrPointInUse
+	}
+	return s.repo.DeletePoint(ctx, id)
+}
+
+// SetDefaultZone 设置用户默认片区
+func (s *service) SetDefaultZone(ctx context.Context, userID int64, zoneID *int64) error {
+	if zoneID != nil {
+		if *zoneID <= 0 {
+			zoneID = nil
+		} else if _, err := s.getZone(ctx, *zoneID); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.SetUserDefaultZone(ctx, userID, zoneID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		// 片区不属于用户所属学校
+		if errors.Is(err, repository.ErrCrossTenant) {
+			return ErrZoneNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// getZone 获取片区，不存在时返回 ErrZoneNotFound
+func (s *service) getZone(ctx context.Context, id int64) (*model.CampusZone, error) {
+	zone, err := s.repo.GetZone(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrZoneNotFound
+		}
+		return nil, err
+	}
+	return zone, nil
+}
+
+// getPoint 获取面交地点，不存在时返回 ErrPointNotFound
+func (s *service) getPoint(ctx context.Context, id int64) (*model.MeetupPoint, error) {
+	point, err := s.repo.GetPoint(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPointNotFound
+		}
+		return nil, err
+	}
+	return point, nil
+}
+
+// ensureZoneNameAvailable 校验片区名称未被其他片区使用
+func (s *service) ensureZoneNameAvailable(ctx context.Context, name string, excludeID int64) error {
+	exists, err := s.repo.ExistsZoneName(ctx, name, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrZoneNameExists
+	}
+	return nil
+}
+
+// ensurePointNameAvailable 校验片区内没有同名面交地点
+func (s *service) ensurePointNameAvailable(ctx context.Context, zoneID int64, name string, excludeID int64) error {
+	exists, err := s.repo.ExistsPointName(ctx, zoneID, name, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrPointNameExists
+	}
+	return nil
+}
+
+// normalizeInput 去除名称与描述首尾空白并校验长度
+func normalizeInput(name, description string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxNameLength {
+		return "", "", fmt.Errorf("%w: 名称长度需为1-%d个字符", ErrInvalidCampus, maxNameLength)
+	}
+	description = strings.TrimSpace(description)
+	if len([]rune(description)) > maxDescriptionLength {
+		return "", "", fmt.Errorf("%w: 描述不能超过%d个字符", ErrInvalidCampus, maxDescriptionLength)
+	}
+	return name, description, nil
+}
@@ -0,0 +1,217 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+// maxEnumOptions 枚举属性允许的最大可选值数量
+const maxEnumOptions = 50
+
+// AttributeInput 创建或更新分类属性的参数
+// Key 与 Type 仅在创建时生效，创建后不可修改，避免已发布商品的属性值失去意义
+type AttributeInput struct {
+	Key       string   `json:"key"`
+	Label     string   `json:"label" binding:"required"`
+	Type      string   `json:"type"`
+	Required  bool     `json:"required"`
+	Options   []string `json:"options"`
+	Unit      string   `json:"unit"`
+	SortOrder int      `json:"sortOrder"`
+}
+
+// AttributeService 分类属性定义服务接口
+type AttributeService interface {
+	// ListAttributes 获取分类属性，inherited 为true时包含从祖先分类继承的属性
+	ListAttributes(ctx context.Context, categoryID int64, inherited bool) ([]model.CategoryAttributeDTO, error)
+	// CreateAttribute 为分类新增属性定义
+	CreateAttribute(ctx context.Context, categoryID int64, input AttributeInput) (*model.CategoryAttributeDTO, error)
+	// UpdateAttribute 更新属性定义
+	UpdateAttribute(ctx context.Context, categoryID, attributeID int64, input AttributeInput) (*model.CategoryAttributeDTO, error)
+	// DeleteAttribute 删除属性定义
+	DeleteAttribute(ctx context.Context, categoryID, attributeID int64) error
+}
+
+// attributeService 分类属性定义服务实现
+type attributeService struct {
+	categoryRepo  repository.CategoryRepository
+	attributeRepo repository.CategoryAttributeRepository
+}
+
+// NewAttributeService 创建分类属性定义服务实例
+func NewAttributeService(categoryRepo repository.CategoryRepository, attributeRepo repository.CategoryAttributeRepository) AttributeService {
+	return &attributeService{
+		categoryRepo:  categoryRepo,
+		attributeRepo: attributeRepo,
+	}
+}
+
+// ListAttributes 获取分类属性
+func (s *attributeService) ListAttributes(ctx context.Context, categoryID int64, inherited bool) ([]model.CategoryAttributeDTO, error) {
+	if err := s.ensureCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	var attrs []model.CategoryAttribute
+	var err error
+	if inherited {
+		attrs, err = s.attributeRepo.ListEffective(ctx, categoryID)
+	} else {
+		attrs, err = s.attributeRepo.ListByCategory(ctx, categoryID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.CategoryAttributeDTO, 0, len(attrs))
+	for i := range attrs {
+		result = append(result, attrs[i].ToDTO())
+	}
+	return result, nil
+}
+
+// CreateAttribute 为分类新增属性定义
+func (s *attributeService) CreateAttribute(ctx context.Context, categoryID int64, input AttributeInput) (*model.CategoryAttributeDTO, error) {
+	if err := s.ensureCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	key := strings.TrimSpace(input.Key)
+	if !model.AttributeKeyPattern.MatchString(key) {
+		return nil, fmt.Errorf("%w: 属性键须以小写字母开头，仅包含小写字母、数字和下划线，最长32个字符", ErrInvalidAttribute)
+	}
+	switch input.Type {
+	case model.AttributeTypeString, model.AttributeTypeNumber, model.AttributeTypeEnum, model.AttributeTypeBoolean:
+	default:
+		return nil, fmt.Errorf("%w: 不支持的属性类型 %s", ErrInvalidAttribute, input.Type)
+	}
+
+	exists, err := s.attributeRepo.ExistsKey(ctx, categoryID, key)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAttributeKeyExists
+	}
+
+	attr := &model.CategoryAttribute{
+		CategoryID: categoryID,
+		Key:        key,
+		Type:       input.Type,
+	}
+	if err := applyAttributeInput(attr, input); err != nil {
+		return nil, err
+	}
+	if err := s.attributeRepo.Create(ctx, attr); err != nil {
+		return nil, err
+	}
+
+	dto := attr.ToDTO()
+	return &dto, nil
+}
+
+// UpdateAttribute 更新属性定义的名称、必填、可选值、单位与排序
+func (s *attributeService) UpdateAttribute(ctx context.Context, categoryID, attributeID int64, input AttributeInput) (*model.CategoryAttributeDTO, error) {
+	attr, err := s.getAttribute(ctx, categoryID, attributeID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Key != "" && input.Key != attr.Key {
+		return nil, fmt.Errorf("%w: 属性键创建后不可修改", ErrInvalidAttribute)
+	}
+	if input.Type != "" && input.Type != attr.Type {
+		return nil, fmt.Errorf("%w: 属性类型创建后不可修改", ErrInvalidAttribute)
+	}
+
+	if err := applyAttributeInput(attr, input); err != nil {
+		return nil, err
+	}
+	if err := s.attributeRepo.Update(ctx, attr); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAttributeNotFound
+		}
+		return nil, err
+	}
+
+	dto := attr.ToDTO()
+	return &dto, nil
+}
+
+// DeleteAttribute 删除属性定义
+func (s *attributeService) DeleteAttribute(ctx context.Context, categoryID, attributeID int64) error {
+	if _, err := s.getAttribute(ctx, categoryID, attributeID); err != nil {
+		return err
+	}
+	return s.attributeRepo.Delete(ctx, attributeID)
+}
+
+// ensureCategory 校验分类存在
+func (s *attributeService) ensureCategory(ctx context.Context, categoryID int64) error {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// getAttribute 获取属于指定分类的属性定义
+func (s *attributeService) getAttribute(ctx context.Context, categoryID, attributeID int64) (*model.CategoryAttribute, error) {
+	attr, err := s.attributeRepo.GetByID(ctx, attributeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAttributeNotFound
+		}
+		return nil, err
+	}
+	if attr.CategoryID != categoryID {
+		return nil, ErrAttributeNotFound
+	}
+	return attr, nil
+}
+
+// applyAttributeInput 校验并写入可修改的属性字段
+func applyAttributeInput(attr *model.CategoryAttribute, input AttributeInput) error {
+	label := strings.TrimSpace(input.Label)
+	if label == "" || len([]rune(label)) > 50 {
+		return fmt.Errorf("%w: 属性名称不能为空且不超过50个字符", ErrInvalidAttribute)
+	}
+	unit := strings.TrimSpace(input.Unit)
+	if len([]rune(unit)) > 16 {
+		return fmt.Errorf("%w: 单位不能超过16个字符", ErrInvalidAttribute)
+	}
+
+	options := []string{}
+	if attr.Type == model.AttributeTypeEnum {
+		seen := make(map[string]bool, len(input.Options))
+		for _, option := range input.Options {
+			option = strings.TrimSpace(option)
+			if option == "" || seen[option] {
+				continue
+			}
+			seen[option] = true
+			options = append(options, option)
+		}
+		if len(options) == 0 {
+			return fmt.Errorf("%w: 枚举属性至少需要一个可选值", ErrInvalidAttribute)
+		}
+		if len(options) > maxEnumOptions {
+			return fmt.Errorf("%w: 枚举可选值不能超过%d个", ErrInvalidAttribute, maxEnumOptions)
+		}
+	}
+
+	attr.Label = label
+	attr.Required = input.Required
+	attr.Unit = unit
+	attr.SortOrder = input.SortOrder
+	attr.SetOptions(options)
+	return nil
+}
@@ -5,9 +5,19 @@ import "errors"
 // 错误码定义
 const (
 	ErrCodeCategoryHasProducts = 4001 // 分类下有商品，无法删除
+	ErrCodeCategoryHasChildren = 4003 // 分类下有子分类，无法删除
+	ErrCodeCategoryCycle       = 4004 // 移动分类会形成循环
 )
 
 // 错误定义
 var (
 	ErrCategoryHasProducts = errors.New("category has products, cannot delete")
+	ErrCategoryHasChildren = errors.New("category has children, cannot delete")
+	ErrCategoryNotFound    = errors.New("分类不存在")
+	ErrParentNotFound      = errors.New("父分类不存在")
+	ErrCategoryCycle       = errors.New("不能将分类移动到自身或其子分类下")
+	ErrInvalidReorder      = errors.New("排序列表必须恰好包含该父分类下的全部子分类")
+	ErrAttributeNotFound   = errors.New("分类属性不存在")
+	ErrAttributeKeyExists  = errors.New("该分类下已存在同名属性")
+	ErrInvalidAttribute    = errors.New("无效的属性定义")
 )
@@ -2,6 +2,9 @@ package category
 
 import (
 	"context"
+	"errors"
+
+	"gorm.io/gorm"
 
 	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
@@ -17,6 +20,14 @@ type CategoryService interface {
 	UpdateCategory(ctx context.Context, category *model.Category) error
 	// DeleteCategory 删除分类，删除前检查引用
 	DeleteCategory(ctx context.Context, id int64) error
+	// ListCategoryTree 获取分类树（顶级分类及其子分类）
+	ListCategoryTree(ctx context.Context) ([]*model.CategoryNode, error)
+	// ListDescendantIDs 获取分类自身及全部后代分类ID
+	ListDescendantIDs(ctx context.Context, id int64) ([]int64, error)
+	// MoveCategory 移动分类到新的父分类下（parentID 为空表示移到顶级），sortOrder 为空时追加到末尾
+	MoveCategory(ctx context.Context, id int64, parentID *int64, sortOrder *int) (*model.Category, error)
+	// ReorderCategories 重排同一父分类下的子分类
+	ReorderCategories(ctx context.Context, parentID *int64, orderedIDs []int64) error
 }
 
 // categoryService 分类服务实现
@@ -46,17 +57,43 @@ func (s *categoryService) ListCategories(ctx context.Context) ([]*model.Category
 }
 
 // CreateCategory 创建分类
+// 指定父分类时校验其存在，新分类追加到同级末尾
 func (s *categoryService) CreateCategory(ctx context.Context, category *model.Category) error {
+	if category.ParentID != nil {
+		if err := s.ensureExists(ctx, *category.ParentID, ErrParentNotFound); err != nil {
+			return err
+		}
+	}
+	sortOrder, err := s.categoryRepo.NextSortOrder(ctx, category.ParentID)
+	if err != nil {
+		return err
+	}
+	category.SortOrder = sortOrder
 	return s.categoryRepo.Create(ctx, category)
 }
 
-// UpdateCategory 更新分类
+// UpdateCategory 更新分类名称与描述
 func (s *categoryService) UpdateCategory(ctx context.Context, category *model.Category) error {
-	return s.categoryRepo.Update(ctx, category)
+	if err := s.categoryRepo.Update(ctx, category); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryNotFound
+		}
+		return err
+	}
+	return nil
 }
 
-// DeleteCategory 删除分类，删除前检查引用
+// DeleteCategory 删除分类，删除前检查子分类与商品引用
 func (s *categoryService) DeleteCategory(ctx context.Context, id int64) error {
+	// 存在子分类时不允许删除，需先移动或删除子分类
+	children, err := s.categoryRepo.CountChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return ErrCategoryHasChildren
+	}
+
 	// 检查是否有关联的商品
 	count, err := s.categoryRepo.CountProductsByCategory(ctx, id)
 	if err != nil {
@@ -68,3 +105,81 @@ func (s *categoryService) DeleteCategory(ctx context.Context, id int64) error {
 	}
 	return s.categoryRepo.Delete(ctx, id)
 }
+
+// ListCategoryTree 获取分类树
+func (s *categoryService) ListCategoryTree(ctx context.Context) ([]*model.CategoryNode, error) {
+	categories, err := s.categoryRepo.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.BuildCategoryTree(categories), nil
+}
+
+// ListDescendantIDs 获取分类自身及全部后代分类ID
+func (s *categoryService) ListDescendantIDs(ctx context.Context, id int64) ([]int64, error) {
+	return s.categoryRepo.ListDescendantIDs(ctx, id)
+}
+
+// MoveCategory 移动分类
+// 目标父分类不能是分类自身或其后代，否则会形成循环；检查与移动在仓库层的同一事务内完成
+func (s *categoryService) MoveCategory(ctx context.Context, id int64, parentID *int64, sortOrder *int) (*model.Category, error) {
+	category, err := s.categoryRepo.Move(ctx, id, parentID, sortOrder)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, ErrCategoryNotFound
+	case errors.Is(err, repository.ErrCategoryParentNotFound):
+		return nil, ErrParentNotFound
+	case errors.Is(err, repository.ErrCategoryCycle):
+		return nil, ErrCategoryCycle
+	case err != nil:
+		return nil, err
+	}
+	return category, nil
+}
+
+// ReorderCategories 重排同一父分类下的子分类
+// orderedIDs 必须恰好是该父分类下的全部直接子分类
+func (s *categoryService) ReorderCategories(ctx context.Context, parentID *int64, orderedIDs []int64) error {
+	categories, err := s.categoryRepo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	siblings := make(map[int64]bool)
+	for _, c := range categories {
+		if sameParent(c.ParentID, parentID) {
+			siblings[c.ID] = true
+		}
+	}
+	if len(orderedIDs) != len(siblings) {
+		return ErrInvalidReorder
+	}
+	seen := make(map[int64]bool, len(orderedIDs))
+	for _, id := range orderedIDs {
+		if !siblings[id] || seen[id] {
+			return ErrInvalidReorder
+		}
+		seen[id] = true
+	}
+
+	return s.categoryRepo.Reorder(ctx, parentID, orderedIDs)
+}
+
+// ensureExists 校验分类存在，不存在时返回 notFound
+func (s *categoryService) ensureExists(ctx context.Context, id int64, notFound error) error {
+	if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return notFound
+		}
+		return err
+	}
+	return nil
+}
+
+// sameParent 判断两个父分类ID是否相同（均为空视为相同）
+func sameParent(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,122 @@
+// Package expiry 实现商品自动过期：卖家长期无活动的在售商品先提醒、再自动下架
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+// myProductsLink 自动下架通知跳转到我的发布页面，卖家可在该页面重新上架
+const myProductsLink = "/my/products"
+
+// Config 自动过期配置
+type Config struct {
+	ExpireAfter  time.Duration // 无活动多长时间后自动下架，<= 0 表示不启用
+	RemindBefore time.Duration // 下架前多长时间提醒，<= 0 表示不提醒
+}
+
+// Notifier 站内通知，由 notification 服务实现
+type Notifier interface {
+	Notify(ctx context.Context, userID int64, kind, title, content, link string) error
+}
+
+// RunResult 一次检查的结果
+type RunResult struct {
+	Reminded int
+	Expired  int
+}
+
+// Service 商品自动过期服务接口
+type Service interface {
+	// Enabled 是否启用自动过期
+	Enabled() bool
+	// Run 执行一次检查：先发送即将过期提醒，再下架已过期的商品
+	Run(ctx context.Context) (*RunResult, error)
+}
+
+type service struct {
+	repo     repository.ListingExpiryRepository
+	notifier Notifier
+	cfg      Config
+}
+
+// NewService 创建服务实例
+func NewService(repo repository.ListingExpiryRepository, notifier Notifier, cfg Config) Service {
+	if cfg.RemindBefore >= cfg.ExpireAfter {
+		cfg.RemindBefore = 0
+	}
+	return &service{repo: repo, notifier: notifier, cfg: cfg}
+}
+
+// Enabled 是否启用自动过期
+func (s *service) Enabled() bool {
+	return s.cfg.ExpireAfter > 0
+}
+
+// Run 执行一次检查
+//
+// 商品的最近活动时间为续期（发布、编辑、重新上架、擦亮）与卖家最近登录中较晚者。
+// 开启提醒时，商品必须在本次活动之后提醒过、且提醒已超过 RemindBefore 才会被下架，
+// 因此任务中断一段时间后恢复也不会在没有提醒的情况下直接下架。
+func (s *service) Run(ctx context.Context) (*RunResult, error) {
+	result := &RunResult{}
+	if !s.Enabled() {
+		return result, nil
+	}
+	now := time.Now()
+
+	var remindedBefore time.Time
+	if s.cfg.RemindBefore > 0 {
+		listings, err := s.repo.MarkReminders(ctx, now.Add(-(s.cfg.ExpireAfter - s.cfg.RemindBefore)))
+		if err != nil {
+			return result, fmt.Errorf("标记过期提醒失败: %w", err)
+		}
+		for _, listing := range listings {
+			expireAt := listing.ActiveAt.Add(s.cfg.ExpireAfter)
+			if earliest := now.Add(s.cfg.RemindBefore); expireAt.Before(earliest) {
+				expireAt = earliest
+			}
+			content := fmt.Sprintf("你发布的「%s」已 %d 天没有活动，将于 %s 后自动下架。如仍在出售，请在商品页点击“擦亮”续期。",
+				listing.Title, daysSince(listing.ActiveAt, now), expireAt.Format("2006-01-02 15:04"))
+			s.notify(ctx, listing, model.NotificationListingExpiring, "商品即将自动下架", content, fmt.Sprintf("/products/%d", listing.ID))
+		}
+		result.Reminded = len(listings)
+		remindedBefore = now.Add(-s.cfg.RemindBefore)
+	}
+
+	listings, err := s.repo.ExpireListings(ctx, now.Add(-s.cfg.ExpireAfter), remindedBefore)
+	if err != nil {
+		return result, fmt.Errorf("自动下架商品失败: %w", err)
+	}
+	for _, listing := range listings {
+		content := fmt.Sprintf("你发布的「%s」已 %d 天没有活动，已自动下架。如仍在出售，可在“我的发布”中重新上架。",
+			listing.Title, daysSince(listing.ActiveAt, now))
+		s.notify(ctx, listing, model.NotificationListingExpired, "商品已自动下架", content, myProductsLink)
+	}
+	result.Expired = len(listings)
+
+	if result.Reminded > 0 || result.Expired > 0 {
+		log.Printf("listing expiry: reminded %d, expired %d", result.Reminded, result.Expired)
+	}
+	return result, nil
+}
+
+// notify 发送通知，失败只记录日志（商品状态已更新，不回滚）
+func (s *service) notify(ctx context.Context, listing repository.ExpiringListing, kind, title, content, link string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.Notify(ctx, listing.SellerID, kind, title, content, link); err != nil {
+		log.Printf("warn: notify listing expiry for product %d failed: %v", listing.ID, err)
+	}
+}
+
+// daysSince 距离 t 的整天数
+func daysSince(t, now time.Time) int {
+	return int(now.Sub(t) / (24 * time.Hour))
+}
@@ -0,0 +1,9 @@
+package notification
+
+import "errors"
+
+// 错误定义
+var (
+	ErrNotificationNotFound = errors.New("通知不存在")
+	ErrInvalidNotification  = errors.New("通知标题和内容不能为空")
+)
@@ -0,0 +1,99 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+// ListResponse 通知列表
+type ListResponse struct {
+	Total         int64                `json:"total"`
+	UnreadCount   int64                `json:"unreadCount"`
+	Notifications []model.Notification `json:"notifications"`
+}
+
+// Service 站内通知服务接口
+type Service interface {
+	// Notify 向用户发送一条站内通知，link 为空表示无跳转
+	Notify(ctx context.Context, userID int64, kind, title, content, link string) error
+	// List 分页获取当前用户的通知
+	List(ctx context.Context, userID int64, unreadOnly bool, page, pageSize int) (*ListResponse, error)
+	// UnreadCount 获取未读通知数
+	UnreadCount(ctx context.Context, userID int64) (int64, error)
+	// MarkRead 将指定通知标记为已读
+	MarkRead(ctx context.Context, userID, id int64) error
+	// MarkAllRead 将全部通知标记为已读
+	MarkAllRead(ctx context.Context, userID int64) (int64, error)
+}
+
+type service struct {
+	repo repository.NotificationRepository
+}
+
+// NewService 创建服务实例
+func NewService(repo repository.NotificationRepository) Service {
+	return &service{repo: repo}
+}
+
+// Notify 向用户发送一条站内通知
+func (s *service) Notify(ctx context.Context, userID int64, kind, title, content, link string) error {
+	title = strings.TrimSpace(title)
+	content = strings.TrimSpace(content)
+	if title == "" || content == "" {
+		return ErrInvalidNotification
+	}
+
+	notification := &model.Notification{
+		UserID:  userID,
+		Type:    kind,
+		Title:   title,
+		Content: content,
+	}
+	if link != "" {
+		notification.Link = &link
+	}
+	return s.repo.Create(ctx, notification)
+}
+
+// List 分页获取当前用户的通知
+func (s *service) List(ctx context.Context, userID int64, unreadOnly bool, page, pageSize int) (*ListResponse, error) {
+	notifications, total, err := s.repo.ListByUser(ctx, userID, unreadOnly, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if notifications == nil {
+		notifications = []model.Notification{}
+	}
+	return &ListResponse{Total: total, UnreadCount: unread, Notifications: notifications}, nil
+}
+
+// UnreadCount 获取未读通知数
+func (s *service) UnreadCount(ctx context.Context, userID int64) (int64, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead 将指定通知标记为已读
+func (s *service) MarkRead(ctx context.Context, userID, id int64) error {
+	if err := s.repo.MarkRead(ctx, userID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotificationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// MarkAllRead 将全部通知标记为已读
+func (s *service) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
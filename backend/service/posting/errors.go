@@ -0,0 +1,22 @@
+package posting
+
+import "errors"
+
+// 错误码定义
+const (
+	ErrCodeQuotaExceeded  = 3006 // 超出发布配额（在售数量或每日发布数量）
+	ErrCodeBlockedKeyword = 3007 // 标题或描述包含屏蔽词
+)
+
+// 错误定义
+var (
+	ErrForSaleLimit     = errors.New("在售商品数量已达上限，请先下架或标记已售部分商品")
+	ErrDailyLimit       = errors.New("24 小时内发布商品数量已达上限，请稍后再试")
+	ErrBlockedKeyword   = errors.New("标题或描述包含违禁内容")
+	ErrInvalidKeyword   = errors.New("屏蔽词长度需为 1-64 个字符")
+	ErrKeywordExists    = errors.New("屏蔽词已存在")
+	ErrKeywordNotFound  = errors.New("屏蔽词不存在")
+	ErrInvalidQuota     = errors.New("配额不能为负数")
+	ErrUserNotFound     = errors.New("用户不存在")
+	ErrQuotaNoteTooLong = errors.New("备注不能超过 255 个字符")
+)
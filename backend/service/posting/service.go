@@ -0,0 +1,398 @@
+// Package posting 提供商品发布限制：在售数量与每日发布数量配额、新账号更严格的默认配额，
+// 以及标题和描述的屏蔽词检查
+package posting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cache"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+const (
+	// keywordCacheTTL 屏蔽词列表缓存时间，增删屏蔽词时立即失效
+	keywordCacheTTL = 10 * time.Minute
+	// maxKeywordLength 屏蔽词最大长度（字符数）
+	maxKeywordLength = 64
+	// maxNoteLength 配额备注最大长度（字符数）
+	maxNoteLength = 255
+	// dailyWindow 每日发布数量按最近 24 小时滚动统计，避免在零点前后集中发布
+	dailyWindow = 24 * time.Hour
+)
+
+// QuotaConfig 全局默认配额，取值 <= 0 表示不限制
+type QuotaConfig struct {
+	MaxForSale int // 同时在售商品数上限
+	MaxDaily   int // 24 小时内发布商品数上限
+	// NewAccountDays 注册不足该天数的账号视为新账号，使用下方更严格的配额
+	NewAccountDays       int
+	NewAccountMaxForSale int
+	NewAccountMaxDaily   int
+}
+
+// UserQuota 用户的发布配额详情
+type UserQuota struct {
+	UserID       int64                   `json:"userId"`
+	IsNewAccount bool                    `json:"isNewAccount"`
+	MaxForSale   int                     `json:"maxForSale"` // 生效的在售上限，0 表示不限制
+	MaxDaily     int                     `json:"maxDaily"`   // 生效的每日上限，0 表示不限制
+	ForSaleCount int64                   `json:"forSaleCount"`
+	DailyCount   int64                   `json:"dailyCount"`
+	Override     *model.UserPostingQuota `json:"override"` // 管理员设置的覆盖，未设置时为 null
+}
+
+// Service 发布限制服务接口
+type Service interface {
+	// CheckCreate 发布新商品前预先检查在售数量与每日发布数量，用于在保存图片等耗时操作前尽早拒绝
+	CheckCreate(ctx context.Context, user *model.User) error
+	// CheckCreateTx 在插入商品的同一事务内（商品已插入后）锁定用户并复核配额，超限时返回错误使事务回滚
+	// 并发发布时以此为准，CheckCreate 的结果可能已过期
+	CheckCreateTx(tx *gorm.DB, user *model.User) error
+	// CheckRelist 重新上架前检查在售数量
+	CheckRelist(ctx context.Context, user *model.User) error
+	// CheckContent 检查标题与描述是否包含屏蔽词
+	CheckContent(ctx context.Context, title, description string) error
+
+	// ListKeywords 获取当前学校的屏蔽词
+	ListKeywords(ctx context.Context) ([]model.BlockedKeyword, error)
+	// AddKeyword 添加屏蔽词
+	AddKeyword(ctx context.Context, operatorID int64, keyword string) (*model.BlockedKeyword, error)
+	// DeleteKeyword 删除屏蔽词
+	DeleteKeyword(ctx context.Context, id int64) error
+
+	// GetUserQuota 获取用户生效的配额与当前用量
+	GetUserQuota(ctx context.Context, userID int64) (*UserQuota, error)
+	// SetUserQuota 设置用户的配额覆盖，字段为 nil 时沿用默认值，0 表示禁止发布
+	SetUserQuota(ctx context.Context, operatorID, userID int64, maxForSale, maxDaily *int, note string) (*UserQuota, error)
+	// ClearUserQuota 删除用户的配额覆盖，恢复默认值
+	ClearUserQuota(ctx context.Context, userID int64) (*UserQuota, error)
+}
+
+type service struct {
+	repo     repository.PostingRepository
+	userRepo repository.UserRepository
+	cache    *cache.MemoryCache
+	config   QuotaConfig
+}
+
+// NewService 创建服务实例
+func NewService(repo repository.PostingRepository, userRepo repository.UserRepository, cache *cache.MemoryCache, config QuotaConfig) Service {
+	return &service{repo: repo, userRepo: userRepo, cache: cache, config: config}
+}
+
+// CheckCreate 发布新商品前预先检查配额（未加锁，并发发布时由 CheckCreateTx 保证不超限）
+func (s *service) CheckCreate(ctx context.Context, user *model.User) error {
+	maxForSale, maxDaily, err := s.effectiveLimits(ctx, user)
+	if err != nil {
+		return err
+	}
+	if err := s.checkForSale(ctx, user.ID, maxForSale); err != nil {
+		return err
+	}
+	if maxDaily < 0 {
+		return nil
+	}
+	count, err := s.repo.CountCreatedSince(ctx, user.ID, time.Now().Add(-dailyWindow))
+	if err != nil {
+		return err
+	}
+	if count >= int64(maxDaily) {
+		return fmt.Errorf("%w（上限 %d 件）", ErrDailyLimit, maxDaily)
+	}
+	return nil
+}
+
+// CheckCreateTx 在插入商品的事务内复核配额
+// 用户行锁使同一用户的并发发布依次执行，后执行的事务能看到先提交的商品；计数已包含本次插入的商品，因此超过上限才拒绝
+func (s *service) CheckCreateTx(tx *gorm.DB, user *model.User) error {
+	maxForSale, maxDaily, err := s.effectiveLimits(tx.Statement.Context, user)
+	if err != nil {
+		return err
+	}
+	if maxForSale < 0 && maxDaily < 0 {
+		return nil
+	}
+	forSale, daily, err := s.repo.LockSellerCounts(tx, user.ID, time.Now().Add(-dailyWindow))
+	if err != nil {
+		return err
+	}
+	if maxForSale >= 0 && forSale > int64(maxForSale) {
+		return fmt.Errorf("%w（上限 %d 件）", ErrForSaleLimit, maxForSale)
+	}
+	if maxDaily >= 0 && daily > int64(maxDaily) {
+		return fmt.Errorf("%w（上限 %d 件）", ErrDailyLimit, maxDaily)
+	}
+	return nil
+}
+
+// CheckRelist 重新上架前检查在售数量
+func (s *service) CheckRelist(ctx context.Context, user *model.User) error {
+	maxForSale, _, err := s.effectiveLimits(ctx, user)
+	if err != nil {
+		return err
+	}
+	return s.checkForSale(ctx, user.ID, maxForSale)
+}
+
+// CheckContent 检查标题与描述是否包含屏蔽词，匹配时忽略大小写与空白字符
+func (s *service) CheckContent(ctx context.Context, title, description string) error {
+	keywords, err := s.loadKeywords(ctx)
+	if err != nil {
+		return err
+	}
+	if len(keywords) == 0 {
+		return nil
+	}
+
+	text := normalizeText(title + " " + description)
+	for _, keyword := range keywords {
+		if needle := normalizeText(keyword.Keyword); needle != "" && strings.Contains(text, needle) {
+			return fmt.Errorf("%w：%s", ErrBlockedKeyword, keyword.Keyword)
+		}
+	}
+	return nil
+}
+
+// ListKeywords 获取当前学校的屏蔽词
+func (s *service) ListKeywords(ctx context.Context) ([]model.BlockedKeyword, error) {
+	return s.repo.ListKeywords(ctx)
+}
+
+// AddKeyword 添加屏蔽词
+func (s *service) AddKeyword(ctx context.Context, operatorID int64, keyword string) (*model.BlockedKeyword, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" || utf8.RuneCountInString(keyword) > maxKeywordLength {
+		return nil, ErrInvalidKeyword
+	}
+	exists, err := s.repo.KeywordExists(ctx, keyword)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrKeywordExists
+	}
+
+	record := &model.BlockedKeyword{Keyword: keyword}
+	if operatorID > 0 {
+		record.CreatedBy = &operatorID
+	}
+	if err := s.repo.CreateKeyword(ctx, record); err != nil {
+		return nil, err
+	}
+	s.invalidateKeywords(ctx)
+	return record, nil
+}
+
+// DeleteKeyword 删除屏蔽词
+func (s *service) DeleteKeyword(ctx context.Context, id int64) error {
+	if err := s.repo.DeleteKeyword(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrKeywordNotFound
+		}
+		return err
+	}
+	s.invalidateKeywords(ctx)
+	return nil
+}
+
+// GetUserQuota 获取用户生效的配额与当前用量
+func (s *service) GetUserQuota(ctx context.Context, userID int64) (*UserQuota, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildUserQuota(ctx, user)
+}
+
+// SetUserQuota 设置用户的配额覆盖
+func (s *service) SetUserQuota(ctx context.Context, operatorID, userID int64, maxForSale, maxDaily *int, note string) (*UserQuota, error) {
+	if (maxForSale != nil && *maxForSale < 0) || (maxDaily != nil && *maxDaily < 0) {
+		return nil, ErrInvalidQuota
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return nil, ErrQuotaNoteTooLong
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	quota := &model.UserPostingQuota{
+		UserID:     userID,
+		MaxForSale: maxForSale,
+		MaxDaily:   maxDaily,
+		Note:       note,
+	}
+	if operatorID > 0 {
+		quota.UpdatedBy = &operatorID
+	}
+	if err := s.repo.SaveQuota(ctx, quota); err != nil {
+		return nil, err
+	}
+	return s.buildUserQuota(ctx, user)
+}
+
+// ClearUserQuota 删除用户的配额覆盖
+func (s *service) ClearUserQuota(ctx context.Context, userID int64) (*UserQuota, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.buildUserQuota(ctx, user)
+}
+
+// effectiveLimits 计算用户生效的配额，返回 -1 表示不限制
+// 优先使用管理员设置的覆盖值（0 表示禁止发布），其次按账号注册时长使用默认值
+func (s *service) effectiveLimits(ctx context.Context, user *model.User) (maxForSale, maxDaily int, err error) {
+	maxForSale, maxDaily = s.config.MaxForSale, s.config.MaxDaily
+	if s.isNewAccount(user) {
+		maxForSale, maxDaily = s.config.NewAccountMaxForSale, s.config.NewAccountMaxDaily
+	}
+	if maxForSale <= 0 {
+		maxForSale = -1
+	}
+	if maxDaily <= 0 {
+		maxDaily = -1
+	}
+
+	override, err := s.repo.GetQuota(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return maxForSale, maxDaily, nil
+		}
+		return 0, 0, err
+	}
+	if override.MaxForSale != nil {
+		maxForSale = *override.MaxForSale
+	}
+	if override.MaxDaily != nil {
+		maxDaily = *override.MaxDaily
+	}
+	return maxForSale, maxDaily, nil
+}
+
+// checkForSale 检查在售数量是否已达上限
+func (s *service) checkForSale(ctx context.Context, userID int64, maxForSale int) error {
+	if maxForSale < 0 {
+		return nil
+	}
+	count, err := s.repo.CountForSale(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if count >= int64(maxForSale) {
+		return fmt.Errorf("%w（上限 %d 件）", ErrForSaleLimit, maxForSale)
+	}
+	return nil
+}
+
+// isNewAccount 判断是否为新注册账号
+func (s *service) isNewAccount(user *model.User) bool {
+	if s.config.NewAccountDays <= 0 {
+		return false
+	}
+	return time.Since(user.CreatedAt) < time.Duration(s.config.NewAccountDays)*24*time.Hour
+}
+
+// buildUserQuota 组装用户配额详情，-1（不限制）对外表示为 0
+func (s *service) buildUserQuota(ctx context.Context, user *model.User) (*UserQuota, error) {
+	maxForSale, maxDaily, err := s.effectiveLimits(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	forSale, err := s.repo.CountForSale(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := s.repo.CountCreatedSince(ctx, user.ID, time.Now().Add(-dailyWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UserQuota{
+		UserID:       user.ID,
+		IsNewAccount: s.isNewAccount(user),
+		MaxForSale:   max(maxForSale, 0),
+		MaxDaily:     max(maxDaily, 0),
+		ForSaleCount: forSale,
+		DailyCount:   daily,
+	}
+	override, err := s.repo.GetQuota(ctx, user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	result.Override = override
+	return result, nil
+}
+
+// getUser 获取当前学校的用户
+func (s *service) getUser(ctx context.Context, userID int64) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.SchoolID != tenant.SchoolID(ctx) {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// loadKeywords 读取当前学校的屏蔽词，缓存未命中时查询数据库并写入缓存
+func (s *service) loadKeywords(ctx context.Context) ([]model.BlockedKeyword, error) {
+	key := keywordCacheKey(ctx)
+	if s.cache != nil {
+		if cached, err := s.cache.Get(ctx, key); err == nil {
+			if keywords, ok := cached.([]model.BlockedKeyword); ok {
+				return keywords, nil
+			}
+		}
+	}
+
+	keywords, err := s.repo.ListKeywords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.cache != nil {
+		_ = s.cache.Set(ctx, key, keywords, keywordCacheTTL)
+	}
+	return keywords, nil
+}
+
+// invalidateKeywords 使当前学校的屏蔽词缓存失效
+func (s *service) invalidateKeywords(ctx context.Context) {
+	if s.cache != nil {
+		_ = s.cache.Delete(ctx, keywordCacheKey(ctx))
+	}
+}
+
+// keywordCacheKey 屏蔽词缓存键（按学校区分）
+func keywordCacheKey(ctx context.Context) string {
+	return fmt.Sprintf("posting:keywords:%d", tenant.SchoolID(ctx))
+}
+
+// normalizeText 转为小写并去除空白字符，避免通过插入空格绕过屏蔽词
+func normalizeText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, text)
+}
@@ -0,0 +1,227 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+// ErrInvalidAttributeFilter 属性筛选条件格式错误
+var ErrInvalidAttributeFilter = errors.New("无效的属性筛选条件")
+
+const (
+	// maxAttributeStringLength 字符串属性值的最大长度
+	maxAttributeStringLength = 100
+	// maxAttributeFilters 单次搜索允许的属性筛选条件数
+	maxAttributeFilters = 10
+)
+
+// attributeFilterOps 筛选表达式中支持的运算符，按长度降序排列以便优先匹配 >= 等双字符运算符
+var attributeFilterOps = []struct {
+	token string
+	op    string
+}{
+	{">=", repository.AttributeOpGte},
+	{"<=", repository.AttributeOpLte},
+	{"!=", repository.AttributeOpNe},
+	{"==", repository.AttributeOpEq},
+	{">", repository.AttributeOpGt},
+	{"<", repository.AttributeOpLt},
+	{"=", repository.AttributeOpEq},
+}
+
+// loadAttributeSchema 获取分类生效的属性定义
+func (s *ProductService) loadAttributeSchema(ctx context.Context, categoryID int64) ([]model.CategoryAttribute, error) {
+	if s.attributeRepo == nil || categoryID <= 0 {
+		return nil, nil
+	}
+	schema, err := s.attributeRepo.ListEffective(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("获取分类属性失败: %w", err)
+	}
+	return schema, nil
+}
+
+// validateAttributes 按分类属性定义校验并规范化属性值
+// dropUnknown 为true时忽略未定义的属性（用于商品换分类后沿用旧属性值），否则报错
+func validateAttributes(schema []model.CategoryAttribute, values map[string]interface{}, dropUnknown bool) (map[string]interface{}, error) {
+	defs := make(map[string]*model.CategoryAttribute, len(schema))
+	for i := range schema {
+		defs[schema[i].Key] = &schema[i]
+	}
+
+	for key := range values {
+		if _, ok := defs[key]; !ok && !dropUnknown {
+			return nil, fmt.Errorf("未知的商品属性: %s", key)
+		}
+	}
+
+	result := make(map[string]interface{}, len(schema))
+	for i := range schema {
+		def := &schema[i]
+		raw, present := values[def.Key]
+		if present {
+			normalized, empty, err := normalizeAttributeValue(def, raw)
+			if err != nil {
+				return nil, err
+			}
+			if !empty {
+				result[def.Key] = normalized
+				continue
+			}
+		}
+		if def.Required {
+			return nil, fmt.Errorf("商品属性「%s」为必填项", def.Label)
+		}
+	}
+	return result, nil
+}
+
+// normalizeAttributeValue 校验单个属性值，返回规范化后的值以及该值是否为空
+// 表单提交时数值与布尔值可能以字符串形式出现，这里统一转换为JSON原生类型
+func normalizeAttributeValue(def *model.CategoryAttribute, raw interface{}) (interface{}, bool, error) {
+	if raw == nil {
+		return nil, true, nil
+	}
+	if str, ok := raw.(string); ok {
+		str = strings.TrimSpace(str)
+		if str == "" {
+			return nil, true, nil
+		}
+		raw = str
+	}
+
+	switch def.Type {
+	case model.AttributeTypeNumber:
+		var n float64
+		switch v := raw.(type) {
+		case float64:
+			n = v
+		case string:
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, false, fmt.Errorf("商品属性「%s」必须为数字", def.Label)
+			}
+			n = parsed
+		default:
+			return nil, false, fmt.Errorf("商品属性「%s」必须为数字", def.Label)
+		}
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, false, fmt.Errorf("商品属性「%s」必须为数字", def.Label)
+		}
+		return n, false, nil
+
+	case model.AttributeTypeBoolean:
+		switch v := raw.(type) {
+		case bool:
+			return v, false, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, false, nil
+			}
+		}
+		return nil, false, fmt.Errorf("商品属性「%s」必须为是或否", def.Label)
+
+	case model.AttributeTypeEnum:
+		str, ok := raw.(string)
+		if !ok {
+			return nil, false, fmt.Errorf("商品属性「%s」的取值无效", def.Label)
+		}
+		for _, option := range def.OptionList() {
+			if option == str {
+				return str, false, nil
+			}
+		}
+		return nil, false, fmt.Errorf("商品属性「%s」的取值无效", def.Label)
+
+	default:
+		str, ok := raw.(string)
+		if !ok {
+			return nil, false, fmt.Errorf("商品属性「%s」必须为文本", def.Label)
+		}
+		if len([]rune(str)) > maxAttributeStringLength {
+			return nil, false, fmt.Errorf("商品属性「%s」不能超过%d个字符", def.Label, maxAttributeStringLength)
+		}
+		return str, false, nil
+	}
+}
+
+// parseAttributeFilters 解析属性筛选表达式，如 storage>=128、brand=Apple
+//
+// 功能说明：
+//   - 指定分类时按该分类生效的属性定义校验属性键并转换取值类型
+//   - 未指定分类时根据取值推断类型：可解析为数字按数值比较，true/false 按布尔比较，其余按字符串比较
+//   - 大小比较运算符仅适用于数值属性
+func (s *ProductService) parseAttributeFilters(ctx context.Context, categoryID int64, exprs []string) ([]repository.AttributeFilter, error) {
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+	if len(exprs) > maxAttributeFilters {
+		return nil, fmt.Errorf("%w: 最多支持%d个属性条件", ErrInvalidAttributeFilter, maxAttributeFilters)
+	}
+
+	var defs map[string]*model.CategoryAttribute
+	if categoryID > 0 {
+		schema, err := s.loadAttributeSchema(ctx, categoryID)
+		if err != nil {
+			return nil, err
+		}
+		defs = make(map[string]*model.CategoryAttribute, len(schema))
+		for i := range schema {
+			defs[schema[i].Key] = &schema[i]
+		}
+	}
+
+	filters := make([]repository.AttributeFilter, 0, len(exprs))
+	for _, expr := range exprs {
+		key, op, rawValue, ok := splitAttributeFilter(expr)
+		if !ok || !model.AttributeKeyPattern.MatchString(key) || rawValue == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAttributeFilter, expr)
+		}
+
+		var value interface{}
+		if defs != nil {
+			def, exists := defs[key]
+			if !exists {
+				return nil, fmt.Errorf("%w: 分类未定义属性 %s", ErrInvalidAttributeFilter, key)
+			}
+			normalized, _, err := normalizeAttributeValue(def, rawValue)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidAttributeFilter, err)
+			}
+			value = normalized
+		} else if n, err := strconv.ParseFloat(rawValue, 64); err == nil && !math.IsNaN(n) && !math.IsInf(n, 0) {
+			value = n
+		} else if b, err := strconv.ParseBool(rawValue); err == nil {
+			value = b
+		} else {
+			value = rawValue
+		}
+
+		if _, numeric := value.(float64); !numeric && op != repository.AttributeOpEq && op != repository.AttributeOpNe {
+			return nil, fmt.Errorf("%w: 属性 %s 仅支持等于或不等于比较", ErrInvalidAttributeFilter, key)
+		}
+		filters = append(filters, repository.AttributeFilter{Key: key, Op: op, Value: value})
+	}
+	return filters, nil
+}
+
+// splitAttributeFilter 将表达式拆分为属性键、运算符和取值
+func splitAttributeFilter(expr string) (string, string, string, bool) {
+	for i := 0; i < len(expr); i++ {
+		for _, candidate := range attributeFilterOps {
+			if strings.HasPrefix(expr[i:], candidate.token) {
+				key := strings.TrimSpace(expr[:i])
+				value := strings.TrimSpace(expr[i+len(candidate.token):])
+				return key, candidate.op, value, true
+			}
+		}
+	}
+	return "", "", "", false
+}
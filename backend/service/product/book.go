@@ -0,0 +1,100 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/util"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// resolveBook 规范化ISBN并在本地图书目录中查找对应图书
+// ISBN为空时返回空值；目录中不存在该书时 book 为nil，不视为错误
+func (s *ProductService) resolveBook(ctx context.Context, rawISBN string) (*string, *model.Book, error) {
+	if strings.TrimSpace(rawISBN) == "" {
+		return nil, nil, nil
+	}
+	isbn, err := util.NormalizeISBN(rawISBN)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.bookRepo == nil {
+		return &isbn, nil, nil
+	}
+
+	book, err := s.bookRepo.GetByISBN(ctx, isbn)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &isbn, nil, nil
+		}
+		return nil, nil, fmt.Errorf("查询图书目录失败: %w", err)
+	}
+	return &isbn, book, nil
+}
+
+// applyBookDefaults 用图书目录信息补全卖家未填写的标题、描述与分类属性
+// 分类属性只补全当前分类定义了的 isbn/author/edition/publisher 属性
+func applyBookDefaults(req *CreateProductRequest, isbn string, book *model.Book, schema []model.CategoryAttribute) {
+	if strings.TrimSpace(req.Title) == "" {
+		req.Title = strings.TrimSpace(book.Title + " " + book.Edition)
+	}
+
+	if strings.TrimSpace(req.Description) == "" {
+		lines := make([]string, 0, 3)
+		if book.Author != "" {
+			lines = append(lines, "作者："+book.Author)
+		}
+		if book.Edition != "" {
+			lines = append(lines, "版次："+book.Edition)
+		}
+		if book.Publisher != "" {
+			lines = append(lines, "出版社："+book.Publisher)
+		}
+		req.Description = strings.Join(lines, "\n")
+	}
+
+	bookValues := map[string]string{
+		"isbn":      isbn,
+		"author":    book.Author,
+		"edition":   book.Edition,
+		"publisher": book.Publisher,
+	}
+	for _, def := range schema {
+		value, ok := bookValues[def.Key]
+		if !ok || value == "" || def.Type != model.AttributeTypeString {
+			continue
+		}
+		if _, provided := req.Attributes[def.Key]; provided {
+			continue
+		}
+		if req.Attributes == nil {
+			req.Attributes = make(map[string]interface{})
+		}
+		req.Attributes[def.Key] = value
+	}
+}
+
+// lookupBookDTO 获取商品关联图书的展示信息，失败或不存在时返回nil
+func (s *ProductService) lookupBookDTO(ctx context.Context, isbn *string) *model.BookDTO {
+	if isbn == nil || s.bookRepo == nil {
+		return nil
+	}
+	book, err := s.bookRepo.GetByISBN(ctx, *isbn)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("warn: lookup book %s failed: %v", *isbn, err)
+		}
+		return nil
+	}
+	courses, err := s.bookRepo.ListCourseCodes(ctx, []int64{book.ID})
+	if err != nil {
+		log.Printf("warn: list courses of book %d failed: %v", book.ID, err)
+	}
+	dto := book.ToDTO(courses[book.ID])
+	return &dto
+}
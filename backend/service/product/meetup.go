@@ -0,0 +1,46 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// resolveMeetupPoint 校验卖家选择的面交地点
+// 查询限定在当前学校内，其他学校的面交地点视为不存在；仓库层保存时还会按商品所属学校再次校验
+// pointID 为0表示不指定面交地点，返回nil
+func (s *ProductService) resolveMeetupPoint(ctx context.Context, pointID int64) (*int64, error) {
+	if pointID <= 0 {
+		return nil, nil
+	}
+	if s.campusRepo == nil {
+		return nil, fmt.Errorf("服务未初始化")
+	}
+	if _, err := s.campusRepo.GetPoint(ctx, pointID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("面交地点不存在")
+		}
+		return nil, fmt.Errorf("查询面交地点失败: %w", err)
+	}
+	return &pointID, nil
+}
+
+// lookupMeetupPointDTO 查询商品详情中展示的面交地点，查询失败时不展示
+func (s *ProductService) lookupMeetupPointDTO(ctx context.Context, pointID *int64) *model.MeetupPointDTO {
+	if pointID == nil || s.campusRepo == nil {
+		return nil
+	}
+	point, err := s.campusRepo.GetPointDetail(ctx, *pointID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("warn: get meetup point %d failed: %v", *pointID, err)
+		}
+		return nil
+	}
+	return point
+}
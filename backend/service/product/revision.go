@@ -0,0 +1,226 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+// revisionHook 返回在商品写入事务内记录修订的钩子
+// before 为编辑前的快照（发布商品时为nil），首次编辑历史商品时会先补录基线版本
+// 修订记录与商品修改同时提交，写入失败时整个编辑回滚，保证修订链与价格历史没有缺口
+func (s *ProductService) revisionHook(product *model.Product, before *model.ProductRevision, editorID int64, reason string) repository.TxHook {
+	if s.revisionRepo == nil {
+		return nil
+	}
+	return func(tx *gorm.DB) error {
+		// 发布商品时 product.ID 在事务内插入后才确定，因此在钩子执行时读取
+		after, err := s.revisionRepo.Snapshot(tx, product.ID, editorID, reason)
+		if err != nil {
+			return fmt.Errorf("记录商品修订失败: %w", err)
+		}
+		if err := s.revisionRepo.RecordInTx(tx, before, after); err != nil {
+			return fmt.Errorf("记录商品修订失败: %w", err)
+		}
+		return nil
+	}
+}
+
+// ListRevisions 获取商品修订历史（仅卖家本人或管理员可查看）
+func (s *ProductService) ListRevisions(ctx context.Context, userID, productID int64, isAdmin bool) ([]model.ProductRevisionDTO, error) {
+	if s.productRepo == nil || s.revisionRepo == nil {
+		return nil, fmt.Errorf("服务未初始化")
+	}
+
+	product, _, _, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("商品不存在")
+		}
+		return nil, err
+	}
+	if !isAdmin && product.SellerID != userID {
+		return nil, fmt.Errorf("无权限操作该商品")
+	}
+
+	revisions, err := s.revisionRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.ProductRevisionDTO, 0, len(revisions))
+	for i := range revisions {
+		var prev *model.ProductRevision
+		if i > 0 {
+			prev = &revisions[i-1]
+		}
+		result = append(result, toRevisionDTO(&revisions[i], prev))
+	}
+	return result, nil
+}
+
+// RevertToRevision 管理员将商品回滚到指定版本的内容
+// 回滚本身会产生一个新版本，原有历史保持不变；商品状态不随回滚改变
+func (s *ProductService) RevertToRevision(ctx context.Context, adminID, productID int64, version int) (*model.Product, error) {
+	if s.productRepo == nil || s.revisionRepo == nil {
+		return nil, fmt.Errorf("服务未初始化")
+	}
+
+	target, err := s.revisionRepo.GetByVersion(ctx, productID, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("版本不存在")
+		}
+		return nil, err
+	}
+
+	product, images, tagIDs, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("商品不存在")
+		}
+		return nil, err
+	}
+	before := model.NewProductRevision(product, images, tagIDs, product.SellerID, model.RevisionReasonInitial)
+
+	product.Title = target.Title
+	product.Description = target.Description
+	product.Price = target.Price
+	product.CategoryID = target.CategoryID
+	product.ConditionID = target.ConditionID
+	tagIDs = target.TagIDList()
+	product.Attributes = model.EncodeAttributeValues(target.AttributeMap())
+
+	if urls := target.ImageURLList(); len(urls) > 0 {
+		images = make([]model.ProductImage, 0, len(urls))
+		for i, url := range urls {
+			images = append(images, model.ProductImage{
+				ProductID: product.ID,
+				URL:       url,
+				SortOrder: i + 1,
+				IsPrimary: i == 0,
+			})
+		}
+		product.MainImageURL = images[0].URL
+	}
+
+	if err := s.productRepo.Update(ctx, product, images, tagIDs, true, s.revisionHook(product, before, adminID, model.RevisionReasonRevert)); err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		_ = s.cache.Delete(ctx, buildDetailCacheKey(ctx, product.ID))
+	}
+
+	return product, nil
+}
+
+// GetPriceHistory 获取商品价格变化历史（公开接口）
+// 商品按当前学校查询，其他学校或不存在的商品返回“商品不存在”；已下架商品仅卖家本人与管理员可查看
+func (s *ProductService) GetPriceHistory(ctx context.Context, productID int64, viewerID *int64, isAdmin bool) ([]model.PricePoint, error) {
+	if s.productRepo == nil {
+		return nil, fmt.Errorf("服务未初始化")
+	}
+
+	product, _, _, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("商品不存在")
+		}
+		return nil, err
+	}
+	viewerIsSeller := viewerID != nil && *viewerID == product.SellerID
+	if product.Status == "Delisted" && !viewerIsSeller && !isAdmin {
+		return nil, fmt.Errorf("商品不存在")
+	}
+
+	return s.priceHistory(ctx, product.ID)
+}
+
+// priceHistory 读取商品价格历史，调用方负责校验商品可见性
+// 仅保留价格发生变化的版本，按时间升序返回
+func (s *ProductService) priceHistory(ctx context.Context, productID int64) ([]model.PricePoint, error) {
+	if s.revisionRepo == nil {
+		return []model.PricePoint{}, nil
+	}
+
+	revisions, err := s.revisionRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]model.PricePoint, 0, len(revisions))
+	for _, rev := range revisions {
+		if n := len(points); n > 0 && points[n-1].Price == rev.Price {
+			continue
+		}
+		points = append(points, model.PricePoint{Price: rev.Price, ChangedAt: rev.CreatedAt})
+	}
+	return points, nil
+}
+
+// priceDropOf 计算最近一次调价的降价金额，未降价返回0
+func priceDropOf(points []model.PricePoint) float64 {
+	n := len(points)
+	if n < 2 || points[n-2].Price <= points[n-1].Price {
+		return 0
+	}
+	return math.Round((points[n-2].Price-points[n-1].Price)*100) / 100
+}
+
+// toRevisionDTO 转换为修订记录DTO，并计算与上一版本的差异
+func toRevisionDTO(rev, prev *model.ProductRevision) model.ProductRevisionDTO {
+	dto := model.ProductRevisionDTO{
+		Version:     rev.Version,
+		EditorID:    rev.EditorID,
+		Reason:      rev.Reason,
+		Title:       rev.Title,
+		Description: rev.Description,
+		Price:       rev.Price,
+		CategoryID:  rev.CategoryID,
+		ConditionID: rev.ConditionID,
+		ImageURLs:   rev.ImageURLList(),
+		TagIDs:      rev.TagIDList(),
+		Attributes:  rev.AttributeMap(),
+		Changes:     []model.RevisionFieldChange{},
+		CreatedAt:   rev.CreatedAt,
+	}
+	if prev == nil {
+		return dto
+	}
+
+	addChange := func(field string, before, after interface{}) {
+		dto.Changes = append(dto.Changes, model.RevisionFieldChange{Field: field, Before: before, After: after})
+	}
+	if prev.Title != rev.Title {
+		addChange("title", prev.Title, rev.Title)
+	}
+	if prev.Description != rev.Description {
+		addChange("description", prev.Description, rev.Description)
+	}
+	if prev.Price != rev.Price {
+		addChange("price", prev.Price, rev.Price)
+	}
+	if prev.CategoryID != rev.CategoryID {
+		addChange("categoryId", prev.CategoryID, rev.CategoryID)
+	}
+	if prev.ConditionID != rev.ConditionID {
+		addChange("conditionId", prev.ConditionID, rev.ConditionID)
+	}
+	if prevURLs := prev.ImageURLList(); fmt.Sprint(prevURLs) != fmt.Sprint(dto.ImageURLs) {
+		addChange("imageUrls", prevURLs, dto.ImageURLs)
+	}
+	if prevTags := prev.TagIDList(); fmt.Sprint(prevTags) != fmt.Sprint(dto.TagIDs) {
+		addChange("tagIds", prevTags, dto.TagIDs)
+	}
+	if prevAttrs := prev.AttributeMap(); model.EncodeAttributeValues(prevAttrs) != model.EncodeAttributeValues(dto.Attributes) {
+		addChange("attributes", prevAttrs, dto.Attributes)
+	}
+	return dto
+}
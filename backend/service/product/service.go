@@ -13,6 +13,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cache"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/common/util"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
@@ -20,10 +21,24 @@ import (
 
 // ProductService 商品服务结构体
 type ProductService struct {
-	productRepo repository.ProductRepository
-	userRepo    repository.UserRepository
-	db          *gorm.DB
-	cache       *cache.MemoryCache
+	productRepo   repository.ProductRepository
+	userRepo      repository.UserRepository
+	revisionRepo  repository.ProductRevisionRepository
+	attributeRepo repository.CategoryAttributeRepository
+	bookRepo      repository.BookRepository
+	tagRepo       repository.TagRepository
+	campusRepo    repository.CampusRepository
+	postingGuard  PostingGuard
+	db            *gorm.DB
+	cache         *cache.MemoryCache
+}
+
+// PostingGuard 发布限制检查（配额与屏蔽词），由 posting 服务实现
+type PostingGuard interface {
+	CheckCreate(ctx context.Context, user *model.User) error
+	CheckCreateTx(tx *gorm.DB, user *model.User) error
+	CheckRelist(ctx context.Context, user *model.User) error
+	CheckContent(ctx context.Context, title, description string) error
 }
 
 // NewProductService 创建商品服务实例
@@ -31,13 +46,25 @@ func NewProductService(
 	db *gorm.DB,
 	productRepo repository.ProductRepository,
 	userRepo repository.UserRepository,
+	revisionRepo repository.ProductRevisionRepository,
+	attributeRepo repository.CategoryAttributeRepository,
+	bookRepo repository.BookRepository,
+	tagRepo repository.TagRepository,
+	campusRepo repository.CampusRepository,
+	postingGuard PostingGuard,
 	cache *cache.MemoryCache,
 ) *ProductService {
 	return &ProductService{
-		productRepo: productRepo,
-		userRepo:    userRepo,
-		db:          db,
-		cache:       cache,
+		productRepo:   productRepo,
+		userRepo:      userRepo,
+		revisionRepo:  revisionRepo,
+		attributeRepo: attributeRepo,
+		bookRepo:      bookRepo,
+		tagRepo:       tagRepo,
+		campusRepo:    campusRepo,
+		postingGuard:  postingGuard,
+		db:            db,
+		cache:         cache,
 	}
 }
 
@@ -49,7 +76,15 @@ type CreateProductRequest struct {
 	ConditionID int64
 	CategoryID  int64
 	TagIDs      []int64
-	Images      []*multipart.FileHeader
+	// ProposedTags 卖家提议的新标签名称，不存在的名称以待审核状态挂到商品上
+	ProposedTags []string
+	// Attributes 分类属性值，按分类属性定义校验
+	Attributes map[string]interface{}
+	// ISBN 教材ISBN（可选），图书目录中存在时自动补全标题、描述与属性
+	ISBN string
+	// MeetupPointID 面交地点（可选），0表示不指定
+	MeetupPointID int64
+	Images        []*multipart.FileHeader
 	// PrimaryImageIndex 前端标记的主图索引，可为空
 	PrimaryImageIndex *int
 }
@@ -64,6 +99,12 @@ type UpdateProductRequest struct {
 	// 前端以逗号分隔字符串传递
 	TagIDs    string   `json:"tagIds"`
 	ImageURLs []string `json:"imageUrls"`
+	// Attributes 分类属性值，为空表示不修改；提供时整体替换
+	Attributes map[string]interface{} `json:"attributes"`
+	// ISBN 为空表示不修改，空字符串表示清除
+	ISBN *string `json:"isbn"`
+	// MeetupPointID 为空表示不修改，0表示清除
+	MeetupPointID *int64 `json:"meetupPointId"`
 }
 
 // SearchRequest 搜索请求
@@ -72,6 +113,7 @@ type SearchRequest struct {
 	PriceMin    *float64
 	PriceMax    *float64
 	ConditionID *int64
+	ZoneID      *int64
 	Page        int
 	PageSize    int
 }
@@ -85,9 +127,13 @@ type SearchParams struct {
 	ConditionIDs []int64
 	CategoryID   *int64
 	TagID        *int64
-	Sort         string
-	Page         int
-	PageSize     int
+	// ZoneID 面交地点所在的校园片区
+	ZoneID *int64
+	// AttributeFilters 属性筛选表达式，如 storage>=128
+	AttributeFilters []string
+	Sort             string
+	Page             int
+	PageSize         int
 }
 
 type statusChangeRecord struct {
@@ -125,11 +171,58 @@ func (s *ProductService) CreateProduct(ctx context.Context, userID int64, req *C
 		return nil, fmt.Errorf("请先完善微信号")
 	}
 
+	// 预先检查发布配额（在售数量、每日发布数量），避免超限时仍保存图片；并发发布在插入事务内复核
+	if s.postingGuard != nil {
+		if err := s.postingGuard.CheckCreate(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
 	// 至少一张图片
 	if len(req.Images) == 0 {
 		return nil, fmt.Errorf("请至少上传一张图片")
 	}
 
+	// 校验分类属性
+	schema, err := s.loadAttributeSchema(ctx, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按ISBN从图书目录补全信息
+	isbn, book, err := s.resolveBook(ctx, req.ISBN)
+	if err != nil {
+		return nil, err
+	}
+	if book != nil {
+		applyBookDefaults(req, *isbn, book, schema)
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		return nil, fmt.Errorf("标题不能为空")
+	}
+
+	// 屏蔽词检查（含ISBN补全后的标题与描述）
+	if s.postingGuard != nil {
+		if err := s.postingGuard.CheckContent(ctx, req.Title, req.Description); err != nil {
+			return nil, err
+		}
+	}
+
+	attributes, err := validateAttributes(schema, req.Attributes, false)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDs, err := s.resolveProposedTags(ctx, req.TagIDs, req.ProposedTags, req.CategoryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	meetupPointID, err := s.resolveMeetupPoint(ctx, req.MeetupPointID)
+	if err != nil {
+		return nil, err
+	}
+
 	primaryIndex := 0
 	if req.PrimaryImageIndex != nil && *req.PrimaryImageIndex >= 0 && *req.PrimaryImageIndex < len(req.Images) {
 		primaryIndex = *req.PrimaryImageIndex
@@ -156,26 +249,35 @@ func (s *ProductService) CreateProduct(ctx context.Context, userID int64, req *C
 	}
 
 	product := &model.Product{
-		Title:        req.Title,
-		Description:  req.Description,
-		Price:        req.Price,
-		CategoryID:   req.CategoryID,
-		ConditionID:  req.ConditionID,
-		SellerID:     userID,
-		Status:       "ForSale",
-		MainImageURL: images[primaryIndex].URL,
-	}
-
-	if _, err := s.productRepo.Create(ctx, product, images, req.TagIDs); err != nil {
+		Title:         req.Title,
+		Description:   req.Description,
+		Price:         req.Price,
+		CategoryID:    req.CategoryID,
+		ConditionID:   req.ConditionID,
+		SellerID:      userID,
+		Status:        "ForSale",
+		MainImageURL:  images[primaryIndex].URL,
+		Attributes:    model.EncodeAttributeValues(attributes),
+		ISBN:          isbn,
+		MeetupPointID: meetupPointID,
+	}
+
+	// 同一事务内锁定用户复核配额，并记录初始版本作为后续修订与价格历史的起点
+	var quotaHook repository.TxHook
+	if s.postingGuard != nil {
+		quotaHook = func(tx *gorm.DB) error { return s.postingGuard.CheckCreateTx(tx, user) }
+	}
+	hook := repository.ChainTxHooks(quotaHook, s.revisionHook(product, nil, userID, model.RevisionReasonCreate))
+	if _, err := s.productRepo.Create(ctx, product, images, tagIDs, hook); err != nil {
 		return nil, err
 	}
 
 	// 确保响应包含主图
 	product.MainImageURL = images[primaryIndex].URL
 
-	dto, err := s.buildDetailDTO(ctx, product, images, req.TagIDs, &userID)
+	dto, err := s.buildDetailDTO(ctx, product, images, tagIDs, &userID)
 	if err == nil && s.cache != nil {
-		_ = s.cache.Set(ctx, buildDetailCacheKey(product.ID), dto, detailCacheTTL)
+		_ = s.cache.Set(ctx, buildDetailCacheKey(ctx, product.ID), dto, detailCacheTTL)
 	}
 
 	return product, nil
@@ -203,17 +305,42 @@ func (s *ProductService) UpdateProduct(ctx context.Context, userID, productID in
 		return nil, fmt.Errorf("已售出的商品不能修改")
 	}
 
+	// 编辑前的快照，用于补录基线版本
+	before := model.NewProductRevision(product, images, tagIDs, product.SellerID, model.RevisionReasonInitial)
+
 	if req.Title != nil {
 		product.Title = strings.TrimSpace(*req.Title)
 	}
 	if req.Description != nil {
 		product.Description = strings.TrimSpace(*req.Description)
 	}
+	// 修改了标题或描述时重新检查屏蔽词（管理员编辑不受限制）
+	if s.postingGuard != nil && !isAdmin && (req.Title != nil || req.Description != nil) {
+		if err := s.postingGuard.CheckContent(ctx, product.Title, product.Description); err != nil {
+			return nil, err
+		}
+	}
 	if req.Price != nil && *req.Price > 0 {
 		product.Price = *req.Price
 	}
-	if req.CategoryID != nil && *req.CategoryID > 0 {
+	if req.ISBN != nil {
+		isbn, _, err := s.resolveBook(ctx, *req.ISBN)
+		if err != nil {
+			return nil, err
+		}
+		product.ISBN = isbn
+	}
+	if req.MeetupPointID != nil {
+		meetupPointID, err := s.resolveMeetupPoint(ctx, *req.MeetupPointID)
+		if err != nil {
+			return nil, err
+		}
+		product.MeetupPointID = meetupPointID
+	}
+	categoryChanged := false
+	if req.CategoryID != nil && *req.CategoryID > 0 && *req.CategoryID != product.CategoryID {
 		product.CategoryID = *req.CategoryID
+		categoryChanged = true
 	}
 	if req.ConditionID != nil && *req.ConditionID > 0 {
 		product.ConditionID = *req.ConditionID
@@ -223,6 +350,24 @@ func (s *ProductService) UpdateProduct(ctx context.Context, userID, productID in
 		tagIDs = parseTagIDs(req.TagIDs)
 	}
 
+	// 提交了属性或更换了分类时按新分类的属性定义校验
+	// 仅更换分类时沿用原属性值，新分类未定义的属性被丢弃
+	if req.Attributes != nil || categoryChanged {
+		schema, err := s.loadAttributeSchema(ctx, product.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		values, dropUnknown := req.Attributes, false
+		if values == nil {
+			values, dropUnknown = product.AttributeMap(), true
+		}
+		attributes, err := validateAttributes(schema, values, dropUnknown)
+		if err != nil {
+			return nil, err
+		}
+		product.Attributes = model.EncodeAttributeValues(attributes)
+	}
+
 	if len(req.ImageURLs) > 0 {
 		images = make([]model.ProductImage, 0, len(req.ImageURLs))
 		for i, url := range req.ImageURLs {
@@ -236,13 +381,17 @@ func (s *ProductService) UpdateProduct(ctx context.Context, userID, productID in
 		product.MainImageURL = images[0].URL
 	}
 
-	if err := s.productRepo.Update(ctx, product, images, tagIDs, isAdmin); err != nil {
+	reason := model.RevisionReasonEdit
+	if isAdmin {
+		reason = model.RevisionReasonAdminEdit
+	}
+	if err := s.productRepo.Update(ctx, product, images, tagIDs, isAdmin, s.revisionHook(product, before, userID, reason)); err != nil {
 		return nil, err
 	}
 
 	// 更新成功后清理详情缓存，避免返回旧数据
 	if s.cache != nil {
-		_ = s.cache.Delete(ctx, buildDetailCacheKey(product.ID))
+		_ = s.cache.Delete(ctx, buildDetailCacheKey(ctx, product.ID))
 	}
 
 	return product, nil
@@ -278,6 +427,9 @@ func (s *ProductService) ChangeStatus(ctx context.Context, userID, productID int
 		if fromStatus != "Delisted" {
 			return fmt.Errorf("状态不匹配，无法重新上架")
 		}
+		if err := s.checkRelistQuota(ctx, userID); err != nil {
+			return err
+		}
 		toStatus = "ForSale"
 	case "sold":
 		if fromStatus != "ForSale" {
@@ -304,6 +456,18 @@ func (s *ProductService) ChangeStatus(ctx context.Context, userID, productID int
 	return nil
 }
 
+// checkRelistQuota 重新上架会增加在售数量，需检查在售配额
+func (s *ProductService) checkRelistQuota(ctx context.Context, userID int64) error {
+	if s.postingGuard == nil {
+		return nil
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("获取用户信息失败: %w", err)
+	}
+	return s.postingGuard.CheckRelist(ctx, user)
+}
+
 // UndoLastStatusChange 撤销状态变更
 func (s *ProductService) UndoLastStatusChange(ctx context.Context, userID, productID int64) error {
 	if s.productRepo == nil || s.cache == nil {
@@ -344,6 +508,40 @@ func (s *ProductService) UndoLastStatusChange(ctx context.Context, userID, produ
 	return nil
 }
 
+// BumpProduct 擦亮在售商品，重新计算自动过期时间
+func (s *ProductService) BumpProduct(ctx context.Context, userID, productID int64) error {
+	if s.productRepo == nil {
+		return fmt.Errorf("服务未初始化")
+	}
+
+	product, _, _, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("商品不存在")
+		}
+		return err
+	}
+
+	if product.SellerID != userID {
+		return fmt.Errorf("无权限操作该商品")
+	}
+	if product.Status != "ForSale" {
+		return fmt.Errorf("仅在售商品可以擦亮，已下架的商品请重新上架")
+	}
+
+	if err := s.productRepo.Renew(ctx, productID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("仅在售商品可以擦亮，已下架的商品请重新上架")
+		}
+		return err
+	}
+
+	if s.cache != nil {
+		_ = s.cache.Delete(ctx, buildDetailCacheKey(ctx, productID))
+	}
+	return nil
+}
+
 // GetProductDetail 获取商品详情
 func (s *ProductService) GetProductDetail(ctx context.Context, productID int64, viewerID *int64) (*model.ProductDetailDTO, error) {
 	if s.productRepo == nil || s.userRepo == nil {
@@ -351,7 +549,7 @@ func (s *ProductService) GetProductDetail(ctx context.Context, productID int64,
 	}
 
 	if s.cache != nil {
-		if val, err := s.cache.Get(ctx, buildDetailCacheKey(productID)); err == nil {
+		if val, err := s.cache.Get(ctx, buildDetailCacheKey(ctx, productID)); err == nil {
 			if dto, ok := val.(*model.ProductDetailDTO); ok && dto != nil {
 				return dto, nil
 			}
@@ -368,7 +566,7 @@ func (s *ProductService) GetProductDetail(ctx context.Context, productID int64,
 
 	dto, err := s.buildDetailDTO(ctx, product, images, tagIDs, viewerID)
 	if err == nil && s.cache != nil {
-		_ = s.cache.Set(ctx, buildDetailCacheKey(productID), dto, detailCacheTTL)
+		_ = s.cache.Set(ctx, buildDetailCacheKey(ctx, productID), dto, detailCacheTTL)
 	}
 
 	return dto, nil
@@ -471,6 +669,7 @@ func (s *ProductService) SearchProducts(ctx context.Context, params *SearchReque
 	if params.ConditionID != nil {
 		searchParams.ConditionID = params.ConditionID
 	}
+	searchParams.ZoneID = params.ZoneID
 
 	return s.Search(ctx, searchParams)
 }
@@ -481,15 +680,25 @@ func (s *ProductService) Search(ctx context.Context, params *SearchParams) ([]mo
 		return nil, 0, fmt.Errorf("服务未初始化")
 	}
 
+	attributeFilters, err := s.parseAttributeFilters(ctx, valueOrZeroInt64(params.CategoryID), params.AttributeFilters)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	repoParams := repository.SearchParams{
-		Keyword:      params.Keyword,
-		Page:         params.Page,
-		PageSize:     params.PageSize,
-		PriceMin:     valueOrZero(params.MinPrice),
-		PriceMax:     valueOrZero(params.MaxPrice),
-		ConditionID:  valueOrZeroInt64(params.ConditionID),
-		ConditionIDs: params.ConditionIDs,
-		Sort:         params.Sort,
+		Keyword:          params.Keyword,
+		Page:             params.Page,
+		PageSize:         params.PageSize,
+		PriceMin:         valueOrZero(params.MinPrice),
+		PriceMax:         valueOrZero(params.MaxPrice),
+		ConditionID:      valueOrZeroInt64(params.ConditionID),
+		ConditionIDs:     params.ConditionIDs,
+		CategoryID:       valueOrZeroInt64(params.CategoryID),
+		TagID:            valueOrZeroInt64(params.TagID),
+		ZoneID:           valueOrZeroInt64(params.ZoneID),
+		CourseCode:       util.NormalizeCourseCode(params.Keyword),
+		AttributeFilters: attributeFilters,
+		Sort:             params.Sort,
 	}
 
 	products, total, err := s.productRepo.Search(ctx, repoParams)
@@ -524,6 +733,7 @@ func (s *ProductService) GetProductsByCategory(ctx context.Context, categoryID i
 	if params.ConditionID != nil {
 		searchParams.ConditionID = params.ConditionID
 	}
+	searchParams.ZoneID = params.ZoneID
 	searchParams.CategoryID = &categoryID
 
 	return s.ListByCategory(ctx, categoryID, searchParams)
@@ -535,15 +745,25 @@ func (s *ProductService) ListByCategory(ctx context.Context, categoryID int64, p
 		return nil, 0, fmt.Errorf("服务未初始化")
 	}
 
+	attributeFilters, err := s.parseAttributeFilters(ctx, valueOrZeroInt64(params.CategoryID), params.AttributeFilters)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	repoParams := repository.SearchParams{
-		Keyword:      params.Keyword,
-		Page:         params.Page,
-		PageSize:     params.PageSize,
-		PriceMin:     valueOrZero(params.MinPrice),
-		PriceMax:     valueOrZero(params.MaxPrice),
-		ConditionID:  valueOrZeroInt64(params.ConditionID),
-		ConditionIDs: params.ConditionIDs,
-		Sort:         params.Sort,
+		Keyword:          params.Keyword,
+		Page:             params.Page,
+		PageSize:         params.PageSize,
+		PriceMin:         valueOrZero(params.MinPrice),
+		PriceMax:         valueOrZero(params.MaxPrice),
+		ConditionID:      valueOrZeroInt64(params.ConditionID),
+		ConditionIDs:     params.ConditionIDs,
+		CategoryID:       valueOrZeroInt64(params.CategoryID),
+		TagID:            valueOrZeroInt64(params.TagID),
+		ZoneID:           valueOrZeroInt64(params.ZoneID),
+		CourseCode:       util.NormalizeCourseCode(params.Keyword),
+		AttributeFilters: attributeFilters,
+		Sort:             params.Sort,
 	}
 
 	products, total, err := s.productRepo.ListByCategory(ctx, categoryID, repoParams)
@@ -756,8 +976,9 @@ func buildStatusCacheKey(productID int64) string {
 	return fmt.Sprintf("product:status:%d:last", productID)
 }
 
-func buildDetailCacheKey(productID int64) string {
-	return fmt.Sprintf("product:detail:%d", productID)
+// buildDetailCacheKey 商品详情缓存键，包含学校ID，避免跨学校命中缓存
+func buildDetailCacheKey(ctx context.Context, productID int64) string {
+	return fmt.Sprintf("product:detail:%d:%d", tenant.SchoolID(ctx), productID)
 }
 
 func valueOrZero(val *float64) float64 {
@@ -805,17 +1026,18 @@ func (s *ProductService) toCardDTO(ctx context.Context, p *model.Product) (model
 	}
 
 	return model.ProductCardDTO{
-		ID:          p.ID,
-		Title:       p.Title,
-		Price:       p.Price,
-		MainImage:   main,
-		Status:      p.Status,
-		SellerID:    p.SellerID,
-		CategoryID:  p.CategoryID,
-		ConditionID: p.ConditionID,
-		Description: p.Description,
-		CreatedAt:   p.CreatedAt,
-		UpdatedAt:   p.UpdatedAt,
+		ID:            p.ID,
+		Title:         p.Title,
+		Price:         p.Price,
+		MainImage:     main,
+		Status:        p.Status,
+		SellerID:      p.SellerID,
+		CategoryID:    p.CategoryID,
+		ConditionID:   p.ConditionID,
+		Description:   p.Description,
+		MeetupPointID: p.MeetupPointID,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
 	}, nil
 }
 
@@ -842,6 +1064,16 @@ func (s *ProductService) buildDetailDTO(ctx context.Context, product *model.Prod
 
 	viewerIsSeller := viewerID != nil && *viewerID == product.SellerID
 
+	// 查询价格历史（失败仅记录日志，不影响详情展示）
+	priceHistory, err := s.priceHistory(ctx, product.ID)
+	if err != nil {
+		log.Printf("warn: get price history failed for product %d: %v", product.ID, err)
+		priceHistory = nil
+	}
+	if len(priceHistory) < 2 {
+		priceHistory = nil
+	}
+
 	// 计算主图
 	mainImage := product.MainImageURL
 	if mainImage == "" && len(images) > 0 {
@@ -867,11 +1099,17 @@ func (s *ProductService) buildDetailDTO(ctx context.Context, product *model.Prod
 		MainImageURL:   mainImage,
 		Images:         images,
 		TagIDs:         tagIDs,
+		Attributes:     product.AttributeMap(),
+		ISBN:           product.ISBN,
+		Book:           s.lookupBookDTO(ctx, product.ISBN),
+		MeetupPoint:    s.lookupMeetupPointDTO(ctx, product.MeetupPointID),
 		Seller:         model.SellerInfo{ID: seller.ID, Nickname: seller.Nickname, AvatarUrl: seller.AvatarUrl},
 		ViewerIsSeller: viewerIsSeller,
 		Status:         product.Status,
 		CreatedAt:      product.CreatedAt,
 		UpdatedAt:      product.UpdatedAt,
 		SellerWechat:   pickSellerWechat(seller.WechatID, viewerIsSeller),
+		PriceHistory:   priceHistory,
+		PriceDrop:      priceDropOf(priceHistory),
 	}, nil
 }
@@ -0,0 +1,66 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxProposedTags 发布商品时允许提议的新标签数量
+	maxProposedTags = 5
+	// maxProposedTagLength 提议标签名称的最大长度（字符数）
+	maxProposedTagLength = 20
+)
+
+// normalizeProposedTags 清理卖家提议的标签名称：去除空白、不区分大小写去重并校验数量与长度
+func normalizeProposedTags(names []string) ([]string, error) {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, raw := range names {
+		name := strings.Join(strings.Fields(raw), " ")
+		if name == "" {
+			continue
+		}
+		if len([]rune(name)) > maxProposedTagLength {
+			return nil, fmt.Errorf("标签「%s」不能超过%d个字符", name, maxProposedTagLength)
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+	if len(result) > maxProposedTags {
+		return nil, fmt.Errorf("最多只能提议%d个新标签", maxProposedTags)
+	}
+	return result, nil
+}
+
+// resolveProposedTags 将提议的标签名称解析为标签ID并与已选标签合并去重
+// 已有标签（含别名）直接复用，新名称创建为待审核标签，待管理员审核后对外展示
+func (s *ProductService) resolveProposedTags(ctx context.Context, tagIDs []int64, names []string, categoryID, userID int64) ([]int64, error) {
+	names, err := normalizeProposedTags(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 || s.tagRepo == nil {
+		return tagIDs, nil
+	}
+
+	proposedIDs, err := s.tagRepo.ResolveProposed(ctx, names, categoryID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("处理提议标签失败: %w", err)
+	}
+
+	result := make([]int64, 0, len(tagIDs)+len(proposedIDs))
+	seen := make(map[int64]bool, len(tagIDs)+len(proposedIDs))
+	for _, id := range append(append([]int64{}, tagIDs...), proposedIDs...) {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
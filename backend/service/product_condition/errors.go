@@ -0,0 +1,18 @@
+package productcondition
+
+import "errors"
+
+// 错误码定义
+const (
+	ErrCodeConditionInUse = 4007 // 新旧程度被商品引用，无法删除
+)
+
+// 错误定义
+var (
+	ErrConditionInUse      = errors.New("该新旧程度已被商品使用，无法删除")
+	ErrConditionNotFound   = errors.New("新旧程度不存在")
+	ErrConditionCodeExists = errors.New("新旧程度编码已存在")
+	ErrConditionNameExists = errors.New("新旧程度名称已存在")
+	ErrInvalidCondition    = errors.New("无效的新旧程度参数")
+	ErrInvalidReorder      = errors.New("排序列表必须恰好包含全部新旧程度")
+)
@@ -2,36 +2,229 @@ package productcondition
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cache"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
 )
 
+const (
+	// conditionListCacheKey 新旧程度列表缓存键
+	conditionListCacheKey = "product_conditions:all"
+	// conditionListCacheTTL 列表缓存时间，任何新旧程度变更都会立即失效
+	conditionListCacheTTL = 30 * time.Minute
+	// maxConditionNameLength 名称最大长度（字符数）
+	maxConditionNameLength = 50
+)
+
+// conditionCodePattern 编码格式：大写字母开头，仅含大写字母、数字和下划线
+var conditionCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,31}$`)
+
 // Service 新旧程度服务接口
 type Service interface {
 	// ListProductConditions 获取所有新旧程度
 	ListProductConditions(ctx context.Context) ([]*model.ProductCondition, error)
+	// CreateProductCondition 创建新旧程度，排在现有项之后
+	CreateProductCondition(ctx context.Context, code, name string) (*model.ProductCondition, error)
+	// UpdateProductCondition 修改新旧程度名称
+	UpdateProductCondition(ctx context.Context, id int64, name string) (*model.ProductCondition, error)
+	// DeleteProductCondition 删除新旧程度，被商品引用时拒绝删除
+	DeleteProductCondition(ctx context.Context, id int64) error
+	// ReorderProductConditions 按给定顺序重排全部新旧程度
+	ReorderProductConditions(ctx context.Context, orderedIDs []int64) error
 }
 
 type service struct {
-	repo repository.ProductConditionRepository
+	repo  repository.ProductConditionRepository
+	cache *cache.MemoryCache
 }
 
 // NewService 创建服务实例
-func NewService(repo repository.ProductConditionRepository) Service {
-	return &service{repo: repo}
+func NewService(repo repository.ProductConditionRepository, cache *cache.MemoryCache) Service {
+	return &service{repo: repo, cache: cache}
 }
 
 // ListProductConditions 获取所有新旧程度
+// 列表优先从缓存读取，返回副本以免调用方修改缓存内容
 func (s *service) ListProductConditions(ctx context.Context) ([]*model.ProductCondition, error) {
-	conditions, err := s.repo.ListAll(ctx)
+	conditions, err := s.loadConditions(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	result := make([]*model.ProductCondition, len(conditions))
 	for i := range conditions {
-		result[i] = &conditions[i]
+		condition := conditions[i]
+		result[i] = &condition
 	}
 	return result, nil
 }
+
+// CreateProductCondition 创建新旧程度
+func (s *service) CreateProductCondition(ctx context.Context, code, name string) (*model.ProductCondition, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !conditionCodePattern.MatchString(code) {
+		return nil, fmt.Errorf("%w: 编码需以大写字母开头，仅含大写字母、数字和下划线", ErrInvalidCondition)
+	}
+	name, err := normalizeConditionName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureUnique(ctx, code, name, 0); err != nil {
+		return nil, err
+	}
+
+	sortOrder, err := s.repo.NextSortOrder(ctx)
+	if err != nil {
+		return nil, err
+	}
+	condition := &model.ProductCondition{Code: code, Name: name, SortOrder: sortOrder}
+	if err := s.repo.Create(ctx, condition); err != nil {
+		return nil, err
+	}
+	s.invalidateCache(ctx)
+	return condition, nil
+}
+
+// UpdateProductCondition 修改新旧程度名称，编码作为对外标识创建后不可修改
+func (s *service) UpdateProductCondition(ctx context.Context, id int64, name string) (*model.ProductCondition, error) {
+	condition, err := s.getCondition(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	name, err = normalizeConditionName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureUnique(ctx, condition.Code, name, id); err != nil {
+		return nil, err
+	}
+
+	condition.Name = name
+	if err := s.repo.Update(ctx, condition); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrConditionNotFound
+		}
+		return nil, err
+	}
+	s.invalidateCache(ctx)
+	return s.repo.GetByID(ctx, id)
+}
+
+// DeleteProductCondition 删除新旧程度，删除前检查商品引用
+func (s *service) DeleteProductCondition(ctx context.Context, id int64) error {
+	if _, err := s.getCondition(ctx, id); err != nil {
+		return err
+	}
+	count, err := s.repo.CountProducts(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 如果有关联的商品，返回错误码4007
+	if count > 0 {
+		return ErrConditionInUse
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidateCache(ctx)
+	return nil
+}
+
+// ReorderProductConditions 按给定顺序重排全部新旧程度
+func (s *service) ReorderProductConditions(ctx context.Context, orderedIDs []int64) error {
+	conditions, err := s.repo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	if len(orderedIDs) != len(conditions) {
+		return ErrInvalidReorder
+	}
+	existing := make(map[int64]bool, len(conditions))
+	for _, c := range conditions {
+		existing[c.ID] = true
+	}
+	seen := make(map[int64]bool, len(orderedIDs))
+	for _, id := range orderedIDs {
+		if !existing[id] || seen[id] {
+			return ErrInvalidReorder
+		}
+		seen[id] = true
+	}
+
+	if err := s.repo.Reorder(ctx, orderedIDs); err != nil {
+		return err
+	}
+	s.invalidateCache(ctx)
+	return nil
+}
+
+// loadConditions 读取新旧程度列表，缓存未命中时查询数据库并写入缓存
+func (s *service) loadConditions(ctx context.Context) ([]model.ProductCondition, error) {
+	if s.cache != nil {
+		if cached, err := s.cache.Get(ctx, conditionListCacheKey); err == nil {
+			if conditions, ok := cached.([]model.ProductCondition); ok {
+				return conditions, nil
+			}
+		}
+	}
+
+	conditions, err := s.repo.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.cache != nil {
+		_ = s.cache.Set(ctx, conditionListCacheKey, conditions, conditionListCacheTTL)
+	}
+	return conditions, nil
+}
+
+// invalidateCache 使列表缓存失效
+func (s *service) invalidateCache(ctx context.Context) {
+	if s.cache != nil {
+		_ = s.cache.Delete(ctx, conditionListCacheKey)
+	}
+}
+
+// getCondition 获取新旧程度，不存在时返回 ErrConditionNotFound
+func (s *service) getCondition(ctx context.Context, id int64) (*model.ProductCondition, error) {
+	condition, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrConditionNotFound
+		}
+		return nil, err
+	}
+	return condition, nil
+}
+
+// ensureUnique 校验编码与名称未被其他新旧程度使用
+func (s *service) ensureUnique(ctx context.Context, code, name string, excludeID int64) error {
+	codeExists, nameExists, err := s.repo.ExistsCodeOrName(ctx, code, name, excludeID)
+	if err != nil {
+		return err
+	}
+	if codeExists {
+		return ErrConditionCodeExists
+	}
+	if nameExists {
+		return ErrConditionNameExists
+	}
+	return nil
+}
+
+// normalizeConditionName 去除名称首尾空白并校验长度
+func normalizeConditionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxConditionNameLength {
+		return "", fmt.Errorf("%w: 名称长度需为1-%d个字符", ErrInvalidCondition, maxConditionNameLength)
+	}
+	return name, nil
+}
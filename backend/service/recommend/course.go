@@ -0,0 +1,107 @@
+package recommend
+
+import (
+	"context"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+const (
+	// maxCourseBooks 课程教材推荐最多返回的图书数
+	maxCourseBooks = 20
+	// listingsPerBook 每本教材附带的在售商品数
+	listingsPerBook = 3
+)
+
+// CourseBookRecommendation 课程教材推荐
+type CourseBookRecommendation struct {
+	CourseCode string                 `json:"courseCode"`
+	Book       model.BookDTO          `json:"book"`
+	Listings   []model.ProductCardDTO `json:"listings"` // 该教材的在售商品，按价格升序
+}
+
+// GetCourseBookRecommendations 根据用户选修的课程推荐教材
+//
+// 功能说明：
+//   - 通过图书与课程的关联找到用户所选课程的教材
+//   - 每本教材附带最多 listingsPerBook 件在售商品（排除用户本人发布），按价格从低到高
+//   - 有在售商品的教材排在前面，同一本书关联多门课程时只出现一次（归入课程代码靠前的课程）
+func (s *RecommendService) GetCourseBookRecommendations(ctx context.Context, userID int64) ([]CourseBookRecommendation, error) {
+	if s.bookRepo == nil {
+		return []CourseBookRecommendation{}, nil
+	}
+
+	courses, err := s.bookRepo.ListUserCourses(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	relations, err := s.bookRepo.ListCoursesByCodes(ctx, courses)
+	if err != nil {
+		return nil, err
+	}
+	if len(relations) == 0 {
+		return []CourseBookRecommendation{}, nil
+	}
+
+	courseOfBook := make(map[int64]string, len(relations))
+	bookIDs := make([]int64, 0, len(relations))
+	for _, rel := range relations {
+		if _, exists := courseOfBook[rel.BookID]; exists {
+			continue
+		}
+		courseOfBook[rel.BookID] = rel.CourseCode
+		bookIDs = append(bookIDs, rel.BookID)
+	}
+
+	books, err := s.bookRepo.ListByIDs(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	bookCourses, err := s.bookRepo.ListCourseCodes(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	isbns := make([]string, 0, len(books))
+	for _, b := range books {
+		isbns = append(isbns, b.ISBN)
+	}
+	var listings []model.Product
+	if err := s.db.WithContext(ctx).
+		Where("school_id = ? AND status = ? AND isbn IN ? AND seller_id <> ?", tenant.SchoolID(ctx), "ForSale", isbns, userID).
+		Order("price ASC, created_at DESC").
+		Find(&listings).Error; err != nil {
+		return nil, err
+	}
+	listingsByISBN := make(map[string][]model.ProductCardDTO)
+	for i := range listings {
+		isbn := *listings[i].ISBN
+		if len(listingsByISBN[isbn]) >= listingsPerBook {
+			continue
+		}
+		listingsByISBN[isbn] = append(listingsByISBN[isbn], s.toProductCardDTO(&listings[i]))
+	}
+
+	withListings := make([]CourseBookRecommendation, 0, len(books))
+	withoutListings := make([]CourseBookRecommendation, 0)
+	for i := range books {
+		rec := CourseBookRecommendation{
+			CourseCode: courseOfBook[books[i].ID],
+			Book:       books[i].ToDTO(bookCourses[books[i].ID]),
+			Listings:   listingsByISBN[books[i].ISBN],
+		}
+		if len(rec.Listings) > 0 {
+			withListings = append(withListings, rec)
+		} else {
+			rec.Listings = []model.ProductCardDTO{}
+			withoutListings = append(withoutListings, rec)
+		}
+	}
+
+	result := append(withListings, withoutListings...)
+	if len(result) > maxCourseBooks {
+		result = result[:maxCourseBooks]
+	}
+	return result, nil
+}
@@ -2,11 +2,13 @@ package recommend
 
 import (
 	"context"
+	"log"
 	"sort"
 	"time"
 
 	"gorm.io/gorm"
 
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/tenant"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
 )
@@ -21,6 +23,8 @@ type RedisClient interface {
 type RecommendService struct {
 	viewRecordRepo repository.ViewRecordRepository
 	productRepo    repository.ProductRepository
+	bookRepo       repository.BookRepository
+	campusRepo     repository.CampusRepository
 	db             *gorm.DB
 	redis          RedisClient // 使用接口，可选
 }
@@ -30,12 +34,16 @@ type RecommendService struct {
 func NewRecommendService(
 	viewRecordRepo repository.ViewRecordRepository,
 	productRepo repository.ProductRepository,
+	bookRepo repository.BookRepository,
+	campusRepo repository.CampusRepository,
 	db *gorm.DB,
 	redis RedisClient,
 ) *RecommendService {
 	return &RecommendService{
 		viewRecordRepo: viewRecordRepo,
 		productRepo:    productRepo,
+		bookRepo:       bookRepo,
+		campusRepo:     campusRepo,
 		db:             db,
 		redis:          redis,
 	}
@@ -86,7 +94,7 @@ func (s *RecommendService) calculateRecommendations(ctx context.Context, userID
 	// 3. 过滤已删除/下架的浏览记录，基于仍在售的商品抽取分类
 	var viewedProducts []model.Product
 	if err := s.db.WithContext(ctx).
-		Where("id IN ? AND status = ?", viewedProductIDs, "ForSale").
+		Where("school_id = ? AND id IN ? AND status = ?", tenant.SchoolID(ctx), viewedProductIDs, "ForSale").
 		Find(&viewedProducts).Error; err != nil {
 		return nil, err
 	}
@@ -196,7 +204,7 @@ func (s *RecommendService) getProductsByIDs(ctx context.Context, productIDs []in
 
 	var products []model.Product
 	err := s.db.WithContext(ctx).
-		Where("id IN ? AND status = ?", productIDs, "ForSale").
+		Where("school_id = ? AND id IN ? AND status = ?", tenant.SchoolID(ctx), productIDs, "ForSale").
 		Find(&products).Error
 
 	if err != nil {
@@ -211,10 +219,13 @@ type HomeData struct {
 	Recommendations []model.ProductCardDTO `json:"recommendations"`
 	Latest          []model.ProductCardDTO `json:"latest"`
 	TotalCount      int64                  `json:"totalCount"`
+	// NearbyZoneID 最新列表就近加权所用的校园片区，未加权时为空
+	NearbyZoneID *int64 `json:"nearbyZoneId,omitempty"`
 }
 
 // GetHomeData 获取首页数据
-func (s *RecommendService) GetHomeData(ctx context.Context, userID *int64, page, pageSize int) (*HomeData, error) {
+// zoneID 为请求指定的校园片区；未指定时使用登录用户的默认片区，该片区面交的商品在最新列表中适当靠前
+func (s *RecommendService) GetHomeData(ctx context.Context, userID *int64, zoneID *int64, page, pageSize int) (*HomeData, error) {
 	const maxRecommendations = 4
 
 	var recommendations []model.Product
@@ -247,7 +258,8 @@ func (s *RecommendService) GetHomeData(ctx context.Context, userID *int64, page,
 	for id := range recommendIDSet {
 		excludeIDs = append(excludeIDs, id)
 	}
-	latestProducts, total, err := s.productRepo.ListLatestForSale(ctx, excludeIDs, page, pageSize)
+	nearbyZoneID := s.resolveNearbyZone(ctx, userID, zoneID)
+	latestProducts, total, err := s.productRepo.ListLatestForSale(ctx, excludeIDs, valueOrZero(nearbyZoneID), page, pageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -307,9 +319,35 @@ func (s *RecommendService) GetHomeData(ctx context.Context, userID *int64, page,
 		Recommendations: recommendDTOs,
 		Latest:          latestDTOs,
 		TotalCount:      adjustedTotal,
+		NearbyZoneID:    nearbyZoneID,
 	}, nil
 }
 
+// resolveNearbyZone 确定首页就近加权使用的片区
+// 查询默认片区失败时不加权，不影响首页展示
+func (s *RecommendService) resolveNearbyZone(ctx context.Context, userID *int64, zoneID *int64) *int64 {
+	if zoneID != nil && *zoneID > 0 {
+		return zoneID
+	}
+	if userID == nil || s.campusRepo == nil {
+		return nil
+	}
+	defaultZoneID, err := s.campusRepo.GetUserDefaultZone(ctx, *userID)
+	if err != nil {
+		log.Printf("warn: get default zone failed for user %d: %v", *userID, err)
+		return nil
+	}
+	return defaultZoneID
+}
+
+// valueOrZero 返回指针指向的值，nil 时返回0
+func valueOrZero(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
 // fetchCategoryProducts 获取某分类的在售商品，排除已浏览和本人发布，按创建时间倒序
 func (s *RecommendService) fetchCategoryProducts(ctx context.Context, categoryID int64, userID int64, excludeIDs []int64, limit int) ([]model.Product, error) {
 	if limit <= 0 {
@@ -317,7 +355,7 @@ func (s *RecommendService) fetchCategoryProducts(ctx context.Context, categoryID
 	}
 
 	query := s.db.WithContext(ctx).
-		Where("status = ? AND category_id = ?", "ForSale", categoryID).
+		Where("school_id = ? AND status = ? AND category_id = ?", tenant.SchoolID(ctx), "ForSale", categoryID).
 		Where("seller_id <> ?", userID)
 
 	if len(excludeIDs) > 0 {
@@ -343,17 +381,18 @@ func (s *RecommendService) toProductCardDTO(product *model.Product) model.Produc
 		Scan(&mainImage)
 
 	return model.ProductCardDTO{
-		ID:          product.ID,
-		Title:       product.Title,
-		Price:       product.Price,
-		MainImage:   mainImage,
-		Status:      product.Status,
-		SellerID:    product.SellerID,
-		CategoryID:  product.CategoryID,
-		ConditionID: product.ConditionID,
-		Description: product.Description,
-		CreatedAt:   product.CreatedAt,
-		UpdatedAt:   product.UpdatedAt,
+		ID:            product.ID,
+		Title:         product.Title,
+		Price:         product.Price,
+		MainImage:     mainImage,
+		Status:        product.Status,
+		SellerID:      product.SellerID,
+		CategoryID:    product.CategoryID,
+		ConditionID:   product.ConditionID,
+		Description:   product.Description,
+		MeetupPointID: product.MeetupPointID,
+		CreatedAt:     product.CreatedAt,
+		UpdatedAt:     product.UpdatedAt,
 	}
 }
 
@@ -399,7 +438,7 @@ func (s *RecommendService) GetRecentViewsWithProducts(ctx context.Context, userI
 	// 获取商品信息
 	var products []model.Product
 	err = s.db.WithContext(ctx).
-		Where("id IN ?", productIDs).
+		Where("school_id = ? AND id IN ?", tenant.SchoolID(ctx), productIDs).
 		Find(&products).Error
 
 	if err != nil {
@@ -0,0 +1,9 @@
+package scheduler
+
+import "errors"
+
+// 错误定义
+var (
+	ErrJobNotFound      = errors.New("任务不存在")
+	ErrSchedulerStarted = errors.New("调度器已启动，不能再注册任务")
+)
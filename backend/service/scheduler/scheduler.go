@@ -0,0 +1,378 @@
+// Package scheduler 实现进程内的定时任务调度器
+//
+// 任务在代码中注册（名称、cron 计划、重试次数与处理函数），启动时同步到 scheduled_jobs 表。
+// 调度器定期轮询到期任务，通过数据库行锁与执行锁（locked_until）保证多实例部署时同一任务只在一个实例上运行；
+// 执行失败按指数退避重试，每次执行写入 job_runs 表，管理员可查看执行记录并手动触发。
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cron"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+const (
+	defaultPollInterval = 15 * time.Second
+	defaultTimeout      = 10 * time.Minute
+	defaultRetryDelay   = time.Minute
+	maxRetryDelay       = time.Hour
+	// lockGrace 执行锁在超时时间之外的余量，避免任务刚好超时时被其他实例接管
+	lockGrace = time.Minute
+	// finishTimeout 记录执行结果的超时时间（调度器停止后仍需写入结果）
+	finishTimeout = 10 * time.Second
+	// maxErrorLength 执行记录中保存的错误信息长度上限
+	maxErrorLength = 2000
+)
+
+// Handler 任务处理函数，需响应 ctx 取消（调度器停止或执行超时）
+type Handler func(ctx context.Context) error
+
+// JobSpec 任务定义
+type JobSpec struct {
+	Name        string
+	Description string
+	Schedule    string        // cron 表达式，见 common/cron
+	MaxRetries  int           // 失败后最多重试次数
+	RetryDelay  time.Duration // 首次重试的等待时间，之后每次翻倍（不超过1小时）；为 0 时为1分钟
+	Timeout     time.Duration // 单次执行超时，为 0 时为10分钟
+	Handler     Handler
+}
+
+// Config 调度器配置
+type Config struct {
+	PollInterval time.Duration // 轮询间隔，为 0 时为15秒
+	Instance     string        // 实例标识，为空时使用主机名与进程号
+}
+
+// JobStatus 任务状态
+type JobStatus struct {
+	model.ScheduledJob
+	Registered bool `json:"registered"` // 当前实例是否注册了该任务（已从代码中移除的任务为 false）
+	Running    bool `json:"running"`    // 当前实例是否正在执行该任务
+}
+
+// registeredJob 已注册的任务
+type registeredJob struct {
+	spec     JobSpec
+	schedule cron.Schedule
+}
+
+// Scheduler 定时任务调度器
+type Scheduler struct {
+	repo repository.JobRepository
+	cfg  Config
+
+	mu       sync.Mutex
+	jobs     map[string]*registeredJob
+	running  map[string]bool
+	started  bool
+	cancel   context.CancelFunc
+	loopDone chan struct{}
+	wake     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewScheduler 创建调度器
+func NewScheduler(repo repository.JobRepository, cfg Config) *Scheduler {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.Instance == "" {
+		host, _ := os.Hostname()
+		cfg.Instance = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	return &Scheduler{
+		repo:    repo,
+		cfg:     cfg,
+		jobs:    make(map[string]*registeredJob),
+		running: make(map[string]bool),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Register 注册任务，需在 Start 之前调用
+func (s *Scheduler) Register(spec JobSpec) error {
+	if spec.Name == "" || len(spec.Name) > 64 {
+		return fmt.Errorf("invalid job name %q", spec.Name)
+	}
+	if spec.Handler == nil {
+		return fmt.Errorf("job %s: handler is required", spec.Name)
+	}
+	if spec.MaxRetries < 0 {
+		return fmt.Errorf("job %s: max retries must not be negative", spec.Name)
+	}
+	schedule, err := cron.Parse(spec.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", spec.Name, err)
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("job %s: schedule %q never fires", spec.Name, spec.Schedule)
+	}
+	if spec.RetryDelay <= 0 {
+		spec.RetryDelay = defaultRetryDelay
+	}
+	if spec.Timeout <= 0 {
+		spec.Timeout = defaultTimeout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return ErrSchedulerStarted
+	}
+	if _, exists := s.jobs[spec.Name]; exists {
+		return fmt.Errorf("job %s already registered", spec.Name)
+	}
+	s.jobs[spec.Name] = &registeredJob{spec: spec, schedule: schedule}
+	return nil
+}
+
+// Start 将已注册的任务同步到数据库并开始轮询
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return nil
+	}
+
+	now := time.Now()
+	for _, name := range s.jobNames() {
+		job := s.jobs[name]
+		if err := s.repo.Sync(ctx, &model.ScheduledJob{
+			Name:        name,
+			Description: job.spec.Description,
+			Schedule:    job.spec.Schedule,
+			MaxRetries:  job.spec.MaxRetries,
+			NextRunAt:   job.schedule.Next(now),
+		}); err != nil {
+			return fmt.Errorf("sync job %s: %w", name, err)
+		}
+	}
+
+	s.started = true
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.loopDone = make(chan struct{})
+	go s.loop(ctx)
+	log.Printf("scheduler: started %d job(s) on instance %s", len(s.jobs), s.cfg.Instance)
+	return nil
+}
+
+// Stop 停止轮询，取消正在执行的任务并等待其返回（执行结果仍会被记录）
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel, loopDone := s.cancel, s.loopDone
+	s.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-loopDone
+	s.wg.Wait()
+}
+
+// ListJobs 获取全部任务及其最近一次执行状态
+func (s *Scheduler) ListJobs(ctx context.Context) ([]JobStatus, error) {
+	jobs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]JobStatus, 0, len(jobs))
+	for _, job := range jobs {
+		_, registered := s.jobs[job.Name]
+		result = append(result, JobStatus{ScheduledJob: job, Registered: registered, Running: s.running[job.Name]})
+	}
+	return result, nil
+}
+
+// ListRuns 获取任务最近的执行记录
+func (s *Scheduler) ListRuns(ctx context.Context, name string, limit int) ([]model.JobRun, error) {
+	if !s.isRegistered(name) {
+		return nil, ErrJobNotFound
+	}
+	runs, err := s.repo.ListRuns(ctx, name, limit)
+	if err != nil {
+		return nil, err
+	}
+	if runs == nil {
+		runs = []model.JobRun{}
+	}
+	return runs, nil
+}
+
+// Trigger 请求立即执行任务，由下一次轮询（可能在其他实例上）执行
+func (s *Scheduler) Trigger(ctx context.Context, name string, userID int64) error {
+	if !s.isRegistered(name) {
+		return ErrJobNotFound
+	}
+	if err := s.repo.RequestRun(ctx, name, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 尚未同步到数据库（没有实例启用调度器）
+			return ErrJobNotFound
+		}
+		return err
+	}
+	// 唤醒本实例的轮询，不必等待下一个轮询周期
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// PruneRuns 删除早于 retention 的执行记录
+func (s *Scheduler) PruneRuns(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.PruneRuns(ctx, time.Now().Add(-retention))
+}
+
+// loop 轮询到期任务
+func (s *Scheduler) loop(ctx context.Context) {
+	defer close(s.loopDone)
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.poll(ctx)
+		select {
+		case <-ticker.C:
+		case <-s.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll 尝试获取每个到期任务的执行锁，获取成功的任务在独立的 goroutine 中执行
+func (s *Scheduler) poll(ctx context.Context) {
+	for _, name := range s.idleJobNames() {
+		if ctx.Err() != nil {
+			return
+		}
+		job := s.jobs[name]
+		now := time.Now()
+		run, err := s.repo.Claim(ctx, name, s.cfg.Instance, now, now.Add(job.spec.Timeout+lockGrace))
+		if err != nil {
+			log.Printf("scheduler: claim job %s failed: %v", name, err)
+			continue
+		}
+		if run == nil {
+			continue
+		}
+
+		s.mu.Lock()
+		s.running[name] = true
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.execute(ctx, job, run)
+	}
+}
+
+// execute 执行任务并记录结果
+func (s *Scheduler) execute(ctx context.Context, job *registeredJob, run *model.JobRun) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.spec.Name)
+		s.mu.Unlock()
+	}()
+
+	runCtx, cancel := context.WithTimeout(ctx, job.spec.Timeout)
+	err := invoke(runCtx, job.spec.Handler)
+	cancel()
+
+	finished := time.Now()
+	result := repository.JobResult{
+		Status:     model.JobStatusSucceeded,
+		FinishedAt: finished,
+		DurationMs: finished.Sub(run.StartedAt).Milliseconds(),
+		NextRunAt:  job.schedule.Next(finished),
+	}
+	if err != nil {
+		msg := err.Error()
+		if len(msg) > maxErrorLength {
+			msg = msg[:maxErrorLength]
+		}
+		result.Status = model.JobStatusFailed
+		result.Error = &msg
+		if run.Attempt < job.spec.MaxRetries {
+			result.Attempt = run.Attempt + 1
+			if retryAt := finished.Add(retryDelay(job.spec.RetryDelay, run.Attempt)); retryAt.Before(result.NextRunAt) {
+				result.NextRunAt = retryAt
+			}
+		}
+		log.Printf("scheduler: job %s failed (attempt %d/%d): %v", job.spec.Name, run.Attempt, job.spec.MaxRetries, err)
+	}
+
+	finishCtx, cancelFinish := context.WithTimeout(context.Background(), finishTimeout)
+	defer cancelFinish()
+	if err := s.repo.Finish(finishCtx, run, result); err != nil {
+		log.Printf("scheduler: record result of job %s failed: %v", job.spec.Name, err)
+	}
+}
+
+// invoke 调用处理函数，panic 视为执行失败
+func invoke(ctx context.Context, handler Handler) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return handler(ctx)
+}
+
+// retryDelay 第 attempt 次失败后的重试等待时间：base * 2^attempt，不超过 maxRetryDelay
+func retryDelay(base time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
+// isRegistered 当前实例是否注册了该任务
+func (s *Scheduler) isRegistered(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.jobs[name]
+	return ok
+}
+
+// jobNames 按名称排序的任务列表，调用方需持有锁
+func (s *Scheduler) jobNames() []string {
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// idleJobNames 当前实例未在执行的任务
+func (s *Scheduler) idleJobNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.jobs))
+	for _, name := range s.jobNames() {
+		if !s.running[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
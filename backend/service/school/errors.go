@@ -0,0 +1,10 @@
+package school
+
+import "errors"
+
+// 错误定义
+var (
+	ErrSchoolNotFound   = errors.New("学校不存在")
+	ErrSchoolCodeExists = errors.New("学校编码已存在")
+	ErrInvalidSchool    = errors.New("无效的学校参数")
+)
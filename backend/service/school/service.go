@@ -0,0 +1,107 @@
+package school
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
+)
+
+// maxNameLength 学校名称最大长度（字符数）
+const maxNameLength = 100
+
+// codePattern 学校编码格式，与数据库 CHECK 约束一致，同时可用作子域名
+var codePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// SchoolInput 创建或修改学校的参数
+type SchoolInput struct {
+	Code     string // 仅创建时有效，创建后不可修改
+	Name     string
+	IsActive bool
+}
+
+// Service 学校（租户）服务接口
+type Service interface {
+	// ListActive 获取启用的学校，供注册与切换学校使用
+	ListActive(ctx context.Context) ([]model.School, error)
+	// ListAll 获取全部学校（超级管理员）
+	ListAll(ctx context.Context) ([]model.School, error)
+	Create(ctx context.Context, input SchoolInput) (*model.School, error)
+	// Update 修改学校名称与启用状态，停用后该学校的请求将被拒绝
+	Update(ctx context.Context, id int64, input SchoolInput) (*model.School, error)
+}
+
+type service struct {
+	repo repository.SchoolRepository
+}
+
+// NewService 创建服务实例
+func NewService(repo repository.SchoolRepository) Service {
+	return &service{repo: repo}
+}
+
+// ListActive 获取启用的学校
+func (s *service) ListActive(ctx context.Context) ([]model.School, error) {
+	return s.repo.List(ctx, true)
+}
+
+// ListAll 获取全部学校
+func (s *service) ListAll(ctx context.Context) ([]model.School, error) {
+	return s.repo.List(ctx, false)
+}
+
+// Create 创建学校
+func (s *service) Create(ctx context.Context, input SchoolInput) (*model.School, error) {
+	code := strings.ToLower(strings.TrimSpace(input.Code))
+	if !codePattern.MatchString(code) {
+		return nil, fmt.Errorf("%w: 编码需为1-32位小写字母、数字或连字符，且不能以连字符开头", ErrInvalidSchool)
+	}
+	name, err := normalizeName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetByCode(ctx, code); err == nil {
+		return nil, ErrSchoolCodeExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	school := &model.School{Code: code, Name: name, IsActive: input.IsActive}
+	if err := s.repo.Create(ctx, school); err != nil {
+		return nil, err
+	}
+	return school, nil
+}
+
+// Update 修改学校
+func (s *service) Update(ctx context.Context, id int64, input SchoolInput) (*model.School, error) {
+	name, err := normalizeName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	school := &model.School{ID: id, Name: name, IsActive: input.IsActive}
+	if err := s.repo.Update(ctx, school); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSchoolNotFound
+		}
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// normalizeName 去除名称首尾空白并校验长度
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxNameLength {
+		return "", fmt.Errorf("%w: 名称长度需为1-%d个字符", ErrInvalidSchool, maxNameLength)
+	}
+	return name, nil
+}
@@ -4,10 +4,18 @@ import "errors"
 
 // 错误码定义
 const (
-	ErrCodeTagHasProducts = 4002 // 标签下有商品，无法删除
+	ErrCodeTagHasProducts  = 4002 // 标签下有商品，无法删除
+	ErrCodeTagNameConflict = 4005 // 标签名称或别名与已有标签冲突
+	ErrCodeTagStatus       = 4006 // 标签当前审核状态不允许该操作
 )
 
 // 错误定义
 var (
-	ErrTagHasProducts = errors.New("tag has products, cannot delete")
+	ErrTagHasProducts     = errors.New("tag has products, cannot delete")
+	ErrTagNotFound        = errors.New("标签不存在")
+	ErrTagNameConflict    = errors.New("名称与已有标签或其别名冲突")
+	ErrInvalidTag         = errors.New("无效的标签参数")
+	ErrCategoryNotFound   = errors.New("分类不存在")
+	ErrInvalidMergeTarget = errors.New("不能将标签合并到自身")
+	ErrTagStatus          = errors.New("标签状态不允许该操作")
 )
@@ -2,21 +2,45 @@ package tag
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
 
 	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
 )
 
+const (
+	// MaxTagAliases 单个标签允许的别名数量上限
+	MaxTagAliases = 20
+	// maxTagNameLength 标签名称与别名的最大长度（字符数）
+	maxTagNameLength = 50
+)
+
 // TagService 标签服务接口
 type TagService interface {
-	// ListTags 获取所有标签，供前台使用
-	ListTags(ctx context.Context) ([]*model.Tag, error)
+	// ListTags 获取标签，供前台使用；categoryID 大于0时仅返回适用于该分类的标签
+	ListTags(ctx context.Context, categoryID int64) ([]*model.Tag, error)
 	// CreateTag 创建标签
 	CreateTag(ctx context.Context, tag *model.Tag) error
 	// UpdateTag 更新标签
 	UpdateTag(ctx context.Context, tag *model.Tag) error
 	// DeleteTag 删除标签，删除前检查引用
 	DeleteTag(ctx context.Context, id int64) error
+	// SetAliases 替换标签的同义词
+	SetAliases(ctx context.Context, id int64, aliases []string) (*model.Tag, error)
+	// MergeTags 将源标签合并到目标标签，返回合并后的目标标签
+	MergeTags(ctx context.Context, sourceID, targetID int64) (*model.Tag, error)
+	// ListReviewTags 分页获取指定状态的标签及使用次数，供管理端审核
+	ListReviewTags(ctx context.Context, status string, page, pageSize int) ([]model.Tag, int64, error)
+	// ApproveTag 通过待审核或已驳回的标签
+	ApproveTag(ctx context.Context, id int64) (*model.Tag, error)
+	// RejectTag 驳回待审核标签，并移除其商品关联
+	RejectTag(ctx context.Context, id int64) error
+	// MapTag 将待审核标签映射到已有标签，提议的名称成为目标标签的别名
+	MapTag(ctx context.Context, id, targetID int64) (*model.Tag, error)
 }
 
 // tagService 标签服务实现
@@ -31,9 +55,17 @@ func NewTagService(tagRepo repository.TagRepository) TagService {
 	}
 }
 
-// ListTags 获取所有标签
-func (s *tagService) ListTags(ctx context.Context) ([]*model.Tag, error) {
-	tags, err := s.tagRepo.ListAll(ctx)
+// ListTags 获取标签
+func (s *tagService) ListTags(ctx context.Context, categoryID int64) ([]*model.Tag, error) {
+	var (
+		tags []model.Tag
+		err  error
+	)
+	if categoryID > 0 {
+		tags, err = s.tagRepo.ListByCategory(ctx, categoryID)
+	} else {
+		tags, err = s.tagRepo.ListAll(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -47,16 +79,43 @@ func (s *tagService) ListTags(ctx context.Context) ([]*model.Tag, error) {
 
 // CreateTag 创建标签
 func (s *tagService) CreateTag(ctx context.Context, tag *model.Tag) error {
-	return s.tagRepo.Create(ctx, tag)
+	if err := s.prepareTag(ctx, tag); err != nil {
+		return err
+	}
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return err
+	}
+	tag.Aliases = []string{}
+	return nil
 }
 
 // UpdateTag 更新标签
 func (s *tagService) UpdateTag(ctx context.Context, tag *model.Tag) error {
-	return s.tagRepo.Update(ctx, tag)
+	if _, err := s.getTag(ctx, tag.ID); err != nil {
+		return err
+	}
+	if err := s.prepareTag(ctx, tag); err != nil {
+		return err
+	}
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTagNotFound
+		}
+		return err
+	}
+	updated, err := s.tagRepo.GetByID(ctx, tag.ID)
+	if err != nil {
+		return err
+	}
+	*tag = *updated
+	return nil
 }
 
 // DeleteTag 删除标签，删除前检查引用
 func (s *tagService) DeleteTag(ctx context.Context, id int64) error {
+	if _, err := s.getTag(ctx, id); err != nil {
+		return err
+	}
 	// 检查是否有关联的商品
 	count, err := s.tagRepo.CountProductsByTag(ctx, id)
 	if err != nil {
@@ -68,3 +127,208 @@ func (s *tagService) DeleteTag(ctx context.Context, id int64) error {
 	}
 	return s.tagRepo.Delete(ctx, id)
 }
+
+// SetAliases 替换标签的同义词
+// 别名不区分大小写去重，不能与本标签名称相同，也不能与其他标签的名称或别名相同
+func (s *tagService) SetAliases(ctx context.Context, id int64, aliases []string) (*model.Tag, error) {
+	tag, err := s.getTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// 别名参与搜索，只允许挂在已通过的标签上
+	if tag.Status != model.TagStatusApproved {
+		return nil, fmt.Errorf("%w: 只能为已通过的标签设置别名", ErrTagStatus)
+	}
+
+	normalized := make([]string, 0, len(aliases))
+	seen := make(map[string]bool, len(aliases))
+	for _, raw := range aliases {
+		alias := strings.TrimSpace(raw)
+		if alias == "" {
+			continue
+		}
+		if len([]rune(alias)) > maxTagNameLength {
+			return nil, fmt.Errorf("%w: 别名不能超过%d个字符", ErrInvalidTag, maxTagNameLength)
+		}
+		key := strings.ToLower(alias)
+		if seen[key] || key == strings.ToLower(tag.Name) {
+			continue
+		}
+		seen[key] = true
+		if err := s.ensureNameAvailable(ctx, alias, id); err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, alias)
+	}
+	if len(normalized) > MaxTagAliases {
+		return nil, fmt.Errorf("%w: 每个标签最多设置%d个别名", ErrInvalidTag, MaxTagAliases)
+	}
+
+	if err := s.tagRepo.ReplaceAliases(ctx, id, normalized); err != nil {
+		return nil, err
+	}
+	return s.tagRepo.GetByID(ctx, id)
+}
+
+// MergeTags 将源标签合并到目标标签
+// 源标签的商品、分类关联与别名全部并入目标标签，源标签名称成为目标标签的别名，随后删除源标签
+func (s *tagService) MergeTags(ctx context.Context, sourceID, targetID int64) (*model.Tag, error) {
+	if sourceID == targetID {
+		return nil, ErrInvalidMergeTarget
+	}
+	if _, err := s.getTag(ctx, sourceID); err != nil {
+		return nil, err
+	}
+	target, err := s.getTag(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Status != model.TagStatusApproved {
+		return nil, fmt.Errorf("%w: 只能合并到已通过的标签", ErrTagStatus)
+	}
+	if err := s.tagRepo.Merge(ctx, sourceID, targetID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return s.tagRepo.GetByID(ctx, targetID)
+}
+
+// ListReviewTags 分页获取指定状态的标签及使用次数，状态为空时默认查询待审核标签
+func (s *tagService) ListReviewTags(ctx context.Context, status string, page, pageSize int) ([]model.Tag, int64, error) {
+	switch status {
+	case "":
+		status = model.TagStatusPending
+	case model.TagStatusPending, model.TagStatusApproved, model.TagStatusRejected:
+	default:
+		return nil, 0, fmt.Errorf("%w: 未知的标签状态 %s", ErrInvalidTag, status)
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.tagRepo.ListWithUsage(ctx, status, page, pageSize)
+}
+
+// ApproveTag 通过待审核或已驳回的标签
+// 已驳回的标签在驳回时已移除商品关联，重新通过后仅恢复为可选标签
+func (s *tagService) ApproveTag(ctx context.Context, id int64) (*model.Tag, error) {
+	tag, err := s.getTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Status == model.TagStatusApproved {
+		return nil, fmt.Errorf("%w: 标签已通过审核", ErrTagStatus)
+	}
+	if err := s.tagRepo.UpdateStatus(ctx, id, tag.Status, model.TagStatusApproved); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: 标签状态已变更，请刷新后重试", ErrTagStatus)
+		}
+		return nil, err
+	}
+	return s.tagRepo.GetByID(ctx, id)
+}
+
+// RejectTag 驳回待审核标签
+func (s *tagService) RejectTag(ctx context.Context, id int64) error {
+	if _, err := s.getPendingTag(ctx, id); err != nil {
+		return err
+	}
+	if err := s.tagRepo.Reject(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: 标签状态已变更，请刷新后重试", ErrTagStatus)
+		}
+		return err
+	}
+	return nil
+}
+
+// MapTag 将待审核标签映射到已有标签
+// 复用合并逻辑：商品改挂到目标标签，提议的名称成为目标标签的别名，之后同名提议会直接命中目标标签
+func (s *tagService) MapTag(ctx context.Context, id, targetID int64) (*model.Tag, error) {
+	if _, err := s.getPendingTag(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.MergeTags(ctx, id, targetID)
+}
+
+// getPendingTag 获取待审核标签
+func (s *tagService) getPendingTag(ctx context.Context, id int64) (*model.Tag, error) {
+	tag, err := s.getTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Status != model.TagStatusPending {
+		return nil, fmt.Errorf("%w: 标签不是待审核状态", ErrTagStatus)
+	}
+	return tag, nil
+}
+
+// getTag 获取标签，不存在时返回 ErrTagNotFound
+func (s *tagService) getTag(ctx context.Context, id int64) (*model.Tag, error) {
+	tag, err := s.tagRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+
+// prepareTag 校验并规范化标签名称与分类
+// 主分类为空时取分类列表的第一个，主分类总是包含在分类关联中
+func (s *tagService) prepareTag(ctx context.Context, tag *model.Tag) error {
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" || len([]rune(tag.Name)) > maxTagNameLength {
+		return fmt.Errorf("%w: 标签名称长度需为1-%d个字符", ErrInvalidTag, maxTagNameLength)
+	}
+	if err := s.ensureNameAvailable(ctx, tag.Name, tag.ID); err != nil {
+		return err
+	}
+
+	if tag.CategoryID <= 0 && len(tag.CategoryIDs) > 0 {
+		tag.CategoryID = tag.CategoryIDs[0]
+	}
+	if tag.CategoryID <= 0 {
+		return fmt.Errorf("%w: 标签至少需要关联一个分类", ErrInvalidTag)
+	}
+	categoryIDs := []int64{tag.CategoryID}
+	seen := map[int64]bool{tag.CategoryID: true}
+	for _, id := range tag.CategoryIDs {
+		if id <= 0 {
+			return fmt.Errorf("%w: 无效的分类ID", ErrInvalidTag)
+		}
+		if !seen[id] {
+			seen[id] = true
+			categoryIDs = append(categoryIDs, id)
+		}
+	}
+	count, err := s.tagRepo.CountCategories(ctx, categoryIDs)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(categoryIDs)) {
+		return ErrCategoryNotFound
+	}
+	tag.CategoryIDs = categoryIDs
+	return nil
+}
+
+// ensureNameAvailable 检查名称是否已被其他标签用作名称或别名
+func (s *tagService) ensureNameAvailable(ctx context.Context, name string, selfID int64) error {
+	existing, err := s.tagRepo.FindByNameOrAlias(ctx, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != selfID {
+		return fmt.Errorf("%w: %s", ErrTagNameConflict, name)
+	}
+	return nil
+}
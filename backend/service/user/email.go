@@ -0,0 +1,195 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/auth"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/mailer"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+const (
+	// emailResendInterval 两次发送验证邮件的最小间隔
+	emailResendInterval = time.Minute
+	// maxEmailLength 邮箱地址最大长度
+	maxEmailLength = 255
+)
+
+// EmailVerificationConfig 校园邮箱验证配置
+type EmailVerificationConfig struct {
+	// Required 为 true 时注册必须填写邮箱，且未验证用户不能发布商品或查看联系方式
+	Required bool
+	// AllowedDomains 允许的邮箱域名，子域名同样允许；为空时不限制域名
+	AllowedDomains []string
+	// TTL 验证链接有效期
+	TTL time.Duration
+	// LinkBaseURL 前端地址，验证链接为 {LinkBaseURL}/verify-email?token=...
+	LinkBaseURL string
+	// ResetTTL 找回密码链接有效期，链接为 {LinkBaseURL}/reset-password?token=...
+	ResetTTL time.Duration
+}
+
+// SetEmail 设置或修改当前用户的邮箱并发送验证邮件
+// 修改后邮箱变为未验证状态，此前发出的验证链接随之失效
+func (s *UserService) SetEmail(ctx context.Context, userID uint, email string) (*UserResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewUserNotFoundError()
+		}
+		return nil, err
+	}
+
+	normalized, err := s.checkEmailAvailable(ctx, email, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Email != nil && strings.EqualFold(*user.Email, normalized) && user.EmailVerifiedAt != nil {
+		return nil, ErrEmailAlreadyVerified
+	}
+
+	if err := s.userRepo.UpdateEmail(ctx, user.ID, normalized); err != nil {
+		return nil, err
+	}
+	user.Email = &normalized
+	user.EmailVerifiedAt = nil
+
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		return nil, fmt.Errorf("发送验证邮件失败: %w", err)
+	}
+
+	response := s.buildUserResponse(user)
+	return &response, nil
+}
+
+// ResendVerificationEmail 重新发送验证邮件，同一用户每分钟最多发送一次
+func (s *UserService) ResendVerificationEmail(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.GetByID(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewUserNotFoundError()
+		}
+		return err
+	}
+	if user.Email == nil {
+		return ErrEmailNotSet
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+	if user.EmailSentAt != nil && time.Since(*user.EmailSentAt) < emailResendInterval {
+		return ErrEmailResendTooSoon
+	}
+
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		return fmt.Errorf("发送验证邮件失败: %w", err)
+	}
+	return nil
+}
+
+// VerifyEmail 校验验证令牌并标记邮箱已验证
+// 令牌中的邮箱与用户当前邮箱不一致（已修改邮箱）时视为无效
+func (s *UserService) VerifyEmail(ctx context.Context, token string) (*UserResponse, error) {
+	userID, email, err := auth.ParseEmailVerifyToken(strings.TrimSpace(token))
+	if err != nil {
+		return nil, ErrInvalidEmailToken
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, userID, email); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidEmailToken
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := s.buildUserResponse(user)
+	return &response, nil
+}
+
+// checkEmailAvailable 校验邮箱格式与域名，并确认未被其他用户使用，返回规范化（小写）后的邮箱
+func (s *UserService) checkEmailAvailable(ctx context.Context, email string, selfID int64) (string, error) {
+	normalized, err := s.normalizeEmail(email)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := s.userRepo.GetByEmail(ctx, normalized)
+	if err == nil && existing.ID != selfID {
+		return "", ErrEmailExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	return normalized, nil
+}
+
+// normalizeEmail 校验邮箱格式并检查域名是否在允许列表中
+func (s *UserService) normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", ErrEmailRequired
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > maxEmailLength {
+		return "", ErrEmailFormat
+	}
+
+	if len(s.emailCfg.AllowedDomains) == 0 {
+		return email, nil
+	}
+	domain := email[strings.LastIndex(email, "@")+1:]
+	for _, allowed := range s.emailCfg.AllowedDomains {
+		if domain == allowed || strings.HasSuffix(domain, "."+allowed) {
+			return email, nil
+		}
+	}
+	return "", ErrEmailDomain
+}
+
+// sendVerificationEmail 生成验证令牌并发送验证邮件
+func (s *UserService) sendVerificationEmail(ctx context.Context, user *model.User) error {
+	if s.mailer == nil || user.Email == nil {
+		return nil
+	}
+
+	ttl := s.emailCfg.TTL
+	if ttl <= 0 {
+		ttl = 30 * time.Minute
+	}
+	token, err := auth.GenerateEmailVerifyToken(user.ID, *user.Email, ttl)
+	if err != nil {
+		return err
+	}
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.emailCfg.LinkBaseURL, url.QueryEscape(token))
+
+	body := fmt.Sprintf(`%s，你好：
+
+请点击以下链接验证你的校园邮箱（%d 分钟内有效）：
+%s
+
+如果链接无法打开，也可以在验证页面粘贴以下验证码：
+%s
+
+如非本人操作，请忽略此邮件。`, user.Nickname, int(ttl.Minutes()), link, token)
+
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      *user.Email,
+		Subject: "校园二手交易平台邮箱验证",
+		Body:    body,
+	}); err != nil {
+		return err
+	}
+	return s.userRepo.TouchEmailSent(ctx, user.ID)
+}
@@ -1,6 +1,12 @@
 package user
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/loginguard"
+)
 
 // Error codes for user service
 const (
@@ -46,6 +52,29 @@ var (
 	ErrInvalidCredentials = errors.New("账号或密码不正确")
 	ErrInvalidOldPassword = errors.New("原密码错误")
 	ErrWechatIDFormat     = errors.New("微信号必须为 4-64 个字符，且只可包含字母、数字、下划线或连字符")
+
+	ErrEmailRequired        = errors.New("请填写校园邮箱")
+	ErrEmailFormat          = errors.New("邮箱格式不正确")
+	ErrEmailDomain          = errors.New("仅支持使用学校邮箱注册")
+	ErrEmailExists          = errors.New("该邮箱已被其他账号使用")
+	ErrEmailNotSet          = errors.New("请先设置邮箱")
+	ErrEmailAlreadyVerified = errors.New("邮箱已验证")
+	ErrEmailResendTooSoon   = errors.New("验证邮件发送过于频繁，请稍后再试")
+	ErrInvalidEmailToken    = errors.New("验证链接无效或已过期，请重新发送验证邮件")
+
+	ErrResetIdentifierRequired = errors.New("请输入账号或邮箱")
+	ErrResetTooFrequent        = errors.New("操作过于频繁，请稍后再试")
+	ErrInvalidResetToken       = errors.New("重置链接无效、已使用或已过期，请重新找回密码")
+
+	ErrTwoFactorAlreadyEnabled   = errors.New("两步验证已启用")
+	ErrTwoFactorNotEnabled       = errors.New("尚未启用两步验证")
+	ErrTwoFactorNotSetup         = errors.New("请先生成两步验证密钥")
+	ErrTwoFactorCodeRequired     = errors.New("请输入验证码")
+	ErrInvalidTwoFactorCode      = errors.New("验证码错误或已使用")
+	ErrTwoFactorTooManyAttempts  = errors.New("验证码错误次数过多，请稍后再试")
+	ErrInvalidTwoFactorChallenge = errors.New("登录验证已过期，请重新登录")
+
+	ErrSessionNotFound = errors.New("登录设备不存在或已退出")
 )
 
 // NewNicknameChangeTooSoonError creates a new error for nickname change too soon
@@ -119,3 +148,40 @@ func NewInvalidOldPasswordError() *ServiceError {
 		Err:     ErrInvalidOldPassword,
 	}
 }
+
+// LoginThrottledError is returned when login attempts are delayed or the account is locked
+// after too many failures; RetryAfter tells the client how long to wait
+type LoginThrottledError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	seconds := e.RetrySeconds()
+	if e.Locked {
+		return fmt.Sprintf("登录失败次数过多，账号已临时锁定，请 %d 分钟后再试", (seconds+59)/60)
+	}
+	return fmt.Sprintf("登录尝试过于频繁，请 %d 秒后再试", seconds)
+}
+
+// RetrySeconds returns the remaining cooldown rounded up to whole seconds
+func (e *LoginThrottledError) RetrySeconds() int {
+	return int((e.RetryAfter + time.Second - 1) / time.Second)
+}
+
+// NewLoginThrottledError creates a LoginThrottledError from the guard's throttle state
+func NewLoginThrottledError(t *loginguard.Throttle) *LoginThrottledError {
+	return &LoginThrottledError{Locked: t.Locked, RetryAfter: t.RetryAfter}
+}
+
+// TwoFactorRequiredError is returned by Login when the password is correct but the account has
+// two-factor authentication enabled; the client submits ChallengeToken with a TOTP or backup code
+// to complete the login
+type TwoFactorRequiredError struct {
+	ChallengeToken string
+	ExpiresIn      time.Duration
+}
+
+func (e *TwoFactorRequiredError) Error() string {
+	return "请输入两步验证码完成登录"
+}
@@ -0,0 +1,153 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/auth"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/mailer"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+// 找回密码频率限制
+const (
+	resetIPLimit       = 10 // 每个 IP 每小时最多发起的找回请求
+	resetAccountLimit  = 3  // 每个账号每小时最多发送的重置邮件
+	resetRequestWindow = time.Hour
+
+	resetAttemptIPLimit = 20 // 每个 IP 每 15 分钟最多提交的重置请求，防止暴力猜测令牌
+	resetAttemptWindow  = 15 * time.Minute
+)
+
+// ForgotPassword 发起找回密码，向账号已验证的邮箱发送一次性重置链接
+//
+// identifier 可以是登录账号或邮箱。为避免泄露账号是否存在，账号不存在、
+// 未绑定已验证邮箱或超出单账号频率限制时同样返回成功，仅 IP 超限时返回错误。
+func (s *UserService) ForgotPassword(ctx context.Context, identifier, clientIP string) error {
+	if !s.limiter.Allow(ctx, "user:pwreset:ip:"+clientIP, resetIPLimit, resetRequestWindow) {
+		return ErrResetTooFrequent
+	}
+
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return ErrResetIdentifierRequired
+	}
+
+	var (
+		user *model.User
+		err  error
+	)
+	if strings.Contains(identifier, "@") {
+		user, err = s.userRepo.GetByEmail(ctx, identifier)
+	} else {
+		user, err = s.userRepo.GetByAccount(ctx, identifier)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user == nil || user.Email == nil || user.EmailVerifiedAt == nil {
+		return nil
+	}
+
+	if !s.limiter.Allow(ctx, fmt.Sprintf("user:pwreset:account:%d", user.ID), resetAccountLimit, resetRequestWindow) {
+		log.Printf("warn: password reset for user %d throttled", user.ID)
+		return nil
+	}
+
+	token, tokenHash, err := newResetToken()
+	if err != nil {
+		return err
+	}
+	ttl := s.emailCfg.ResetTTL
+	if ttl <= 0 {
+		ttl = 30 * time.Minute
+	}
+	if err := s.userRepo.CreatePasswordResetToken(ctx, &model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(ttl),
+		RequestIP: clientIP,
+	}); err != nil {
+		return err
+	}
+
+	if s.mailer == nil {
+		return nil
+	}
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.emailCfg.LinkBaseURL, url.QueryEscape(token))
+	body := fmt.Sprintf(`%s，你好：
+
+我们收到了重置账号 %s 密码的请求。请在 %d 分钟内点击以下链接设置新密码（链接仅可使用一次）：
+%s
+
+重置成功后，所有已登录的设备都需要重新登录。
+如非本人操作，请忽略此邮件，你的密码不会被修改。`, user.Nickname, user.Account, int(ttl.Minutes()), link)
+
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      *user.Email,
+		Subject: "校园二手交易平台找回密码",
+		Body:    body,
+	}); err != nil {
+		return fmt.Errorf("发送重置邮件失败: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword 使用找回密码令牌设置新密码
+// 令牌使用后立即失效，同时作废该用户此前签发的全部登录令牌
+func (s *UserService) ResetPassword(ctx context.Context, token, newPassword, clientIP string) error {
+	if !s.limiter.Allow(ctx, "user:pwreset:attempt:"+clientIP, resetAttemptIPLimit, resetAttemptWindow) {
+		return ErrResetTooFrequent
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrInvalidResetToken
+	}
+	if len(newPassword) < 8 {
+		return NewPasswordTooShortError()
+	}
+
+	newHashedPassword, err := auth.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.userRepo.ResetPasswordByToken(ctx, hashResetToken(token), newHashedPassword); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	return nil
+}
+
+// newResetToken 生成随机重置令牌，返回明文（写入邮件）与哈希（落库）
+func newResetToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashResetToken(token), nil
+}
+
+// hashResetToken 计算令牌的 SHA-256 哈希（十六进制）
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,49 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cache"
+)
+
+// attemptLimiter 基于内存缓存的固定窗口计数器，用于限制找回密码等敏感操作的频率
+type attemptLimiter struct {
+	cache *cache.MemoryCache
+	mu    sync.Mutex
+}
+
+// attemptWindow 窗口内的计数
+type attemptWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// newAttemptLimiter 创建计数器，memCache 为 nil 时不做限制
+func newAttemptLimiter(memCache *cache.MemoryCache) *attemptLimiter {
+	return &attemptLimiter{cache: memCache}
+}
+
+// Allow 记录一次尝试，窗口内超过 limit 次时返回 false
+func (l *attemptLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) bool {
+	if l == nil || l.cache == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	state := attemptWindow{resetAt: now.Add(window)}
+	if value, err := l.cache.Get(ctx, key); err == nil {
+		if existing, ok := value.(attemptWindow); ok && now.Before(existing.resetAt) {
+			state = existing
+		}
+	}
+	if state.count >= limit {
+		return false
+	}
+	state.count++
+	_ = l.cache.Set(ctx, key, state, time.Until(state.resetAt))
+	return true
+}
@@ -2,10 +2,15 @@ package user
 
 import (
 	"context"
+	"log"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/yycy134679/school-secondhand-trading-system/backend/common/auth"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/cache"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/loginguard"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/mailer"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
 	"github.com/yycy134679/school-secondhand-trading-system/backend/repository"
 	"gorm.io/gorm"
@@ -13,13 +18,36 @@ import (
 
 // UserService handles user business logic
 type UserService struct {
-	userRepo repository.UserRepository
+	userRepo        repository.UserRepository
+	twoFactorRepo   repository.TwoFactorRepository
+	sessionRepo     repository.SessionRepository
+	limiter         *attemptLimiter
+	loginGuard      *loginguard.Guard
+	mailer          mailer.Mailer
+	notifier        Notifier
+	emailCfg        EmailVerificationConfig
+	twoFactorIssuer string
 }
 
 // NewUserService creates a new user service instance
-func NewUserService(userRepo repository.UserRepository) *UserService {
+// mail may be nil, in which case verification and reset emails are not sent;
+// memCache may be nil, in which case password reset requests and two-factor codes are not rate limited;
+// loginGuard may be nil, in which case login attempts are not throttled;
+// twoFactorRepo may be nil, in which case two-factor authentication is unavailable;
+// sessionRepo may be nil, in which case tokens are not bound to sessions and logins are not recorded;
+// notifier may be nil, in which case new device logins are not notified;
+// twoFactorIssuer is shown as the account issuer in authenticator apps
+func NewUserService(userRepo repository.UserRepository, twoFactorRepo repository.TwoFactorRepository, sessionRepo repository.SessionRepository, memCache *cache.MemoryCache, loginGuard *loginguard.Guard, mail mailer.Mailer, notifier Notifier, emailCfg EmailVerificationConfig, twoFactorIssuer string) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:        userRepo,
+		twoFactorRepo:   twoFactorRepo,
+		sessionRepo:     sessionRepo,
+		limiter:         newAttemptLimiter(memCache),
+		loginGuard:      loginGuard,
+		mailer:          mail,
+		notifier:        notifier,
+		emailCfg:        emailCfg,
+		twoFactorIssuer: twoFactorIssuer,
 	}
 }
 
@@ -28,6 +56,7 @@ type UserRegisterRequest struct {
 	Account  string  `json:"account"`
 	Nickname string  `json:"nickname"`
 	Password string  `json:"password"`
+	Email    string  `json:"email"`
 	WechatID *string `json:"wechatId,omitempty"`
 }
 
@@ -59,6 +88,17 @@ type UserResponse struct {
 	AvatarUrl string  `json:"avatarUrl"`
 	IsAdmin   bool    `json:"isAdmin"`
 	WechatID  *string `json:"wechatId,omitempty"`
+	// MustResetPassword 管理员已强制重置密码，前端应引导用户立即修改密码
+	MustResetPassword bool `json:"mustResetPassword"`
+	// DefaultZoneID 默认校园片区，首页优先推荐该片区的商品
+	DefaultZoneID *int64 `json:"defaultZoneId"`
+	// SchoolID 所属学校（租户）
+	SchoolID int64 `json:"schoolId"`
+	// IsSuperAdmin 超级管理员可通过 X-School-Code 请求头管理任意学校
+	IsSuperAdmin bool `json:"isSuperAdmin"`
+	// Email 校园邮箱，EmailVerified 为 false 时需完成验证（开启邮箱验证时才限制发布与联系）
+	Email         *string `json:"email"`
+	EmailVerified bool    `json:"emailVerified"`
 }
 
 // AuthResponse represents the authentication response
@@ -68,7 +108,8 @@ type AuthResponse struct {
 }
 
 // Register registers a new user and returns authentication response
-func (s *UserService) Register(ctx context.Context, account, nickname, password string, wechatID *string) (*AuthResponse, error) {
+// When email is given a verification email is sent; it is mandatory if email verification is required
+func (s *UserService) Register(ctx context.Context, account, nickname, password, email string, wechatID *string, client ClientInfo) (*AuthResponse, error) {
 	// Validate account format (only letters and numbers)
 	if !s.isValidAccountFormat(account) {
 		return nil, NewInvalidAccountFormatError()
@@ -79,6 +120,19 @@ func (s *UserService) Register(ctx context.Context, account, nickname, password
 		return nil, NewPasswordTooShortError()
 	}
 
+	// Validate email (format, allowed domain, uniqueness)
+	email = strings.TrimSpace(email)
+	if email == "" && s.emailCfg.Required {
+		return nil, ErrEmailRequired
+	}
+	if email != "" {
+		normalized, err := s.checkEmailAvailable(ctx, email, 0)
+		if err != nil {
+			return nil, err
+		}
+		email = normalized
+	}
+
 	// Check if account already exists
 	existingUser, err := s.userRepo.GetByAccount(ctx, account)
 	if err != nil {
@@ -112,6 +166,9 @@ func (s *UserService) Register(ctx context.Context, account, nickname, password
 	if wechatID != nil {
 		user.WechatID = *wechatID
 	}
+	if email != "" {
+		user.Email = &email
+	}
 
 	// Insert user
 	err = s.userRepo.Create(ctx, user)
@@ -119,17 +176,26 @@ func (s *UserService) Register(ctx context.Context, account, nickname, password
 		return nil, err
 	}
 
-	// Generate token
-	token, err := auth.GenerateToken(int64(user.ID))
-	if err != nil {
-		return nil, err
+	// Send verification email (best effort, the user can resend later)
+	if user.Email != nil {
+		if err := s.sendVerificationEmail(ctx, user); err != nil {
+			log.Printf("warn: send verification email for user %d failed: %v", user.ID, err)
+		}
 	}
 
-	// Create response
-	return &AuthResponse{
-		User:  s.buildUserResponse(user),
-		Token: token,
-	}, nil
+	// Sign the new user in on this device
+	return s.startSession(ctx, user, client, time.Time{})
+}
+
+// loginFailed records a failed login attempt and returns the error to report
+// The failure was already counted by loginGuard.Attempt; the attempt that triggered a lockout reports the lockout directly.
+// userID is nil when the account does not exist
+func (s *UserService) loginFailed(ctx context.Context, account string, userID *int64, client ClientInfo) error {
+	s.recordLogin(ctx, account, userID, client, model.LoginFailureInvalidCredentials)
+	if t := s.loginGuard.Status(ctx, account, client.IP); t != nil && t.Locked {
+		return NewLoginThrottledError(t)
+	}
+	return NewInvalidCredentialsError()
 }
 
 // isValidAccountFormat checks if the account format is valid (only letters and numbers)
@@ -152,41 +218,67 @@ func (s *UserService) buildUserResponse(user *model.User) UserResponse {
 		AvatarUrl: user.AvatarUrl,
 		IsAdmin:   user.IsAdmin,
 		WechatID:  wechatID,
+
+		MustResetPassword: user.MustResetPassword,
+		DefaultZoneID:     user.DefaultZoneID,
+		SchoolID:          user.SchoolID,
+		IsSuperAdmin:      user.IsSuperAdmin,
+		Email:             user.Email,
+		EmailVerified:     user.EmailVerifiedAt != nil,
 	}
 }
 
 // Login authenticates a user and returns authentication response
-func (s *UserService) Login(ctx context.Context, account, password string, remember bool) (*AuthResponse, error) {
+// Attempts are counted per account and per client IP before the password is checked and refunded on success,
+// so concurrent requests cannot slip past the progressive delay; once a threshold is reached further
+// attempts are rejected with LoginThrottledError until the cooldown or lockout expires.
+// Accounts with two-factor authentication enabled get TwoFactorRequiredError instead of a token
+// and finish the login with CompleteTwoFactorLogin. Every attempt is recorded in the login history
+func (s *UserService) Login(ctx context.Context, account, password string, remember bool, client ClientInfo) (*AuthResponse, error) {
+	// Reject attempts during cooldown or lockout before touching the password hash
+	if t := s.loginGuard.Attempt(ctx, account, client.IP); t != nil {
+		var userID *int64
+		if user, err := s.userRepo.GetByAccount(ctx, account); err == nil && user != nil {
+			userID = &user.ID
+		}
+		s.recordLogin(ctx, account, userID, client, model.LoginFailureThrottled)
+		return nil, NewLoginThrottledError(t)
+	}
+
 	// Get user by account
 	user, err := s.userRepo.GetByAccount(ctx, account)
 	if err != nil {
-		// 如果是记录不存在错误，返回无效凭证错误
+		// 如果是记录不存在错误，返回无效凭证错误（同样计入失败次数，避免探测账号是否存在）
 		if err == gorm.ErrRecordNotFound {
-			return nil, NewInvalidCredentialsError()
+			return nil, s.loginFailed(ctx, account, nil, client)
 		}
 		return nil, err
 	}
 	if user == nil {
-		return nil, NewInvalidCredentialsError()
+		return nil, s.loginFailed(ctx, account, nil, client)
 	}
 
 	// Verify password
 	err = auth.ComparePassword(user.Password, password)
 	if err != nil {
-		return nil, NewInvalidCredentialsError()
+		return nil, s.loginFailed(ctx, account, &user.ID, client)
 	}
+	s.loginGuard.RecordSuccess(ctx, account, client.IP)
 
-	// Generate token
-	token, err := auth.GenerateToken(int64(user.ID))
+	// Two-factor accounts must present a TOTP or backup code before a token is issued
+	enabled, err := s.isTwoFactorEnabled(ctx, user.ID)
 	if err != nil {
 		return nil, err
 	}
+	if enabled {
+		challenge, err := auth.GenerateTwoFactorChallenge(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &TwoFactorRequiredError{ChallengeToken: challenge, ExpiresIn: auth.TwoFactorChallengeTTL}
+	}
 
-	// Create response
-	return &AuthResponse{
-		User:  s.buildUserResponse(user),
-		Token: token,
-	}, nil
+	return s.loginSucceeded(ctx, user, client, time.Time{})
 }
 
 // GetProfile returns the user profile by user ID
@@ -297,6 +389,7 @@ func (s *UserService) ChangePassword(ctx context.Context, userID uint, oldPasswo
 
 	// Update password
 	user.Password = newHashedPassword
+	user.MustResetPassword = false
 	now := time.Now()
 	user.UpdatedAt = now
 
@@ -0,0 +1,326 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/auth"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+const (
+	// maxUserAgentLength User-Agent 最大保存长度（与数据库字段一致）
+	maxUserAgentLength = 512
+	// defaultLoginHistoryLimit 登录记录默认返回条数
+	defaultLoginHistoryLimit = 20
+	// maxLoginHistoryLimit 登录记录最多返回条数
+	maxLoginHistoryLimit = 100
+	// securityPageLink 安全中心页面路径，新设备登录通知跳转至此
+	securityPageLink = "/user/security"
+)
+
+// ClientInfo 发起请求的客户端信息，用于登录记录与会话管理
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// Notifier 站内通知发送接口，由通知服务实现
+type Notifier interface {
+	Notify(ctx context.Context, userID int64, kind, title, content, link string) error
+}
+
+// SessionResponse 已登录设备（会话）
+type SessionResponse struct {
+	ID         int64     `json:"id"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiredAt  time.Time `json:"expiredAt"`
+	// Current 是否为发起请求的当前会话
+	Current bool `json:"current"`
+}
+
+// LoginRecordResponse 登录记录
+type LoginRecordResponse struct {
+	ID            int64     `json:"id"`
+	Success       bool      `json:"success"`
+	FailureReason string    `json:"failureReason,omitempty"`
+	Device        string    `json:"device"`
+	IP            string    `json:"ip"`
+	UserAgent     string    `json:"userAgent"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// ListSessions 获取当前用户未过期的登录会话，currentSessionID 对应的会话标记为当前设备
+func (s *UserService) ListSessions(ctx context.Context, userID uint, currentSessionID string) ([]SessionResponse, error) {
+	result := make([]SessionResponse, 0)
+	if s.sessionRepo == nil {
+		return result, nil
+	}
+
+	sessions, err := s.sessionRepo.ListActiveSessions(ctx, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		result = append(result, SessionResponse{
+			ID:         session.ID,
+			Device:     describeUserAgent(session.UserAgent),
+			IP:         session.IP,
+			UserAgent:  session.UserAgent,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiredAt:  session.ExpiredAt,
+			Current:    currentSessionID != "" && session.Token == currentSessionID,
+		})
+	}
+	return result, nil
+}
+
+// RevokeSession 移除当前用户的指定会话，该设备上的登录令牌立即失效
+func (s *UserService) RevokeSession(ctx context.Context, userID uint, sessionID int64) error {
+	if s.sessionRepo == nil {
+		return ErrSessionNotFound
+	}
+	if err := s.sessionRepo.DeleteSession(ctx, int64(userID), sessionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// RevokeOtherSessions 移除当前设备以外的全部会话，返回移除数量
+func (s *UserService) RevokeOtherSessions(ctx context.Context, userID uint, currentSessionID string) (int64, error) {
+	if s.sessionRepo == nil {
+		return 0, nil
+	}
+	return s.sessionRepo.DeleteOtherSessions(ctx, int64(userID), currentSessionID)
+}
+
+// ListLoginHistory 获取当前用户最近的登录记录（含失败记录）
+func (s *UserService) ListLoginHistory(ctx context.Context, userID uint, limit int) ([]LoginRecordResponse, error) {
+	result := make([]LoginRecordResponse, 0)
+	if s.sessionRepo == nil {
+		return result, nil
+	}
+	if limit <= 0 {
+		limit = defaultLoginHistoryLimit
+	}
+	if limit > maxLoginHistoryLimit {
+		limit = maxLoginHistoryLimit
+	}
+
+	records, err := s.sessionRepo.ListLoginRecords(ctx, int64(userID), limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		result = append(result, LoginRecordResponse{
+			ID:            record.ID,
+			Success:       record.Success,
+			FailureReason: record.FailureReason,
+			Device:        describeUserAgent(record.UserAgent),
+			IP:            record.IP,
+			UserAgent:     record.UserAgent,
+			CreatedAt:     record.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+// loginSucceeded 完成登录：新设备登录时发送站内通知，记录登录成功并创建会话
+func (s *UserService) loginSucceeded(ctx context.Context, user *model.User, client ClientInfo, mfaAt time.Time) (*AuthResponse, error) {
+	s.notifyNewDevice(ctx, user, client)
+	s.recordLogin(ctx, user.Account, &user.ID, client, "")
+	return s.startSession(ctx, user, client, mfaAt)
+}
+
+// startSession 创建登录会话并签发绑定该会话的令牌，同时记录登录时间
+// mfaAt 为通过两步验证的时间，未进行两步验证时为零值
+func (s *UserService) startSession(ctx context.Context, user *model.User, client ClientInfo, mfaAt time.Time) (*AuthResponse, error) {
+	var token string
+	if s.sessionRepo == nil {
+		generated, err := auth.GenerateToken(int64(user.ID))
+		if err != nil {
+			return nil, err
+		}
+		token = generated
+	} else {
+		sessionID, err := newSessionID()
+		if err != nil {
+			return nil, err
+		}
+		session := &model.Session{
+			UserID:    user.ID,
+			Token:     sessionID,
+			IP:        client.IP,
+			UserAgent: truncateUserAgent(client.UserAgent),
+			ExpiredAt: time.Now().Add(auth.TokenTTL),
+		}
+		if err := s.sessionRepo.CreateSession(ctx, session); err != nil {
+			return nil, err
+		}
+		token, err = auth.GenerateSessionToken(int64(user.ID), sessionID, mfaAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Record login time (best effort)
+	if err := s.userRepo.TouchLastLogin(ctx, user.ID); err != nil {
+		log.Printf("warn: record last login for user %d failed: %v", user.ID, err)
+	}
+
+	return &AuthResponse{
+		User:  s.buildUserResponse(user),
+		Token: token,
+	}, nil
+}
+
+// reissueToken 为当前会话重新签发令牌（如两步验证后），并延长会话有效期
+func (s *UserService) reissueToken(ctx context.Context, userID int64, sessionID string, mfaAt time.Time) (string, error) {
+	token, err := auth.GenerateSessionToken(userID, sessionID, mfaAt)
+	if err != nil {
+		return "", err
+	}
+	if s.sessionRepo != nil && sessionID != "" {
+		if err := s.sessionRepo.ExtendSession(ctx, sessionID, time.Now().Add(auth.TokenTTL)); err != nil {
+			log.Printf("warn: extend session for user %d failed: %v", userID, err)
+		}
+	}
+	return token, nil
+}
+
+// recordLogin 记录一次登录尝试（best effort），failureReason 为空表示登录成功
+func (s *UserService) recordLogin(ctx context.Context, account string, userID *int64, client ClientInfo, failureReason string) {
+	if s.sessionRepo == nil {
+		return
+	}
+	if len(account) > 50 {
+		account = account[:50]
+	}
+	record := &model.LoginRecord{
+		UserID:        userID,
+		Account:       account,
+		Success:       failureReason == "",
+		FailureReason: failureReason,
+		IP:            client.IP,
+		UserAgent:     truncateUserAgent(client.UserAgent),
+		DeviceHash:    deviceHash(client.UserAgent),
+	}
+	if err := s.sessionRepo.CreateLoginRecord(ctx, record); err != nil {
+		log.Printf("warn: record login for account %q failed: %v", account, err)
+	}
+}
+
+// notifyNewDevice 用户此前在其他设备登录过、但从未在当前设备成功登录时发送站内通知
+// 首次登录不通知；需在写入本次登录记录之前调用
+func (s *UserService) notifyNewDevice(ctx context.Context, user *model.User, client ClientInfo) {
+	if s.sessionRepo == nil || s.notifier == nil {
+		return
+	}
+
+	known, err := s.sessionRepo.HasSuccessfulLogin(ctx, user.ID, deviceHash(client.UserAgent))
+	if err != nil || known {
+		return
+	}
+	loggedInBefore, err := s.sessionRepo.HasSuccessfulLogin(ctx, user.ID, "")
+	if err != nil || !loggedInBefore {
+		return
+	}
+
+	content := fmt.Sprintf("你的账号于 %s 在新设备（%s，IP %s）上登录。如非本人操作，请立即修改密码并在安全中心移除该设备。",
+		time.Now().Format("2006-01-02 15:04"), describeUserAgent(client.UserAgent), client.IP)
+	if err := s.notifier.Notify(ctx, user.ID, model.NotificationNewDeviceLogin, "新设备登录提醒", content, securityPageLink); err != nil {
+		log.Printf("warn: notify new device login for user %d failed: %v", user.ID, err)
+	}
+}
+
+// newSessionID 生成随机会话标识
+func newSessionID() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// deviceHash 计算设备标识（User-Agent 的 SHA-256 哈希），User-Agent 为空时返回空字符串
+func deviceHash(userAgent string) string {
+	userAgent = strings.ToLower(strings.TrimSpace(userAgent))
+	if userAgent == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// truncateUserAgent 截断过长的 User-Agent
+func truncateUserAgent(userAgent string) string {
+	userAgent = strings.TrimSpace(userAgent)
+	if len(userAgent) > maxUserAgentLength {
+		return userAgent[:maxUserAgentLength]
+	}
+	return userAgent
+}
+
+// describeUserAgent 从 User-Agent 中识别浏览器与操作系统，用于展示设备名称
+func describeUserAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+
+	browser := ""
+	switch {
+	case strings.Contains(ua, "micromessenger"):
+		browser = "微信"
+	case strings.Contains(ua, "edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/"):
+		browser = "Opera"
+	case strings.Contains(ua, "firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	}
+
+	os := ""
+	switch {
+	case strings.Contains(ua, "windows"):
+		os = "Windows"
+	case strings.Contains(ua, "android"):
+		os = "Android"
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"):
+		os = "iOS"
+	case strings.Contains(ua, "mac os"):
+		os = "macOS"
+	case strings.Contains(ua, "linux"):
+		os = "Linux"
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " · " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	default:
+		return "未知设备"
+	}
+}
@@ -0,0 +1,340 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/auth"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/common/totp"
+	"github.com/yycy134679/school-secondhand-trading-system/backend/model"
+)
+
+const (
+	// twoFactorSkew 允许前后各 1 个时间步（30 秒）的时钟偏差
+	twoFactorSkew = 1
+	// twoFactorMaxAttempts 每个用户在窗口内最多尝试的验证码次数
+	twoFactorMaxAttempts = 5
+	// twoFactorAttemptWindow 验证码尝试次数的统计窗口
+	twoFactorAttemptWindow = 5 * time.Minute
+
+	// backupCodeCount 每次生成的备用码数量
+	backupCodeCount = 10
+	// backupCodeLength 备用码长度（不含分隔符），展示为 xxxxx-xxxxx
+	backupCodeLength = 10
+	// backupCodeAlphabet 备用码字符集，去除易混淆的 0/o、1/l/i
+	backupCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// TwoFactorStatus 两步验证状态
+type TwoFactorStatus struct {
+	Enabled              bool       `json:"enabled"`
+	EnabledAt            *time.Time `json:"enabledAt"`
+	BackupCodesRemaining int64      `json:"backupCodesRemaining"`
+	// Required 管理员必须启用两步验证才能访问管理后台
+	Required bool `json:"required"`
+}
+
+// TwoFactorSetup 两步验证绑定信息
+// ProvisioningURI 为 otpauth:// 链接，前端渲染为二维码供验证器应用扫描；无法扫码时可手动输入 Secret
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// TwoFactorEnableResult 启用两步验证的结果
+// 备用码只在此时展示一次；Token 为已通过两步验证的新登录令牌，管理员可直接访问管理后台
+type TwoFactorEnableResult struct {
+	BackupCodes []string `json:"backupCodes"`
+	Token       string   `json:"token"`
+}
+
+// GetTwoFactorStatus 获取当前用户的两步验证状态
+func (s *UserService) GetTwoFactorStatus(ctx context.Context, userID uint) (*TwoFactorStatus, error) {
+	user, err := s.userRepo.GetByID(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	status := &TwoFactorStatus{Required: user.IsAdmin || user.IsSuperAdmin}
+	setting, err := s.getTwoFactor(ctx, user.ID)
+	if err != nil || setting == nil || setting.EnabledAt == nil {
+		return status, err
+	}
+
+	remaining, err := s.twoFactorRepo.CountBackupCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	status.Enabled = true
+	status.EnabledAt = setting.EnabledAt
+	status.BackupCodesRemaining = remaining
+	return status, nil
+}
+
+// SetupTwoFactor 生成新的 TOTP 密钥（待确认），重复调用会替换尚未确认的密钥
+func (s *UserService) SetupTwoFactor(ctx context.Context, userID uint) (*TwoFactorSetup, error) {
+	if s.twoFactorRepo == nil {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	user, err := s.userRepo.GetByID(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	saved, err := s.twoFactorRepo.SavePending(ctx, user.ID, secret)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.twoFactorIssuer, user.Account, secret),
+	}, nil
+}
+
+// EnableTwoFactor 使用验证器应用生成的验证码确认绑定，启用两步验证并生成备用码
+// sessionID 为当前登录会话，新令牌沿用该会话
+func (s *UserService) EnableTwoFactor(ctx context.Context, userID uint, sessionID, code string) (*TwoFactorEnableResult, error) {
+	setting, err := s.getTwoFactor(ctx, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		return nil, ErrTwoFactorNotSetup
+	}
+	if setting.EnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, ErrTwoFactorCodeRequired
+	}
+	if !s.limiter.Allow(ctx, twoFactorAttemptKey(setting.UserID), twoFactorMaxAttempts, twoFactorAttemptWindow) {
+		return nil, ErrTwoFactorTooManyAttempts
+	}
+	step, ok := totp.Validate(setting.Secret, code, time.Now(), twoFactorSkew)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newBackupCodes()
+	if err != nil {
+		return nil, err
+	}
+	enabled, err := s.twoFactorRepo.Enable(ctx, setting.UserID, step, hashes)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	token, err := s.reissueToken(ctx, setting.UserID, sessionID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &TwoFactorEnableResult{BackupCodes: codes, Token: token}, nil
+}
+
+// DisableTwoFactor 校验验证码（或备用码）后关闭两步验证
+// 管理员关闭后需重新启用才能访问管理后台
+func (s *UserService) DisableTwoFactor(ctx context.Context, userID uint, code string) error {
+	setting, err := s.requireTwoFactor(ctx, int64(userID))
+	if err != nil {
+		return err
+	}
+	if err := s.verifyTwoFactorCode(ctx, setting, code); err != nil {
+		return err
+	}
+	return s.twoFactorRepo.Delete(ctx, setting.UserID)
+}
+
+// RegenerateBackupCodes 校验验证码（或备用码）后重新生成备用码，旧备用码全部作废
+func (s *UserService) RegenerateBackupCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	setting, err := s.requireTwoFactor(ctx, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyTwoFactorCode(ctx, setting, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newBackupCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.ReplaceBackupCodes(ctx, setting.UserID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// StepUpTwoFactor 已登录用户重新校验验证码，返回带最新两步验证时间的登录令牌
+// 管理员的两步验证超过有效期后，需通过此接口换取新令牌才能继续访问管理后台
+func (s *UserService) StepUpTwoFactor(ctx context.Context, userID uint, sessionID, code string) (string, error) {
+	setting, err := s.requireTwoFactor(ctx, int64(userID))
+	if err != nil {
+		return "", err
+	}
+	if err := s.verifyTwoFactorCode(ctx, setting, code); err != nil {
+		return "", err
+	}
+	return s.reissueToken(ctx, setting.UserID, sessionID, time.Now())
+}
+
+// CompleteTwoFactorLogin 使用登录时返回的挑战令牌与验证码（或备用码）完成登录
+// 验证失败同样记入登录记录
+func (s *UserService) CompleteTwoFactorLogin(ctx context.Context, challengeToken, code string, client ClientInfo) (*AuthResponse, error) {
+	userID, err := auth.ParseTwoFactorChallenge(strings.TrimSpace(challengeToken))
+	if err != nil {
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidTwoFactorChallenge
+		}
+		return nil, err
+	}
+	setting, err := s.getTwoFactor(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil || setting.EnabledAt == nil {
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+
+	if err := s.verifyTwoFactorCode(ctx, setting, code); err != nil {
+		s.recordLogin(ctx, user.Account, &user.ID, client, model.LoginFailureTwoFactor)
+		return nil, err
+	}
+	return s.loginSucceeded(ctx, user, client, time.Now())
+}
+
+// isTwoFactorEnabled 判断用户是否已启用两步验证
+func (s *UserService) isTwoFactorEnabled(ctx context.Context, userID int64) (bool, error) {
+	if s.twoFactorRepo == nil {
+		return false, nil
+	}
+	return s.twoFactorRepo.IsEnabled(ctx, userID)
+}
+
+// getTwoFactor 获取两步验证设置，未设置时返回 nil
+func (s *UserService) getTwoFactor(ctx context.Context, userID int64) (*model.UserTwoFactor, error) {
+	if s.twoFactorRepo == nil {
+		return nil, nil
+	}
+	setting, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return setting, nil
+}
+
+// requireTwoFactor 获取已启用的两步验证设置，未启用时返回 ErrTwoFactorNotEnabled
+func (s *UserService) requireTwoFactor(ctx context.Context, userID int64) (*model.UserTwoFactor, error) {
+	setting, err := s.getTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil || setting.EnabledAt == nil {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	return setting, nil
+}
+
+// verifyTwoFactorCode 校验 6 位动态验证码或备用码
+// 动态验证码按时间步只能使用一次，备用码使用后作废；错误次数按用户限流
+func (s *UserService) verifyTwoFactorCode(ctx context.Context, setting *model.UserTwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ErrTwoFactorCodeRequired
+	}
+	if !s.limiter.Allow(ctx, twoFactorAttemptKey(setting.UserID), twoFactorMaxAttempts, twoFactorAttemptWindow) {
+		return ErrTwoFactorTooManyAttempts
+	}
+
+	if step, ok := totp.Validate(setting.Secret, code, time.Now(), twoFactorSkew); ok {
+		consumed, err := s.twoFactorRepo.ConsumeStep(ctx, setting.UserID, step)
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := s.twoFactorRepo.UseBackupCode(ctx, setting.UserID, hashBackupCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// twoFactorAttemptKey 验证码尝试次数的缓存键
+func twoFactorAttemptKey(userID int64) string {
+	return fmt.Sprintf("2fa:attempt:%d", userID)
+}
+
+// newBackupCodes 生成一组备用码，返回明文（展示给用户）与哈希（落库）
+func newBackupCodes() ([]string, []string, error) {
+	codes := make([]string, 0, backupCodeCount)
+	hashes := make([]string, 0, backupCodeCount)
+	max := big.NewInt(int64(len(backupCodeAlphabet)))
+	for i := 0; i < backupCodeCount; i++ {
+		var sb strings.Builder
+		for j := 0; j < backupCodeLength; j++ {
+			if j == backupCodeLength/2 {
+				sb.WriteByte('-')
+			}
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, nil, err
+			}
+			sb.WriteByte(backupCodeAlphabet[n.Int64()])
+		}
+		code := sb.String()
+		codes = append(codes, code)
+		hashes = append(hashes, hashBackupCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashBackupCode 计算备用码的 SHA-256 哈希，忽略大小写、空格与分隔符
+func hashBackupCode(code string) string {
+	normalized := strings.ToLower(code)
+	normalized = strings.NewReplacer("-", "", " ", "").Replace(normalized)
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,13 +3,21 @@ export enum ErrorCode {
   PARAM_ERROR = 1001,
   UNAUTHORIZED = 1002,
   FORBIDDEN = 1003,
+  TOO_MANY_REQUESTS = 1004,
   ACCOUNT_EXISTS = 2001,
   LOGIN_FAILED = 2002,
+  EMAIL_NOT_VERIFIED = 2003,
+  LOGIN_THROTTLED = 2004,
+  ACCOUNT_LOCKED = 2005,
+  TWO_FACTOR_REQUIRED = 2006,
+  TWO_FACTOR_SETUP_REQUIRED = 2007,
   PRODUCT_NOT_FOUND = 3001,
   NOT_OWNER = 3002,
   INVALID_STATUS_TRANSITION = 3003,
   PRODUCT_SOLD = 3004,
   REVOKE_FAILED = 3005,
+  QUOTA_EXCEEDED = 3006,
+  BLOCKED_KEYWORD = 3007,
   CATEGORY_IN_USE = 4001,
   TAG_IN_USE = 4002,
 }
@@ -0,0 +1,9 @@
+export interface Book {
+  id: number
+  isbn: string
+  title: string
+  author: string
+  edition: string
+  publisher: string
+  courseCodes: string[]
+}
@@ -0,0 +1,15 @@
+export interface MeetupPoint {
+  id: number
+  zoneId: number
+  name: string
+  description: string
+  sortOrder: number
+}
+
+export interface CampusZone {
+  id: number
+  name: string
+  description: string
+  sortOrder: number
+  points: MeetupPoint[]
+}
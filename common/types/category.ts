@@ -2,6 +2,28 @@ export interface Category {
   id: number
   name: string
   description?: string
+  parentId?: number | null
+  sortOrder?: number
+  createdAt: string
+  updatedAt: string
+}
+
+export interface CategoryNode extends Category {
+  children: CategoryNode[]
+}
+
+export type CategoryAttributeType = 'string' | 'number' | 'enum' | 'boolean'
+
+export interface CategoryAttribute {
+  id: number
+  categoryId: number
+  key: string
+  label: string
+  type: CategoryAttributeType
+  required: boolean
+  options: string[]
+  unit?: string
+  sortOrder: number
   createdAt: string
   updatedAt: string
 }
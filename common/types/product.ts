@@ -8,6 +8,7 @@ export interface Product {
   conditionId: number
   sellerId: number
   categoryId: number
+  meetupPointId?: number
   createdAt: string
   updatedAt: string
 }
@@ -0,0 +1,8 @@
+export interface School {
+  id: number
+  code: string
+  name: string
+  isActive: boolean
+  created_at: string
+  updated_at: string
+}
@@ -2,6 +2,14 @@ export interface Tag {
   id: number
   name: string
   categoryId?: number
+  // 标签适用的全部分类（含主分类）
+  categoryIds?: number[]
+  // 同义词，搜索时与标签名称等效
+  aliases?: string[]
+  // 审核状态：Approved / Pending / Rejected
+  status?: 'Approved' | 'Pending' | 'Rejected'
+  // 使用该标签的商品数（管理端列表返回）
+  usageCount?: number
   createdAt: string
   updatedAt: string
 }
@@ -5,6 +5,11 @@ export interface User {
   avatarUrl?: string
   wechatId?: string
   isAdmin: boolean
+  defaultZoneId?: number | null
+  schoolId?: number
+  isSuperAdmin?: boolean
+  email?: string | null
+  emailVerified?: boolean
   lastNicknameChangedAt?: string
   createdAt: string
   updatedAt: string
@@ -0,0 +1,28 @@
+import request from '@/utils/request'
+import type { ApiResponse } from '@common/types/api'
+import type { Book } from '@common/types/book'
+import type { Product } from '@common/types/product'
+
+// 课程教材推荐
+export interface CourseBookRecommendation {
+  courseCode: string
+  book: Book
+  listings: Product[]
+}
+
+// 根据 ISBN 查询图书目录（发布教材时自动填充）
+export function getBookByIsbn(isbn: string) {
+  return request.get<ApiResponse<Book>>(`/books/isbn/${encodeURIComponent(isbn)}`)
+}
+
+export function getMyCourses() {
+  return request.get<ApiResponse<{ courses: string[] }>>('/users/courses')
+}
+
+export function updateMyCourses(courses: string[]) {
+  return request.put<ApiResponse<{ courses: string[] }>>('/users/courses', { courses })
+}
+
+export function getCourseBooks() {
+  return request.get<ApiResponse<{ items: CourseBookRecommendation[] }>>('/users/courses/books')
+}
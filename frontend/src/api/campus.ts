@@ -0,0 +1,13 @@
+import request from '@/utils/request'
+import type { ApiResponse } from '@common/types/api'
+import type { CampusZone } from '@common/types/campus'
+
+// 校园片区及其面交地点
+export function getCampusZones() {
+  return request.get<ApiResponse<CampusZone[]>>('/campus/zones')
+}
+
+// zoneId 传 null 清除默认片区
+export function updateDefaultZone(zoneId: number | null) {
+  return request.put<ApiResponse<{ defaultZoneId: number | null }>>('/users/default-zone', { zoneId })
+}
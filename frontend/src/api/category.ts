@@ -1,7 +1,16 @@
 import request from '@/utils/request'
 import type { ApiResponse } from '@common/types/api'
-import type { Category } from '@common/types/category'
+import type { Category, CategoryAttribute, CategoryNode } from '@common/types/category'
 
 export function getCategories() {
   return request.get<ApiResponse<Category[]>>('/categories')
 }
+
+export function getCategoryTree() {
+  return request.get<ApiResponse<CategoryNode[]>>('/categories', { params: { tree: true } })
+}
+
+// 获取分类生效的属性定义（含从父分类继承的属性）
+export function getCategoryAttributes(categoryId: number) {
+  return request.get<ApiResponse<CategoryAttribute[]>>(`/categories/${categoryId}/attributes`)
+}
@@ -14,6 +14,7 @@ export interface HomeProduct {
   conditionId?: number
   sellerId?: number
   categoryId?: number
+  meetupPointId?: number
   createdAt?: string
   updatedAt?: string
 }
@@ -22,8 +23,10 @@ export interface HomeData {
   recommendations: HomeProduct[]
   latest: HomeProduct[]
   totalCount: number
+  nearbyZoneId?: number
 }
 
-export function getHomeData(params?: { page?: number; pageSize?: number }) {
+// zoneId 指定时该片区的商品优先，未指定时后端使用用户的默认片区
+export function getHomeData(params?: { page?: number; pageSize?: number; zoneId?: number }) {
   return request.get<ApiResponse<HomeData>>('/home', { params })
 }
@@ -0,0 +1,43 @@
+import request from '@/utils/request'
+import type { ApiResponse } from '@common/types/api'
+
+// 站内通知
+export interface Notification {
+  id: number
+  userId: number
+  // 通知类型，如 security.new_device（新设备登录）
+  type: string
+  title: string
+  content: string
+  link: string | null
+  readAt: string | null
+  createdAt: string
+}
+
+export interface NotificationListResponse {
+  total: number
+  unreadCount: number
+  notifications: Notification[]
+}
+
+export interface NotificationListParams {
+  page?: number
+  pageSize?: number
+  unread?: boolean
+}
+
+export function getNotifications(params: NotificationListParams = {}) {
+  return request.get<ApiResponse<NotificationListResponse>>('/notifications', { params })
+}
+
+export function getUnreadNotificationCount() {
+  return request.get<ApiResponse<{ count: number }>>('/notifications/unread-count')
+}
+
+export function markNotificationRead(id: number) {
+  return request.post<ApiResponse<void>>(`/notifications/${id}/read`)
+}
+
+export function markAllNotificationsRead() {
+  return request.post<ApiResponse<{ updated: number }>>('/notifications/read-all')
+}
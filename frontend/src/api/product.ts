@@ -1,5 +1,6 @@
 import request from '@/utils/request'
 import type { ApiResponse, PageResult } from '@common/types/api'
+import type { Book } from '@common/types/book'
 import type { Product } from '@common/types/product'
 import type { ProductCondition } from '@common/types/product_condition'
 
@@ -14,6 +15,9 @@ export interface ProductDetail extends Product {
   conditionName: string
   images: ProductImage[]
   tagIds: number[]
+  attributes: Record<string, string | number | boolean>
+  isbn?: string
+  book?: Book
   seller: {
     id: number
     nickname: string
@@ -21,10 +25,21 @@ export interface ProductDetail extends Product {
   }
   viewerIsSeller: boolean
   sellerWechat: string | null
+  priceHistory?: PricePoint[]
+  priceDrop: number
+}
+
+// 价格历史节点
+export interface PricePoint {
+  price: number
+  changedAt: string
 }
 
 // 发布商品参数 (FormData)
 // title, description, price, categoryId, tagIds, conditionId, images
+// attributes 为分类属性值的 JSON 字符串（可选）
+// isbn 教材 ISBN（可选），图书目录中存在时未填写的标题与描述会自动补全
+// proposedTags 卖家提议的新标签名称（可选，可重复字段或逗号分隔），审核通过后对外展示
 // 这里只定义接口，实际调用时传 FormData
 
 // 编辑商品参数
@@ -36,6 +51,8 @@ export interface UpdateProductParams {
   tagIds?: number[] // 前端使用数组，在请求前转换为逗号分隔字符串
   conditionId?: number
   imageUrls?: string[]
+  attributes?: Record<string, string | number | boolean>
+  isbn?: string
 }
 
 // 搜索参数
@@ -90,6 +107,11 @@ export function undoProductStatusChange(id: number) {
   return request.post<ApiResponse<Product>>(`/products/${id}/status/undo`)
 }
 
+// 擦亮在售商品，续期避免长期无活动被自动下架
+export function bumpProduct(id: number) {
+  return request.post<ApiResponse<{ message: string }>>(`/products/${id}/bump`)
+}
+
 export function getProductDetail(id: number) {
   return request.get<ApiResponse<ProductDetail>>(`/products/${id}`)
 }
@@ -0,0 +1,8 @@
+import request from '@/utils/request'
+import type { ApiResponse } from '@common/types/api'
+import type { School } from '@common/types/school'
+
+// 启用的学校列表，注册时选择学校
+export function getSchools() {
+  return request.get<ApiResponse<School[]>>('/schools')
+}
@@ -2,6 +2,7 @@ import request from '@/utils/request'
 import type { ApiResponse } from '@common/types/api'
 import type { Tag } from '@common/types/tag'
 
-export function getTags() {
-  return request.get<ApiResponse<Tag[]>>('/tags')
+// 指定 categoryId 时仅返回适用于该分类（含父分类）的标签
+export function getTags(categoryId?: number) {
+  return request.get<ApiResponse<Tag[]>>('/tags', { params: categoryId ? { categoryId } : undefined })
 }
@@ -9,6 +9,7 @@ export interface RegisterParams {
   nickname: string
   password: string
   confirmPassword: string
+  email?: string
   wechatId?: string
 }
 
@@ -37,10 +38,121 @@ export function register(data: RegisterParams) {
   return request.post<ApiResponse<LoginResponse>>('/users/register', data)
 }
 
+// 登录被限制（错误码 2004/2005）时响应 data 中的冷却信息
+export interface LoginThrottleInfo {
+  retryAfterSeconds: number
+  locked: boolean
+}
+
 export function login(data: LoginParams) {
   return request.post<ApiResponse<LoginResponse>>('/users/login', data)
 }
 
+// 已启用两步验证的账号登录时返回错误码 2006，data 中携带挑战令牌
+export interface TwoFactorChallenge {
+  twoFactorToken: string
+  expiresInSeconds: number
+}
+
+// code 为验证器应用中的 6 位验证码或备用码
+export function loginWithTwoFactor(twoFactorToken: string, code: string) {
+  return request.post<ApiResponse<LoginResponse>>('/users/login/2fa', { twoFactorToken, code })
+}
+
+export interface TwoFactorStatus {
+  enabled: boolean
+  enabledAt: string | null
+  backupCodesRemaining: number
+  // 管理员必须启用两步验证才能访问管理后台
+  required: boolean
+}
+
+// provisioningUri 为 otpauth:// 链接，渲染为二维码供验证器应用扫描
+export interface TwoFactorSetup {
+  secret: string
+  provisioningUri: string
+}
+
+export interface TwoFactorEnableResult {
+  backupCodes: string[]
+  token: string
+}
+
+export function getTwoFactorStatus() {
+  return request.get<ApiResponse<TwoFactorStatus>>('/users/2fa')
+}
+
+export function setupTwoFactor() {
+  return request.post<ApiResponse<TwoFactorSetup>>('/users/2fa/setup')
+}
+
+export function enableTwoFactor(code: string) {
+  return request.post<ApiResponse<TwoFactorEnableResult>>('/users/2fa/enable', { code })
+}
+
+export function disableTwoFactor(code: string) {
+  return request.post<ApiResponse<void>>('/users/2fa/disable', { code })
+}
+
+export function regenerateBackupCodes(code: string) {
+  return request.post<ApiResponse<{ backupCodes: string[] }>>('/users/2fa/backup-codes', { code })
+}
+
+// 管理后台返回 2006 时调用，换取新的登录令牌
+export function verifyTwoFactor(code: string) {
+  return request.post<ApiResponse<{ token: string }>>('/users/2fa/verify', { code })
+}
+
+// 已登录设备
+export interface UserSession {
+  id: number
+  device: string
+  ip: string
+  userAgent: string
+  createdAt: string
+  lastSeenAt: string
+  expiredAt: string
+  // 是否为当前设备
+  current: boolean
+}
+
+// 登录记录，failureReason 取值 invalid_credentials / throttled / two_factor
+export interface LoginRecord {
+  id: number
+  success: boolean
+  failureReason?: string
+  device: string
+  ip: string
+  userAgent: string
+  createdAt: string
+}
+
+export function getSessions() {
+  return request.get<ApiResponse<UserSession[]>>('/users/sessions')
+}
+
+export function revokeSession(id: number) {
+  return request.delete<ApiResponse<void>>(`/users/sessions/${id}`)
+}
+
+export function revokeOtherSessions() {
+  return request.delete<ApiResponse<{ revoked: number }>>('/users/sessions')
+}
+
+export function getLoginHistory(limit = 20) {
+  return request.get<ApiResponse<LoginRecord[]>>('/users/login-history', { params: { limit } })
+}
+
+// 下载我的数据（ZIP：个人资料、商品及图片、浏览记录、登录记录）
+export function exportMyData() {
+  return request.get<Blob>('/users/me/export', { responseType: 'blob', timeout: 120000 })
+}
+
+// 注销账号：在售商品下架，个人资料匿名化，已售记录保留
+export function deleteAccount(password: string) {
+  return request.post<ApiResponse<{ delistedProducts: number }>>('/users/me/delete', { password })
+}
+
 export function getProfile() {
   return request.get<ApiResponse<User>>('/users/profile')
 }
@@ -53,6 +165,34 @@ export function changePassword(data: ChangePasswordParams) {
   return request.put<ApiResponse<void>>('/users/password', data)
 }
 
+export function setEmail(email: string) {
+  return request.put<ApiResponse<User>>('/users/email', { email })
+}
+
+export function resendVerificationEmail() {
+  return request.post<ApiResponse<void>>('/users/email/resend')
+}
+
+export function verifyEmail(token: string) {
+  return request.post<ApiResponse<User>>('/users/email/verify', { token })
+}
+
+// 重置密码参数
+export interface ResetPasswordParams {
+  token: string
+  newPassword: string
+  confirmPassword: string
+}
+
+// account 可以是登录账号或已验证的邮箱
+export function forgotPassword(account: string) {
+  return request.post<ApiResponse<void>>('/users/password/forgot', { account })
+}
+
+export function resetPassword(data: ResetPasswordParams) {
+  return request.post<ApiResponse<void>>('/users/password/reset', data)
+}
+
 export interface RecentView {
   viewedAt: string
   product: Product
@@ -23,6 +23,11 @@ service.interceptors.request.use(
       // 请根据实际情况修改
       config.headers.Authorization = `Bearer ${token}`
     }
+    // 未通过子域名区分学校时，使用本地选择的学校编码
+    const schoolCode = localStorage.getItem('school_code')
+    if (schoolCode) {
+      config.headers['X-School-Code'] = schoolCode
+    }
     return config
   },
   (error: unknown) => {
@@ -35,6 +40,10 @@ service.interceptors.request.use(
 // 响应拦截器
 service.interceptors.response.use(
   (response: AxiosResponse<ApiResponse>) => {
+    // 文件下载（如导出个人数据）直接返回原始响应，由调用方处理
+    if (response.config.responseType === 'blob' && !String(response.headers['content-type']).includes('json')) {
+      return response
+    }
     const res = response.data
 
     // 如果 code 不为 0，则判断为错误